@@ -7,3 +7,5 @@ require gopkg.in/yaml.v3 v3.0.1
 require github.com/dominikbraun/graph v0.23.0
 
 require github.com/joho/godotenv v1.5.1
+
+require github.com/klauspost/compress v1.18.0
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+// Package compression provides the codecs used to store artifacts, caches,
+// and logs on disk. Files written with a codec start with a header naming
+// it, so files written with different settings remain readable after the
+// configuration changes, and data stored as-is is never mistaken for
+// compressed data, even when it is itself gzip or zstd output.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses and decompresses a stream.
+type Codec interface {
+	// Name identifies the codec in the header of the files it writes.
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	// None stores data as-is.
+	None Codec = noneCodec{}
+	// Gzip uses compress/gzip at the default level.
+	Gzip Codec = gzipCodec{}
+	// Zstd uses zstandard at the default level.
+	Zstd Codec = zstdCodec{}
+)
+
+// magic starts the header of encoded data: magic, the codec name, and a
+// newline. The leading NUL keeps it from colliding with text.
+var magic = []byte("\x00atomos-codec:")
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Codec{"none": None, "gzip": Gzip, "zstd": Zstd}
+)
+
+// Register makes a custom codec readable by Decompress and ReadFile, and
+// available through ByName. Built-in codecs can't be replaced.
+func Register(codec Codec) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := codec.Name()
+	if name == "" || strings.ContainsAny(name, "\n\x00") {
+		return fmt.Errorf("invalid compression codec name %q", name)
+	}
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("compression codec '%s' is already registered", name)
+	}
+	registry[name] = codec
+	return nil
+}
+
+// ByName returns the codec registered under name ("none", "gzip", "zstd",
+// or a registered custom codec).
+func ByName(name string) (Codec, error) {
+	if name == "" {
+		return None, nil
+	}
+
+	registryMu.RLock()
+	codec, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown compression codec '%s'", name)
+	}
+	return codec, nil
+}
+
+// Compress encodes data with codec behind a header naming it. Data stored
+// with None is left as-is, unless it starts like a header itself.
+func Compress(data []byte, codec Codec) ([]byte, error) {
+	if codec == nil {
+		codec = None
+	}
+	if codec.Name() == None.Name() && !bytes.HasPrefix(data, magic) {
+		return data, nil
+	}
+
+	compressed, err := codec.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s compression failed: %w", codec.Name(), err)
+	}
+
+	encoded := make([]byte, 0, len(magic)+len(codec.Name())+1+len(compressed))
+	encoded = append(encoded, magic...)
+	encoded = append(encoded, codec.Name()...)
+	encoded = append(encoded, '\n')
+	return append(encoded, compressed...), nil
+}
+
+// Decompress decodes data written by Compress with whichever codec its
+// header names. Data without a header was stored as-is and is returned
+// unchanged.
+func Decompress(data []byte) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(data, magic)
+	if !ok {
+		return data, nil
+	}
+
+	name, payload, ok := bytes.Cut(rest, []byte{'\n'})
+	if !ok {
+		return nil, errors.New("truncated compression header")
+	}
+	codec, err := ByName(string(name))
+	if err != nil {
+		return nil, err
+	}
+	return codec.Decompress(payload)
+}
+
+// WriteFile compresses data with codec and writes it to path.
+func WriteFile(path string, data []byte, codec Codec) error {
+	encoded, err := Compress(data, codec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, encoded, 0644)
+}
+
+// ReadFile reads path and transparently decompresses it.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decompressed, err := Decompress(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+
+	return decompressed, nil
+}
+
+type noneCodec struct{}
+
+func (noneCodec) Name() string                           { return "none" }
+func (noneCodec) Compress(data []byte) ([]byte, error)   { return data, nil }
+func (noneCodec) Decompress(data []byte) ([]byte, error) { return data, nil }
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return "gzip" }
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) Name() string { return "zstd" }
+
+func (zstdCodec) Compress(data []byte) ([]byte, error) {
+	w, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+	return w.EncodeAll(data, nil), nil
+}
+
+func (zstdCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.DecodeAll(data, nil)
+}
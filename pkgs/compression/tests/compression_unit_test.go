@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat("atomos artifact\n", 100))
+	gzipped, err := compression.Gzip.Compress(data)
+	if err != nil {
+		t.Fatalf("gzip failed: %v", err)
+	}
+	zstded, err := compression.Zstd.Compress(data)
+	if err != nil {
+		t.Fatalf("zstd failed: %v", err)
+	}
+
+	inputs := map[string][]byte{
+		"text":        data,
+		"empty":       {},
+		"gzip output": gzipped,
+		"zstd output": zstded,
+		"header-like": []byte("\x00atomos-codec:zstd\nnot zstd"),
+	}
+
+	for name, input := range inputs {
+		for _, codec := range []compression.Codec{compression.None, compression.Gzip, compression.Zstd} {
+			path := filepath.Join(t.TempDir(), "artifact")
+			if err := compression.WriteFile(path, input, codec); err != nil {
+				t.Fatalf("%s with %s: WriteFile failed: %v", name, codec.Name(), err)
+			}
+			got, err := compression.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%s with %s: ReadFile failed: %v", name, codec.Name(), err)
+			}
+			if !bytes.Equal(got, input) {
+				t.Errorf("%s with %s: read %q, wrote %q", name, codec.Name(), got, input)
+			}
+		}
+	}
+}
+
+func TestNoneStoresDataAsIs(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"samples": 3}`)
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	if err := compression.WriteFile(path, data, compression.None); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !bytes.Equal(raw, data) {
+		t.Errorf("expected the file to hold the data unchanged, got %q", raw)
+	}
+}
+
+func TestCompressedFilesAreSmaller(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat("repetitive output line\n", 1000))
+	for _, codec := range []compression.Codec{compression.Gzip, compression.Zstd} {
+		encoded, err := compression.Compress(data, codec)
+		if err != nil {
+			t.Fatalf("%s: Compress failed: %v", codec.Name(), err)
+		}
+		if len(encoded) >= len(data)/10 {
+			t.Errorf("%s: expected a much smaller encoding, got %d of %d bytes", codec.Name(), len(encoded), len(data))
+		}
+	}
+}
+
+type reverseCodec struct{}
+
+func (reverseCodec) Name() string { return "reverse" }
+
+func (reverseCodec) Compress(data []byte) ([]byte, error) { return reverse(data), nil }
+
+func (reverseCodec) Decompress(data []byte) ([]byte, error) { return reverse(data), nil }
+
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	if err := compression.Register(reverseCodec{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := compression.Register(reverseCodec{}); err == nil {
+		t.Error("expected registering the same name twice to fail")
+	}
+
+	codec, err := compression.ByName("reverse")
+	if err != nil {
+		t.Fatalf("ByName failed: %v", err)
+	}
+	encoded, err := compression.Compress([]byte("abc"), codec)
+	if err != nil {
+		t.Fatalf("Compress failed: %v", err)
+	}
+	decoded, err := compression.Decompress(encoded)
+	if err != nil || string(decoded) != "abc" {
+		t.Errorf("expected abc, got %q (%v)", decoded, err)
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range map[string]string{
+		"unknown codec":    "\x00atomos-codec:lz4\ndata",
+		"truncated header": "\x00atomos-codec:zstd",
+		"corrupt payload":  "\x00atomos-codec:gzip\nnot gzip",
+	} {
+		if _, err := compression.Decompress([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := compression.ByName("lz4"); err == nil {
+		t.Error("expected an unknown codec name to fail")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// CloudWatchEvent is a log event in the shape expected by PutLogEvents.
+type CloudWatchEvent struct {
+	Timestamp int64 // Milliseconds since the Unix epoch
+	Message   string
+}
+
+// CloudWatchClient puts events into a CloudWatch Logs stream. It is a thin
+// adapter over the AWS SDK's PutLogEvents, so AtomOS does not depend on the
+// SDK; the adapter is expected to create the stream if it does not exist.
+type CloudWatchClient interface {
+	PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent) error
+}
+
+// PutLogEvents limits. The size of an event is its message in bytes plus
+// a fixed overhead.
+const (
+	maxCloudWatchBatch      = 10000
+	maxCloudWatchBatchBytes = 1048576
+	maxCloudWatchEventBytes = 262144
+	cloudWatchEventOverhead = 26
+)
+
+// truncatedSuffix ends the line of a record cut to fit in one event.
+const truncatedSuffix = " [truncated]"
+
+// CloudWatchSink ships records to CloudWatch Logs, one log stream per run.
+// Each event message is the JSON-encoded record, labels included. Records
+// too large for a single event have their line truncated, and batches are
+// split to stay within the PutLogEvents count and size limits.
+type CloudWatchSink struct {
+	Client CloudWatchClient
+	Group  string
+	// StreamPrefix is prepended to the run ID to name the log stream.
+	StreamPrefix string
+}
+
+func (s *CloudWatchSink) Ship(ctx context.Context, records []Record) error {
+	byStream := map[string][]CloudWatchEvent{}
+	var order []string
+
+	for _, record := range records {
+		stream := s.StreamPrefix + record.Labels[LabelRunID]
+		if _, ok := byStream[stream]; !ok {
+			order = append(order, stream)
+		}
+
+		msg, err := cloudWatchMessage(record)
+		if err != nil {
+			return err
+		}
+		byStream[stream] = append(byStream[stream], CloudWatchEvent{Timestamp: record.Time.UnixMilli(), Message: msg})
+	}
+
+	for _, stream := range order {
+		events := byStream[stream]
+		for len(events) > 0 {
+			n, size := 0, 0
+			for n < min(len(events), maxCloudWatchBatch) {
+				eventSize := len(events[n].Message) + cloudWatchEventOverhead
+				if size+eventSize > maxCloudWatchBatchBytes {
+					break
+				}
+				size += eventSize
+				n++
+			}
+			if err := s.Client.PutLogEvents(ctx, s.Group, stream, events[:n]); err != nil {
+				return fmt.Errorf("CloudWatch PutLogEvents failed: %w", err)
+			}
+			events = events[n:]
+		}
+	}
+
+	return nil
+}
+
+// cloudWatchMessage encodes record as an event message, truncating its line
+// until the event fits within maxCloudWatchEventBytes.
+func cloudWatchMessage(record Record) (string, error) {
+	limit := maxCloudWatchEventBytes - cloudWatchEventOverhead
+	line := record.Line
+	for {
+		msg, err := json.Marshal(record)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode log record: %w", err)
+		}
+		if len(msg) <= limit {
+			return string(msg), nil
+		}
+		if line == "" {
+			return "", errors.New("log record labels exceed the CloudWatch event size limit")
+		}
+
+		// Escaping may make the line longer once encoded, so cut at least
+		// the excess and retry until it fits.
+		keep := max(len(line)-(len(msg)-limit)-len(truncatedSuffix), 0)
+		for keep > 0 && !utf8.RuneStart(line[keep]) {
+			keep--
+		}
+		line = line[:keep]
+		record.Line = line + truncatedSuffix
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink appends records to a file as JSON lines.
+type FileSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileSink returns a sink appending to path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: path}
+}
+
+func (s *FileSink) Ship(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to write log record: %w", err)
+		}
+	}
+
+	return nil
+}
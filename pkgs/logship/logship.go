@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+// Package logship ships block output and orchestrator logs to external
+// sinks (files, syslog, Loki, CloudWatch) so runs integrate with centralized
+// logging. Every record carries labels identifying the run it belongs to.
+package logship
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stream identifies where a log line came from.
+type Stream string
+
+const (
+	Stdout       Stream = "stdout"
+	Stderr       Stream = "stderr"
+	Orchestrator Stream = "orchestrator"
+)
+
+// Labels attached to records by the workflow manager.
+const (
+	LabelWorkflow    = "workflow"
+	LabelRunID       = "run_id"
+	LabelExecutionID = "execution_id"
+	LabelBlock       = "block"
+	LabelEntry       = "entry"
+)
+
+// Record is a single log line.
+type Record struct {
+	Time   time.Time         `json:"time"`
+	Stream Stream            `json:"stream"`
+	Line   string            `json:"line"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Sink receives batches of records. Records of a batch share their labels
+// and stream and are ordered by time.
+type Sink interface {
+	Ship(ctx context.Context, records []Record) error
+}
+
+// Multi fans records out to several sinks, returning every error.
+type Multi []Sink
+
+func (m Multi) Ship(ctx context.Context, records []Record) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Ship(ctx, records); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatLabels renders labels as sorted key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return "[" + strings.Join(pairs, " ") + "]"
+}
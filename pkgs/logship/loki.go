@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LokiSink pushes records to a Grafana Loki instance. Records are grouped
+// into one Loki stream per label set, with the record stream added as the
+// "stream" label.
+type LokiSink struct {
+	URL      string            // Base URL, e.g. http://localhost:3100
+	TenantID string            // Sent as X-Scope-OrgID when set
+	Username string            // Basic auth, optional
+	Password string            // Basic auth, optional
+	Labels   map[string]string // Static labels added to every stream
+	Client   *http.Client      // Defaults to a client with a 10s timeout
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (s *LokiSink) Ship(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(lokiPush{Streams: s.streams(records)})
+	if err != nil {
+		return fmt.Errorf("failed to encode Loki push: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(s.URL, "/")+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Loki request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.TenantID)
+	}
+	if s.Username != "" {
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Loki push failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Loki push failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+func (s *LokiSink) streams(records []Record) []lokiStream {
+	var streams []lokiStream
+	index := map[string]int{}
+
+	for _, record := range records {
+		labels := maps.Clone(s.Labels)
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		maps.Copy(labels, record.Labels)
+		labels["stream"] = string(record.Stream)
+
+		key := formatLabels(labels)
+		i, ok := index[key]
+		if !ok {
+			i = len(streams)
+			index[key] = i
+			streams = append(streams, lokiStream{Stream: labels})
+		}
+
+		ts := strconv.FormatInt(record.Time.UnixNano(), 10)
+		streams[i].Values = append(streams[i].Values, [2]string{ts, record.Line})
+	}
+
+	return streams
+}
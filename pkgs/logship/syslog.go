@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+//go:build !windows && !plan9
+
+package logship
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink writes records to a syslog daemon. Stderr lines are logged at
+// warning level, everything else at info.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to a syslog daemon. An empty network and raddr use
+// the local daemon; otherwise network is "udp" or "tcp" and raddr its address.
+func NewSyslogSink(network, raddr, tag string) (*SyslogSink, error) {
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+func (s *SyslogSink) Ship(ctx context.Context, records []Record) error {
+	for _, record := range records {
+		msg := formatLabels(record.Labels) + " " + record.Line
+
+		var err error
+		if record.Stream == Stderr {
+			err = s.writer.Warning(msg)
+		} else {
+			err = s.writer.Info(msg)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write to syslog: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+//go:build windows || plan9
+
+package logship
+
+import (
+	"context"
+	"errors"
+)
+
+// SyslogSink is unavailable on this platform.
+type SyslogSink struct{}
+
+// NewSyslogSink always fails: syslog is not supported on this platform.
+func NewSyslogSink(network, raddr, tag string) (*SyslogSink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+func (s *SyslogSink) Ship(ctx context.Context, records []Record) error {
+	return errors.New("syslog is not supported on this platform")
+}
+
+// Close is a no-op.
+func (s *SyslogSink) Close() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/logship"
+)
+
+func TestLokiSinkGroupsStreams(t *testing.T) {
+	t.Parallel()
+
+	type push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+
+	received := make(chan push, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/push" || r.Header.Get("X-Scope-OrgID") != "team-a" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		var p push
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received <- p
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := &logship.LokiSink{URL: server.URL, TenantID: "team-a", Labels: map[string]string{"env": "ci"}}
+	labels := map[string]string{logship.LabelRunID: "r1", logship.LabelBlock: "parser"}
+	now := time.Now()
+
+	err := sink.Ship(t.Context(), []logship.Record{
+		{Time: now, Stream: logship.Stdout, Line: "one", Labels: labels},
+		{Time: now, Stream: logship.Stderr, Line: "oops", Labels: labels},
+		{Time: now, Stream: logship.Stdout, Line: "two", Labels: labels},
+	})
+	if err != nil {
+		t.Fatalf("Ship failed: %s", err)
+	}
+
+	p := <-received
+	if len(p.Streams) != 2 {
+		t.Fatalf("expected 2 streams, got %d", len(p.Streams))
+	}
+
+	stdout := p.Streams[0]
+	if stdout.Stream["stream"] != "stdout" || stdout.Stream["env"] != "ci" || stdout.Stream[logship.LabelRunID] != "r1" {
+		t.Errorf("unexpected stdout labels: %v", stdout.Stream)
+	}
+	if len(stdout.Values) != 2 || stdout.Values[0][1] != "one" || stdout.Values[1][1] != "two" {
+		t.Errorf("unexpected stdout values: %v", stdout.Values)
+	}
+}
+
+// fakeCloudWatch records the batches passed to PutLogEvents.
+type fakeCloudWatch struct {
+	batches [][]logship.CloudWatchEvent
+}
+
+func (c *fakeCloudWatch) PutLogEvents(ctx context.Context, group, stream string, events []logship.CloudWatchEvent) error {
+	c.batches = append(c.batches, events)
+	return nil
+}
+
+func TestCloudWatchSinkLimits(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{logship.LabelRunID: "r1"}
+	now := time.Now()
+	huge := strings.Repeat("x", 300*1024)
+
+	tests := []struct {
+		name        string
+		records     []logship.Record
+		wantBatches int
+	}{
+		{"count", slices.Repeat([]logship.Record{{Time: now, Line: "line", Labels: labels}}, 25000), 3},
+		{"size", slices.Repeat([]logship.Record{{Time: now, Line: strings.Repeat("y", 200*1024), Labels: labels}}, 12), 3},
+		{"oversized events", slices.Repeat([]logship.Record{{Time: now, Line: huge, Labels: labels}}, 5), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &fakeCloudWatch{}
+			sink := &logship.CloudWatchSink{Client: client, Group: "atomos"}
+			if err := sink.Ship(t.Context(), tt.records); err != nil {
+				t.Fatalf("Ship failed: %s", err)
+			}
+			if len(client.batches) != tt.wantBatches {
+				t.Errorf("expected %d batches, got %d", tt.wantBatches, len(client.batches))
+			}
+
+			events := 0
+			for _, batch := range client.batches {
+				size := 0
+				for _, event := range batch {
+					eventSize := len(event.Message) + 26
+					if eventSize > 256*1024 {
+						t.Fatalf("event of %d bytes exceeds 256 KB", eventSize)
+					}
+					size += eventSize
+				}
+				if len(batch) > 10000 || size > 1024*1024 {
+					t.Errorf("batch of %d events and %d bytes exceeds the PutLogEvents limits", len(batch), size)
+				}
+				events += len(batch)
+			}
+			if events != len(tt.records) {
+				t.Errorf("expected %d events, got %d", len(tt.records), events)
+			}
+		})
+	}
+}
+
+func TestCloudWatchSinkTruncatesLine(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeCloudWatch{}
+	sink := &logship.CloudWatchSink{Client: client, Group: "atomos"}
+	line := strings.Repeat("é\"", 100*1024)
+	if err := sink.Ship(t.Context(), []logship.Record{{Time: time.Now(), Line: line, Labels: map[string]string{logship.LabelRunID: "r1"}}}); err != nil {
+		t.Fatalf("Ship failed: %s", err)
+	}
+
+	var record logship.Record
+	if err := json.Unmarshal([]byte(client.batches[0][0].Message), &record); err != nil {
+		t.Fatalf("truncated message is not a JSON record: %s", err)
+	}
+	kept, ok := strings.CutSuffix(record.Line, " [truncated]")
+	if !ok || !strings.HasPrefix(line, kept) || len(kept) == 0 {
+		t.Errorf("expected a truncated prefix of the line, got %d bytes", len(record.Line))
+	}
+	if !utf8.ValidString(record.Line) {
+		t.Error("expected the truncated line to stay valid UTF-8")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNameConflict is returned when a block is installed from a repository
+// other than the one an installed block of the same name came from.
+var ErrNameConflict = errors.New("block name already installed from another repository")
+
+// applyAlias installs the block of blockInfo under req.Alias: the alias
+// replaces the manifest name as the block's identity, so its directory,
+// metadata, and workflow references all use it.
+func applyAlias(req InstallRequest, blockInfo *BlockInfo) error {
+	if req.Alias == "" || req.Alias == blockInfo.Name {
+		return nil
+	}
+	if req.Alias == "." || req.Alias == ".." || strings.ContainsAny(req.Alias, `/\`) || strings.HasPrefix(req.Alias, ".") {
+		return fmt.Errorf("invalid alias %q: aliases are used as directory names", req.Alias)
+	}
+	if req.Alias == shimDirName {
+		return fmt.Errorf("invalid alias %q: reserved for the shim directory", req.Alias)
+	}
+
+	blockInfo.aliasOf = blockInfo.Name
+	blockInfo.Name = req.Alias
+	return nil
+}
+
+// Alias returns the alias the block was installed under, "" for blocks
+// installed under their manifest name. Reinstalls pass it on so the block
+// keeps its identity.
+func (m *BlockMetadata) Alias() string {
+	if m.AliasOf == "" {
+		return ""
+	}
+	return m.Name
+}
+
+// checkNameConflict makes sure installing repo as name doesn't clobber a
+// block of the same name installed from another repository. Forced
+// installs replace it with a warning. Local directories are development
+// checkouts that move around, so they never conflict.
+func (pm *PackageManager) checkNameConflict(req InstallRequest, name, repo string) error {
+	if _, isLocal := parseLocalRepo(req.Repo); isLocal || !pm.isBlockInstalled(name) {
+		return nil
+	}
+	installed, err := pm.getMetadata(name)
+	if err != nil || installed.SourceRepo == "" {
+		return nil
+	}
+	if _, isLocal := parseLocalRepo(installed.SourceRepo); isLocal {
+		return nil
+	}
+
+	for _, known := range []string{installed.SourceRepo, installed.RedirectedFrom} {
+		if strings.EqualFold(known, repo) || strings.EqualFold(known, req.Repo) {
+			return nil
+		}
+	}
+
+	if req.Force {
+		pm.log().Warn("replacing a block installed from another repository", "block", name, "installed", installed.SourceRepo, "requested", req.Repo)
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' comes from %s, not %s; install it under an alias or uninstall the other one", ErrNameConflict, name, installed.SourceRepo, req.Repo)
+}
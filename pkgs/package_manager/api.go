@@ -10,10 +10,13 @@
 package packagemanager
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -37,6 +40,44 @@ func NewPackageManagerWithTestDir(testDir string) *PackageManager {
 		installDir = getDefaultInstallDirPath()
 	}
 
+	pm, _ := newPackageManager(installDir, Options{})
+	return pm
+}
+
+// Options configures NewPackageManagerWithOptions.
+type Options struct {
+	// InstallDir is where blocks are installed. When empty, $ATOMOS_HOME,
+	// an existing ~/.atomos, $XDG_DATA_HOME/atomos, then ~/.atomos are
+	// tried in order.
+	InstallDir string
+	// Logger receives the warnings of loading the existing installation,
+	// and later ones as with SetLogger.
+	Logger *slog.Logger
+	// MetadataStore persists block metadata. When nil, each version is
+	// kept in its own JSON file under the install directory.
+	MetadataStore MetadataStore
+}
+
+// NewPackageManagerWithOptions creates a package manager for the install
+// directory opts selects, creating the directory if needed, and loads the
+// existing installation if present.
+func NewPackageManagerWithOptions(opts Options) (*PackageManager, error) {
+	installDir := opts.InstallDir
+	if installDir == "" {
+		installDir = getDefaultInstallDirPath()
+	}
+
+	installDir, err := filepath.Abs(installDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve install dir: %w", err)
+	}
+
+	return newPackageManager(installDir, opts)
+}
+
+// newPackageManager creates a package manager for installDir, loading the
+// installation when it exists and creating the directory otherwise.
+func newPackageManager(installDir string, opts Options) (*PackageManager, error) {
 	var dirExists bool
 	if _, err := os.Stat(installDir); err == nil {
 		dirExists = true
@@ -44,75 +85,247 @@ func NewPackageManagerWithTestDir(testDir string) *PackageManager {
 
 	pm := &PackageManager{
 		InstallDir:   installDir,
+		preexisting:  dirExists,
 		loadedBlocks: make(map[string]*BlockMetadata),
+		locker:       NewFileLocker(installDir),
+		logger:       opts.Logger,
+		store:        opts.MetadataStore,
 	}
 
 	if dirExists {
 		if err := pm.loadExistingInstallation(); err != nil {
-			fmt.Printf("Warning: Failed to load existing installation: %v\n", err)
+			pm.log().Warn("failed to load existing installation", "dir", installDir, "error", err)
 		}
-		return pm
+		return pm, nil
 	}
 
-	os.MkdirAll(installDir, 0755)
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		return pm, fmt.Errorf("failed to create install dir: %w", err)
+	}
 
-	return pm
+	return pm, nil
+}
+
+// Install downloads a block and returns its metadata. Cancelling ctx aborts
+// any in-flight GitHub request or download.
+func (pm *PackageManager) Install(ctx context.Context, req InstallRequest) (*BlockMetadata, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return pm.install(ctx, req)
+}
+
+// install performs Install while the caller holds the install dir lock.
+func (pm *PackageManager) install(ctx context.Context, req InstallRequest) (*BlockMetadata, error) {
+	ctx = withProgressRepo(ctx, req.Repo)
+	pm.reportPhase(ctx, PhaseResolving)
+	complete := pm.instrumentInstall(req)
+	start := time.Now()
+
+	if err := pm.checkPolicy(req.Repo); err != nil {
+		complete(nil, err)
+		return nil, err
+	}
+
+	metadata, err := pm.installFromSource(ctx, req)
+	complete(metadata, err)
+	pm.recordInstall(ctx, req, start, metadata, err)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.reportPhase(ctx, PhaseDone)
+	return metadata, nil
 }
 
-// Install downloads a block and returns its metadata
-func (pm *PackageManager) Install(req InstallRequest) (*BlockMetadata, error) {
-	blockInfo, err := pm.fetchBlockInfo(req.Repo)
+// installFromSource installs req from the vendor directory, a local
+// directory, GitLab, or GitHub.
+func (pm *PackageManager) installFromSource(ctx context.Context, req InstallRequest) (*BlockMetadata, error) {
+	if pm.vendorDir != "" {
+		return pm.installFromVendor(ctx, req)
+	}
+
+	if dir, ok := parseLocalRepo(req.Repo); ok {
+		return pm.installFromLocal(ctx, req, dir)
+	}
+
+	if source, ok := parseGitLabRepo(req.Repo); ok {
+		ctx, err := pm.checkPublisher(ctx, req, req.Repo)
+		if err != nil {
+			return nil, err
+		}
+		if err := requireUnsigned(ctx, "GitLab releases"); err != nil {
+			return nil, err
+		}
+		return pm.installFromGitLab(ctx, req, source)
+	}
+
+	source := pm.canonicalRepo(ctx, req.Repo)
+	if err := pm.checkPolicy(source); err != nil {
+		return nil, err
+	}
+	ctx, err := pm.checkPublisher(ctx, req, source)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRepoPath(source); err != nil {
+		return nil, err
+	}
+	repo, _ := splitRepoPath(source)
+
+	commit, byCommit := parseCommitVersion(req.Version)
+	switch {
+	case byCommit:
+		if commit, err = pm.resolveCommit(ctx, repo, commit); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(req.Version, commitVersionPrefix):
+		return nil, fmt.Errorf("invalid commit version '%s': expected sha: followed by 7 to 40 hex digits", req.Version)
+	}
+
+	blockInfo, err := pm.fetchBlockInfoAt(ctx, source, commit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch block info: %w", err)
 	}
+	if err := applyAlias(req, blockInfo); err != nil {
+		return nil, err
+	}
+	if err := pm.checkNameConflict(req, blockInfo.Name, source); err != nil {
+		return nil, err
+	}
 
 	if !req.Force {
-		if pm.isBlockInstalled(blockInfo.Name) {
-			metadata, metaErr := pm.getMetadata(blockInfo.Name)
-			if metaErr != nil {
-				return nil, fmt.Errorf("block '%s' is already installed but failed to read metadata: %w", blockInfo.Name, metaErr)
+		if metadata, ok, err := pm.cachedBlock(blockInfo.Name); err != nil || ok {
+			if ok {
+				pm.checkRetagged(ctx, repo, blockInfo, metadata)
 			}
-			log.Printf("%s coming from cache", blockInfo.Name)
-			return metadata, nil
+			return metadata, err
 		}
 	}
 
-	version := req.Version
-	if version == "" {
-		latestRelease, err := pm.getLatestRelease(req.Repo)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get latest release: %w", err)
-		}
-		version = latestRelease.TagName
+	pm.applyRegistryStatus(ctx, blockInfo, repo)
+	version, released := "", true
+	fromTag := !byCommit && req.BuildFromSource && pm.buildsFromTags(ctx, repo, blockInfo)
+	switch {
+	case byCommit:
+		version, released, err = pm.commitVersion(ctx, repo, commit)
+	case fromTag:
+		version, err = pm.resolveTagVersion(ctx, repo, req.Version, blockInfo.YankedVersions)
+	default:
+		version, err = pm.resolveReleaseVersion(ctx, repo, req.Version, blockInfo.YankedVersions, req.AllowPrerelease)
 	}
-
-	binaryPath, err := pm.downloadBinary(req.Repo, version, blockInfo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download binary: %w", err)
+		return nil, err
+	}
+	if err := pm.checkYanked(req, blockInfo, version); err != nil {
+		return nil, err
+	}
+	if err := pm.checkRequirements(ctx, req, blockInfo.Name, blockInfo.Requires); err != nil {
+		return nil, err
 	}
 
-	metadata := &BlockMetadata{
-		Name:        blockInfo.Name,
-		Version:     version,
-		SourceRepo:  req.Repo,
-		BinaryPath:  binaryPath,
-		InstalledAt: time.Now(),
-		LastUpdated: time.Now(),
-		IsActive:    true,
-		LSPEntries:  convertEntriesToMap(blockInfo.Entries),
+	var (
+		binaryPath, digest, inferred string
+		assetErr                     error
+		checkout                     = pm.gitCheckout(pm.githubCloneURL(repo), ServiceGitHub, version)
+	)
+	switch {
+	case fromTag:
+		assetErr = fmt.Errorf("%s publishes no releases", repo)
+		checkout = pm.tarballCheckout(repo, version)
+	case released:
+		inferred, assetErr = pm.resolvePlatformAsset(blockInfo, pm.releaseAssetNames(ctx, repo, version), req.ProbeAssets)
+	default:
+		// Unreleased commits have no assets: asking for one means building it.
+		assetErr = fmt.Errorf("no release was published from commit %s, and building it needs a build command in the manifest", commit)
+		req.BuildFromSource = true
+		checkout = pm.gitCheckout(pm.githubCloneURL(repo), ServiceGitHub, commit)
+	}
+	if assetErr != nil {
+		if err := requireUnsigned(ctx, "binaries built from source"); err != nil {
+			return nil, fmt.Errorf("%w (%v)", err, assetErr)
+		}
+	}
+	pending := pm.startDataAssets(ctx, blockInfo, func(asset, dst string) (string, error) {
+		return pm.downloadAsset(ctx, repo, version, asset, dst, nil)
+	})
+	if assetErr != nil {
+		binaryPath, digest, err = pm.buildFallback(ctx, req, blockInfo, version, assetErr, inRepoDir(checkout, source))
+	} else if binaryPath, digest, err = pm.downloadBinary(ctx, repo, version, blockInfo); err != nil {
+		err = fmt.Errorf("failed to download binary: %w", err)
+	}
+	dataFiles, dataErr := pending.wait()
+	if err := errors.Join(err, dataErr); err != nil {
+		return nil, err
 	}
 
-	if err := pm.storeMetadata(metadata); err != nil {
-		return nil, fmt.Errorf("failed to store metadata: %w", err)
+	metadata := &BlockMetadata{
+		Name:            blockInfo.Name,
+		Description:     blockInfo.Description,
+		Version:         version,
+		SourceRepo:      source,
+		BinaryPath:      binaryPath,
+		SHA256:          digest,
+		InstalledAt:     time.Now(),
+		LastUpdated:     time.Now(),
+		IsActive:        true,
+		LSPEntries:      convertEntriesToMap(blockInfo.Entries),
+		InferredAsset:   inferred,
+		PostInstall:     blockInfo.Hooks.PostInstall,
+		BuiltFromSource: assetErr != nil,
+		Commit:          commit,
+		DataFiles:       dataFiles,
+	}
+	if source != req.Repo {
+		metadata.RedirectedFrom = req.Repo
 	}
+	if assetErr == nil {
+		metadata.Asset = binaryAsset(blockInfo, inferred)
+	}
+	pm.markStatus(metadata, blockInfo)
 
-	pm.loadedBlocks[metadata.Name] = metadata
+	return pm.commitInstall(ctx, metadata)
+}
 
-	return metadata, nil
+// resolveReleaseVersion returns the release tag of repo that version names:
+// the latest release when it's empty, or the highest release satisfying it
+// when it's a constraint. Yanked versions are skipped in both cases, and
+// prereleases unless prerelease is set.
+func (pm *PackageManager) resolveReleaseVersion(ctx context.Context, repo, version string, yanked []string, prerelease bool) (string, error) {
+	switch {
+	case version == "":
+		latestRelease, err := pm.getLatestRelease(ctx, repo, prerelease)
+		if err != nil {
+			return "", fmt.Errorf("failed to get latest release: %w", err)
+		}
+		if !isYanked(yanked, latestRelease.TagName) {
+			return latestRelease.TagName, nil
+		}
+		resolved, err := pm.resolveVersionConstraint(ctx, repo, "*", yanked, prerelease)
+		if err != nil {
+			return "", fmt.Errorf("latest release %s is yanked: %w", latestRelease.TagName, err)
+		}
+		return resolved, nil
+	case IsChannel(version):
+		return pm.resolveChannel(ctx, repo, version, yanked)
+	case IsVersionConstraint(version):
+		resolved, err := pm.resolveVersionConstraint(ctx, repo, version, yanked, prerelease)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve version constraint: %w", err)
+		}
+		return resolved, nil
+	}
+	return version, nil
 }
 
 // GetLoadedBlock returns a specific block by name from the loaded installation
 func (pm *PackageManager) GetLoadedBlock(Blockname string) (*BlockMetadata, bool) {
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
 	if pm.loadedBlocks == nil {
 		return nil, false
 	}
@@ -120,29 +333,103 @@ func (pm *PackageManager) GetLoadedBlock(Blockname string) (*BlockMetadata, bool
 	return block, exists
 }
 
-// Uninstall removes an installed block
-func (pm *PackageManager) Uninstall(Blockname string) error {
+// Uninstall removes the active version of an installed block. When other
+// versions remain installed, the newest of them becomes the active one.
+func (pm *PackageManager) Uninstall(ctx context.Context, Blockname string) error {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return pm.uninstall(Blockname)
+}
+
+// uninstall performs Uninstall while the caller holds the install dir lock.
+func (pm *PackageManager) uninstall(Blockname string) error {
 	metadata, err := pm.getMetadata(Blockname)
 	if err != nil {
-		return fmt.Errorf("block '%s' is not installed: %v", Blockname, err)
+		return errNotInstalled(Blockname, "", err)
+	}
+	return pm.uninstallVersion(Blockname, metadata)
+}
+
+// uninstallVersion removes the binary and metadata of one installed version
+// of a block. When it was the active version and others remain, the newest
+// of them becomes the active one; removing another version leaves the
+// active one alone. The caller holds the install dir lock.
+func (pm *PackageManager) uninstallVersion(Blockname string, metadata *BlockMetadata) (err error) {
+	start := time.Now()
+	defer func() { pm.recordUninstall(metadata, start, err) }()
+
+	if err := pm.checkFence(); err != nil {
+		return err
 	}
 
+	pm.commitMu.Lock()
+	loaded, ok := pm.loadedBlocks[Blockname]
+	pm.commitMu.Unlock()
+	wasActive := !ok || loaded.Version == metadata.Version
+
 	if err := os.Remove(metadata.BinaryPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove binary: %v", err)
 	}
+	pm.log().Debug("removed binary", "block", Blockname, "version", metadata.Version, "path", metadata.BinaryPath)
+
+	if err := pm.metadataStore().Delete(Blockname, metadata.Version); err != nil {
+		return err
+	}
+
+	event := UninstallEvent{Name: Blockname, Version: metadata.Version}
+
+	blockDir := filepath.Join(pm.InstallDir, Blockname)
+	_ = os.Remove(pm.versionBinDir(Blockname, metadata.Version))
+	if wasActive {
+		_ = os.Remove(filepath.Join(blockDir, activeVersionFile))
+	}
 
-	metadataPath := filepath.Join(pm.InstallDir, Blockname, "metadata", fmt.Sprintf("%s.json", metadata.Version))
-	if err := os.Remove(metadataPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove metadata: %v", err)
+	pm.commitMu.Lock()
+	err = pm.forgetVersionLocked(Blockname, wasActive)
+	pm.commitMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Listeners may call back into the package manager, so they are only
+	// notified once commitMu is released.
+	pm.emit(func(l EventListener) { l.OnUninstall(event) })
+	return nil
+}
+
+// forgetVersionLocked updates the loaded blocks after a version of Blockname
+// was removed: the newest remaining version is activated when the removed one
+// was active, and the shim and empty directories go with the last version.
+// The caller holds commitMu.
+func (pm *PackageManager) forgetVersionLocked(Blockname string, wasActive bool) error {
+	blockDir := filepath.Join(pm.InstallDir, Blockname)
+	if pm.isBlockInstalled(Blockname) {
+		if !wasActive {
+			return nil
+		}
+		remaining, err := pm.getMetadata(Blockname)
+		if err != nil {
+			return fmt.Errorf("failed to read remaining versions: %w", err)
+		}
+		delete(pm.loadedBlocks, Blockname)
+		return pm.activateLocked(remaining)
 	}
 
-	// Attempt to remove block directory if empty
-	_ = os.Remove(filepath.Join(pm.InstallDir, Blockname))
+	pm.removeShim(Blockname)
+
+	// Attempt to remove the now empty block directory
+	_ = os.Remove(filepath.Join(blockDir, activationHistoryFile))
+	_ = os.Remove(filepath.Join(blockDir, "bin"))
+	_ = os.Remove(filepath.Join(blockDir, "metadata"))
+	_ = os.Remove(blockDir)
 
 	// Remove from loaded blocks if the package manager is loaded
 	if pm.loadedBlocks != nil {
 		delete(pm.loadedBlocks, Blockname)
 	}
-
 	return nil
 }
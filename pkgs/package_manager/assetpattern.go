@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AnyPlatformKey is the binary.assets key whose asset, usually a pattern,
+// applies to every platform without an entry of its own.
+const AnyPlatformKey = "*"
+
+// isAssetPattern reports whether a binary.assets value is a pattern to
+// match against the release's asset names rather than an asset name: a
+// regexp between slashes, or a glob with *, ?, [...], {os}, or {arch}.
+func isAssetPattern(value string) bool {
+	if isURLAsset(value) {
+		return false
+	}
+	return isRegexpPattern(value) || strings.ContainsAny(value, "*?[") ||
+		strings.Contains(value, "{os}") || strings.Contains(value, "{arch}")
+}
+
+func isRegexpPattern(value string) bool {
+	return len(value) > 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/")
+}
+
+// compileAssetPattern turns an asset pattern into a regexp matching whole
+// asset names of platform, ignoring case. {os} and {arch} match any
+// spelling of the platform's os and arch, e.g. darwin, macos, or osx.
+func compileAssetPattern(pattern, platform string) (*regexp.Regexp, error) {
+	goos, goarch, _ := strings.Cut(platform, "-")
+	placeholders := strings.NewReplacer(
+		"{os}", spellings(osAliases, goos),
+		"{arch}", spellings(archAliases, goarch),
+	)
+
+	if isRegexpPattern(pattern) {
+		return regexp.Compile("(?i)" + placeholders.Replace(pattern[1:len(pattern)-1]))
+	}
+
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*':
+			expr.WriteString(".*")
+		case c == '?':
+			expr.WriteString(".")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in asset pattern '%s'", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if negated, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + negated
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		case strings.HasPrefix(pattern[i:], "{os}"), strings.HasPrefix(pattern[i:], "{arch}"):
+			placeholder := pattern[i : i+strings.IndexByte(pattern[i:], '}')+1]
+			expr.WriteString(placeholders.Replace(placeholder))
+			i += len(placeholder) - 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return regexp.Compile("(?i)^" + expr.String() + "$")
+}
+
+// spellings returns an alternation of the known spellings of key.
+func spellings(aliases map[string][]string, key string) string {
+	names := aliases[key]
+	if len(names) == 0 {
+		names = []string{key}
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return "(?:" + strings.Join(quoted, "|") + ")"
+}
+
+// resolveAssetPattern replaces the pattern blockInfo declares for platform,
+// under its own key or AnyPlatformKey, with the one asset listed by
+// listAssets that it matches. Manifests without a pattern are left as is.
+func resolveAssetPattern(blockInfo *BlockInfo, platform string, listAssets func() ([]string, error)) error {
+	pattern, ok := blockInfo.Binary.Assets[platform]
+	if !ok {
+		if pattern, ok = blockInfo.Binary.Assets[AnyPlatformKey]; !ok {
+			return nil
+		}
+	}
+	if !isAssetPattern(pattern) {
+		blockInfo.Binary.Assets[platform] = pattern
+		return nil
+	}
+
+	re, err := compileAssetPattern(pattern, platform)
+	if err != nil {
+		return fmt.Errorf("invalid asset pattern '%s': %w", pattern, err)
+	}
+
+	names, err := listAssets()
+	if err != nil {
+		return fmt.Errorf("failed to list assets to match '%s': %w", pattern, err)
+	}
+
+	var matches []string
+	for _, name := range names {
+		if name != blockInfo.Binary.ChecksumsAsset && !isNonBinaryAsset(name) && re.MatchString(name) {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("%w %s: no asset matches '%s'", ErrAssetMissingForPlatform, platform, pattern)
+	case 1:
+		blockInfo.Binary.Assets[platform] = matches[0]
+		return nil
+	default:
+		return fmt.Errorf("asset pattern '%s' is ambiguous for platform %s, it matches: %s", pattern, platform, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// AuthReason says why GitHub refused a request.
+type AuthReason string
+
+const (
+	AuthNoToken      AuthReason = "no_token"      // No token was sent
+	AuthInvalidToken AuthReason = "invalid_token" // The token is malformed, expired, or revoked
+	AuthMissingScope AuthReason = "missing_scope" // A classic token lacks a scope the repository needs
+	AuthSSORequired  AuthReason = "sso_required"  // The token isn't authorized for the organization's SAML SSO
+	AuthNoAccess     AuthReason = "no_access"     // The token is valid but not granted the repository
+)
+
+// AuthError is returned when GitHub answers 401 or 403 to a request for a
+// repository. The token is probed to tell what is missing.
+type AuthError struct {
+	Repo   string
+	Status int // HTTP status of the refused request
+	Reason AuthReason
+	// Public is set when the repository can be read without a token.
+	Public bool
+	// Scopes are the scopes of a classic token, nil for fine-grained and
+	// app tokens, which don't report them.
+	Scopes []string
+	// Missing are the scopes the token needs and lacks.
+	Missing []string
+	// SSOURL is where the token can be authorized for SAML SSO.
+	SSOURL string
+	// Message is GitHub's explanation, if it gave one.
+	Message string
+}
+
+// Is makes every AuthError match ErrAuth.
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuth
+}
+
+func (e *AuthError) Error() string {
+	prefix := fmt.Sprintf("authentication failed for repository %s", e.Repo)
+	switch e.Reason {
+	case AuthNoToken:
+		return prefix + ": no GitHub token was sent; set GITHUB_TOKEN or configure a credential provider"
+	case AuthInvalidToken:
+		return prefix + ": the GitHub token is invalid, expired, or revoked"
+	case AuthMissingScope:
+		scopes := "none"
+		if len(e.Scopes) > 0 {
+			scopes = strings.Join(e.Scopes, ", ")
+		}
+		return fmt.Sprintf("%s: the GitHub token lacks the %s scope (it has: %s)", prefix, strings.Join(e.Missing, ", "), scopes)
+	case AuthSSORequired:
+		if e.SSOURL == "" {
+			return prefix + ": the GitHub token isn't authorized for the organization's SAML single sign-on"
+		}
+		return fmt.Sprintf("%s: the GitHub token isn't authorized for the organization's SAML single sign-on; authorize it at %s", prefix, e.SSOURL)
+	case AuthNoAccess:
+		return prefix + ": the GitHub token can't access the repository; grant a fine-grained token the repository, or check that its owner has access"
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("%s: GitHub refused the request (HTTP %d): %s", prefix, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s: GitHub refused the request (HTTP %d)", prefix, e.Status)
+}
+
+// authError diagnoses a 401 or 403 answer to a request for repo, with its
+// body, by probing the token and the repository.
+func (pm *PackageManager) authError(ctx context.Context, repo string, status int, body []byte) error {
+	authErr := &AuthError{Repo: repo, Status: status, Message: githubMessage(body)}
+
+	repoURL := pm.githubAPI("/repos/%s", repo)
+	if anonymous, _, _, err := pm.github().probe(ctx, repoURL, ""); err == nil && anonymous == http.StatusOK {
+		authErr.Public = true
+	}
+
+	token, err := pm.token(ctx, ServiceGitHub, repoURL)
+	switch {
+	case err != nil || token == "":
+		authErr.Reason = AuthNoToken
+		return authErr
+	case status == http.StatusUnauthorized:
+		authErr.Reason = AuthInvalidToken
+		return authErr
+	}
+
+	_, header, probed, err := pm.github().probe(ctx, repoURL, token)
+	if err != nil {
+		pm.log().Debug("failed to probe GitHub token", "repo", repo, "error", err)
+		return authErr
+	}
+	if sso := header.Get("X-GitHub-SSO"); sso != "" || strings.Contains(strings.ToLower(authErr.Message+githubMessage(probed)), "saml") {
+		authErr.Reason = AuthSSORequired
+		if _, url, ok := strings.Cut(sso, "url="); ok {
+			authErr.SSOURL = strings.TrimSpace(url)
+		}
+		return authErr
+	}
+
+	if scopes, classic := header["X-Oauth-Scopes"]; classic {
+		authErr.Scopes = []string{}
+		for _, scope := range strings.Split(strings.Join(scopes, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				authErr.Scopes = append(authErr.Scopes, scope)
+			}
+		}
+		// Public repositories are readable with any token.
+		if !authErr.Public && !slices.Contains(authErr.Scopes, "repo") {
+			authErr.Reason = AuthMissingScope
+			authErr.Missing = []string{"repo"}
+			return authErr
+		}
+	}
+
+	authErr.Reason = AuthNoAccess
+	return authErr
+}
+
+// githubMessage extracts the message of a GitHub error response.
+func githubMessage(body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+	return response.Message
+}
+
+// probe issues a single GET with token, or without one when it is "",
+// bypassing the cache and retries, and returns the status, headers, and
+// body of the response.
+func (c *githubClient) probe(ctx context.Context, url, token string) (int, http.Header, []byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp.StatusCode, resp.Header, body, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// buildTimeout bounds the clone and the build command of a block built
+// from source.
+const buildTimeout = 30 * time.Minute
+
+// buildEnvStripped lists variables removed from the environment of build
+// commands, so credentials never reach the block's build scripts.
+var buildEnvStripped = []string{"GITHUB_TOKEN", "GH_TOKEN", "GITLAB_TOKEN"}
+
+// sourceCheckout provides the source tree of a block at the version being
+// installed. cleanup removes it, if it was created for the build.
+type sourceCheckout func(ctx context.Context) (dir string, cleanup func(), err error)
+
+// buildFallback builds the binary from source when the platform has no
+// release asset, the request opts in with BuildFromSource, and the manifest
+// declares a build command. Otherwise it returns assetErr, the reason no
+// asset could be used.
+func (pm *PackageManager) buildFallback(ctx context.Context, req InstallRequest, blockInfo *BlockInfo, version string, assetErr error, checkout sourceCheckout) (string, string, error) {
+	if !req.BuildFromSource || blockInfo.Build.Command == "" {
+		return "", "", assetErr
+	}
+
+	pm.log().Warn("no release asset for this platform, building from source", "block", blockInfo.Name, "version", version, "reason", assetErr)
+	binaryPath, digest, err := pm.buildFromSource(ctx, blockInfo, version, checkout)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build from source: %w", err)
+	}
+	return binaryPath, digest, nil
+}
+
+// buildFromSource runs the manifest's build command in a checkout of the
+// block and copies the binary it produces into the version bin directory.
+// Output of the clone and the build is appended to <block>/install.log.
+func (pm *PackageManager) buildFromSource(ctx context.Context, blockInfo *BlockInfo, version string, checkout sourceCheckout) (string, string, error) {
+	output := blockInfo.Build.Output
+	if output == "" || filepath.IsAbs(output) || !filepath.IsLocal(filepath.FromSlash(output)) {
+		return "", "", fmt.Errorf("build.output must be a path inside the repository, got %q", output)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, buildTimeout)
+	defer cancel()
+
+	dir, cleanup, err := checkout(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	blockDir := filepath.Join(pm.InstallDir, blockInfo.Name)
+	if err := os.MkdirAll(blockDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create block directory: %w", err)
+	}
+	logPath := filepath.Join(blockDir, installLogFile)
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open install log: %w", err)
+	}
+	defer log.Close()
+
+	fmt.Fprintf(log, "=== %s build %s %s: %s\n", time.Now().Format(time.RFC3339), blockInfo.Name, version, blockInfo.Build.Command)
+	cmd := hookCommand(ctx, blockInfo.Build.Command)
+	cmd.Dir = dir
+	cmd.Env = append(buildEnviron(), EnvHookBlock+"="+blockInfo.Name, EnvHookVersion+"="+version)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(log, "=== failed: %v\n", err)
+		return "", "", fmt.Errorf("build command %q failed: %w (see %s)", blockInfo.Build.Command, err, logPath)
+	}
+
+	built := filepath.Join(dir, filepath.FromSlash(output))
+	binDir := pm.versionBinDir(blockInfo.Name, version)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create bin directory: %w", err)
+	}
+	binaryPath := filepath.Join(binDir, executableName(filepath.Base(built), hostPlatform()))
+	if err := copyFile(built, binaryPath, 0755); err != nil {
+		return "", "", fmt.Errorf("build did not produce %s: %w", output, err)
+	}
+
+	digest, err := hashFile(binaryPath)
+	if err != nil {
+		return "", "", err
+	}
+	pm.log().Debug("built binary from source", "block", blockInfo.Name, "version", version, "path", binaryPath, "sha256", digest)
+	return binaryPath, digest, nil
+}
+
+// buildEnviron is the environment of build commands: the package manager's
+// own, without credentials.
+func buildEnviron() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(strings.ToUpper(name), "ATOMOS_TOKEN_") || containsFold(buildEnvStripped, name) {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// localCheckout builds a local block in place, in its own directory.
+func localCheckout(dir string) sourceCheckout {
+	return func(ctx context.Context) (string, func(), error) {
+		return dir, func() {}, nil
+	}
+}
+
+// gitCheckout shallow-clones cloneURL at tag into a temporary directory.
+// The token for service is passed to git through its environment, never on
+// the command line.
+func (pm *PackageManager) gitCheckout(cloneURL, service, tag string) sourceCheckout {
+	return func(ctx context.Context) (string, func(), error) {
+		if _, err := exec.LookPath("git"); err != nil {
+			return "", nil, errors.New("git is required to build from source")
+		}
+
+		dir, err := os.MkdirTemp("", "atomos-build-*")
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create build directory: %w", err)
+		}
+		cleanup := func() { _ = os.RemoveAll(dir) }
+
+		env := append(buildEnviron(), "GIT_TERMINAL_PROMPT=0")
+		token, err := pm.token(ctx, service, cloneURL)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		if token != "" {
+			user := "x-access-token"
+			if service == ServiceGitLab {
+				user = "oauth2"
+			}
+			basic := base64.StdEncoding.EncodeToString([]byte(user + ":" + token))
+			env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic "+basic)
+		}
+
+		// Commits can't be cloned by name: fetch the single commit instead.
+		steps := [][]string{{"clone", "--quiet", "--depth", "1", "--branch", tag, "--", cloneURL, dir}}
+		if isFullCommitSHA(tag) {
+			steps = [][]string{
+				{"init", "--quiet", dir},
+				{"-C", dir, "fetch", "--quiet", "--depth", "1", "--", cloneURL, tag},
+				{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+			}
+		}
+		for _, args := range steps {
+			cmd := exec.CommandContext(ctx, "git", args...)
+			cmd.Env = env
+			if out, err := cmd.CombinedOutput(); err != nil {
+				cleanup()
+				return "", nil, fmt.Errorf("failed to clone %s at %s: %w: %s", cloneURL, tag, err, strings.TrimSpace(string(out)))
+			}
+		}
+		return dir, cleanup, nil
+	}
+}
+
+// githubCloneURL derives the git URL of repo from the GitHub API in use:
+// github.com for api.github.com, and the host of a GitHub Enterprise API
+// otherwise.
+func (pm *PackageManager) githubCloneURL(repo string) string {
+	u, err := url.Parse(pm.githubAPI(""))
+	if err != nil {
+		return "https://github.com/" + repo + ".git"
+	}
+	if u.Host == "api.github.com" {
+		u.Host = "github.com"
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3") + "/" + repo + ".git"
+	return u.String()
+}
+
+// cloneURL is the git URL of a GitLab project.
+func (src gitLabSource) cloneURL() string {
+	return src.baseURL + "/" + src.project + ".git"
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Entries of a bundle written by ExportBundle: the catalog of the exported
+// versions, then the binary of each version at blocks/<name>/<version>.
+const (
+	bundleCatalogFile = "catalog.json"
+	bundleBlocksDir   = "blocks/"
+)
+
+// ExportBundle writes every installed block version, binaries and metadata,
+// into a single gzipped tarball at path. ImportBundle restores it on
+// another machine or CI runner without downloading anything.
+func (pm *PackageManager) ExportBundle(path string) error {
+	catalog, err := pm.catalog()
+	if err != nil {
+		return fmt.Errorf("failed to list installed blocks: %w", err)
+	}
+
+	// Write next to the target and rename so a failed export never leaves a
+	// truncated bundle behind.
+	file, err := os.CreateTemp(filepath.Dir(path), ".bundle-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := writeBundle(file, catalog); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set bundle permissions: %w", err)
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func writeBundle(w io.Writer, catalog *Catalog) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: bundleCatalogFile, Mode: 0644, Size: int64(len(data))}); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	for _, entry := range catalog.Blocks {
+		if err := writeBundleBinary(tw, &entry.Metadata); err != nil {
+			return fmt.Errorf("failed to bundle %s@%s: %w", entry.Metadata.Name, entry.Metadata.Version, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func writeBundleBinary(tw *tar.Writer, metadata *BlockMetadata) error {
+	binary, err := os.Open(metadata.BinaryPath)
+	if err != nil {
+		return err
+	}
+	defer binary.Close()
+
+	info, err := binary.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    bundleBlocksDir + metadata.Name + "/" + metadata.Version,
+		Mode:    0755,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, binary)
+	return err
+}
+
+// ImportBundle installs the block versions of a bundle written by
+// ExportBundle. Like SyncFrom, versions already installed with the same
+// checksum are skipped, every binary is checked against the SHA-256
+// recorded in the bundle, and versions that were active on the exporting
+// machine become active here.
+func (pm *PackageManager) ImportBundle(ctx context.Context, path string) (*SyncResult, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	// Binaries are extracted first since the catalog may list them in any
+	// order.
+	staging, err := os.MkdirTemp("", "atomos-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	catalog, err := extractBundle(file, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	fetch := func(remote BlockMetadata) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(staging, remote.Name, remote.Version))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("binary missing from bundle")
+		}
+		return data, err
+	}
+	return pm.applyCatalog(ctx, catalog, nil, fetch)
+}
+
+// extractBundle writes the binaries of a bundle into dir/<name>/<version>
+// and returns its catalog.
+func extractBundle(r io.Reader, dir string) (*Catalog, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	var catalog *Catalog
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch name := path.Clean(header.Name); {
+		case name == bundleCatalogFile:
+			catalog = &Catalog{}
+			if err := json.NewDecoder(tr).Decode(catalog); err != nil {
+				return nil, fmt.Errorf("failed to parse bundle catalog: %w", err)
+			}
+
+		case header.Typeflag == tar.TypeReg && strings.HasPrefix(name, bundleBlocksDir):
+			block, version, ok := strings.Cut(strings.TrimPrefix(name, bundleBlocksDir), "/")
+			if !ok {
+				return nil, fmt.Errorf("unexpected bundle entry %s", header.Name)
+			}
+			if err := validateBlockName(block, version); err != nil {
+				return nil, err
+			}
+			if err := extractBundleFile(tr, filepath.Join(dir, block, version)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if catalog == nil {
+		return nil, fmt.Errorf("invalid bundle: %s is missing", bundleCatalogFile)
+	}
+	return catalog, nil
+}
+
+func extractBundleFile(r io.Reader, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	return out.Close()
+}
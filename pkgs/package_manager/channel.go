@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Release channels accepted in place of a version. They follow the order
+// the source lists releases in, newest first, like GitHub's latest release.
+const (
+	ChannelStable  = "stable"  // Newest release that isn't a prerelease
+	ChannelBeta    = "beta"    // Newest prerelease
+	ChannelNightly = "nightly" // Newest release of any kind
+)
+
+// IsChannel reports whether version names a release channel.
+func IsChannel(version string) bool {
+	switch version {
+	case ChannelStable, ChannelBeta, ChannelNightly:
+		return true
+	}
+	return false
+}
+
+// isExactVersion reports whether version is a tag rather than empty, a
+// range, or a channel.
+func isExactVersion(version string) bool {
+	return version != "" && !IsVersionConstraint(version) && !IsChannel(version)
+}
+
+// channelRelease is what channels select releases on.
+type channelRelease struct {
+	tag        string
+	prerelease bool
+}
+
+// selectChannel returns the tag of the first of releases, newest first,
+// that is on channel and isn't yanked.
+func selectChannel(releases []channelRelease, channel string, yanked []string) (string, error) {
+	for _, release := range releases {
+		if isYanked(yanked, release.tag) {
+			continue
+		}
+		switch {
+		case channel == ChannelNightly,
+			channel == ChannelStable && !release.prerelease,
+			channel == ChannelBeta && release.prerelease:
+			return release.tag, nil
+		}
+	}
+	return "", fmt.Errorf("no release on the %s channel", channel)
+}
+
+// isPrereleaseTag reports whether tag is a semver prerelease, for sources
+// that don't flag prereleases.
+func isPrereleaseTag(tag string) bool {
+	v, err := parseSemver(tag)
+	return err == nil && v.prerelease != ""
+}
+
+// resolveChannel returns the newest GitHub release of repo on channel,
+// going by the releases' prerelease flag.
+func (pm *PackageManager) resolveChannel(ctx context.Context, repo, channel string, yanked []string) (string, error) {
+	releases, err := pm.listReleases(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []channelRelease
+	for _, release := range releases {
+		if !release.Draft {
+			candidates = append(candidates, channelRelease{tag: release.TagName, prerelease: release.Prerelease})
+		}
+	}
+
+	tag, err := selectChannel(candidates, channel, yanked)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", repo, err)
+	}
+	return tag, nil
+}
+
+// selectVendoredChannel returns the highest vendored version on channel,
+// telling prereleases by their semver tag.
+func selectVendoredChannel(candidates []BlockMetadata, channel string) (BlockMetadata, error) {
+	sorted := slices.SortedFunc(slices.Values(candidates), func(a, b BlockMetadata) int {
+		return compareVersions(b.Version, a.Version)
+	})
+
+	releases := make([]channelRelease, len(sorted))
+	for i, c := range sorted {
+		releases[i] = channelRelease{tag: c.Version, prerelease: isPrereleaseTag(c.Version)}
+	}
+
+	tag, err := selectChannel(releases, channel, nil)
+	if err != nil {
+		return BlockMetadata{}, err
+	}
+	return sorted[slices.IndexFunc(sorted, func(c BlockMetadata) bool { return c.Version == tag })], nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// fetchNamedAsset downloads a release asset by name, used to read the
+// checksums file published alongside a binary.
+type fetchNamedAsset func(name string) ([]byte, error)
+
+// listAssetNames returns the names of the assets published alongside a
+// binary, used to find a conventional checksums file, or nil when they
+// can't be listed.
+type listAssetNames func() []string
+
+// conventionalChecksumsAssets are the checksums files looked for, in order
+// and ignoring case, when the manifest declares no checksum.
+var conventionalChecksumsAssets = []string{"checksums.txt", "SHA256SUMS", "SHA256SUMS.txt"}
+
+// conventionalChecksumsSuffix matches the "<project>_<version>_checksums.txt"
+// files published by GoReleaser.
+const conventionalChecksumsSuffix = "_checksums.txt"
+
+// verifyChecksum compares the digest of a downloaded GitHub asset with the one
+// declared by the block. Blocks that declare no checksum are accepted as-is.
+func (pm *PackageManager) verifyChecksum(ctx context.Context, repo, version string, blockInfo *BlockInfo, platform, assetName, digest string) error {
+	fetch := func(name string) ([]byte, error) {
+		release, err := pm.getReleaseByTag(ctx, repo, version)
+		if err != nil {
+			return nil, err
+		}
+
+		asset, err := pm.findAsset(release, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := pm.fetchAsset(ctx, repo, asset, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	list := func() []string {
+		release, err := pm.getReleaseByTag(ctx, repo, version)
+		if err != nil {
+			return nil
+		}
+		names := make([]string, len(release.Assets))
+		for i, asset := range release.Assets {
+			names[i] = asset.Name
+		}
+		return names
+	}
+
+	return checkDigest(blockInfo, platform, assetName, digest, pm.withURLAssets(ctx, fetch), list)
+}
+
+// checkDigest compares digest with the checksum the block declares for
+// assetName, the binary of platform, if any.
+func checkDigest(blockInfo *BlockInfo, platform, assetName, digest string, fetch fetchNamedAsset, list listAssetNames) error {
+	expected, err := expectedChecksum(blockInfo, platform, assetName, fetch, list)
+	if err != nil {
+		return fmt.Errorf("failed to resolve checksum for '%s': %w", assetName, err)
+	}
+	if expected == "" {
+		return nil
+	}
+
+	if !strings.EqualFold(expected, digest) {
+		return fmt.Errorf("%w for '%s': expected sha256 %s, got %s", ErrChecksumMismatch, assetName, expected, digest)
+	}
+
+	return nil
+}
+
+// expectedChecksum looks the asset up in the manifest's checksums section,
+// then in the checksums release asset the manifest points to. Without
+// either, a conventional checksums file published alongside the binary is
+// used when it lists the asset.
+func expectedChecksum(blockInfo *BlockInfo, platform, assetName string, fetch fetchNamedAsset, list listAssetNames) (string, error) {
+	for _, key := range []string{assetName, platform} {
+		if sum, ok := blockInfo.Checksums[key]; ok {
+			return normalizeDigest(sum), nil
+		}
+	}
+
+	if blockInfo.Binary.ChecksumsAsset == "" {
+		return conventionalChecksum(assetName, fetch, list)
+	}
+
+	data, err := fetch(blockInfo.Binary.ChecksumsAsset)
+	if err != nil {
+		return "", err
+	}
+
+	sum, ok := parseChecksumsFile(data)[assetName]
+	if !ok {
+		return "", fmt.Errorf("'%s' has no entry for '%s'", blockInfo.Binary.ChecksumsAsset, assetName)
+	}
+
+	return sum, nil
+}
+
+// conventionalChecksum looks assetName up in the first conventional
+// checksums file among the listed assets. Files that don't list the asset,
+// e.g. those of another block of a monorepo, are ignored.
+func conventionalChecksum(assetName string, fetch fetchNamedAsset, list listAssetNames) (string, error) {
+	if list == nil {
+		return "", nil
+	}
+	name := findConventionalChecksumsAsset(list())
+	if name == "" {
+		return "", nil
+	}
+
+	data, err := fetch(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s': %w", name, err)
+	}
+	return parseChecksumsFile(data)[assetName], nil
+}
+
+// findConventionalChecksumsAsset returns the conventional checksums file
+// among names, or "".
+func findConventionalChecksumsAsset(names []string) string {
+	for _, conventional := range conventionalChecksumsAssets {
+		for _, name := range names {
+			if strings.EqualFold(name, conventional) {
+				return name
+			}
+		}
+	}
+	for _, name := range names {
+		if strings.HasSuffix(strings.ToLower(name), conventionalChecksumsSuffix) {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseChecksumsFile parses sha256sum output: "<digest>  <name>" per line,
+// with an optional '*' marking binary mode before the name.
+func parseChecksumsFile(data []byte) map[string]string {
+	sums := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		name := strings.TrimPrefix(fields[len(fields)-1], "*")
+		sums[name] = normalizeDigest(fields[0])
+	}
+
+	return sums
+}
+
+// normalizeDigest strips an optional "sha256:" prefix and lowercases.
+func normalizeDigest(sum string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(sum), "sha256:"))
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// commitVersionPrefix marks an InstallRequest.Version naming a commit,
+// e.g. "sha:abcdef1", rather than a release.
+const commitVersionPrefix = "sha:"
+
+// commitVersionLength is how many hex digits of the commit the version of
+// a block built from an unreleased commit keeps, e.g. "sha-abcdef123456".
+const commitVersionLength = 12
+
+// parseCommitVersion returns the (possibly abbreviated) commit SHA version
+// names, if it has the "sha:" prefix and 7 to 40 hex digits.
+func parseCommitVersion(version string) (string, bool) {
+	sha, ok := strings.CutPrefix(version, commitVersionPrefix)
+	if !ok || len(sha) < 7 || len(sha) > 40 || !isHex(sha) {
+		return "", false
+	}
+	return strings.ToLower(sha), true
+}
+
+// isFullCommitSHA reports whether ref is a complete 40 digit commit SHA.
+func isFullCommitSHA(ref string) bool {
+	return len(ref) == 40 && isHex(ref)
+}
+
+func isHex(s string) bool {
+	return strings.Trim(strings.ToLower(s), "0123456789abcdef") == ""
+}
+
+// resolveCommit expands a commit SHA of repo, possibly abbreviated, to its
+// full 40 digit form.
+func (pm *PackageManager) resolveCommit(ctx context.Context, repo, sha string) (string, error) {
+	status, body, err := pm.github().get(ctx, pm.githubAPI("/repos/%s/commits/%s", repo, sha))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %s: %w", sha, err)
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		return "", fmt.Errorf("commit %s not found in repository %s", sha, repo)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", pm.authError(ctx, repo, status, body)
+	default:
+		return "", fmt.Errorf("GitHub API error %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal(body, &commit); err != nil || !isFullCommitSHA(commit.SHA) {
+		return "", fmt.Errorf("failed to decode commit %s of %s", sha, repo)
+	}
+	return strings.ToLower(commit.SHA), nil
+}
+
+// commitVersion returns the tag of a published release of repo pointing at
+// commit, whose assets can be installed as is. Unreleased commits, which
+// have to be built, get a version such as "sha-abcdef123456" instead, and
+// released reports false.
+func (pm *PackageManager) commitVersion(ctx context.Context, repo, commit string) (version string, released bool, err error) {
+	tags, err := pm.listTags(ctx, repo)
+	if err != nil {
+		return "", false, err
+	}
+
+	tagged := map[string]bool{}
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Commit.SHA, commit) {
+			tagged[tag.Name] = true
+		}
+	}
+	if len(tagged) > 0 {
+		releases, err := pm.listReleases(ctx, repo)
+		if err != nil {
+			return "", false, err
+		}
+		for _, release := range releases {
+			if !release.Draft && tagged[release.TagName] {
+				return release.TagName, true, nil
+			}
+		}
+	}
+
+	return "sha-" + commit[:commitVersionLength], false, nil
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Services a credential can be requested for.
+const (
+	ServiceGitHub = "github"
+	ServiceGitLab = "gitlab"
+)
+
+// credentialCommandTimeout bounds the helper commands (gh, security,
+// secret-tool) run to look up a token.
+const credentialCommandTimeout = 10 * time.Second
+
+// CredentialRequest identifies the host a request is about to be sent to.
+type CredentialRequest struct {
+	Host    string // e.g. github.com, github.example.com, gitlab.com
+	Service string // ServiceGitHub or ServiceGitLab
+}
+
+// CredentialProvider returns the token authenticating requests to a host.
+// It returns "" with a nil error when it has no token for the host, so
+// providers can be chained.
+type CredentialProvider interface {
+	Token(ctx context.Context, req CredentialRequest) (string, error)
+}
+
+// CredentialFunc adapts a function to CredentialProvider.
+type CredentialFunc func(ctx context.Context, req CredentialRequest) (string, error)
+
+func (f CredentialFunc) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// ChainCredentials asks each provider in turn and returns the first token.
+type ChainCredentials []CredentialProvider
+
+func (c ChainCredentials) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	for _, provider := range c {
+		token, err := provider.Token(ctx, req)
+		if err != nil || token != "" {
+			return token, err
+		}
+	}
+	return "", nil
+}
+
+// EnvCredentials reads tokens from environment variables:
+// ATOMOS_TOKEN_<HOST> first (the host upper-cased, every character other
+// than letters and digits replaced by "_", e.g. ATOMOS_TOKEN_GITHUB_EXAMPLE_COM),
+// then GITHUB_TOKEN or GH_TOKEN for GitHub hosts and GITLAB_TOKEN for GitLab
+// hosts.
+type EnvCredentials struct{}
+
+func (EnvCredentials) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	if token := os.Getenv(hostTokenVar(req.Host)); token != "" {
+		return token, nil
+	}
+
+	switch req.Service {
+	case ServiceGitHub:
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			return token, nil
+		}
+		return os.Getenv("GH_TOKEN"), nil
+	case ServiceGitLab:
+		return os.Getenv("GITLAB_TOKEN"), nil
+	}
+	return "", nil
+}
+
+func hostTokenVar(host string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, host)
+	return "ATOMOS_TOKEN_" + name
+}
+
+// FileCredentials reads tokens from a YAML file mapping hosts to tokens:
+//
+//	hosts:
+//	  github.com: ghp_...
+//	  github.example.com: ghp_...
+//	  gitlab.com: glpat-...
+//
+// A missing file provides no tokens.
+type FileCredentials struct {
+	Path string
+}
+
+type credentialsFile struct {
+	Hosts map[string]string `yaml:"hosts"`
+}
+
+// DefaultCredentialsFile is the file read by the default credential chain,
+// atomos/credentials.yaml in the user's configuration directory.
+func DefaultCredentialsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = filepath.Join(userHomeDir(), ".config")
+	}
+	return filepath.Join(dir, "atomos", "credentials.yaml")
+}
+
+func (f FileCredentials) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file credentialsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("failed to parse credentials file %s: %w", f.Path, err)
+	}
+	for host, token := range file.Hosts {
+		if strings.EqualFold(host, req.Host) {
+			return token, nil
+		}
+	}
+	return "", nil
+}
+
+// GHCLICredentials asks the GitHub CLI for its token (`gh auth token
+// --hostname <host>`) for GitHub hosts. Hosts gh isn't logged in to, and
+// machines without gh, provide no token. Answers are cached per host.
+type GHCLICredentials struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (g *GHCLICredentials) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	if req.Service != ServiceGitHub {
+		return "", nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if token, ok := g.tokens[req.Host]; ok {
+		return token, nil
+	}
+	if g.tokens == nil {
+		g.tokens = map[string]string{}
+	}
+
+	token := runCredentialCommand(ctx, "gh", "auth", "token", "--hostname", req.Host)
+	g.tokens[req.Host] = token
+	return token, nil
+}
+
+// KeychainCredentials reads tokens stored in the OS keychain under Service
+// ("atomos" when empty), with the host as account: the macOS keychain
+// through `security`, and the Secret Service on Linux through
+// `secret-tool` (attributes service and host). Other platforms provide no
+// tokens.
+type KeychainCredentials struct {
+	Service string
+}
+
+func (k KeychainCredentials) Token(ctx context.Context, req CredentialRequest) (string, error) {
+	service := k.Service
+	if service == "" {
+		service = "atomos"
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return runCredentialCommand(ctx, "security", "find-generic-password", "-s", service, "-a", req.Host, "-w"), nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return runCredentialCommand(ctx, "secret-tool", "lookup", "service", service, "host", req.Host), nil
+	}
+	return "", nil
+}
+
+// runCredentialCommand returns the trimmed output of a credential helper,
+// or "" when it is not installed or fails, e.g. because it has no token.
+func runCredentialCommand(ctx context.Context, name string, args ...string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// DefaultCredentials is the chain used unless SetCredentialProvider is
+// called: environment variables, then DefaultCredentialsFile, then the
+// GitHub CLI.
+func DefaultCredentials() CredentialProvider {
+	return ChainCredentials{EnvCredentials{}, FileCredentials{Path: DefaultCredentialsFile()}, &GHCLICredentials{}}
+}
+
+// SetCredentialProvider sets where tokens for GitHub and GitLab requests
+// come from. Passing nil restores DefaultCredentials.
+func (pm *PackageManager) SetCredentialProvider(provider CredentialProvider) {
+	pm.credMu.Lock()
+	defer pm.credMu.Unlock()
+
+	pm.credentials = provider
+}
+
+// token returns the token for requests of service to rawURL, "" when none
+// is configured.
+func (pm *PackageManager) token(ctx context.Context, service, rawURL string) (string, error) {
+	pm.credMu.Lock()
+	if pm.credentials == nil {
+		pm.credentials = DefaultCredentials()
+	}
+	provider := pm.credentials
+	pm.credMu.Unlock()
+
+	host := credentialHost(rawURL)
+	token, err := provider.Token(ctx, CredentialRequest{Host: host, Service: service})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve credentials for %s: %w", host, err)
+	}
+	return token, nil
+}
+
+// credentialHost is the host credentials are looked up for: the host of
+// rawURL, with GitHub's API and raw content hosts folded into github.com.
+func credentialHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "api.github.com" || host == "raw.githubusercontent.com" {
+		return "github.com"
+	}
+	return host
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DataAsset is a file a block needs next to its binary, such as a model,
+// a configuration template, or a wordlist, declared under data: in the
+// manifest and installed into <block>/data/.
+type DataAsset struct {
+	Name string `yaml:"name"` // Key of the file in BlockMetadata.DataFiles
+	// Asset is the release asset, HTTPS URL, or, for local blocks, the
+	// path relative to the block directory.
+	Asset string `yaml:"asset"`
+	// Path is where the file goes under <block>/data/; the asset's file
+	// name by default.
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"` // Digest the file must match, if given; a "sha256:" prefix is allowed
+}
+
+// dataFetcher downloads the release asset or local file asset to dst and
+// returns its hex-encoded SHA256 digest.
+type dataFetcher func(asset, dst string) (string, error)
+
+// dataPath returns where asset goes, relative to the data directory.
+func (asset DataAsset) dataPath() (string, error) {
+	if asset.Path != "" {
+		return filepath.FromSlash(asset.Path), nil
+	}
+	if isURLAsset(asset.Asset) {
+		return urlAssetName(asset.Asset)
+	}
+	return filepath.Base(filepath.FromSlash(asset.Asset)), nil
+}
+
+// DefaultAssetWorkers is how many assets of a block, its binary included,
+// are downloaded at once unless SetAssetWorkers says otherwise.
+const DefaultAssetWorkers = 4
+
+// SetAssetWorkers sets how many assets of a single block, its binary
+// included, are downloaded at once; DefaultAssetWorkers when n <= 0.
+func (pm *PackageManager) SetAssetWorkers(n int) {
+	pm.assetWorkers = n
+}
+
+// dataDownloads are the data assets of a block being downloaded in the
+// background while its binary is installed.
+type dataDownloads struct {
+	wg    sync.WaitGroup
+	files map[string]string
+	errs  []error // Error of each asset, in manifest order
+}
+
+// wait waits for the downloads and returns the paths of the data files by
+// name, or every failure joined.
+func (d *dataDownloads) wait() (map[string]string, error) {
+	d.wg.Wait()
+	if err := errors.Join(d.errs...); err != nil {
+		return nil, err
+	}
+	return d.files, nil
+}
+
+// startDataAssets starts downloading the data assets of a block into its
+// data directory with fetch, or from their URL, on a pool of workers that
+// leaves one of the block's asset workers to the binary. Data downloads
+// don't report progress, which tracks the binary.
+func (pm *PackageManager) startDataAssets(ctx context.Context, blockInfo *BlockInfo, fetch dataFetcher) *dataDownloads {
+	d := &dataDownloads{errs: make([]error, len(blockInfo.Data))}
+	if len(blockInfo.Data) == 0 {
+		return d
+	}
+
+	rels := make([]string, len(blockInfo.Data))
+	owners := map[string]string{}
+	for i, asset := range blockInfo.Data {
+		rel, err := asset.dataPath()
+		if err != nil {
+			d.errs[i] = fmt.Errorf("data asset '%s': %w", asset.Name, err)
+			continue
+		}
+		if !filepath.IsLocal(rel) {
+			d.errs[i] = fmt.Errorf("data asset '%s': path %q must stay inside the data directory", asset.Name, asset.Path)
+			continue
+		}
+		if owner, ok := owners[rel]; ok {
+			d.errs[i] = fmt.Errorf("data assets '%s' and '%s' both install to %s", owner, asset.Name, rel)
+			continue
+		}
+		owners[rel] = asset.Name
+		rels[i] = rel
+	}
+	if errors.Join(d.errs...) != nil {
+		return d
+	}
+
+	workers := pm.assetWorkers
+	if workers <= 0 {
+		workers = DefaultAssetWorkers
+	}
+	workers = max(min(workers-1, len(blockInfo.Data)), 1)
+
+	ctx = context.WithValue(ctx, quietDownloadKey{}, true)
+	dataDir := filepath.Join(pm.InstallDir, blockInfo.Name, blockDataDir)
+	jobs := make(chan int, len(blockInfo.Data))
+	for i := range blockInfo.Data {
+		jobs <- i
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	d.files = make(map[string]string, len(blockInfo.Data))
+	for range workers {
+		d.wg.Go(func() {
+			for i := range jobs {
+				dst := filepath.Join(dataDir, rels[i])
+				if d.errs[i] = pm.installDataAsset(ctx, blockInfo, blockInfo.Data[i], dst, fetch); d.errs[i] == nil {
+					mu.Lock()
+					d.files[blockInfo.Data[i].Name] = dst
+					mu.Unlock()
+				}
+			}
+		})
+	}
+	return d
+}
+
+// installDataAsset downloads one data asset to dst and verifies it against
+// the digest the manifest declares. The file is written next to dst and
+// renamed into place, so a failed download leaves the previous one
+// untouched.
+func (pm *PackageManager) installDataAsset(ctx context.Context, blockInfo *BlockInfo, asset DataAsset, dst string, fetch dataFetcher) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	tmp := dst + ".download"
+	var (
+		digest string
+		err    error
+	)
+	if isURLAsset(asset.Asset) {
+		digest, err = pm.downloadURLAsset(ctx, asset.Asset, tmp, nil)
+	} else {
+		digest, err = fetch(asset.Asset, tmp)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to download data asset '%s': %w", asset.Name, err)
+	}
+
+	expected := asset.SHA256
+	if expected == "" {
+		expected = blockInfo.Checksums[asset.Asset]
+	}
+	if expected = normalizeDigest(expected); expected != "" && expected != digest {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("%w for data asset '%s': expected %s, got %s", ErrChecksumMismatch, asset.Name, expected, digest)
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to move data asset '%s' into place: %w", asset.Name, err)
+	}
+	pm.log().Debug("installed data asset", "block", blockInfo.Name, "name", asset.Name, "path", dst, "sha256", digest)
+	return nil
+}
+
+// localDataFetcher copies data assets from a block directory.
+func localDataFetcher(dir string) dataFetcher {
+	return func(asset, dst string) (string, error) {
+		src := filepath.FromSlash(asset)
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(dir, src)
+		}
+		if err := copyFile(src, dst, 0644); err != nil {
+			return "", err
+		}
+		return hashFile(dst)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxDeltaWindow bounds the memory a patch may make the decoder use. Patches
+// made with --patch-from need a window at least as large as the old binary.
+const maxDeltaWindow = 1 << 30
+
+// deltaAssetName expands the manifest's binary.patch template for the
+// patch turning version from of assetName into the release's version.
+func deltaAssetName(blockInfo *BlockInfo, assetName, from string) string {
+	return strings.NewReplacer("{asset}", assetName, "{from}", from).Replace(blockInfo.Binary.Patch)
+}
+
+// downloadDelta tries to produce the host's assetName at localPath by
+// applying the release's zstd patch to the installed version of the block
+// instead of downloading the whole binary. The patched binary is written
+// next to localPath and only moved into place once verify accepts it. It
+// reports false, leaving nothing behind, whenever the block publishes no
+// patch from the installed version or the patched binary fails
+// verification; the caller then falls back to a full download.
+func (pm *PackageManager) downloadDelta(ctx context.Context, repo, version string, blockInfo *BlockInfo, platform, assetName, localPath string, verify verifyDownload) (string, bool) {
+	if blockInfo.Binary.Patch == "" || platform != hostPlatform() {
+		return "", false
+	}
+
+	pm.commitMu.Lock()
+	previous := pm.loadedBlocks[blockInfo.Name]
+	pm.commitMu.Unlock()
+	if previous == nil || sameVersion(previous.Version, version) {
+		return "", false
+	}
+	if previousRepo, _ := splitRepoPath(previous.SourceRepo); previousRepo != repo {
+		return "", false
+	}
+
+	old, err := os.ReadFile(previous.BinaryPath)
+	if err != nil {
+		return "", false
+	}
+
+	patchName := deltaAssetName(blockInfo, assetName, previous.Version)
+	partPath := localPath + partSuffix
+	digest, err := pm.applyDelta(ctx, repo, version, patchName, old, partPath)
+	if err != nil {
+		_ = os.Remove(partPath)
+		pm.log().Debug("delta update unavailable, downloading the full binary", "block", blockInfo.Name, "patch", patchName, "error", err)
+		return "", false
+	}
+
+	if err := verify(partPath, digest); err != nil {
+		_ = os.Remove(partPath)
+		pm.log().Warn("patched binary failed verification, downloading the full binary", "block", blockInfo.Name, "patch", patchName, "error", err)
+		return "", false
+	}
+	if err := os.Rename(partPath, localPath); err != nil {
+		_ = os.Remove(partPath)
+		pm.log().Warn("failed to move patched binary into place, downloading the full binary", "block", blockInfo.Name, "error", err)
+		return "", false
+	}
+
+	pm.log().Debug("applied delta update", "block", blockInfo.Name, "from", previous.Version, "to", version, "patch", patchName)
+	return digest, true
+}
+
+// applyDelta downloads the patch asset, applies it to old, and writes the
+// result to localPath, returning its hex-encoded SHA256 digest.
+func (pm *PackageManager) applyDelta(ctx context.Context, repo, version, patchName string, old []byte, localPath string) (string, error) {
+	release, err := pm.getReleaseByTag(ctx, repo, version)
+	if err != nil {
+		return "", err
+	}
+	asset, err := pm.findAsset(release, patchName)
+	if err != nil {
+		return "", err
+	}
+
+	pm.reportPhase(ctx, PhaseDownloading)
+	var patch bytes.Buffer
+	if err := pm.fetchAsset(ctx, repo, asset, &patch); err != nil {
+		return "", fmt.Errorf("failed to download patch: %w", err)
+	}
+
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderDictRaw(0, old), zstd.WithDecoderMaxWindow(maxDeltaWindow))
+	if err != nil {
+		return "", err
+	}
+	defer decoder.Close()
+
+	patched, err := decoder.DecodeAll(patch.Bytes(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	if err := os.WriteFile(localPath, patched, 0644); err != nil {
+		return "", fmt.Errorf("failed to write patched binary: %w", err)
+	}
+
+	sum := sha256.Sum256(patched)
+	return hex.EncodeToString(sum[:]), nil
+}
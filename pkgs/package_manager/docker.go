@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// BinaryFromDocker is the binary.from value of blocks shipped as a
+// container image instead of release assets.
+const BinaryFromDocker = "docker"
+
+// EnvContainerCLI overrides the container CLI used to pull and run image
+// blocks, e.g. podman. It defaults to docker.
+const EnvContainerCLI = "ATOMOS_CONTAINER_CLI"
+
+// imageRefExt is the extension of the file recorded as the binary of an
+// image block. It holds the image reference, pinned by digest when the
+// registry reports one.
+const imageRefExt = ".image"
+
+// ContainerCLI returns the container CLI used for image blocks.
+func ContainerCLI() string {
+	if cli := os.Getenv(EnvContainerCLI); cli != "" {
+		return cli
+	}
+	return "docker"
+}
+
+// IsImageBlock reports whether the manifest ships the block as a container
+// image.
+func IsImageBlock(blockInfo *BlockInfo) bool {
+	return blockInfo.Binary.From == BinaryFromDocker
+}
+
+// ImageRef returns the container image of an installed image block, read
+// from the reference file recorded as its binary.
+func ImageRef(binaryPath string) (string, bool, error) {
+	if filepath.Ext(binaryPath) != imageRefExt {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to read image reference: %w", err)
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// imageName returns the image of blockInfo at version, substituting the
+// {version} placeholder of binary.image.
+func imageName(blockInfo *BlockInfo, version string) (string, error) {
+	if blockInfo.Binary.Image == "" {
+		return "", fmt.Errorf("block '%s' is built from docker but its manifest has no binary.image", blockInfo.Name)
+	}
+	return strings.ReplaceAll(blockInfo.Binary.Image, "{version}", version), nil
+}
+
+// pullImage pulls the image of blockInfo and records its reference in
+// <block>/bin/<version>/<block>.image, returning the reference file's path
+// and digest like a downloaded binary.
+func (pm *PackageManager) pullImage(ctx context.Context, blockInfo *BlockInfo, version string) (string, string, error) {
+	image, err := imageName(blockInfo, version)
+	if err != nil {
+		return "", "", err
+	}
+
+	pm.reportPhase(ctx, PhaseDownloading)
+	cli := ContainerCLI()
+	var stderr bytes.Buffer
+	pull := exec.CommandContext(ctx, cli, "pull", image)
+	pull.Stderr = &stderr
+	if err := pull.Run(); err != nil {
+		return "", "", fmt.Errorf("failed to pull image %s: %w: %s", image, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Pin the reference to the pulled digest so a moved tag can't change
+	// what the installed version runs.
+	ref := image
+	out, err := exec.CommandContext(ctx, cli, "image", "inspect", "--format", "{{index .RepoDigests 0}}", image).Output()
+	if pinned := strings.TrimSpace(string(out)); err == nil && pinned != "" {
+		ref = pinned
+	} else {
+		pm.log().Warn("image has no registry digest, recording its tag", "block", blockInfo.Name, "image", image)
+	}
+	pm.log().Debug("pulled image", "block", blockInfo.Name, "image", ref)
+
+	binDir := pm.versionBinDir(blockInfo.Name, version)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create bin directory: %w", err)
+	}
+	refPath := filepath.Join(binDir, blockInfo.Name+imageRefExt)
+	if err := os.WriteFile(refPath, []byte(ref+"\n"), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to record image reference: %w", err)
+	}
+
+	digest, err := hashFile(refPath)
+	if err != nil {
+		return "", "", err
+	}
+	return refPath, digest, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Failure causes callers can branch on with errors.Is, instead of matching
+// error strings. More specific errors such as *AuthError carry the details.
+var (
+	// ErrBlockNotFound is returned for operations on a block, or a version
+	// of one, that isn't installed.
+	ErrBlockNotFound = errors.New("block is not installed")
+	// ErrAssetMissingForPlatform is returned when a block publishes no
+	// binary for the platform, or its release lacks the asset the manifest
+	// names for it.
+	ErrAssetMissingForPlatform = errors.New("no binary found for platform")
+	// ErrAuth is matched by every *AuthError, and by authentication
+	// failures of GitLab and catalog sync requests.
+	ErrAuth = errors.New("authentication failed")
+	// ErrRateLimited is returned when the GitHub API rate limit, or the
+	// retries of a rate-limited download, are exhausted.
+	ErrRateLimited = errors.New("rate limit exceeded")
+	// ErrChecksumMismatch is returned when a download doesn't match the
+	// checksum published or recorded for it.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// notInstalledError is the error of operations on a block, or one version
+// of it, that isn't installed. It matches ErrBlockNotFound and wraps the
+// failure that revealed it, if any.
+type notInstalledError struct {
+	block   string
+	version string
+	cause   error
+}
+
+// errNotInstalled returns a notInstalledError; version and cause may be
+// empty.
+func errNotInstalled(block, version string, cause error) error {
+	return &notInstalledError{block: block, version: version, cause: cause}
+}
+
+func (e *notInstalledError) Error() string {
+	msg := fmt.Sprintf("block '%s' is not installed", e.block)
+	if e.version != "" {
+		msg = fmt.Sprintf("version '%s' of block '%s' is not installed", e.version, e.block)
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+func (e *notInstalledError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{ErrBlockNotFound}
+	}
+	return []error{ErrBlockNotFound, e.cause}
+}
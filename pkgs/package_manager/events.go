@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import "time"
+
+// InstallEvent describes an install. OnInstallStart receives the request's
+// coordinates only; OnInstallComplete also receives the outcome.
+// InstallWithProgress streams them with the phase and download progress.
+type InstallEvent struct {
+	Repo     string         // InstallRequest.Repo of the install
+	Version  string         // Requested version, empty for the latest
+	Metadata *BlockMetadata // Installed block, nil when the install failed
+	Err      error          // Why the install failed
+	Duration time.Duration  // Time the install took
+
+	Phase      InstallPhase // Set by InstallWithProgress only
+	Downloaded int64        // Bytes of the binary downloaded so far
+	Total      int64        // Size of the binary, -1 while unknown
+	Percent    int          // Share of the binary downloaded, -1 while unknown
+}
+
+// UninstallEvent describes a removed block version.
+type UninstallEvent struct {
+	Name    string
+	Version string
+}
+
+// EventListener is notified of the package manager's lifecycle events, e.g.
+// to drive a UI, write an audit log, or export metrics. InstallAll installs
+// concurrently, so listeners must be safe to call from several goroutines.
+// Embed NopEventListener to implement only some of the methods.
+type EventListener interface {
+	OnInstallStart(InstallEvent)
+	OnDownloadProgress(Progress)
+	OnInstallComplete(InstallEvent)
+	OnUninstall(UninstallEvent)
+}
+
+// NopEventListener ignores every event.
+type NopEventListener struct{}
+
+func (NopEventListener) OnInstallStart(InstallEvent)    {}
+func (NopEventListener) OnDownloadProgress(Progress)    {}
+func (NopEventListener) OnInstallComplete(InstallEvent) {}
+func (NopEventListener) OnUninstall(UninstallEvent)     {}
+
+// AddEventListener registers a listener notified of every install and
+// uninstall. Listeners are called in registration order, synchronously, so
+// a slow listener slows the operation down. Listeners may be added while
+// operations run, and may call back into the package manager.
+func (pm *PackageManager) AddEventListener(listener EventListener) {
+	pm.listenersMu.Lock()
+	defer pm.listenersMu.Unlock()
+
+	pm.listeners = append(pm.listeners, listener)
+}
+
+// hasListeners reports whether any listener is registered.
+func (pm *PackageManager) hasListeners() bool {
+	pm.listenersMu.RLock()
+	defer pm.listenersMu.RUnlock()
+
+	return len(pm.listeners) > 0
+}
+
+// emit calls notify for every registered listener.
+func (pm *PackageManager) emit(notify func(EventListener)) {
+	pm.listenersMu.RLock()
+	listeners := pm.listeners
+	pm.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		notify(listener)
+	}
+}
+
+// instrumentInstall notifies listeners that the install of req starts and
+// returns the function notifying them of its outcome.
+func (pm *PackageManager) instrumentInstall(req InstallRequest) func(*BlockMetadata, error) {
+	if !pm.hasListeners() {
+		return func(*BlockMetadata, error) {}
+	}
+
+	start := time.Now()
+	pm.emit(func(l EventListener) { l.OnInstallStart(InstallEvent{Repo: req.Repo, Version: req.Version}) })
+	return func(metadata *BlockMetadata, err error) {
+		event := InstallEvent{Repo: req.Repo, Version: req.Version, Metadata: metadata, Err: err, Duration: time.Since(start)}
+		pm.emit(func(l EventListener) { l.OnInstallComplete(event) })
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// windowsExecutableExts are the extensions Windows runs a file by; see
+// PATHEXT.
+var windowsExecutableExts = []string{".exe", ".com", ".bat", ".cmd", ".ps1"}
+
+// isWindowsPlatform reports whether platform, an <os>-<arch> key or a bare
+// GOOS, targets Windows.
+func isWindowsPlatform(platform string) bool {
+	goos, _, _ := strings.Cut(platform, "-")
+	return goos == "windows"
+}
+
+// executableName returns the file name a binary asset of platform is
+// stored under. Windows only runs files by extension, so an asset without
+// one, e.g. "tool-windows-amd64", is stored as "tool-windows-amd64.exe".
+// Names that already carry an extension, like archives, are kept as is.
+func executableName(name, platform string) string {
+	if !isWindowsPlatform(platform) || hasFileExtension(name) {
+		return name
+	}
+	return name + ".exe"
+}
+
+// hasFileExtension reports whether name ends in a short alphanumeric
+// extension with at least one letter, so version and platform suffixes such
+// as "tool-1.2" or "tool_v1.2-windows-amd64" don't count as one.
+func hasFileExtension(name string) bool {
+	ext := filepath.Ext(name)
+	if len(ext) < 2 || len(ext) > 5 {
+		return false
+	}
+	hasLetter := false
+	for _, r := range ext[1:] {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// makeExecutable sets the executable bits on the binary of platform at
+// path. Windows has no such bits, so binaries of Windows platforms are left
+// untouched.
+func makeExecutable(path, platform string) error {
+	if isWindowsPlatform(platform) {
+		return nil
+	}
+	return os.Chmod(path, 0755)
+}
+
+// isExecutable reports whether the file at path with mode can be run on
+// the host: Windows decides by extension, everything else by mode bits.
+func isExecutable(path string, mode os.FileMode) bool {
+	if isWindowsPlatform(hostPlatform()) {
+		return slices.Contains(windowsExecutableExts, strings.ToLower(filepath.Ext(path)))
+	}
+	return mode.Perm()&0111 != 0
+}
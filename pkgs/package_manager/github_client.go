@@ -0,0 +1,329 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+)
+
+const (
+	// maxRateLimitWait is the longest the client sleeps for a rate limit to
+	// reset before giving up.
+	maxRateLimitWait = time.Minute
+	// maxRateLimitRetries bounds retries of rate-limited requests.
+	maxRateLimitRetries = 3
+
+	githubCacheDir = ".cache/github"
+)
+
+// githubClient issues GitHub API GETs on behalf of the package manager. It
+// tracks the rate limit reported in X-RateLimit-* headers, waits and retries
+// when a request is rate limited, and caches responses with their ETag so
+// repeated lookups are answered by cheap conditional requests.
+type githubClient struct {
+	cacheDir   string
+	httpClient func() *http.Client
+	timeout    func() time.Duration
+	retry      func() RetryPolicy
+	log        func() *slog.Logger
+	token      func(ctx context.Context, url string) (string, error)
+
+	mu        sync.Mutex
+	remaining int // -1 until a response reported it
+	reset     time.Time
+}
+
+// githubCacheEntry is a cached response body and its validator, stored as
+// zstd-compressed JSON.
+type githubCacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// github returns the package manager's GitHub API client.
+func (pm *PackageManager) github() *githubClient {
+	pm.githubOnce.Do(func() {
+		pm.githubClient = &githubClient{
+			cacheDir:   filepath.Join(pm.InstallDir, githubCacheDir),
+			httpClient: pm.httpClient,
+			timeout:    pm.requestTimeout,
+			retry:      pm.retryPolicy,
+			log:        pm.log,
+			token: func(ctx context.Context, url string) (string, error) {
+				return pm.token(ctx, ServiceGitHub, url)
+			},
+			remaining: -1,
+		}
+	})
+	return pm.githubClient
+}
+
+// get performs an authenticated GET and returns the status code and body.
+// A 304 answer is turned into the cached 200 response.
+func (c *githubClient) get(ctx context.Context, url string) (int, []byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout())
+	defer cancel()
+
+	token, err := c.token(ctx, url)
+	if err != nil {
+		return 0, nil, err
+	}
+	key := c.cacheKey(url, token)
+	cached, hasCache := c.readCache(key)
+
+	if wait := c.exhaustedFor(); wait > 0 {
+		if hasCache {
+			c.log().Warn("GitHub rate limit exhausted, using cached response", "url", url)
+			return http.StatusOK, cached.Body, nil
+		}
+		if wait > maxRateLimitWait {
+			return 0, nil, fmt.Errorf("GitHub API %w until %s", ErrRateLimited, c.resetTime().Format(time.RFC3339))
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	backoff := time.Second
+	policy := c.retry()
+	retryDelay, failures := policy.Delay, 0
+	// retryTransient waits before retrying a transient failure, or reports
+	// false once the retry budget is spent.
+	retryTransient := func(reason any) (bool, error) {
+		failures++
+		if failures >= policy.MaxAttempts {
+			return false, nil
+		}
+		c.log().Warn("GitHub request failed, retrying", "url", url, "error", reason, "delay", retryDelay)
+		if err := sleepCtx(ctx, retryDelay); err != nil {
+			return false, err
+		}
+		retryDelay *= 2
+		return true, nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if hasCache && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		c.log().Debug("GitHub request", "url", url, "attempt", attempt+1, "conditional", req.Header.Get("If-None-Match") != "")
+		client := c.httpClient()
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, nil, err
+			}
+			retry, sleepErr := retryTransient(err)
+			if sleepErr != nil {
+				return 0, nil, sleepErr
+			}
+			if !retry {
+				return 0, nil, err
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		c.observeRateLimit(resp.Header)
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && hasCache:
+			c.log().Debug("GitHub response not modified, using cache", "url", url)
+			return http.StatusOK, cached.Body, nil
+
+		case resp.StatusCode == http.StatusOK:
+			if etag := resp.Header.Get("ETag"); etag != "" {
+				c.writeCache(key, githubCacheEntry{ETag: etag, Body: body})
+			}
+			return resp.StatusCode, body, nil
+
+		case isRateLimited(resp, body) && attempt < maxRateLimitRetries:
+			wait := rateLimitWait(resp.Header, backoff)
+			if wait > maxRateLimitWait {
+				return 0, nil, fmt.Errorf("GitHub API %w; resets in %s", ErrRateLimited, wait.Round(time.Second))
+			}
+			c.log().Warn("GitHub API rate limited, retrying", "url", url, "wait", wait.Round(time.Second))
+			if err := sleepCtx(ctx, wait); err != nil {
+				return 0, nil, err
+			}
+			backoff *= 2
+
+		case isRateLimited(resp, body):
+			return 0, nil, fmt.Errorf("GitHub API %w after %d retries", ErrRateLimited, attempt)
+
+		case isTransient(resp.StatusCode):
+			retry, err := retryTransient(resp.Status)
+			if err != nil {
+				return 0, nil, err
+			}
+			if !retry {
+				return resp.StatusCode, body, nil
+			}
+
+		default:
+			return resp.StatusCode, body, nil
+		}
+	}
+}
+
+// isTransient reports whether a status is worth retrying the request for.
+func isTransient(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// isRateLimited distinguishes rate-limit responses from permission errors,
+// which share the 403 status.
+func isRateLimited(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			resp.Header.Get("Retry-After") != "" ||
+			strings.Contains(strings.ToLower(string(body)), "rate limit")
+	default:
+		return false
+	}
+}
+
+// rateLimitWait honours Retry-After, then X-RateLimit-Reset, and falls back
+// to the exponential backoff for secondary limits that give neither.
+func rateLimitWait(header http.Header, backoff time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0) + time.Second
+		}
+	}
+	return backoff
+}
+
+func (c *githubClient) observeRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remaining = remaining
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		c.reset = time.Unix(reset, 0)
+	}
+}
+
+// exhaustedFor returns how long until the rate limit resets when no
+// requests are left, and zero otherwise.
+func (c *githubClient) exhaustedFor() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.remaining != 0 {
+		return 0
+	}
+	return max(time.Until(c.reset), 0)
+}
+
+func (c *githubClient) resetTime() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.reset
+}
+
+// cacheKey separates cache entries per token so responses for private
+// repositories are never served to another identity.
+func (c *githubClient) cacheKey(url, token string) string {
+	sum := sha256.Sum256([]byte(token + "\x00" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *githubClient) readCache(key string) (githubCacheEntry, bool) {
+	var entry githubCacheEntry
+	data, err := compression.ReadFile(filepath.Join(c.cacheDir, key+".json"))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// writeCache stores a response; failures only cost a future full request.
+func (c *githubClient) writeCache(key string, entry githubCacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if data, err = compression.Compress(data, compression.Zstd); err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.cacheDir, 0700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.cacheDir, ".entry-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), filepath.Join(c.cacheDir, key+".json"))
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.Join(errors.New("interrupted while waiting for the GitHub rate limit"), ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// gitLabRepoPrefix marks InstallRequest.Repo values hosted on GitLab:
+	// "gitlab:group/project" for gitlab.com, or
+	// "gitlab:https://gitlab.example.com/group/project" for self-hosted instances.
+	gitLabRepoPrefix  = "gitlab:"
+	defaultGitLabHost = "https://gitlab.com"
+	sourceTypeGitLab  = "gitlab"
+)
+
+// gitLabSource identifies a project on a GitLab instance.
+type gitLabSource struct {
+	baseURL string // e.g. https://gitlab.com
+	project string // e.g. group/subgroup/project
+}
+
+type gitLabRelease struct {
+	TagName         string `json:"tag_name"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitLabAssetLink `json:"links"`
+	} `json:"assets"`
+}
+
+type gitLabAssetLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// parseGitLabRepo recognizes GitLab repository coordinates.
+func parseGitLabRepo(repo string) (gitLabSource, bool) {
+	rest, ok := strings.CutPrefix(repo, gitLabRepoPrefix)
+	if !ok {
+		return gitLabSource{}, false
+	}
+
+	if u, err := url.Parse(rest); err == nil && u.Scheme != "" && u.Host != "" {
+		return gitLabSource{
+			baseURL: u.Scheme + "://" + u.Host,
+			project: strings.Trim(u.Path, "/"),
+		}, true
+	}
+
+	return gitLabSource{baseURL: defaultGitLabHost, project: strings.Trim(rest, "/")}, true
+}
+
+func (src gitLabSource) apiURL(format string, args ...any) string {
+	projectID := url.PathEscape(src.project)
+	return fmt.Sprintf("%s/api/v4/projects/%s", src.baseURL, projectID) + fmt.Sprintf(format, args...)
+}
+
+// installFromGitLab installs a block whose manifest and releases live on a
+// GitLab instance. It mirrors the GitHub flow of Install.
+func (pm *PackageManager) installFromGitLab(ctx context.Context, req InstallRequest, src gitLabSource) (*BlockMetadata, error) {
+	blockInfo, err := pm.fetchGitLabBlockInfo(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block info: %w", err)
+	}
+	if err := applyAlias(req, blockInfo); err != nil {
+		return nil, err
+	}
+	if err := pm.checkNameConflict(req, blockInfo.Name, req.Repo); err != nil {
+		return nil, err
+	}
+
+	if !req.Force {
+		if metadata, ok, err := pm.cachedBlock(blockInfo.Name); err != nil || ok {
+			return metadata, err
+		}
+	}
+
+	releases, err := pm.listGitLabReleases(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	pm.applyRegistryStatus(ctx, blockInfo, req.Repo)
+	release, err := selectGitLabRelease(releases, req.Version, blockInfo.YankedVersions)
+	if err != nil {
+		return nil, err
+	}
+	if err := pm.checkYanked(req, blockInfo, release.TagName); err != nil {
+		return nil, err
+	}
+	if err := pm.checkRequirements(ctx, req, blockInfo.Name, blockInfo.Requires); err != nil {
+		return nil, err
+	}
+
+	listAssets := func() ([]string, error) {
+		names := make([]string, 0, len(release.Assets.Links))
+		for _, link := range release.Assets.Links {
+			names = append(names, link.Name)
+		}
+		return names, nil
+	}
+	var binaryPath, digest string
+	inferred, assetErr := pm.resolvePlatformAsset(blockInfo, listAssets, req.ProbeAssets)
+	pending := pm.startDataAssets(ctx, blockInfo, func(asset, dst string) (string, error) {
+		link, err := release.findLink(asset)
+		if err != nil {
+			return "", err
+		}
+		data, err := pm.gitLabGet(ctx, src, link.downloadURL())
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:]), nil
+	})
+	if assetErr != nil {
+		binaryPath, digest, err = pm.buildFallback(ctx, req, blockInfo, release.TagName, assetErr, pm.gitCheckout(src.cloneURL(), ServiceGitLab, release.TagName))
+	} else if binaryPath, digest, err = pm.downloadGitLabBinary(ctx, src, release, blockInfo); err != nil {
+		err = fmt.Errorf("failed to download binary: %w", err)
+	}
+	dataFiles, dataErr := pending.wait()
+	if err := errors.Join(err, dataErr); err != nil {
+		return nil, err
+	}
+
+	metadata := &BlockMetadata{
+		Name:            blockInfo.Name,
+		Description:     blockInfo.Description,
+		Version:         release.TagName,
+		SourceRepo:      req.Repo,
+		BinaryPath:      binaryPath,
+		SHA256:          digest,
+		InstalledAt:     time.Now(),
+		LastUpdated:     time.Now(),
+		IsActive:        true,
+		LSPEntries:      convertEntriesToMap(blockInfo.Entries),
+		InferredAsset:   inferred,
+		PostInstall:     blockInfo.Hooks.PostInstall,
+		BuiltFromSource: assetErr != nil,
+		DataFiles:       dataFiles,
+	}
+	pm.markStatus(metadata, blockInfo)
+
+	return pm.commitInstall(ctx, metadata)
+}
+
+// gitLabGet performs an authenticated GET against a GitLab instance. The
+// token is only sent to the instance hosting the project.
+func (pm *PackageManager) gitLabGet(ctx context.Context, src gitLabSource, rawURL string) ([]byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, pm.requestTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if strings.HasPrefix(rawURL, src.baseURL+"/") {
+		token, err := pm.token(ctx, ServiceGitLab, src.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	}
+
+	client := pm.httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitLab request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("not found on GitLab: %s", rawURL)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w - check GITLAB_TOKEN permissions for project %s", ErrAuth, src.project)
+	default:
+		return nil, fmt.Errorf("GitLab API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+func (pm *PackageManager) fetchGitLabBlockInfo(ctx context.Context, src gitLabSource) (*BlockInfo, error) {
+	data, err := pm.gitLabGet(ctx, src, src.apiURL("/repository/files/agentic_support.yaml/raw?ref=HEAD"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agentic_support.yaml: %w", err)
+	}
+
+	blockInfo, err := parseBlockInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockInfo.Source.Type != "" && blockInfo.Source.Type != sourceTypeGitLab {
+		return nil, fmt.Errorf("agentic_support.yaml declares source type '%s', expected '%s'", blockInfo.Source.Type, sourceTypeGitLab)
+	}
+
+	return blockInfo, nil
+}
+
+// listGitLabReleases returns the project's releases, newest first.
+func (pm *PackageManager) listGitLabReleases(ctx context.Context, src gitLabSource) ([]gitLabRelease, error) {
+	var releases []gitLabRelease
+	for page := 1; ; page++ {
+		data, err := pm.gitLabGet(ctx, src, src.apiURL("/releases?per_page=100&page=%d", page))
+		if err != nil {
+			return nil, err
+		}
+
+		var pageReleases []gitLabRelease
+		if err := json.Unmarshal(data, &pageReleases); err != nil {
+			return nil, fmt.Errorf("failed to decode releases JSON: %w", err)
+		}
+
+		releases = append(releases, pageReleases...)
+		if len(pageReleases) < 100 {
+			return releases, nil
+		}
+	}
+}
+
+// selectGitLabRelease picks the release matching version: the newest one when
+// empty, the highest match for a semver range, or the exact tag (with or
+// without a leading 'v'). Yanked releases are only picked by exact tag.
+func selectGitLabRelease(releases []gitLabRelease, version string, yanked []string) (*gitLabRelease, error) {
+	var published []gitLabRelease
+	for _, release := range releases {
+		exact := isExactVersion(version)
+		if !release.UpcomingRelease && (exact || !isYanked(yanked, release.TagName)) {
+			published = append(published, release)
+		}
+	}
+
+	switch {
+	case version == "":
+		if len(published) == 0 {
+			return nil, fmt.Errorf("no releases found")
+		}
+		return &published[0], nil
+
+	case IsChannel(version):
+		releases := make([]channelRelease, len(published))
+		for i, release := range published {
+			releases[i] = channelRelease{tag: release.TagName, prerelease: isPrereleaseTag(release.TagName)}
+		}
+		tag, err := selectChannel(releases, version, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &published[slices.IndexFunc(published, func(r gitLabRelease) bool { return r.TagName == tag })], nil
+
+	case IsVersionConstraint(version):
+		vc, err := ParseVersionConstraint(version)
+		if err != nil {
+			return nil, err
+		}
+
+		tags := make([]string, 0, len(published))
+		for _, release := range published {
+			tags = append(tags, release.TagName)
+		}
+
+		best := highestMatchingTag(tags, vc.Matches)
+		for i := range published {
+			if best != "" && published[i].TagName == best {
+				return &published[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no release satisfies version constraint '%s'", version)
+
+	default:
+		for i := range published {
+			if strings.TrimPrefix(published[i].TagName, "v") == strings.TrimPrefix(version, "v") {
+				return &published[i], nil
+			}
+		}
+		return nil, fmt.Errorf("release not found for tag '%s' (tried with/without 'v')", version)
+	}
+}
+
+func (release *gitLabRelease) findLink(name string) (*gitLabAssetLink, error) {
+	for i := range release.Assets.Links {
+		if release.Assets.Links[i].Name == name {
+			return &release.Assets.Links[i], nil
+		}
+	}
+	return nil, fmt.Errorf("asset '%s' not found in release %s", name, release.TagName)
+}
+
+func (link *gitLabAssetLink) downloadURL() string {
+	if link.DirectAssetURL != "" {
+		return link.DirectAssetURL
+	}
+	return link.URL
+}
+
+// downloadGitLabBinary downloads and verifies the binary for the current
+// platform into the standard <block>/bin layout.
+func (pm *PackageManager) downloadGitLabBinary(ctx context.Context, src gitLabSource, release *gitLabRelease, blockInfo *BlockInfo) (string, string, error) {
+	if IsImageBlock(blockInfo) {
+		return pm.pullImage(ctx, blockInfo, release.TagName)
+	}
+
+	binaryName, err := pm.getBinaryNameForPlatform(blockInfo)
+	if err != nil {
+		return "", "", err
+	}
+
+	pm.reportPhase(ctx, PhaseDownloading)
+	var data []byte
+	if isURLAsset(binaryName) {
+		assetURL := binaryName
+		if binaryName, err = urlAssetName(assetURL); err != nil {
+			return "", "", err
+		}
+
+		var buf bytes.Buffer
+		if err := pm.fetchURL(ctx, assetURL, &buf); err != nil {
+			return "", "", fmt.Errorf("failed to download asset: %w", err)
+		}
+		data = buf.Bytes()
+	} else {
+		link, err := release.findLink(binaryName)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %w", ErrAssetMissingForPlatform, err)
+		}
+
+		data, err = pm.gitLabGet(ctx, src, link.downloadURL())
+		if err != nil {
+			return "", "", fmt.Errorf("failed to download asset: %w", err)
+		}
+	}
+
+	pm.reportPhase(ctx, PhaseVerifying)
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	fetch := func(name string) ([]byte, error) {
+		checksumsLink, err := release.findLink(name)
+		if err != nil {
+			return nil, err
+		}
+		return pm.gitLabGet(ctx, src, checksumsLink.downloadURL())
+	}
+	list := func() []string {
+		names := make([]string, len(release.Assets.Links))
+		for i, link := range release.Assets.Links {
+			names[i] = link.Name
+		}
+		return names
+	}
+	if err := checkDigest(blockInfo, hostPlatform(), binaryName, digest, pm.withURLAssets(ctx, fetch), list); err != nil {
+		return "", "", err
+	}
+
+	binDir := pm.versionBinDir(blockInfo.Name, release.TagName)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	localPath := filepath.Join(binDir, executableName(binaryName, hostPlatform()))
+	if err := os.WriteFile(localPath, data, 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write binary: %w", err)
+	}
+
+	if err := makeExecutable(localPath, hostPlatform()); err != nil {
+		return "", "", fmt.Errorf("failed to make binary executable: %w", err)
+	}
+
+	return localPath, digest, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// healthCheckTimeout bounds the health check run of an installed binary.
+const healthCheckTimeout = 30 * time.Second
+
+// HealthCheck runs the installed binary once to confirm it executes on this
+// machine before the install is committed.
+type HealthCheck struct {
+	Args []string `yaml:"args" json:"args,omitempty"` // e.g. ["--version"]
+	// Expect is a regular expression the combined output must match. The
+	// exit status alone decides when it's empty.
+	Expect string `yaml:"expect" json:"expect,omitempty"`
+}
+
+// runHealthCheck runs the block's health check, if it declares one, and
+// fails the install when the binary doesn't run or its output doesn't
+// match. WebAssembly modules are skipped: they only run in the workflow
+// engine's WASI runtime.
+func (pm *PackageManager) runHealthCheck(ctx context.Context, metadata *BlockMetadata) error {
+	check := metadata.HealthCheck
+	if check == nil {
+		return nil
+	}
+	if strings.HasSuffix(metadata.BinaryPath, ".wasm") {
+		pm.log().Debug("skipping health check of a WebAssembly module", "block", metadata.Name)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	image, isImage, err := ImageRef(metadata.BinaryPath)
+	switch {
+	case err != nil:
+		return err
+	case isImage:
+		cmd = exec.CommandContext(ctx, ContainerCLI(), append([]string{"run", "--rm", image}, check.Args...)...)
+	default:
+		cmd = exec.CommandContext(ctx, metadata.BinaryPath, check.Args...)
+	}
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("health check of block '%s' failed: %w: %s", metadata.Name, err, strings.TrimSpace(output.String()))
+	}
+
+	if check.Expect != "" {
+		expect, err := regexp.Compile(check.Expect)
+		if err != nil {
+			return fmt.Errorf("invalid health check pattern of block '%s': %w", metadata.Name, err)
+		}
+		if !expect.Match(output.Bytes()) {
+			return fmt.Errorf("health check of block '%s' failed: output %q doesn't match %q", metadata.Name, strings.TrimSpace(output.String()), check.Expect)
+		}
+	}
+
+	pm.log().Debug("health check passed", "block", metadata.Name, "version", metadata.Version)
+	return nil
+}
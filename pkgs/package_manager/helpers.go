@@ -10,19 +10,18 @@
 package packagemanager
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 type GitHubAsset struct {
@@ -36,39 +35,146 @@ type githubContent struct {
 	Encoding string `json:"encoding"`
 }
 
-func (pm *PackageManager) fetchBlockInfo(repo string) (*BlockInfo, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	client := &http.Client{}
+// defaultRequestTimeout bounds requests whose context has no deadline unless
+// HTTPConfig.RequestTimeout overrides it.
+const defaultRequestTimeout = 30 * time.Second
+
+// withDefaultTimeout applies timeout unless the caller already set a
+// deadline, so callers can both cancel and extend requests.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
 
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/agentic_support.yaml", repo)
-	req, err := http.NewRequest("GET", apiURL, nil)
+// doDownload sends a download request. Unless the caller set a deadline,
+// the response headers must arrive within the request timeout, but the body
+// is read without one, so large binaries on slow links aren't cut off; the
+// caller's context still cancels the download.
+func (pm *PackageManager) doDownload(req *http.Request) (*http.Response, error) {
+	client := pm.httpClient()
+	if _, ok := req.Context().Deadline(); ok {
+		return client.Do(req)
+	}
+
+	timeout := pm.requestTimeout()
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(timeout, func() {
+		cancel(fmt.Errorf("no response within %s: %w", timeout, context.DeadlineExceeded))
+	})
+	resp, err := client.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		// The timer fired before Do returned, so ctx is canceled.
+		if err == nil {
+			resp.Body.Close()
+		}
+		return nil, context.Cause(ctx)
+	}
 	if err != nil {
+		cancel(err)
 		return nil, err
 	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: func() { cancel(nil) }}
+	return resp, nil
+}
 
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+// cancelOnClose releases the context of a response once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+type githubRepository struct {
+	FullName string `json:"full_name"`
+}
+
+// canonicalRepo returns the current "owner/name" of repo. GitHub keeps
+// redirecting renamed and transferred repositories, so the lookup follows the
+// redirect and reads the canonical name from the response. Any failure keeps
+// the requested coordinates and lets the subsequent calls report the error.
+// The directory and selected block of a source are kept.
+func (pm *PackageManager) canonicalRepo(ctx context.Context, source string) string {
+	repo, _ := splitRepoPath(source)
+	return pm.canonicalRepoName(ctx, repo) + source[len(repo):]
+}
+
+// canonicalRepoName is canonicalRepo for bare "owner/name" coordinates.
+func (pm *PackageManager) canonicalRepoName(ctx context.Context, repo string) string {
+	status, body, err := pm.github().get(ctx, pm.githubAPI("/repos/%s", repo))
+	if err != nil || status != http.StatusOK {
+		return repo
 	}
 
-	resp, err := client.Do(req)
+	var info githubRepository
+	if err := json.Unmarshal(body, &info); err != nil || info.FullName == "" {
+		return repo
+	}
+
+	if strings.EqualFold(info.FullName, repo) {
+		return repo
+	}
+
+	pm.log().Warn("repository has moved; update workflows and lockfiles referencing the old name", "repo", repo, "moved_to", info.FullName)
+	return info.FullName
+}
+
+func (pm *PackageManager) fetchBlockInfo(ctx context.Context, repo string) (*BlockInfo, error) {
+	return pm.fetchBlockInfoAt(ctx, repo, "")
+}
+
+// fetchBlockInfoAt reads the manifest of repo at ref, a branch, tag, or
+// commit; "" is the default branch. Manifests of monorepo blocks are read
+// from their directory, and those of selected blocks from agentic_support/.
+func (pm *PackageManager) fetchBlockInfoAt(ctx context.Context, source, ref string) (*BlockInfo, error) {
+	repo, _ := splitRepoPath(source)
+	data, err := pm.fetchRepoFile(ctx, repo, ref, manifestPath(source))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch agentic_support.yaml: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	blockInfo, err := parseBlockInfo(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
+	}
+	if block := selectedBlock(source); block != "" && blockInfo.Name != block {
+		return nil, fmt.Errorf("%s names block '%s', not '%s'", manifestPath(source), blockInfo.Name, block)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
+	return blockInfo, nil
+}
+
+// fetchRepoFile reads a file of a GitHub repository at ref, or on the
+// default branch when ref is "", from the raw content URL when one is
+// configured and through the contents API otherwise.
+func (pm *PackageManager) fetchRepoFile(ctx context.Context, repo, ref, path string) ([]byte, error) {
+	if raw := pm.githubRawURL(); raw != "" {
+		return pm.fetchRawRepoFile(ctx, raw, repo, ref, path)
+	}
+
+	apiURL := pm.githubAPI("/repos/%s/contents/%s", repo, path)
+	if ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(ref)
+	}
+	status, body, err := pm.github().get(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+
+	if status != http.StatusOK {
+		switch status {
 		case http.StatusNotFound:
-			return nil, fmt.Errorf("agentic_support.yaml not found in repository %s", repo)
+			return nil, fmt.Errorf("%s not found in repository %s", path, repo)
 		case http.StatusUnauthorized, http.StatusForbidden:
-			return nil, fmt.Errorf("authentication failed - check GITHUB_TOKEN permissions for repository %s", repo)
+			return nil, pm.authError(ctx, repo, status, body)
 		default:
-			return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+			return nil, fmt.Errorf("GitHub API error %d: %s", status, strings.TrimSpace(string(body)))
 		}
 	}
 
@@ -86,51 +192,64 @@ func (pm *PackageManager) fetchBlockInfo(repo string) (*BlockInfo, error) {
 		return nil, fmt.Errorf("failed to decode base64 content: %w", err)
 	}
 
-	var blockInfo BlockInfo
-	if err := yaml.Unmarshal(data, &blockInfo); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
-	}
-
-	return &blockInfo, nil
+	return data, nil
 }
 
-// getLatestRelease fetches the latest release from GitHub (supports both public and private repos)
-func (pm *PackageManager) getLatestRelease(repo string) (*GitHubRelease, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// fetchRawRepoFile reads a file at ref, or of the default branch (HEAD)
+// when ref is "", from a raw content server.
+func (pm *PackageManager) fetchRawRepoFile(ctx context.Context, raw, repo, ref, path string) ([]byte, error) {
+	if ref == "" {
+		ref = "HEAD"
 	}
-
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
-
-	req, err := http.NewRequest("GET", url, nil)
+	status, body, err := pm.github().get(ctx, fmt.Sprintf("%s/%s/%s/%s", raw, repo, ref, path))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to fetch %s: %w", path, err)
 	}
 
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+	switch status {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s not found in repository %s", path, repo)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, pm.authError(ctx, repo, status, body)
+	default:
+		return nil, fmt.Errorf("GitHub raw content error %d: %s", status, strings.TrimSpace(string(body)))
 	}
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+// getLatestRelease fetches the latest release from GitHub (supports both public and private repos).
+// GitHub's latest release is never a prerelease, so with prerelease set the
+// releases are listed instead and the newest one that isn't a draft wins.
+func (pm *PackageManager) getLatestRelease(ctx context.Context, repo string, prerelease bool) (*GitHubRelease, error) {
+	if prerelease {
+		releases, err := pm.listReleases(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range releases {
+			if !release.Draft {
+				return &release, nil
+			}
+		}
+		return nil, fmt.Errorf("no releases found for repository %s", repo)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	url := pm.githubAPI("/repos/%s/releases/latest", repo)
+
+	status, body, err := pm.github().get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
+	if status != http.StatusOK {
+		switch status {
 		case http.StatusNotFound:
 			return nil, fmt.Errorf("no releases found for repository %s", repo)
 		case http.StatusUnauthorized, http.StatusForbidden:
-			return nil, fmt.Errorf("authentication failed - check GITHUB_TOKEN permissions for repository %s", repo)
+			return nil, pm.authError(ctx, repo, status, body)
 		default:
-			return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+			return nil, fmt.Errorf("GitHub API error %d: %s", status, strings.TrimSpace(string(body)))
 		}
 	}
 
@@ -142,66 +261,208 @@ func (pm *PackageManager) getLatestRelease(repo string) (*GitHubRelease, error)
 	return &release, nil
 }
 
-// downloadBinary downloads a binary for the current platform
-func (pm *PackageManager) downloadBinary(repo, version string, blockInfo *BlockInfo) (string, error) {
-	binaryName, err := pm.getBinaryNameForPlatform(blockInfo)
+// listReleases fetches every release of a repository, following pagination.
+func (pm *PackageManager) listReleases(ctx context.Context, repo string) ([]GitHubRelease, error) {
+	var releases []GitHubRelease
+	for page := 1; ; page++ {
+		url := pm.githubAPI("/repos/%s/releases?per_page=100&page=%d", repo, page)
+
+		status, body, err := pm.github().get(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+
+		if status != http.StatusOK {
+			switch status {
+			case http.StatusNotFound:
+				return nil, fmt.Errorf("repository %s not found", repo)
+			case http.StatusUnauthorized, http.StatusForbidden:
+				return nil, pm.authError(ctx, repo, status, body)
+			default:
+				return nil, fmt.Errorf("GitHub API error %d: %s", status, strings.TrimSpace(string(body)))
+			}
+		}
+
+		var pageReleases []GitHubRelease
+		if err := json.Unmarshal(body, &pageReleases); err != nil {
+			return nil, fmt.Errorf("failed to decode releases JSON: %w", err)
+		}
+
+		releases = append(releases, pageReleases...)
+		if len(pageReleases) < 100 {
+			return releases, nil
+		}
+	}
+}
+
+// resolveVersionConstraint returns the tag of the highest published release
+// satisfying the constraint, prereleases included when prerelease is set.
+func (pm *PackageManager) resolveVersionConstraint(ctx context.Context, repo, constraint string, yanked []string, prerelease bool) (string, error) {
+	vc, err := ParseVersionConstraint(constraint)
 	if err != nil {
 		return "", err
 	}
 
-	binDir := filepath.Join(pm.InstallDir, blockInfo.Name, "bin")
-	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create bin directory: %w", err)
+	releases, err := pm.listReleases(ctx, repo)
+	if err != nil {
+		return "", err
 	}
 
-	localPath := filepath.Join(binDir, binaryName)
+	var tags []string
+	for _, release := range releases {
+		if !release.Draft {
+			tags = append(tags, release.TagName)
+		}
+	}
 
-	if err := pm.downloadAsset(repo, version, binaryName, localPath); err != nil {
-		return "", fmt.Errorf("downloadAsset failed: %w", err)
+	match := vc.Matches
+	if prerelease {
+		match = vc.MatchesPrerelease
+	}
+	bestTag := highestMatchingTag(withoutYanked(tags, yanked), match)
+	if bestTag == "" {
+		return "", fmt.Errorf("no release of %s satisfies version constraint '%s'", repo, constraint)
 	}
 
-	if runtime.GOOS != "windows" {
-		if err := os.Chmod(localPath, 0755); err != nil {
-			return "", fmt.Errorf("failed to make binary executable: %w", err)
+	return bestTag, nil
+}
+
+// downloadBinary downloads a binary for the current platform, verifies it
+// against the checksums declared by the block, and returns its path and
+// SHA256 digest.
+func (pm *PackageManager) downloadBinary(ctx context.Context, repo, version string, blockInfo *BlockInfo) (string, string, error) {
+	if IsImageBlock(blockInfo) {
+		if err := requireUnsigned(ctx, "container images"); err != nil {
+			return "", "", err
 		}
+		return pm.pullImage(ctx, blockInfo, version)
 	}
 
-	return localPath, nil
+	return pm.downloadPlatformBinary(ctx, repo, version, blockInfo, hostPlatform(), pm.versionBinDir(blockInfo.Name, version))
 }
 
-// downloadAsset downloads a specific asset from a GitHub release
-func (pm *PackageManager) downloadAsset(repo, version, assetName, localPath string) error {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return errors.New("GITHUB_TOKEN is required for downloading assets")
+// downloadPlatformBinary downloads and verifies the binary of platform into
+// binDir, returning its path and SHA256 digest.
+func (pm *PackageManager) downloadPlatformBinary(ctx context.Context, repo, version string, blockInfo *BlockInfo, platform, binDir string) (string, string, error) {
+	binaryName, err := assetForPlatform(blockInfo, platform)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	assetURL := ""
+	if isURLAsset(binaryName) {
+		assetURL = binaryName
+		if binaryName, err = urlAssetName(assetURL); err != nil {
+			return "", "", err
+		}
+	}
+	localPath := filepath.Join(binDir, executableName(binaryName, platform))
+
+	// The download is verified before it replaces localPath, which may hold
+	// the working binary of a forced reinstall.
+	verify := func(path, digest string) error {
+		pm.reportPhase(ctx, PhaseVerifying)
+		if err := pm.verifyChecksum(ctx, repo, version, blockInfo, platform, binaryName, digest); err != nil {
+			return err
+		}
+		if err := pm.verifySignature(ctx, repo, version, binaryName, path); err != nil {
+			return err
+		}
+		if err := makeExecutable(path, platform); err != nil {
+			return fmt.Errorf("failed to make binary executable: %w", err)
+		}
+		return nil
+	}
+
+	var digest string
+	if assetURL != "" {
+		if err := requireUnsigned(ctx, "assets downloaded from URLs"); err != nil {
+			return "", "", err
+		}
+		digest, err = pm.downloadURLAsset(ctx, assetURL, localPath, verify)
+		if err != nil {
+			return "", "", fmt.Errorf("downloadURLAsset failed: %w", err)
+		}
+	} else if patched, ok := pm.downloadDelta(ctx, repo, version, blockInfo, platform, binaryName, localPath, verify); ok {
+		digest = patched
+	} else {
+		digest, err = pm.downloadAsset(ctx, repo, version, binaryName, localPath, verify)
+		if err != nil {
+			return "", "", fmt.Errorf("downloadAsset failed: %w", err)
+		}
 	}
 
+	return localPath, digest, nil
+}
+
+// downloadAsset downloads a specific asset from a GitHub release, checking
+// it with verify before it is written to localPath, and returns the
+// hex-encoded SHA256 digest of the file.
+func (pm *PackageManager) downloadAsset(ctx context.Context, repo, version, assetName, localPath string, verify verifyDownload) (string, error) {
 	// Get release to find asset
-	release, err := pm.getReleaseByTag(repo, version)
+	release, err := pm.getReleaseByTag(ctx, repo, version)
 	if err != nil {
-		return fmt.Errorf("failed to resolve release '%s': %w", version, err)
+		return "", fmt.Errorf("failed to resolve release '%s': %w", version, err)
 	}
 
 	// Find the asset (not just the URL).
 	asset, err := pm.findAsset(release, assetName)
 	if err != nil {
-		return fmt.Errorf("findAsset failed: %w", err)
+		return "", fmt.Errorf("%w: %w", ErrAssetMissingForPlatform, err)
 	}
 
+	source := fmt.Sprintf("github:%s/%d", repo, asset.ID)
+	newRequest := func(ctx context.Context) (*http.Request, error) {
+		return pm.newAssetRequest(ctx, repo, asset)
+	}
+
+	return pm.downloadResumable(ctx, source, newRequest, localPath, verify)
+}
+
+// newAssetRequest builds the request downloading a release asset. With a
+// token it goes through the GitHub API endpoint, which private repositories
+// require. Without one, the asset's browser download URL is used: public
+// repositories serve it anonymously, without spending the API rate limit.
+// A configured mirror stands in for GitHub, so anonymous downloads keep
+// going through its API endpoint.
+func (pm *PackageManager) newAssetRequest(ctx context.Context, repo string, asset *ReleaseAsset) (*http.Request, error) {
 	// Use the GitHub API endpoint with asset ID.
-	assetURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/assets/%d", repo, asset.ID)
+	assetURL := pm.githubAPI("/repos/%s/releases/assets/%d", repo, asset.ID)
+	token, err := pm.token(ctx, ServiceGitHub, assetURL)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" && asset.DownloadURL != "" && pm.githubMirror() == "" {
+		assetURL = asset.DownloadURL
+		pm.log().Debug("downloading asset anonymously", "repo", repo, "asset", asset.Name, "url", assetURL)
+	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("GET", assetURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", assetURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create asset request: %w", err)
+		return nil, fmt.Errorf("failed to create asset request: %w", err)
 	}
 
 	// Required headers for GitHub asset downloads
-	req.Header.Set("Authorization", "Bearer "+token)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 	req.Header.Set("Accept", "application/octet-stream") // Critical for binary downloads
 
-	resp, err := client.Do(req)
+	return req, nil
+}
+
+// fetchAsset streams a release asset into w.
+func (pm *PackageManager) fetchAsset(ctx context.Context, repo string, asset *ReleaseAsset, w io.Writer) error {
+	req, err := pm.newAssetRequest(ctx, repo, asset)
+	if err != nil {
+		return err
+	}
+
+	resp, err := pm.doDownload(req)
 	if err != nil {
 		return fmt.Errorf("failed to download asset: %w", err)
 	}
@@ -212,86 +473,98 @@ func (pm *PackageManager) downloadAsset(repo, version, assetName, localPath stri
 		return fmt.Errorf("download failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
 	}
 
-	// Create the local file
-	file, err := os.Create(localPath)
-	if err != nil {
-		return fmt.Errorf("failed to create local file: %w", err)
-	}
-	defer file.Close()
-
-	// Copy the downloaded content to the file
-	if _, err := io.Copy(file, resp.Body); err != nil {
+	// Copy the downloaded content to the writer
+	if _, err := io.Copy(w, resp.Body); err != nil {
 		return fmt.Errorf("failed to write to file: %w", err)
 	}
 
 	return nil
 }
 
-// isBlockInstalled checks if there's at least one versioned metadata file under <block>/metadata/
+// isBlockInstalled checks if the metadata store holds at least one version of the block
 func (pm *PackageManager) isBlockInstalled(Blockname string) bool {
-	blockDir := filepath.Join(pm.InstallDir, Blockname, "metadata")
-	entries, err := os.ReadDir(blockDir)
-	if err != nil {
-		return false
+	if files, ok := pm.metadataStore().(*FileMetadataStore); ok {
+		return files.hasMetadataFiles(Blockname)
 	}
-	for _, e := range entries {
-		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
-			return true
-		}
-	}
-	return false
+	versions, err := pm.metadataStore().Versions(Blockname)
+	return err == nil && len(versions) > 0
 }
 
-// getMetadata retrieves block metadata from disk
+// getMetadata retrieves the metadata of the active version of a block from
+// the metadata store, or of the newest version when none is recorded as
+// active. Corrupted version files of the per-file store are set aside and
+// the next-newest valid one is used instead (see recoverMetadata).
 func (pm *PackageManager) getMetadata(Blockname string) (*BlockMetadata, error) {
-	// Choose the most recently modified version metadata file
+	if _, ok := pm.metadataStore().(*FileMetadataStore); !ok {
+		return pm.getStoredMetadata(Blockname)
+	}
+
 	blockDir := filepath.Join(pm.InstallDir, Blockname, "metadata")
-	entries, err := os.ReadDir(blockDir)
+	paths, err := metadataFilesByRecency(blockDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open metadata directory: %w", err)
 	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no metadata found for block %s", Blockname)
+	}
 
-	var latestPath string
-	var latestMod int64
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
-			continue
+	if active := pm.activeVersion(Blockname); active != "" {
+		activePath := pm.metadataPath(Blockname, active)
+		if i := slices.Index(paths, activePath); i > 0 {
+			paths = slices.Insert(slices.Delete(paths, i, i+1), 0, activePath)
 		}
-		p := filepath.Join(blockDir, e.Name())
-		info, err := os.Stat(p)
-		if err != nil {
-			continue
-		}
-		if info.ModTime().UnixNano() > latestMod {
-			latestMod = info.ModTime().UnixNano()
-			latestPath = p
-		}
-	}
-	if latestPath == "" {
-		return nil, fmt.Errorf("no metadata found for block %s", Blockname)
 	}
 
-	file, err := os.Open(latestPath)
+	return pm.recoverMetadata(Blockname, paths)
+}
+
+// getStoredMetadata is getMetadata for stores other than the per-file one.
+func (pm *PackageManager) getStoredMetadata(Blockname string) (*BlockMetadata, error) {
+	versions, err := pm.metadataStore().Versions(Blockname)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open metadata file: %w", err)
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
 	}
-	defer file.Close()
-
-	var metadata BlockMetadata
-	if err := json.NewDecoder(file).Decode(&metadata); err != nil {
-		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no metadata found for block %s", Blockname)
 	}
 
-	return &metadata, nil
+	if active := pm.activeVersion(Blockname); active != "" {
+		for _, metadata := range versions {
+			if metadata.Version == active {
+				return metadata, nil
+			}
+		}
+	}
+	return versions[0], nil
 }
 
 const (
 	getDefaultInstallDirPathName = ".atomos"
+
+	// EnvAtomosHome overrides the default install directory.
+	EnvAtomosHome = "ATOMOS_HOME"
+	// xdgDataDirName is the install directory under $XDG_DATA_HOME.
+	xdgDataDirName = "atomos"
 )
 
+// getDefaultInstallDirPath returns $ATOMOS_HOME when set. Otherwise, an
+// existing ~/.atomos is kept so upgrades find their blocks, then
+// $XDG_DATA_HOME/atomos is used when XDG_DATA_HOME is set, and ~/.atomos
+// when it isn't.
 func getDefaultInstallDirPath() string {
-	home := userHomeDir()
-	return filepath.Join(home, getDefaultInstallDirPathName)
+	if dir := os.Getenv(EnvAtomosHome); dir != "" {
+		return dir
+	}
+
+	legacy := filepath.Join(userHomeDir(), getDefaultInstallDirPathName)
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+
+	if data := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(data) {
+		return filepath.Join(data, xdgDataDirName)
+	}
+	return legacy
 }
 
 // loadExistingInstallation loads the existing installation state
@@ -307,6 +580,62 @@ func (pm *PackageManager) loadExistingInstallation() error {
 	return nil
 }
 
+// cachedBlock returns the installed metadata of a block when it is already
+// installed, so Install can skip the download unless forced.
+func (pm *PackageManager) cachedBlock(name string) (*BlockMetadata, bool, error) {
+	if !pm.isBlockInstalled(name) {
+		return nil, false, nil
+	}
+
+	metadata, err := pm.getMetadata(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("block '%s' is already installed but failed to read metadata: %w", name, err)
+	}
+
+	pm.log().Debug("block already installed, skipping download", "block", name, "version", metadata.Version)
+	return metadata, true, nil
+}
+
+// commitInstall scans the freshly installed binary, runs its post_install
+// hooks and health check, then persists its metadata and makes it the
+// active version of the block.
+func (pm *PackageManager) commitInstall(ctx context.Context, metadata *BlockMetadata) (*BlockMetadata, error) {
+	pm.reportPhase(ctx, PhaseInstalling)
+	if err := pm.scanBinary(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	if err := pm.runPostInstallHooks(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	if err := pm.runHealthCheck(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	if err := pm.checkFence(); err != nil {
+		return nil, err
+	}
+
+	if err := pm.accountSize(metadata); err != nil {
+		return nil, err
+	}
+
+	// A pin outlives explicit installs of other versions.
+	if prev, ok := pm.loadedBlocks[metadata.Name]; ok && prev.Pinned {
+		metadata.Pinned = true
+	}
+
+	if err := pm.activateLocked(metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
 // convertEntriesToMap converts a slice of Entry to a map[string]Entry using the entry name as the key
 func convertEntriesToMap(entries []Entry) map[string]Entry {
 	result := make(map[string]Entry)
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// installHistoryDir holds the audit logs of a block.
+	installHistoryDir = "logs"
+	// installHistoryFile records every install, update, and uninstall of a
+	// block, one JSON object per line. Unlike the hook and build output in
+	// <block>/install.log, it survives uninstalling the block.
+	installHistoryFile = "install.log"
+)
+
+// InstallOperation is the kind of operation an InstallRecord describes.
+type InstallOperation string
+
+const (
+	OperationInstall   InstallOperation = "install"
+	OperationUpdate    InstallOperation = "update"
+	OperationUninstall InstallOperation = "uninstall"
+)
+
+// InstallRecord is an entry of a block's install history.
+type InstallRecord struct {
+	Time            time.Time        `json:"time"`                       // When the operation started
+	Operation       InstallOperation `json:"operation"`                  // Install, update, or uninstall
+	Repo            string           `json:"repo,omitempty"`             // Requested repository
+	Requested       string           `json:"requested,omitempty"`        // Requested version, empty for the latest
+	Version         string           `json:"version,omitempty"`          // Version installed or removed
+	PreviousVersion string           `json:"previous_version,omitempty"` // Version an update replaced
+	Asset           string           `json:"asset,omitempty"`            // Installed binary or release asset
+	SHA256          string           `json:"sha256,omitempty"`           // Digest of the installed binary
+	Duration        time.Duration    `json:"duration"`                   // Time the operation took
+	Error           string           `json:"error,omitempty"`            // Why the operation failed
+}
+
+// historyKey carries the operation an install is part of through its context.
+type historyKey struct{}
+
+// historyOperation describes an install made by another operation.
+type historyOperation struct {
+	operation InstallOperation
+	block     string
+	previous  string
+}
+
+// withHistoryOperation records installs made under ctx as operation on
+// block, replacing previous.
+func withHistoryOperation(ctx context.Context, operation InstallOperation, block, previous string) context.Context {
+	return context.WithValue(ctx, historyKey{}, historyOperation{operation: operation, block: block, previous: previous})
+}
+
+// GetInstallHistory returns the recorded operations on a block, oldest
+// first. The history is kept after the block is uninstalled.
+func (pm *PackageManager) GetInstallHistory(Blockname string) ([]InstallRecord, error) {
+	f, err := os.Open(pm.installHistoryPath(Blockname))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install history: %w", err)
+	}
+	defer f.Close()
+
+	var records []InstallRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record InstallRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A line cut short by a crash shouldn't hide the rest.
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read install history: %w", err)
+	}
+	return records, nil
+}
+
+func (pm *PackageManager) installHistoryPath(block string) string {
+	return filepath.Join(pm.InstallDir, block, installHistoryDir, installHistoryFile)
+}
+
+// recordInstall appends the outcome of the install of req, started at
+// start, to the history of the installed block. Failed installs are
+// recorded for the block being updated, or the installed block req's
+// repository provides; others can't be attributed and aren't recorded.
+func (pm *PackageManager) recordInstall(ctx context.Context, req InstallRequest, start time.Time, metadata *BlockMetadata, err error) {
+	op, _ := ctx.Value(historyKey{}).(historyOperation)
+	record := InstallRecord{
+		Time:            start,
+		Operation:       OperationInstall,
+		Repo:            req.Repo,
+		Requested:       req.Version,
+		PreviousVersion: op.previous,
+		Duration:        time.Since(start),
+	}
+	if op.operation != "" {
+		record.Operation = op.operation
+	}
+
+	block := op.block
+	if metadata != nil {
+		block = metadata.Name
+		record.Version = metadata.Version
+		record.SHA256 = metadata.SHA256
+		record.Asset = metadata.Asset
+		if record.Asset == "" {
+			record.Asset = metadata.InferredAsset
+		}
+		if record.Asset == "" {
+			record.Asset = filepath.Base(metadata.BinaryPath)
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+		if block == "" {
+			block = pm.blockFromRepo(req.Repo)
+		}
+	}
+	// Blocks that were already installed are returned without installing.
+	if block == "" || (err == nil && metadata.InstalledAt.Before(start)) {
+		return
+	}
+	pm.appendInstallHistory(block, record)
+}
+
+// recordUninstall appends the removal of metadata's version, started at
+// start, to the history of its block.
+func (pm *PackageManager) recordUninstall(metadata *BlockMetadata, start time.Time, err error) {
+	record := InstallRecord{
+		Time:      start,
+		Operation: OperationUninstall,
+		Repo:      metadata.SourceRepo,
+		Version:   metadata.Version,
+		Duration:  time.Since(start),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	pm.appendInstallHistory(metadata.Name, record)
+}
+
+// blockFromRepo returns the loaded block installed from repo, or "".
+func (pm *PackageManager) blockFromRepo(repo string) string {
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	for name, metadata := range pm.loadedBlocks {
+		if metadata.SourceRepo == repo || metadata.RedirectedFrom == repo {
+			return name
+		}
+	}
+	return ""
+}
+
+// appendInstallHistory appends record to the history of block. Failing to
+// write it is only a warning: the audit trail never fails an operation.
+func (pm *PackageManager) appendInstallHistory(block string, record InstallRecord) {
+	if pm.readOnly {
+		return
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	path := pm.installHistoryPath(block)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		pm.log().Warn("failed to record install history", "block", block, "error", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		pm.log().Warn("failed to record install history", "block", block, "error", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		pm.log().Warn("failed to record install history", "block", block, "error", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	// installLogFile collects the output of a block's install hooks.
+	installLogFile = "install.log"
+	// blockDataDir is where hooks store what the binary needs at runtime,
+	// such as downloaded models or configuration.
+	blockDataDir = "data"
+	// hookTimeout bounds each hook command.
+	hookTimeout = 5 * time.Minute
+)
+
+// Environment variables describing the block to its hooks.
+const (
+	EnvHookBlock   = "ATOMOS_BLOCK_NAME"
+	EnvHookVersion = "ATOMOS_BLOCK_VERSION"
+	EnvHookBinary  = "ATOMOS_BLOCK_BINARY"
+	EnvHookDataDir = "ATOMOS_BLOCK_DATA_DIR"
+)
+
+// hookEnvPassthrough lists the only variables hooks inherit from the
+// package manager, so tokens such as GITHUB_TOKEN never reach them.
+var hookEnvPassthrough = []string{"PATH", "HOME", "TMPDIR", "LANG", "SYSTEMROOT", "USERPROFILE", "TEMP", "TMP"}
+
+// runPostInstallHooks runs the post_install commands of a block in order,
+// from its version bin directory, with a minimal environment. Their output
+// is appended to <block>/install.log; the first failing command fails the
+// install.
+func (pm *PackageManager) runPostInstallHooks(ctx context.Context, metadata *BlockMetadata) error {
+	if len(metadata.PostInstall) == 0 {
+		return nil
+	}
+
+	blockDir := filepath.Join(pm.InstallDir, metadata.Name)
+	dataDir := filepath.Join(blockDir, blockDataDir)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	logPath := filepath.Join(blockDir, installLogFile)
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open install log: %w", err)
+	}
+	defer log.Close()
+
+	env := []string{
+		EnvHookBlock + "=" + metadata.Name,
+		EnvHookVersion + "=" + metadata.Version,
+		EnvHookBinary + "=" + metadata.BinaryPath,
+		EnvHookDataDir + "=" + dataDir,
+	}
+	for _, name := range hookEnvPassthrough {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	for _, command := range metadata.PostInstall {
+		fmt.Fprintf(log, "=== %s post_install %s %s: %s\n", time.Now().Format(time.RFC3339), metadata.Name, metadata.Version, command)
+
+		hookCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+		cmd := hookCommand(hookCtx, command)
+		cmd.Dir = filepath.Dir(metadata.BinaryPath)
+		cmd.Env = env
+		cmd.Stdout = log
+		cmd.Stderr = log
+		err := cmd.Run()
+		cancel()
+
+		if err != nil {
+			fmt.Fprintf(log, "=== failed: %v\n", err)
+			return fmt.Errorf("post_install hook %q of block '%s' failed: %w (see %s)", command, metadata.Name, err, logPath)
+		}
+	}
+
+	return nil
+}
+
+// hookCommand runs a hook through the platform shell.
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
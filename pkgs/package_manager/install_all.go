@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultInstallWorkers is the worker pool size used by InstallAll when
+// none is given.
+const DefaultInstallWorkers = 4
+
+// InstallOutcome is the outcome of one request of InstallAll.
+type InstallOutcome struct {
+	Request  InstallRequest
+	Metadata *BlockMetadata
+	Err      error
+}
+
+// Result summarizes the outcome as an InstallResult, including the
+// deprecation notice of the installed block.
+func (o InstallOutcome) Result() InstallResult {
+	if o.Err != nil {
+		return InstallResult{Message: o.Err.Error()}
+	}
+
+	return InstallResult{
+		Success:    true,
+		Message:    fmt.Sprintf("installed %s %s", o.Metadata.Name, o.Metadata.Version),
+		BinaryPath: o.Metadata.BinaryPath,
+		Blockname:  o.Metadata.Name,
+		Version:    o.Metadata.Version,
+		Deprecated: o.Metadata.Deprecated,
+	}
+}
+
+// InstallAll installs several blocks concurrently with a pool of workers
+// (DefaultInstallWorkers when workers <= 0) and returns one result per
+// request, in request order. Requests for the same repository are installed
+// one after another by a single worker, so they never write the same block
+// directory at once, and identical requests are installed once and share
+// their result. The returned error joins every failure.
+func (pm *PackageManager) InstallAll(ctx context.Context, reqs []InstallRequest, workers int) ([]InstallOutcome, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if workers <= 0 {
+		workers = DefaultInstallWorkers
+	}
+
+	results := make([]InstallOutcome, len(reqs))
+	first := map[InstallRequest]int{} // Index of the first occurrence of each request
+	var groups [][]int                // Indexes of the distinct requests of each repository
+	group := map[string]int{}
+	for i, req := range reqs {
+		if _, seen := first[req]; seen {
+			continue
+		}
+		first[req] = i
+		g, ok := group[req.Repo]
+		if !ok {
+			g = len(groups)
+			group[req.Repo] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(workers, len(groups)) {
+		wg.Go(func() {
+			for indexes := range jobs {
+				for _, i := range indexes {
+					metadata, err := pm.install(ctx, reqs[i])
+					results[i] = InstallOutcome{Request: reqs[i], Metadata: metadata, Err: err}
+				}
+			}
+		})
+	}
+	for _, indexes := range groups {
+		jobs <- indexes
+	}
+	close(jobs)
+	wg.Wait()
+
+	var errs []error
+	for i, req := range reqs {
+		if j := first[req]; j != i {
+			results[i] = InstallOutcome{Request: req, Metadata: results[j].Metadata, Err: results[j].Err}
+			continue
+		}
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("failed to install '%s': %w", req.Repo, results[i].Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// localRepoPrefix marks InstallRequest.Repo values pointing at a block
+	// checked out on disk, e.g. "file:///home/me/blocks/my-block".
+	localRepoPrefix = "file://"
+
+	// localVersion is recorded when neither the request nor the manifest
+	// names a version.
+	localVersion = "local"
+)
+
+// parseLocalRepo returns the block directory of a file:// repository.
+func parseLocalRepo(repo string) (string, bool) {
+	dir, ok := strings.CutPrefix(repo, localRepoPrefix)
+	if !ok {
+		return "", false
+	}
+	return filepath.FromSlash(dir), true
+}
+
+// installFromLocal installs a block from a directory on disk without any
+// network call: the manifest is read from the directory and the binary for
+// the current platform, given relative to it, is copied into the install dir.
+func (pm *PackageManager) installFromLocal(ctx context.Context, req InstallRequest, dir string) (*BlockMetadata, error) {
+	blockInfo, err := readLocalBlockInfo(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block info: %w", err)
+	}
+	if err := applyAlias(req, blockInfo); err != nil {
+		return nil, err
+	}
+	if err := pm.checkNameConflict(req, blockInfo.Name, req.Repo); err != nil {
+		return nil, err
+	}
+
+	if !req.Force {
+		if metadata, ok, err := pm.cachedBlock(blockInfo.Name); err != nil || ok {
+			return metadata, err
+		}
+	}
+
+	version := localBlockVersion(blockInfo, req.Version)
+	pm.applyRegistryStatus(ctx, blockInfo, req.Repo)
+	if err := pm.checkYanked(req, blockInfo, version); err != nil {
+		return nil, err
+	}
+	if err := pm.checkRequirements(ctx, req, blockInfo.Name, blockInfo.Requires); err != nil {
+		return nil, err
+	}
+
+	var binaryPath, digest string
+	inferred, assetErr := pm.resolvePlatformAsset(blockInfo, listLocalAssets(dir), req.ProbeAssets)
+	pending := pm.startDataAssets(ctx, blockInfo, localDataFetcher(dir))
+	if assetErr != nil {
+		binaryPath, digest, err = pm.buildFallback(ctx, req, blockInfo, version, assetErr, localCheckout(dir))
+	} else if binaryPath, digest, err = pm.copyLocalBinary(ctx, dir, blockInfo, version); err != nil {
+		err = fmt.Errorf("failed to copy binary: %w", err)
+	}
+	dataFiles, dataErr := pending.wait()
+	if err := errors.Join(err, dataErr); err != nil {
+		return nil, err
+	}
+
+	metadata := &BlockMetadata{
+		Name:            blockInfo.Name,
+		Description:     blockInfo.Description,
+		Version:         version,
+		SourceRepo:      req.Repo,
+		BinaryPath:      binaryPath,
+		SHA256:          digest,
+		InstalledAt:     time.Now(),
+		LastUpdated:     time.Now(),
+		IsActive:        true,
+		LSPEntries:      convertEntriesToMap(blockInfo.Entries),
+		InferredAsset:   inferred,
+		PostInstall:     blockInfo.Hooks.PostInstall,
+		BuiltFromSource: assetErr != nil,
+		DataFiles:       dataFiles,
+	}
+	pm.markStatus(metadata, blockInfo)
+
+	return pm.commitInstall(ctx, metadata)
+}
+
+// localBlockVersion returns the version a local block is installed as: the
+// requested one, else the manifest's, else "local".
+func localBlockVersion(blockInfo *BlockInfo, requested string) string {
+	version := requested
+	if !isExactVersion(version) {
+		version = blockInfo.Version
+	}
+	if version == "" {
+		version = localVersion
+	}
+	return version
+}
+
+// listLocalAssets lists the files of a block directory, which stand in for
+// release assets.
+func listLocalAssets(dir string) func() ([]string, error) {
+	return func() ([]string, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		return names, nil
+	}
+}
+
+func readLocalBlockInfo(dir string) (*BlockInfo, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "agentic_support.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	blockInfo, err := parseBlockInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockInfo, nil
+}
+
+// copyLocalBinary copies the platform binary into <block>/bin/<version>, verifying it
+// against the manifest's checksums like a downloaded asset.
+func (pm *PackageManager) copyLocalBinary(ctx context.Context, dir string, blockInfo *BlockInfo, version string) (string, string, error) {
+	if IsImageBlock(blockInfo) {
+		return pm.pullImage(ctx, blockInfo, version)
+	}
+
+	return pm.copyLocalPlatformBinary(ctx, dir, blockInfo, hostPlatform(), pm.versionBinDir(blockInfo.Name, version))
+}
+
+// copyLocalPlatformBinary copies and verifies the binary of platform into
+// binDir, returning its path and SHA256 digest.
+func (pm *PackageManager) copyLocalPlatformBinary(ctx context.Context, dir string, blockInfo *BlockInfo, platform, binDir string) (string, string, error) {
+	assetPath, err := assetForPlatform(blockInfo, platform)
+	if err != nil {
+		return "", "", err
+	}
+	if !filepath.IsAbs(assetPath) {
+		assetPath = filepath.Join(dir, assetPath)
+	}
+
+	src, err := os.Open(assetPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open local binary: %w", err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	binaryName := filepath.Base(assetPath)
+	localPath := filepath.Join(binDir, executableName(binaryName, platform))
+
+	dst, err := os.Create(localPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create local file: %w", err)
+	}
+	defer dst.Close()
+
+	hasher := sha256.New()
+	total := int64(-1)
+	if info, err := src.Stat(); err == nil {
+		total = info.Size()
+	}
+	if _, err := io.Copy(pm.countDownload(ctx, io.MultiWriter(dst, hasher), 0, total), src); err != nil {
+		return "", "", fmt.Errorf("failed to write binary: %w", err)
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	pm.log().Debug("copied local binary", "from", assetPath, "to", dst.Name(), "sha256", digest)
+
+	fetch := func(name string) ([]byte, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		return os.ReadFile(name)
+	}
+	list := func() []string {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil
+		}
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		return names
+	}
+	if err := checkDigest(blockInfo, platform, binaryName, digest, fetch, list); err != nil {
+		_ = os.Remove(localPath)
+		return "", "", err
+	}
+
+	if err := makeExecutable(localPath, platform); err != nil {
+		return "", "", fmt.Errorf("failed to make binary executable: %w", err)
+	}
+
+	return localPath, digest, nil
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	lockFileName  = ".atomos.lock"
+	fenceFileName = ".atomos.fence"
+)
+
+// Locker serializes mutations of an install directory that may be shared by
+// several hosts (e.g. over NFS). Lock returns a fencing token that increases
+// on every acquisition; Validate reports an error when the token no longer
+// owns the lock, so a holder whose lease expired cannot corrupt state.
+type Locker interface {
+	Lock(ctx context.Context) (uint64, error)
+	Validate(token uint64) error
+	Unlock(token uint64) error
+}
+
+// Renewer is implemented by Lockers whose leases expire. While it holds the
+// lock, the package manager calls Renew every RenewInterval so operations
+// longer than the lease, e.g. builds from source, keep it.
+type Renewer interface {
+	Renew(token uint64) error
+	RenewInterval() time.Duration
+}
+
+// FileLocker is a Locker backed by lock files inside the install directory.
+// Acquisition relies on exclusive file creation, which is atomic on local
+// filesystems and on NFSv3+.
+type FileLocker struct {
+	Dir          string
+	TTL          time.Duration // Locks not renewed for this long are considered stale and are broken
+	Timeout      time.Duration // How long Lock waits before giving up
+	PollInterval time.Duration
+}
+
+type lockInfo struct {
+	Token      uint64    `json:"token"`
+	Host       string    `json:"host"`
+	PID        int       `json:"pid"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// NewFileLocker creates a FileLocker for dir with conservative defaults.
+func NewFileLocker(dir string) *FileLocker {
+	return &FileLocker{
+		Dir:          dir,
+		TTL:          10 * time.Minute,
+		Timeout:      2 * time.Minute,
+		PollInterval: 500 * time.Millisecond,
+	}
+}
+
+// SetLocker replaces the lock guarding the install directory during every
+// mutating operation. NewPackageManager uses a FileLocker on the install
+// directory; passing nil disables cross-process locking.
+func (pm *PackageManager) SetLocker(locker Locker) {
+	pm.locker = locker
+}
+
+// Lock acquires the lock file, breaking it if its holder exceeded the TTL.
+func (fl *FileLocker) Lock(ctx context.Context) (uint64, error) {
+	if err := os.MkdirAll(fl.Dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	deadline := time.Now().Add(fl.Timeout)
+	lockPath := filepath.Join(fl.Dir, lockFileName)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			token, tokenErr := fl.nextToken()
+			if tokenErr == nil {
+				tokenErr = writeLockInfo(file, token)
+			}
+			file.Close()
+			if tokenErr != nil {
+				_ = os.Remove(lockPath)
+				return 0, tokenErr
+			}
+			return token, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return 0, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if fl.isStale(lockPath) {
+			_ = os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("timed out waiting for install dir lock %s", lockPath)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("waiting for install dir lock: %w", ctx.Err())
+		case <-time.After(fl.PollInterval):
+		}
+	}
+}
+
+// Validate checks that token still owns the lock.
+func (fl *FileLocker) Validate(token uint64) error {
+	info, err := readLockInfo(filepath.Join(fl.Dir, lockFileName))
+	if err != nil {
+		return fmt.Errorf("lock lost: %w", err)
+	}
+	if info.Token != token {
+		return fmt.Errorf("lock lost: fencing token %d superseded by %d", token, info.Token)
+	}
+	return nil
+}
+
+// Unlock releases the lock if token still owns it.
+func (fl *FileLocker) Unlock(token uint64) error {
+	if err := fl.Validate(token); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(fl.Dir, lockFileName)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// Renew extends the lease of token by touching the lock file.
+func (fl *FileLocker) Renew(token uint64) error {
+	if err := fl.Validate(token); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := os.Chtimes(filepath.Join(fl.Dir, lockFileName), now, now); err != nil {
+		return fmt.Errorf("failed to renew lock: %w", err)
+	}
+	return nil
+}
+
+// RenewInterval renews the lease three times per TTL.
+func (fl *FileLocker) RenewInterval() time.Duration {
+	return fl.TTL / 3
+}
+
+// nextToken increments the persistent fencing counter.
+func (fl *FileLocker) nextToken() (uint64, error) {
+	fencePath := filepath.Join(fl.Dir, fenceFileName)
+
+	var current uint64
+	if data, err := os.ReadFile(fencePath); err == nil {
+		current, _ = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	}
+
+	next := current + 1
+	if err := os.WriteFile(fencePath, []byte(strconv.FormatUint(next, 10)), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write fencing token: %w", err)
+	}
+	return next, nil
+}
+
+func (fl *FileLocker) isStale(lockPath string) bool {
+	stat, statErr := os.Stat(lockPath)
+	info, err := readLockInfo(lockPath)
+	if err != nil {
+		// A lock file that can't be parsed is either being written right now
+		// or was left behind by a crash; fall back to its modification time.
+		return statErr == nil && time.Since(stat.ModTime()) > fl.TTL
+	}
+	// A holder on this host that exited without unlocking, e.g. because it
+	// crashed, doesn't need to wait for the TTL.
+	if host, _ := os.Hostname(); info.Host == host && info.PID != os.Getpid() && !processAlive(info.PID) {
+		return true
+	}
+	// Renewals touch the lock file, so the lease runs from the later of the
+	// acquisition and the last renewal.
+	renewed := info.AcquiredAt
+	if statErr == nil && stat.ModTime().After(renewed) {
+		renewed = stat.ModTime()
+	}
+	return time.Since(renewed) > fl.TTL
+}
+
+func writeLockInfo(file *os.File, token uint64) error {
+	host, _ := os.Hostname()
+	info := lockInfo{Token: token, Host: host, PID: os.Getpid(), AcquiredAt: time.Now()}
+	if err := json.NewEncoder(file).Encode(info); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return nil
+}
+
+func readLockInfo(lockPath string) (*lockInfo, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var info lockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return &info, nil
+}
+
+// acquireLock serializes the mutating operations of this process, then locks
+// the install dir against other processes if a Locker is configured. The
+// returned release function is always safe to call.
+func (pm *PackageManager) acquireLock(ctx context.Context) (func(), error) {
+	if pm.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	pm.opMu.Lock()
+	if err := ctx.Err(); err != nil {
+		pm.opMu.Unlock()
+		return nil, err
+	}
+	if pm.locker == nil {
+		return pm.opMu.Unlock, nil
+	}
+
+	token, err := pm.locker.Lock(ctx)
+	if err != nil {
+		pm.opMu.Unlock()
+		return nil, fmt.Errorf("failed to lock install dir: %w", err)
+	}
+	pm.fence = token
+	stopRenewing := pm.renewLock(token)
+
+	return func() {
+		stopRenewing()
+		if err := pm.locker.Unlock(token); err != nil {
+			pm.log().Warn("failed to release install dir lock", "error", err)
+		}
+		pm.fence = 0
+		pm.opMu.Unlock()
+	}, nil
+}
+
+// renewLock keeps renewing the lease of token while the lock is held, if the
+// locker's leases expire. The returned function stops it.
+func (pm *PackageManager) renewLock(token uint64) func() {
+	renewer, ok := pm.locker.(Renewer)
+	if !ok || renewer.RenewInterval() <= 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(renewer.RenewInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := renewer.Renew(token); err != nil {
+					// The operation notices the loss at its next checkFence.
+					pm.log().Warn("failed to renew install dir lock", "error", err)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// checkFence fails when the lock held by this package manager was broken by
+// another host, preventing writes based on a stale view of the install dir.
+func (pm *PackageManager) checkFence() error {
+	if pm.locker == nil {
+		return nil
+	}
+	return pm.locker.Validate(pm.fence)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import "log/slog"
+
+// SetLogger sets the logger receiving the package manager's warnings and
+// notices, and at debug level its GitHub requests, cache hits, and file
+// operations. Passing nil restores slog.Default().
+func (pm *PackageManager) SetLogger(logger *slog.Logger) {
+	pm.logger = logger
+}
+
+// log returns the configured logger, or slog.Default() when none is set.
+func (pm *PackageManager) log() *slog.Logger {
+	if pm.logger == nil {
+		return slog.Default()
+	}
+	return pm.logger
+}
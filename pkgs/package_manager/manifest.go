@@ -0,0 +1,294 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFile is the block manifest at the root of a block repository.
+const manifestFile = "agentic_support.yaml"
+
+// envNamePattern matches the variable names a sandbox profile may list.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Manifest issue codes reported in a ManifestError.
+const (
+	CodeManifestSyntax    = "yaml-syntax"
+	CodeMissingField      = "missing-field"
+	CodeInvalidField      = "invalid-field"
+	CodeUnknownPlatform   = "unknown-platform"
+	CodeDuplicateEntry    = "duplicate-entry"
+	CodeEmptyCommand      = "empty-command"
+	CodeIncompleteBuild   = "incomplete-build"
+	CodeMissingEntryField = "missing-entry-field"
+)
+
+// Operating systems and architectures accepted in binary.assets keys.
+var (
+	knownOS   = []string{"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js", "linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows"}
+	knownArch = []string{"386", "amd64", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle", "ppc64", "ppc64le", "riscv64", "s390x", "wasm"}
+)
+
+// ManifestIssue is a single problem found in a block manifest, positioned
+// so it can be fixed in the YAML.
+type ManifestIssue struct {
+	Code    string
+	Field   string // Dotted path of the offending field, e.g. "entries[1].name"
+	Message string
+	Line    int
+	Column  int
+}
+
+func (i ManifestIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s [%s]", i.Line, i.Column, i.Field, i.Message, i.Code)
+}
+
+// ManifestError is returned by installs whose block manifest is invalid,
+// before anything is downloaded. It lists every issue found.
+type ManifestError struct {
+	Issues []ManifestIssue
+}
+
+func (e *ManifestError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid %s: %s", manifestFile, strings.Join(issues, "; "))
+}
+
+// manifestLine extracts the position from yaml.v3 error messages.
+var manifestLine = regexp.MustCompile(`line (\d+)`)
+
+// parseBlockInfo unmarshals and validates a block manifest. Invalid
+// manifests are reported as a *ManifestError.
+func parseBlockInfo(data []byte) (*BlockInfo, error) {
+	var root yaml.Node
+	var blockInfo BlockInfo
+	err := yaml.Unmarshal(data, &root)
+	if err == nil {
+		err = root.Decode(&blockInfo)
+	}
+	if err != nil {
+		issue := ManifestIssue{Code: CodeManifestSyntax, Message: err.Error()}
+		if m := manifestLine.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		return nil, &ManifestError{Issues: []ManifestIssue{issue}}
+	}
+
+	doc := &root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	if issues := validateBlockInfo(&blockInfo, doc); len(issues) > 0 {
+		return nil, &ManifestError{Issues: issues}
+	}
+
+	return &blockInfo, nil
+}
+
+// manifestChecker collects the issues of a manifest.
+type manifestChecker struct {
+	issues []ManifestIssue
+}
+
+func (c *manifestChecker) report(code string, node *yaml.Node, field, message string) {
+	issue := ManifestIssue{Code: code, Field: field, Message: message}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	c.issues = append(c.issues, issue)
+}
+
+// validateBlockInfo checks a decoded manifest against its YAML tree, used
+// to position the issues.
+func validateBlockInfo(blockInfo *BlockInfo, root *yaml.Node) []ManifestIssue {
+	c := &manifestChecker{}
+
+	if strings.TrimSpace(blockInfo.Name) == "" {
+		c.report(CodeMissingField, manifestNode(root, "name"), "name", "name is required")
+	} else if blockInfo.Name == shimDirName {
+		c.report(CodeInvalidField, manifestNode(root, "name"), "name", fmt.Sprintf("name '%s' is reserved for the shim directory", shimDirName))
+	}
+
+	binary := manifestNode(root, "binary")
+	switch blockInfo.Binary.From {
+	case "", "release":
+		if len(blockInfo.Binary.Assets) == 0 && blockInfo.Build.Command == "" {
+			c.report(CodeMissingField, binary, "binary.assets", "binary.assets is required unless the block has a build command")
+		}
+	case BinaryFromDocker:
+		if blockInfo.Binary.Image == "" {
+			c.report(CodeMissingField, binary, "binary.image", "binary.image is required for docker blocks")
+		}
+	default:
+		c.report(CodeInvalidField, manifestNode(binary, "from"), "binary.from", fmt.Sprintf("unknown binary source '%s', expected release or docker", blockInfo.Binary.From))
+	}
+
+	if assets := manifestNode(binary, "assets"); len(blockInfo.Binary.Assets) > 0 && assets != binary && assets.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(assets.Content); i += 2 {
+			key, value := assets.Content[i], assets.Content[i+1]
+			if !knownPlatform(key.Value) {
+				c.report(CodeUnknownPlatform, key, "binary.assets."+key.Value, fmt.Sprintf("unknown platform '%s', expected <os>-<arch> (e.g. linux-amd64), %s, or %s", key.Value, WasmAssetKey, AnyPlatformKey))
+			}
+			if strings.TrimSpace(value.Value) == "" {
+				c.report(CodeMissingField, value, "binary.assets."+key.Value, "asset name is empty")
+			} else if isAssetPattern(value.Value) {
+				if _, err := compileAssetPattern(value.Value, hostPlatform()); err != nil {
+					c.report(CodeInvalidField, value, "binary.assets."+key.Value, fmt.Sprintf("invalid asset pattern: %v", err))
+				}
+			}
+		}
+	}
+
+	if patch := blockInfo.Binary.Patch; patch != "" && !strings.Contains(patch, "{from}") {
+		c.report(CodeInvalidField, manifestNode(binary, "patch"), "binary.patch", "binary.patch must contain {from}, the version the patch applies to")
+	}
+
+	build := manifestNode(root, "build")
+	switch {
+	case blockInfo.Build.Command == "" && blockInfo.Build.Output != "":
+		c.report(CodeIncompleteBuild, build, "build.command", "build.output is set but build.command is empty")
+	case blockInfo.Build.Command != "" && blockInfo.Build.Output == "":
+		c.report(CodeIncompleteBuild, build, "build.output", "build.command is set but build.output is empty")
+	}
+
+	hooks := manifestNode(manifestNode(root, "hooks"), "post_install")
+	for i, command := range blockInfo.Hooks.PostInstall {
+		if strings.TrimSpace(command) == "" {
+			c.report(CodeEmptyCommand, manifestItem(hooks, i), fmt.Sprintf("hooks.post_install[%d]", i), "post_install command is empty")
+		}
+	}
+
+	if check := blockInfo.HealthCheck; check != nil && check.Expect != "" {
+		if _, err := regexp.Compile(check.Expect); err != nil {
+			c.report(CodeInvalidField, manifestNode(manifestNode(root, "healthcheck"), "expect"), "healthcheck.expect", fmt.Sprintf("invalid pattern: %v", err))
+		}
+	}
+
+	requires := manifestNode(root, "requires")
+	for i, raw := range blockInfo.Requires {
+		if _, err := ParseRequirement(raw); err != nil {
+			c.report(CodeInvalidField, manifestItem(requires, i), fmt.Sprintf("requires[%d]", i), err.Error())
+		}
+	}
+
+	if sandbox := blockInfo.Sandbox; sandbox != nil {
+		node := manifestNode(root, "sandbox")
+		network := manifestNode(node, "network")
+		for i, host := range sandbox.Network {
+			if strings.TrimSpace(host) == "" || strings.Contains(host, "://") || strings.ContainsAny(host, " /") {
+				c.report(CodeInvalidField, manifestItem(network, i), fmt.Sprintf("sandbox.network[%d]", i), fmt.Sprintf("'%s' is not a host: use \"api.example.com\", \"*.example.com\", \"host:port\", or \"*\"", host))
+			}
+		}
+		writable := manifestNode(node, "writable")
+		for i, path := range sandbox.Writable {
+			if path != "~" && !strings.HasPrefix(path, "~/") && !filepath.IsAbs(path) {
+				c.report(CodeInvalidField, manifestItem(writable, i), fmt.Sprintf("sandbox.writable[%d]", i), fmt.Sprintf("writable path '%s' must be absolute or start with ~/", path))
+			}
+		}
+		env := manifestNode(node, "env")
+		for i, name := range sandbox.Env {
+			if !envNamePattern.MatchString(name) {
+				c.report(CodeInvalidField, manifestItem(env, i), fmt.Sprintf("sandbox.env[%d]", i), fmt.Sprintf("'%s' is not an environment variable name", name))
+			}
+		}
+	}
+
+	data := manifestNode(root, "data")
+	dataNames := map[string]int{}
+	for i, asset := range blockInfo.Data {
+		node := manifestItem(data, i)
+		field := fmt.Sprintf("data[%d]", i)
+		if strings.TrimSpace(asset.Name) == "" {
+			c.report(CodeMissingField, node, field+".name", "data asset name is required")
+		} else if first, ok := dataNames[asset.Name]; ok {
+			c.report(CodeInvalidField, manifestNode(node, "name"), field+".name", fmt.Sprintf("data asset '%s' is already defined by data[%d]", asset.Name, first))
+		} else {
+			dataNames[asset.Name] = i
+		}
+		if strings.TrimSpace(asset.Asset) == "" {
+			c.report(CodeMissingField, node, field+".asset", "data asset is required")
+		}
+		if asset.Path != "" && !filepath.IsLocal(filepath.FromSlash(asset.Path)) {
+			c.report(CodeInvalidField, manifestNode(node, "path"), field+".path", "data path must stay inside the block's data directory")
+		}
+	}
+
+	entries := manifestNode(root, "entries")
+	seen := map[string]int{}
+	for i, entry := range blockInfo.Entries {
+		node := manifestItem(entries, i)
+		field := fmt.Sprintf("entries[%d]", i)
+		if strings.TrimSpace(entry.Name) == "" {
+			c.report(CodeMissingEntryField, node, field+".name", "entry name is required")
+			continue
+		}
+		if first, ok := seen[entry.Name]; ok {
+			c.report(CodeDuplicateEntry, manifestNode(node, "name"), field+".name", fmt.Sprintf("entry '%s' is already defined by entries[%d]", entry.Name, first))
+			continue
+		}
+		seen[entry.Name] = i
+
+		for j, input := range entry.Inputs {
+			if input.Name == "" {
+				c.report(CodeMissingEntryField, manifestItem(manifestNode(node, "inputs"), j), fmt.Sprintf("%s.inputs[%d].name", field, j), fmt.Sprintf("input of entry '%s' has no name", entry.Name))
+			}
+		}
+		for j, output := range entry.Outputs {
+			if output.Name == "" {
+				c.report(CodeMissingEntryField, manifestItem(manifestNode(node, "outputs"), j), fmt.Sprintf("%s.outputs[%d].name", field, j), fmt.Sprintf("output of entry '%s' has no name", entry.Name))
+			}
+		}
+	}
+
+	return c.issues
+}
+
+// knownPlatform reports whether key is a valid binary.assets key.
+func knownPlatform(key string) bool {
+	if key == WasmAssetKey || key == AnyPlatformKey {
+		return true
+	}
+	goos, goarch, ok := strings.Cut(key, "-")
+	return ok && slices.Contains(knownOS, goos) && slices.Contains(knownArch, goarch)
+}
+
+// manifestNode returns the value node of key in a mapping, or the mapping
+// itself when the key is absent so issues still point nearby.
+func manifestNode(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return node
+}
+
+// manifestItem returns the i-th element of a sequence node, or the node
+// itself when it has no such element.
+func manifestItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return node
+	}
+	return node.Content[i]
+}
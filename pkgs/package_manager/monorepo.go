@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// monorepoSeparator separates a GitHub repository from the directory
+// holding a block's manifest, e.g. "owner/repo//tools/profiler", so several
+// blocks can live in one repository.
+const monorepoSeparator = "//"
+
+// blockSelector picks one block of a repository exposing several, e.g.
+// "owner/repo#profiler", whose manifest is agentic_support/profiler.yaml.
+const blockSelector = "#"
+
+// multiBlockDir holds the manifests of a repository exposing several
+// blocks, one <block>.yaml per block.
+const multiBlockDir = "agentic_support"
+
+// splitRepoPath splits GitHub coordinates into the repository and the
+// directory of the block within it, "" for the repository root. A block
+// selector is dropped (see selectedBlock).
+func splitRepoPath(source string) (string, string) {
+	source, _, _ = strings.Cut(source, blockSelector)
+	repo, dir, _ := strings.Cut(source, monorepoSeparator)
+	return repo, strings.Trim(dir, "/")
+}
+
+// selectedBlock returns the block source selects with "#name", "" when it
+// names the repository's only block.
+func selectedBlock(source string) string {
+	_, block, _ := strings.Cut(source, blockSelector)
+	return block
+}
+
+// checkRepoPath rejects block directories that would leave the repository
+// and block selectors that aren't plain names.
+func checkRepoPath(source string) error {
+	_, dir := splitRepoPath(source)
+	if dir != "" && !filepath.IsLocal(filepath.FromSlash(dir)) {
+		return fmt.Errorf("invalid block directory '%s' in %s: must be a path inside the repository", dir, source)
+	}
+	if _, block, ok := strings.Cut(source, blockSelector); ok {
+		if block == "" || block == "." || block == ".." || strings.ContainsAny(block, `/\#`) {
+			return fmt.Errorf("invalid block '%s' in %s: expected owner/repo#<block name>", block, source)
+		}
+	}
+	return nil
+}
+
+// manifestPath is the path of the manifest of the block at source within
+// its repository: agentic_support.yaml in the block's directory, or
+// agentic_support/<block>.yaml there for a selected block.
+func manifestPath(source string) string {
+	_, dir := splitRepoPath(source)
+	if block := selectedBlock(source); block != "" {
+		return path.Join(dir, multiBlockDir, block+".yaml")
+	}
+	return path.Join(dir, "agentic_support.yaml")
+}
+
+// inRepoDir runs the build of a block at repo from its directory, so a
+// monorepo block's build command and output are relative to its manifest.
+func inRepoDir(checkout sourceCheckout, repo string) sourceCheckout {
+	_, dir := splitRepoPath(repo)
+	if dir == "" {
+		return checkout
+	}
+	return func(ctx context.Context) (string, func(), error) {
+		root, cleanup, err := checkout(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+		return filepath.Join(root, filepath.FromSlash(dir)), cleanup, nil
+	}
+}
+
+// ListRepoBlocks returns the names of the blocks a repository exposes in
+// its agentic_support/ directory, sorted, each installable as
+// "<source>#<name>". source may name a monorepo directory.
+func (pm *PackageManager) ListRepoBlocks(ctx context.Context, source string) ([]string, error) {
+	if err := checkRepoPath(source); err != nil {
+		return nil, err
+	}
+	repo, dir := splitRepoPath(pm.canonicalRepo(ctx, source))
+	listing := path.Join(dir, multiBlockDir)
+
+	status, body, err := pm.github().get(ctx, pm.githubAPI("/repos/%s/contents/%s", repo, listing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", listing, err)
+	}
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s not found in repository %s", listing, repo)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, pm.authError(ctx, repo, status, body)
+	default:
+		return nil, fmt.Errorf("GitHub API error %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	var entries []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("%s is not a directory of manifests: %w", listing, err)
+	}
+
+	var blocks []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name, ".yaml"); ok && entry.Type == "file" {
+			blocks = append(blocks, name)
+		}
+	}
+	slices.Sort(blocks)
+	return blocks, nil
+}
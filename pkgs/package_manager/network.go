@@ -0,0 +1,230 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+// NetworkConfig routes the package manager's traffic for air-gapped or
+// corporate environments. Empty fields fall back to the ATOMOS_PROXY and
+// ATOMOS_MIRROR environment variables, then to the defaults.
+type NetworkConfig struct {
+	// ProxyURL is the HTTP(S) proxy every request goes through. When unset,
+	// the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY variables apply.
+	ProxyURL string
+	// MirrorURL replaces https://api.github.com as the base of GitHub API
+	// calls and release asset downloads, e.g. an internal mirror serving
+	// the same paths. The GitHub token is sent to the mirror.
+	MirrorURL string
+}
+
+// GitHubConfig points the package manager at a GitHub Enterprise Server
+// instead of github.com. Empty fields fall back to the
+// ATOMOS_GITHUB_API_URL and ATOMOS_GITHUB_RAW_URL environment variables.
+type GitHubConfig struct {
+	// APIURL is the base of the REST API, e.g.
+	// https://github.example.com/api/v3.
+	APIURL string
+	// RawURL, when set, serves repository files such as
+	// agentic_support.yaml at <RawURL>/<owner>/<repo>/<ref>/<path>, e.g.
+	// https://github.example.com/raw. Files are read through the contents
+	// API otherwise.
+	RawURL string
+}
+
+// SetGitHubConfig configures the GitHub instance later installs use. A
+// mirror set with SetNetworkConfig still takes precedence for API calls.
+func (pm *PackageManager) SetGitHubConfig(cfg GitHubConfig) error {
+	for _, u := range []string{cfg.APIURL, cfg.RawURL} {
+		if err := validateNetworkURL(u); err != nil {
+			return err
+		}
+	}
+
+	pm.githubConfig = cfg
+	return nil
+}
+
+// githubMirror returns the configured mirror standing in for the GitHub
+// API, or "" when requests go to GitHub itself.
+func (pm *PackageManager) githubMirror() string {
+	if pm.network.MirrorURL != "" {
+		return pm.network.MirrorURL
+	}
+	if mirror := os.Getenv("ATOMOS_MIRROR"); mirror != "" && pm.validEnvURL("ATOMOS_MIRROR", mirror) {
+		return mirror
+	}
+	return ""
+}
+
+// githubRawURL returns the configured raw content base, or "" to read files
+// through the contents API.
+func (pm *PackageManager) githubRawURL() string {
+	raw := pm.githubConfig.RawURL
+	if raw == "" {
+		raw = os.Getenv("ATOMOS_GITHUB_RAW_URL")
+		if raw != "" && !pm.validEnvURL("ATOMOS_GITHUB_RAW_URL", raw) {
+			return ""
+		}
+	}
+	return strings.TrimSuffix(raw, "/")
+}
+
+// SetNetworkConfig configures the proxy and mirror used by later installs.
+func (pm *PackageManager) SetNetworkConfig(cfg NetworkConfig) error {
+	for _, u := range []string{cfg.ProxyURL, cfg.MirrorURL} {
+		if err := validateNetworkURL(u); err != nil {
+			return err
+		}
+	}
+
+	pm.network = cfg
+	return nil
+}
+
+// validEnvURL reports whether the URL taken from the environment variable
+// name is usable, warning when it isn't.
+func (pm *PackageManager) validEnvURL(name, raw string) bool {
+	if err := validateNetworkURL(raw); err != nil {
+		pm.log().Warn("ignoring "+name, "error", err)
+		return false
+	}
+	return true
+}
+
+// validateNetworkURL accepts empty or absolute http(s) URLs.
+func validateNetworkURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: expected an http(s) URL", raw)
+	}
+	return nil
+}
+
+// proxyURL returns the configured proxy, if any.
+func (pm *PackageManager) proxyURL() string {
+	if pm.network.ProxyURL != "" {
+		return pm.network.ProxyURL
+	}
+	return os.Getenv("ATOMOS_PROXY")
+}
+
+// HTTPConfig lets embedders control how the package manager talks to
+// GitHub, GitLab, and download hosts, e.g. to add corporate TLS roots,
+// tracing, or their own retry budget. Zero fields keep the defaults.
+type HTTPConfig struct {
+	// Client sends every request as is: neither Transport nor the proxy
+	// of NetworkConfig apply to it.
+	Client *http.Client
+	// Transport replaces http.DefaultTransport under the package manager's
+	// own client. The configured proxy is only applied to it when it's an
+	// *http.Transport.
+	Transport http.RoundTripper
+	// RequestTimeout bounds each API request whose context has no deadline,
+	// 30s by default. Downloads are only bounded until their response
+	// headers arrive, however long the body takes.
+	RequestTimeout time.Duration
+	Retry          RetryPolicy
+}
+
+// RetryPolicy controls retries of transient failures: dropped connections,
+// and 502, 503, and 504 answers of GitHub API calls. Interrupted downloads
+// are resumed rather than restarted.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per request, the first included; 4 by default, 1 disables retries
+	Delay       time.Duration // Wait before the first retry, doubled after each; 1s by default
+}
+
+// SetHTTPConfig configures the HTTP client, timeout, and retries of later
+// requests.
+func (pm *PackageManager) SetHTTPConfig(cfg HTTPConfig) error {
+	if cfg.RequestTimeout < 0 || cfg.Retry.MaxAttempts < 0 || cfg.Retry.Delay < 0 {
+		return fmt.Errorf("invalid HTTP config: timeouts, attempts, and delays can't be negative")
+	}
+
+	pm.http = cfg
+	return nil
+}
+
+// requestTimeout returns the timeout of requests without a deadline.
+func (pm *PackageManager) requestTimeout() time.Duration {
+	return cmp.Or(pm.http.RequestTimeout, defaultRequestTimeout)
+}
+
+// retryPolicy returns the retry policy with defaults filled in.
+func (pm *PackageManager) retryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: cmp.Or(pm.http.Retry.MaxAttempts, maxDownloadAttempts),
+		Delay:       cmp.Or(pm.http.Retry.Delay, downloadRetryDelay),
+	}
+}
+
+// httpClient returns the client used for every outgoing request.
+func (pm *PackageManager) httpClient() *http.Client {
+	if pm.http.Client != nil {
+		return pm.http.Client
+	}
+
+	transport := pm.http.Transport
+	raw := pm.proxyURL()
+	if raw == "" {
+		return &http.Client{Transport: transport}
+	}
+
+	proxy, err := url.Parse(raw)
+	if err != nil || validateNetworkURL(raw) != nil {
+		pm.log().Warn("ignoring invalid proxy URL", "proxy", raw)
+		return &http.Client{Transport: transport}
+	}
+
+	base, ok := transport.(*http.Transport)
+	if transport == nil {
+		base, ok = http.DefaultTransport.(*http.Transport), true
+	}
+	if !ok {
+		pm.log().Debug("not applying the proxy to a custom transport", "proxy", raw)
+		return &http.Client{Transport: transport}
+	}
+
+	proxied := base.Clone()
+	proxied.Proxy = http.ProxyURL(proxy)
+	return &http.Client{Transport: proxied}
+}
+
+// githubAPI formats a GitHub API path against the mirror, else the
+// configured GitHub Enterprise API, else api.github.com.
+func (pm *PackageManager) githubAPI(format string, args ...any) string {
+	base := githubAPIURL
+	if mirror := pm.githubMirror(); mirror != "" {
+		base = mirror
+	} else if pm.githubConfig.APIURL != "" {
+		base = pm.githubConfig.APIURL
+	} else if api := os.Getenv("ATOMOS_GITHUB_API_URL"); api != "" && pm.validEnvURL("ATOMOS_GITHUB_API_URL", api) {
+		base = api
+	}
+
+	return strings.TrimSuffix(base, "/") + fmt.Sprintf(format, args...)
+}
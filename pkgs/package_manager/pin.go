@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"fmt"
+)
+
+// Pin freezes a block at its active version: Update, UpdateAll and Sync
+// leave it alone until Unpin is called. The flag is stored in the metadata
+// of every installed version, so switching versions with Use or installing
+// one explicitly keeps the block pinned.
+func (pm *PackageManager) Pin(Blockname string) error {
+	return pm.setPinned(Blockname, true)
+}
+
+// Unpin lets Update, UpdateAll and Sync move a pinned block again.
+func (pm *PackageManager) Unpin(Blockname string) error {
+	return pm.setPinned(Blockname, false)
+}
+
+// IsPinned reports whether a block is pinned. Blocks that aren't installed
+// are not.
+func (pm *PackageManager) IsPinned(Blockname string) bool {
+	metadata, err := pm.getMetadata(Blockname)
+	return err == nil && metadata.Pinned
+}
+
+func (pm *PackageManager) setPinned(Blockname string, pinned bool) error {
+	if pm.readOnly {
+		return ErrReadOnly
+	}
+
+	versions, err := pm.ListVersions(Blockname)
+	if err != nil {
+		return err
+	}
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	for _, metadata := range versions {
+		if metadata.Pinned == pinned {
+			continue
+		}
+		metadata.Pinned = pinned
+		if err := pm.storeMetadata(metadata); err != nil {
+			return fmt.Errorf("failed to update version '%s' of block '%s': %w", metadata.Version, Blockname, err)
+		}
+	}
+
+	if loaded, ok := pm.loadedBlocks[Blockname]; ok {
+		updated := *loaded
+		updated.Pinned = pinned
+		pm.loadedBlocks[Blockname] = &updated
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PlanAction is what installing a request would do to the host.
+type PlanAction string
+
+const (
+	PlanInstall   PlanAction = "install"   // The block isn't installed yet
+	PlanUpgrade   PlanAction = "upgrade"   // A newer version replaces the active one
+	PlanDowngrade PlanAction = "downgrade" // An older version replaces the active one
+	PlanReinstall PlanAction = "reinstall" // The forced request installs the active version again
+	PlanNone      PlanAction = "none"      // The block is installed and Install would return it as is
+)
+
+// InstallPlan describes what Install would do with a request.
+type InstallPlan struct {
+	Repo           string     `json:"repo"` // Source installed from, after repository redirects
+	Name           string     `json:"name"`
+	Version        string     `json:"version"`
+	CurrentVersion string     `json:"current_version,omitempty"` // Active version, if installed
+	Action         PlanAction `json:"action"`
+	// Asset is the release asset, file, or URL downloaded for this
+	// platform; empty when building from source or pulling an image.
+	Asset string `json:"asset,omitempty"`
+	// DownloadSize is the size of Asset in bytes, 0 when unknown.
+	DownloadSize int64 `json:"download_size,omitempty"`
+	// BuildFromSource is set when no asset fits the platform and the
+	// manifest's build command would run.
+	BuildFromSource bool `json:"build_from_source,omitempty"`
+	// Deprecated holds the block's deprecation notice when it has one.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// Plan resolves req like Install, its manifest, version, and platform
+// asset, and reports what installing it would change, so callers can ask
+// before modifying the host. Nothing is downloaded or written to the
+// install dir, and the trust prompt isn't asked. GitHub and local blocks
+// are supported.
+func (pm *PackageManager) Plan(ctx context.Context, req InstallRequest) (*InstallPlan, error) {
+	if rule, ok := pm.policy.allows(req.Repo); !ok {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrRepoNotAllowed, req.Repo, rule)
+	}
+
+	switch dir, isLocal := parseLocalRepo(req.Repo); {
+	case pm.vendorDir != "":
+		return nil, errors.New("Plan is not available in offline mode")
+	case isLocal:
+		return pm.planLocal(ctx, req, dir)
+	}
+	if _, isGitLab := parseGitLabRepo(req.Repo); isGitLab {
+		return nil, fmt.Errorf("Plan supports GitHub and local blocks, not %s", req.Repo)
+	}
+	return pm.planGitHub(ctx, req)
+}
+
+// planLocal is Plan for a block directory.
+func (pm *PackageManager) planLocal(ctx context.Context, req InstallRequest, dir string) (*InstallPlan, error) {
+	blockInfo, err := readLocalBlockInfo(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block info: %w", err)
+	}
+	if err := applyAlias(req, blockInfo); err != nil {
+		return nil, err
+	}
+
+	plan := &InstallPlan{Repo: req.Repo, Name: blockInfo.Name}
+	done, err := pm.planCurrent(plan, req, req.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return plan, nil
+	}
+
+	plan.Version = localBlockVersion(blockInfo, req.Version)
+	pm.applyRegistryStatus(ctx, blockInfo, req.Repo)
+	if err := pm.planVersion(plan, req, blockInfo); err != nil {
+		return nil, err
+	}
+
+	_, assetErr := pm.resolvePlatformAsset(blockInfo, listLocalAssets(dir), req.ProbeAssets)
+	if err := planAsset(plan, req, blockInfo, assetErr); err != nil {
+		return nil, err
+	}
+	if plan.Asset == "" || isURLAsset(plan.Asset) {
+		return plan, nil
+	}
+	if info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(plan.Asset))); err == nil {
+		plan.DownloadSize = info.Size()
+	}
+	return plan, nil
+}
+
+// planGitHub is Plan for a GitHub repository.
+func (pm *PackageManager) planGitHub(ctx context.Context, req InstallRequest) (*InstallPlan, error) {
+	source := pm.canonicalRepo(ctx, req.Repo)
+	if rule, ok := pm.policy.allows(source); !ok {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrRepoNotAllowed, source, rule)
+	}
+	if err := checkRepoPath(source); err != nil {
+		return nil, err
+	}
+	repo, _ := splitRepoPath(source)
+
+	commit, byCommit := parseCommitVersion(req.Version)
+	switch {
+	case byCommit:
+		var err error
+		if commit, err = pm.resolveCommit(ctx, repo, commit); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(req.Version, commitVersionPrefix):
+		return nil, fmt.Errorf("invalid commit version '%s': expected sha: followed by 7 to 40 hex digits", req.Version)
+	}
+
+	blockInfo, err := pm.fetchBlockInfoAt(ctx, source, commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block info: %w", err)
+	}
+	if err := applyAlias(req, blockInfo); err != nil {
+		return nil, err
+	}
+
+	plan := &InstallPlan{Repo: source, Name: blockInfo.Name}
+	done, err := pm.planCurrent(plan, req, source)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return plan, nil
+	}
+
+	pm.applyRegistryStatus(ctx, blockInfo, repo)
+	released := true
+	fromTag := !byCommit && req.BuildFromSource && pm.buildsFromTags(ctx, repo, blockInfo)
+	switch {
+	case byCommit:
+		plan.Version, released, err = pm.commitVersion(ctx, repo, commit)
+	case fromTag:
+		plan.Version, err = pm.resolveTagVersion(ctx, repo, req.Version, blockInfo.YankedVersions)
+	default:
+		plan.Version, err = pm.resolveReleaseVersion(ctx, repo, req.Version, blockInfo.YankedVersions, req.AllowPrerelease)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := pm.planVersion(plan, req, blockInfo); err != nil {
+		return nil, err
+	}
+
+	var assetErr error
+	switch {
+	case fromTag:
+		assetErr = fmt.Errorf("%s publishes no releases", repo)
+	case released:
+		_, assetErr = pm.resolvePlatformAsset(blockInfo, pm.releaseAssetNames(ctx, repo, plan.Version), req.ProbeAssets)
+	default:
+		assetErr = fmt.Errorf("no release was published from commit %s, and building it needs a build command in the manifest", commit)
+		req.BuildFromSource = true
+	}
+	if err := planAsset(plan, req, blockInfo, assetErr); err != nil {
+		return nil, err
+	}
+	if plan.Asset == "" || isURLAsset(plan.Asset) {
+		return plan, nil
+	}
+
+	release, err := pm.getReleaseByTag(ctx, repo, plan.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve release '%s': %w", plan.Version, err)
+	}
+	asset, err := pm.findAsset(release, plan.Asset)
+	if err != nil {
+		return nil, err
+	}
+	plan.DownloadSize = int64(asset.Size)
+	return plan, nil
+}
+
+// planCurrent fills in the installed version of the planned block, and
+// reports whether the plan is complete because Install would return it
+// without downloading anything.
+func (pm *PackageManager) planCurrent(plan *InstallPlan, req InstallRequest, source string) (bool, error) {
+	if err := pm.checkNameConflict(req, plan.Name, source); err != nil {
+		return true, err
+	}
+	if !pm.isBlockInstalled(plan.Name) {
+		plan.Action = PlanInstall
+		return false, nil
+	}
+
+	current, err := pm.getMetadata(plan.Name)
+	if err != nil {
+		return true, fmt.Errorf("block '%s' is already installed but failed to read metadata: %w", plan.Name, err)
+	}
+	plan.CurrentVersion = current.Version
+	if req.Force {
+		return false, nil
+	}
+
+	plan.Action = PlanNone
+	plan.Version = current.Version
+	return true, nil
+}
+
+// planVersion checks the resolved version of plan like Install, and
+// compares it with the installed one.
+func (pm *PackageManager) planVersion(plan *InstallPlan, req InstallRequest, blockInfo *BlockInfo) error {
+	if err := pm.checkYanked(req, blockInfo, plan.Version); err != nil {
+		return err
+	}
+	plan.Deprecated = blockInfo.Deprecated
+
+	if plan.CurrentVersion == "" {
+		return nil
+	}
+	switch compareVersions(plan.Version, plan.CurrentVersion) {
+	case 1:
+		plan.Action = PlanUpgrade
+	case -1:
+		plan.Action = PlanDowngrade
+	default:
+		plan.Action = PlanReinstall
+	}
+	return nil
+}
+
+// planAsset records the asset of plan, or that the binary would be built
+// from source when assetErr says no asset fits the platform.
+func planAsset(plan *InstallPlan, req InstallRequest, blockInfo *BlockInfo, assetErr error) error {
+	if assetErr != nil {
+		if !req.BuildFromSource || blockInfo.Build.Command == "" {
+			return assetErr
+		}
+		plan.BuildFromSource = true
+		return nil
+	}
+	if IsImageBlock(blockInfo) {
+		return nil
+	}
+
+	asset, err := assetForPlatform(blockInfo, hostPlatform())
+	if err != nil {
+		return err
+	}
+	plan.Asset = asset
+	return nil
+}
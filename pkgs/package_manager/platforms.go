@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// platformsDir holds the binaries fetched for other platforms inside a
+// version's bin directory: <block>/bin/<version>/platforms/<platform>/.
+const platformsDir = "platforms"
+
+// PlatformBinary is a block binary fetched for a target platform.
+type PlatformBinary struct {
+	Platform string // binary.assets key, e.g. "linux-amd64"
+	Path     string
+	SHA256   string
+}
+
+// InstallForPlatforms downloads the binary of the block req names for each
+// of platforms into <block>/bin/<version>/platforms/<platform>/, e.g. so a
+// CI job can package blocks for machines of another architecture. The
+// binaries are verified against the block's checksums like a regular
+// install, but nothing is activated or run: the host's installation and
+// metadata are left untouched. GitHub and local blocks are supported.
+func (pm *PackageManager) InstallForPlatforms(ctx context.Context, req InstallRequest, platforms []string) ([]PlatformBinary, error) {
+	if len(platforms) == 0 {
+		return nil, errors.New("no target platforms given")
+	}
+	for _, platform := range platforms {
+		if goos, goarch, ok := strings.Cut(platform, "-"); !ok || goos == "" || goarch == "" {
+			return nil, fmt.Errorf("invalid platform '%s': expected <os>-<arch>", platform)
+		}
+	}
+
+	if err := pm.checkPolicy(req.Repo); err != nil {
+		return nil, err
+	}
+
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx = withProgressRepo(ctx, req.Repo)
+	pm.reportPhase(ctx, PhaseResolving)
+
+	var (
+		blockInfo  *BlockInfo
+		version    string
+		listAssets func() ([]string, error)
+		fetch      func(platform, binDir string) (string, string, error)
+	)
+	switch dir, isLocal := parseLocalRepo(req.Repo); {
+	case pm.vendorDir != "":
+		return nil, errors.New("InstallForPlatforms is not available in offline mode")
+	case isLocal:
+		if blockInfo, err = readLocalBlockInfo(dir); err != nil {
+			return nil, fmt.Errorf("failed to read block info: %w", err)
+		}
+		if err := applyAlias(req, blockInfo); err != nil {
+			return nil, err
+		}
+		version = localBlockVersion(blockInfo, req.Version)
+		listAssets = listLocalAssets(dir)
+		fetch = func(platform, binDir string) (string, string, error) {
+			return pm.copyLocalPlatformBinary(ctx, dir, blockInfo, platform, binDir)
+		}
+	default:
+		if _, isGitLab := parseGitLabRepo(req.Repo); isGitLab {
+			return nil, fmt.Errorf("InstallForPlatforms supports GitHub and local blocks, not %s", req.Repo)
+		}
+		source := pm.canonicalRepo(ctx, req.Repo)
+		if err := pm.checkPolicy(source); err != nil {
+			return nil, err
+		}
+		if ctx, err = pm.checkPublisher(ctx, req, source); err != nil {
+			return nil, err
+		}
+		if err := checkRepoPath(source); err != nil {
+			return nil, err
+		}
+		repo, _ := splitRepoPath(source)
+		if blockInfo, err = pm.fetchBlockInfo(ctx, source); err != nil {
+			return nil, fmt.Errorf("failed to fetch block info: %w", err)
+		}
+		if err := applyAlias(req, blockInfo); err != nil {
+			return nil, err
+		}
+		if version, err = pm.resolveReleaseVersion(ctx, repo, req.Version, blockInfo.YankedVersions, req.AllowPrerelease); err != nil {
+			return nil, err
+		}
+		listAssets = pm.releaseAssetNames(ctx, repo, version)
+		fetch = func(platform, binDir string) (string, string, error) {
+			return pm.downloadPlatformBinary(ctx, repo, version, blockInfo, platform, binDir)
+		}
+	}
+	if IsImageBlock(blockInfo) {
+		return nil, fmt.Errorf("block '%s' is a container image; pull it for other platforms with the container CLI", blockInfo.Name)
+	}
+
+	binaries := make([]PlatformBinary, 0, len(platforms))
+	for _, platform := range platforms {
+		if err := resolveAssetPattern(blockInfo, platform, listAssets); err != nil {
+			return nil, err
+		}
+		binDir := filepath.Join(pm.versionBinDir(blockInfo.Name, version), platformsDir, platform)
+		path, digest, err := fetch(platform, binDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s binary of '%s': %w", platform, blockInfo.Name, err)
+		}
+		binaries = append(binaries, PlatformBinary{Platform: platform, Path: path, SHA256: digest})
+	}
+
+	pm.reportPhase(ctx, PhaseDone)
+	return binaries, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// refusalsFileName is the append-only log of installs refused by the
+// repository policy, one JSON object per line.
+const refusalsFileName = ".atomos.refusals"
+
+// ErrRepoNotAllowed is returned when the repository policy refuses to
+// install a repository.
+var ErrRepoNotAllowed = errors.New("repository not allowed by policy")
+
+// RepoPolicy restricts which repositories may be installed, so agentic
+// systems can't be talked into pulling arbitrary executables. Patterns are
+// matched case-insensitively against the coordinates given to Install, e.g.
+// "acme/tool", "gitlab:group/project", or "file:///opt/blocks/tool", and
+// against the name a GitHub repository was redirected to. A pattern matches
+// a repository if it's equal to it, a prefix of it up to a '/' ("acme"
+// covers every repository of the acme org), or a path.Match glob matching it
+// ("acme/*-tool").
+type RepoPolicy struct {
+	Allow []string // When non-empty, only matching repositories are installed
+	Deny  []string // Matching repositories are never installed, even if allowed
+}
+
+// PolicyRefusal records an install refused by the repository policy.
+type PolicyRefusal struct {
+	Time time.Time `json:"time"`
+	Repo string    `json:"repo"`
+	Rule string    `json:"rule"` // The deny pattern matched, or "not allowed" outside the allowlist
+}
+
+// SetRepoPolicy sets the policy every install is checked against. The zero
+// RepoPolicy allows everything.
+func (pm *PackageManager) SetRepoPolicy(policy RepoPolicy) error {
+	for _, pattern := range slices.Concat(policy.Allow, policy.Deny) {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid repository pattern '%s'", pattern)
+		}
+	}
+
+	pm.policy = policy
+	return nil
+}
+
+// PolicyRefusals returns the installs refused by the repository policy, by
+// this process and any other one sharing the install dir, oldest first.
+func (pm *PackageManager) PolicyRefusals() ([]PolicyRefusal, error) {
+	file, err := os.Open(filepath.Join(pm.InstallDir, refusalsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open refusal log: %w", err)
+	}
+	defer file.Close()
+
+	var refusals []PolicyRefusal
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var refusal PolicyRefusal
+		if err := json.Unmarshal(scanner.Bytes(), &refusal); err != nil {
+			continue
+		}
+		refusals = append(refusals, refusal)
+	}
+	return refusals, scanner.Err()
+}
+
+// checkPolicy refuses repo when the repository policy doesn't allow it, and
+// records the refusal.
+func (pm *PackageManager) checkPolicy(repo string) error {
+	rule, ok := pm.policy.allows(repo)
+	if ok {
+		return nil
+	}
+
+	pm.log().Warn("refused to install repository outside the policy", "repo", repo, "rule", rule)
+	if err := pm.recordRefusal(PolicyRefusal{Time: time.Now().UTC(), Repo: repo, Rule: rule}); err != nil {
+		pm.log().Warn("failed to record policy refusal", "repo", repo, "error", err)
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrRepoNotAllowed, repo, rule)
+}
+
+// allows reports whether policy allows repo and, when it doesn't, why.
+func (policy RepoPolicy) allows(repo string) (string, bool) {
+	for _, pattern := range policy.Deny {
+		if matchRepo(pattern, repo) {
+			return "denied by " + pattern, false
+		}
+	}
+	if len(policy.Allow) == 0 {
+		return "", true
+	}
+	for _, pattern := range policy.Allow {
+		if matchRepo(pattern, repo) {
+			return "", true
+		}
+	}
+	return "not allowed", false
+}
+
+// matchRepo reports whether pattern covers repo, ignoring case.
+func matchRepo(pattern, repo string) bool {
+	pattern, repo = strings.ToLower(strings.TrimSuffix(pattern, "/")), strings.ToLower(repo)
+	if repo == pattern || strings.HasPrefix(repo, pattern+"/") || strings.HasPrefix(repo, pattern+blockSelector) {
+		return true
+	}
+	matched, _ := path.Match(pattern, repo)
+	return matched
+}
+
+// recordRefusal appends refusal to the refusal log.
+func (pm *PackageManager) recordRefusal(refusal PolicyRefusal) error {
+	if pm.readOnly {
+		return nil
+	}
+
+	line, err := json.Marshal(refusal)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(pm.InstallDir, refusalsFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Platform spellings found in release asset names, by GOOS and GOARCH.
+var (
+	osAliases = map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "macos", "osx", "apple"},
+		"windows": {"windows", "win", "win64", "win32"},
+		"freebsd": {"freebsd"},
+	}
+	archAliases = map[string][]string{
+		"amd64": {"amd64", "x86_64", "x64"},
+		"arm64": {"arm64", "aarch64"},
+		"386":   {"386", "i386", "i686", "x86"},
+		"arm":   {"arm", "armv6", "armv7", "armhf"},
+	}
+)
+
+// Asset name suffixes that never hold a binary.
+var nonBinarySuffixes = []string{".sha256", ".sha512", ".sig", ".asc", ".pem", ".sbom", ".txt", ".json", ".yaml", ".yml", ".md"}
+
+// resolvePlatformAsset makes sure blockInfo has a binary for the current
+// platform. When the manifest lists none, the names returned by listAssets
+// are probed for one naming the current os and arch: with probe it is
+// selected and returned, otherwise the install fails suggesting it.
+func (pm *PackageManager) resolvePlatformAsset(blockInfo *BlockInfo, listAssets func() ([]string, error), probe bool) (string, error) {
+	platformKey := fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+	if err := resolveAssetPattern(blockInfo, platformKey, listAssets); err != nil {
+		return "", err
+	}
+	if _, ok := blockInfo.Binary.Assets[platformKey]; ok {
+		return "", nil
+	}
+	if _, ok := blockInfo.Binary.Assets[WasmAssetKey]; ok || IsImageBlock(blockInfo) {
+		return "", nil
+	}
+
+	assetNames, err := listAssets()
+	if err != nil {
+		return "", fmt.Errorf("%w %s and listing release assets failed: %w", ErrAssetMissingForPlatform, platformKey, err)
+	}
+
+	matches := probePlatformAssets(assetNames, blockInfo.Binary.ChecksumsAsset)
+	switch {
+	case len(matches) == 0:
+		return "", fmt.Errorf("%w %s", ErrAssetMissingForPlatform, platformKey)
+	case len(matches) > 1:
+		return "", fmt.Errorf("%w %s; several release assets match it: %s", ErrAssetMissingForPlatform, platformKey, strings.Join(matches, ", "))
+	case !probe:
+		return "", fmt.Errorf("%w %s; release asset '%s' looks like a match, install with ProbeAssets to use it", ErrAssetMissingForPlatform, platformKey, matches[0])
+	}
+
+	if blockInfo.Binary.Assets == nil {
+		blockInfo.Binary.Assets = map[string]string{}
+	}
+	blockInfo.Binary.Assets[platformKey] = matches[0]
+	pm.log().Warn("manifest lists no binary for this platform, using probed release asset", "block", blockInfo.Name, "platform", platformKey, "asset", matches[0])
+
+	return matches[0], nil
+}
+
+// probePlatformAssets returns the asset names naming the current os and
+// arch and no other platform.
+func probePlatformAssets(names []string, checksumsAsset string) []string {
+	var matches []string
+	for _, name := range names {
+		if name == checksumsAsset || isNonBinaryAsset(name) {
+			continue
+		}
+
+		osName, arch := assetPlatform(name)
+		if osName == runtime.GOOS && (arch == runtime.GOARCH || (arch == "" && osName == "darwin" && strings.Contains(strings.ToLower(name), "universal"))) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
+
+// assetPlatform infers the GOOS and GOARCH an asset name refers to. Either
+// is empty when the name mentions none, or several conflicting ones.
+func assetPlatform(name string) (string, string) {
+	lower := strings.ToLower(path.Base(name))
+	// Join spellings that contain separators before splitting into tokens.
+	lower = strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64").Replace(lower)
+	tokens := strings.FieldsFunc(lower, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+
+	return matchAlias(tokens, osAliases), matchAlias(tokens, archAliases)
+}
+
+func matchAlias(tokens []string, aliases map[string][]string) string {
+	found := ""
+	for _, token := range tokens {
+		for key, spellings := range aliases {
+			for _, spelling := range spellings {
+				if token != spelling {
+					continue
+				}
+				if found != "" && found != key {
+					return ""
+				}
+				found = key
+			}
+		}
+	}
+	return found
+}
+
+func isNonBinaryAsset(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range nonBinarySuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return strings.Contains(lower, "checksum") || strings.HasPrefix(lower, "sha256sums")
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// InstallPhase is the stage an install is in.
+type InstallPhase string
+
+const (
+	PhaseResolving   InstallPhase = "resolving"   // Reading the manifest and picking the release
+	PhaseDownloading InstallPhase = "downloading" // Fetching the binary
+	PhaseVerifying   InstallPhase = "verifying"   // Checking the binary's checksum
+	PhaseInstalling  InstallPhase = "installing"  // Scanning, running hooks, and activating
+	PhaseDone        InstallPhase = "done"
+	PhaseFailed      InstallPhase = "failed" // Only streamed by InstallWithProgress
+)
+
+// Progress is a snapshot of an install in flight.
+type Progress struct {
+	Repo       string // InstallRequest.Repo of the install
+	Phase      InstallPhase
+	Downloaded int64 // Bytes of the binary downloaded so far, resumed ones included
+	Total      int64 // Size of the binary, -1 while unknown
+}
+
+// ProgressReporter receives the progress of installs, e.g. to draw progress
+// bars. InstallAll installs concurrently, so Report must be safe to call
+// from several goroutines.
+type ProgressReporter interface {
+	Report(Progress)
+}
+
+// ProgressFunc adapts a function to ProgressReporter.
+type ProgressFunc func(Progress)
+
+func (f ProgressFunc) Report(p Progress) { f(p) }
+
+// SetProgressReporter sets the reporter notified of every install's phases
+// and download progress. Passing nil disables reporting.
+func (pm *PackageManager) SetProgressReporter(reporter ProgressReporter) {
+	pm.progress = reporter
+}
+
+// installEventBuffer is how many events InstallWithProgress queues for a
+// slow reader before download progress starts being dropped.
+const installEventBuffer = 64
+
+// InstallWithProgress starts installing req in the background and streams
+// its phases and download progress, ending with a PhaseDone event carrying
+// the metadata or a PhaseFailed event carrying the error, after which the
+// channel is closed. Download progress is dropped rather than slowing the
+// install down when the reader falls behind, but phase changes and the final
+// event wait for it, so the channel must be drained. The error is only set
+// when the install can't start.
+func (pm *PackageManager) InstallWithProgress(ctx context.Context, req InstallRequest) (<-chan InstallEvent, error) {
+	if pm.readOnly {
+		return nil, ErrReadOnly
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	events := make(chan InstallEvent, installEventBuffer)
+	ctx = context.WithValue(ctx, progressSinkKey{}, func(p Progress) {
+		event := InstallEvent{Repo: req.Repo, Version: req.Version, Phase: p.Phase, Downloaded: p.Downloaded, Total: p.Total, Percent: -1}
+		switch p.Phase {
+		case PhaseDone:
+			// Sent with the metadata once Install returns.
+			return
+		case PhaseDownloading:
+			if p.Total > 0 {
+				event.Percent = int(p.Downloaded * 100 / p.Total)
+			}
+			select {
+			case events <- event:
+			default:
+			}
+			return
+		}
+		events <- event
+	})
+
+	go func() {
+		defer close(events)
+		start := time.Now()
+		metadata, err := pm.Install(ctx, req)
+		event := InstallEvent{Repo: req.Repo, Version: req.Version, Metadata: metadata, Err: err, Duration: time.Since(start),
+			Phase: PhaseDone, Total: -1, Percent: 100}
+		if err != nil {
+			event.Phase, event.Percent = PhaseFailed, -1
+		}
+		events <- event
+	}()
+	return events, nil
+}
+
+type progressRepoKey struct{}
+
+// quietDownloadKey marks downloads that don't report progress, such as the
+// data assets downloaded next to the binary.
+type quietDownloadKey struct{}
+
+// progressSinkKey carries the function InstallWithProgress streams the
+// progress of one install to.
+type progressSinkKey struct{}
+
+// withProgressRepo tags ctx with the repo an install reports progress for.
+func withProgressRepo(ctx context.Context, repo string) context.Context {
+	return context.WithValue(ctx, progressRepoKey{}, repo)
+}
+
+// reportPhase notifies the reporter that the install running under ctx
+// entered phase.
+func (pm *PackageManager) reportPhase(ctx context.Context, phase InstallPhase) {
+	pm.reportProgress(ctx, phase, 0, -1)
+}
+
+func (pm *PackageManager) reportProgress(ctx context.Context, phase InstallPhase, downloaded, total int64) {
+	sink, _ := ctx.Value(progressSinkKey{}).(func(Progress))
+	if pm.progress == nil && !pm.hasListeners() && sink == nil {
+		return
+	}
+	repo, _ := ctx.Value(progressRepoKey{}).(string)
+	progress := Progress{Repo: repo, Phase: phase, Downloaded: downloaded, Total: total}
+	if pm.progress != nil {
+		pm.progress.Report(progress)
+	}
+	if sink != nil {
+		sink(progress)
+	}
+	if phase == PhaseDownloading {
+		pm.emit(func(l EventListener) { l.OnDownloadProgress(progress) })
+	}
+}
+
+// progressWriter reports the bytes written through it as download progress.
+type progressWriter struct {
+	w          io.Writer
+	report     func(downloaded int64)
+	downloaded int64
+}
+
+// countDownload wraps w so writes are reported, starting from the offset of
+// a resumed download.
+func (pm *PackageManager) countDownload(ctx context.Context, w io.Writer, offset, total int64) io.Writer {
+	if pm.progress == nil && !pm.hasListeners() && ctx.Value(progressSinkKey{}) == nil || ctx.Value(quietDownloadKey{}) != nil {
+		return w
+	}
+
+	pm.reportProgress(ctx, PhaseDownloading, offset, total)
+	return &progressWriter{w: w, downloaded: offset, report: func(downloaded int64) {
+		pm.reportProgress(ctx, PhaseDownloading, downloaded, total)
+	}}
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.downloaded += int64(n)
+	pw.report(pw.downloaded)
+	return n, err
+}
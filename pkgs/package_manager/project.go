@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectManifestFile is the conventional name of a project manifest.
+const ProjectManifestFile = "atomos.yaml"
+
+// ProjectManifest lists the blocks a project requires, the declarative
+// counterpart of Install calls:
+//
+//	blocks:
+//	  - repo: AlexsanderHamir/profiler
+//	    version: ^1.2.0
+//	  - repo: someone/profiler
+//	    alias: profiler-fork
+type ProjectManifest struct {
+	Blocks []ProjectBlock `yaml:"blocks"`
+}
+
+// ProjectBlock is one requirement of a project manifest.
+type ProjectBlock struct {
+	Repo    string `yaml:"repo"`    // Install coordinates, as accepted by InstallRequest.Repo
+	Version string `yaml:"version"` // Exact tag or semver range; any version when empty
+	Alias   string `yaml:"alias"`   // Name to install the block under
+}
+
+// ProjectSyncResult reports what Sync changed, as "name@version" items.
+type ProjectSyncResult struct {
+	Installed []string `json:"installed"`  // Required blocks that were missing
+	Updated   []string `json:"updated"`    // Blocks moved into their required range
+	UpToDate  []string `json:"up_to_date"` // Blocks already satisfying the manifest
+	Pruned    []string `json:"pruned"`     // Blocks removed because they aren't listed
+	Pinned    []string `json:"pinned"`     // Pinned blocks left as they are instead of updated or pruned
+}
+
+// LoadProjectManifest reads and checks a project manifest.
+func LoadProjectManifest(path string) (*ProjectManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project manifest: %w", err)
+	}
+
+	var manifest ProjectManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse project manifest: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i, block := range manifest.Blocks {
+		if block.Repo == "" {
+			return nil, fmt.Errorf("blocks[%d] of %s has no repo", i, path)
+		}
+		if IsVersionConstraint(block.Version) {
+			if _, err := ParseVersionConstraint(block.Version); err != nil {
+				return nil, fmt.Errorf("blocks[%d] of %s: %w", i, path, err)
+			}
+		}
+		key := strings.ToLower(block.Repo) + "|" + block.Alias
+		if seen[key] {
+			return nil, fmt.Errorf("%s lists %s more than once", path, block.Repo)
+		}
+		seen[key] = true
+	}
+
+	return &manifest, nil
+}
+
+// Sync makes the installation match the project manifest at manifestPath:
+// missing blocks are installed, blocks whose active version is outside the
+// required range are moved to the highest version within it, and installed
+// blocks the manifest doesn't list are uninstalled with all their versions.
+// Pinned blocks (see Pin) are neither moved nor uninstalled.
+func (pm *PackageManager) Sync(ctx context.Context, manifestPath string) (*ProjectSyncResult, error) {
+	manifest, err := LoadProjectManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result := &ProjectSyncResult{}
+	required := map[string]bool{}
+	for _, block := range manifest.Blocks {
+		req := InstallRequest{Repo: block.Repo, Version: block.Version, Alias: block.Alias}
+
+		installed := pm.installedFor(block)
+		switch {
+		case installed == nil:
+			metadata, err := pm.install(ctx, req)
+			if err != nil {
+				return result, fmt.Errorf("failed to install %s: %w", block.Repo, err)
+			}
+			result.Installed = append(result.Installed, metadata.Name+"@"+metadata.Version)
+			required[metadata.Name] = true
+
+		case installed.Pinned && !versionSatisfies(installed.Version, block.Version):
+			result.Pinned = append(result.Pinned, installed.Name+"@"+installed.Version)
+			required[installed.Name] = true
+
+		case !versionSatisfies(installed.Version, block.Version):
+			req.Force = true
+			metadata, err := pm.install(ctx, req)
+			if err != nil {
+				return result, fmt.Errorf("failed to update %s to %s: %w", installed.Name, block.Version, err)
+			}
+			result.Updated = append(result.Updated, metadata.Name+"@"+metadata.Version)
+			required[metadata.Name] = true
+
+		default:
+			result.UpToDate = append(result.UpToDate, installed.Name+"@"+installed.Version)
+			required[installed.Name] = true
+		}
+	}
+
+	pm.commitMu.Lock()
+	names := slices.Sorted(maps.Keys(pm.loadedBlocks))
+	pm.commitMu.Unlock()
+
+	for _, name := range names {
+		if required[name] {
+			continue
+		}
+		metadata, _ := pm.GetLoadedBlock(name)
+		if metadata != nil && metadata.Pinned {
+			result.Pinned = append(result.Pinned, name+"@"+metadata.Version)
+			continue
+		}
+		for pm.isBlockInstalled(name) {
+			if err := pm.uninstall(name); err != nil {
+				return result, fmt.Errorf("failed to prune %s: %w", name, err)
+			}
+		}
+		item := name
+		if metadata != nil {
+			item += "@" + metadata.Version
+		}
+		result.Pruned = append(result.Pruned, item)
+	}
+
+	return result, nil
+}
+
+// installedFor returns the active version of the installed block a
+// requirement refers to: by alias when it has one, else by repository.
+func (pm *PackageManager) installedFor(block ProjectBlock) *BlockMetadata {
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	if block.Alias != "" {
+		metadata := pm.loadedBlocks[block.Alias]
+		if metadata != nil && !sameSource(metadata, block.Repo) {
+			return nil
+		}
+		return metadata
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(pm.loadedBlocks)) {
+		if metadata := pm.loadedBlocks[name]; metadata.AliasOf == "" && sameSource(metadata, block.Repo) {
+			return metadata
+		}
+	}
+	return nil
+}
+
+// sameSource reports whether metadata was installed from repo.
+func sameSource(metadata *BlockMetadata, repo string) bool {
+	return strings.EqualFold(metadata.SourceRepo, repo) || strings.EqualFold(metadata.RedirectedFrom, repo)
+}
+
+// versionSatisfies reports whether an installed version meets a required
+// version: any version when it's empty or a channel, a match for a range,
+// or the same tag with or without a leading 'v'.
+func versionSatisfies(installed, required string) bool {
+	switch {
+	case required == "" || IsChannel(required):
+		return true
+	case IsVersionConstraint(required):
+		vc, err := ParseVersionConstraint(required)
+		return err == nil && vc.Matches(installed)
+	default:
+		return sameVersion(installed, required)
+	}
+}
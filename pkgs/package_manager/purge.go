@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PurgeResult reports what Purge deleted.
+type PurgeResult struct {
+	Blockname string   `json:"block_name"`
+	Versions  []string `json:"versions"` // Installed versions that were removed, oldest first
+	Removed   []string `json:"removed"`  // Paths deleted: the block directory's entries, then the directory and its shim
+	Bytes     int64    `json:"bytes"`    // Disk space freed under the block directory
+}
+
+// UninstallVersion removes one installed version of a block, with or
+// without a leading 'v'. Removing the active version makes the newest
+// remaining one active, as Uninstall does; removing any other version
+// leaves the active one in place.
+func (pm *PackageManager) UninstallVersion(ctx context.Context, Blockname, version string) error {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	metadata, err := pm.GetMetadata(Blockname, version)
+	if err != nil {
+		return err
+	}
+	return pm.uninstallVersion(Blockname, metadata)
+}
+
+// Purge removes every trace of a block: all installed versions, their
+// metadata, the install history and hook logs, data assets, activation
+// history, the block directory itself, and its shim. Unlike Uninstall, no
+// uninstall record is kept, since the history goes with the directory.
+// Listeners still get an OnUninstall event for each removed version.
+func (pm *PackageManager) Purge(ctx context.Context, Blockname string) (*PurgeResult, error) {
+	if Blockname == "" || Blockname == shimDirName || strings.HasPrefix(Blockname, ".") || strings.ContainsAny(Blockname, `/\`) {
+		return nil, fmt.Errorf("invalid block name %q", Blockname)
+	}
+
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	blockDir := filepath.Join(pm.InstallDir, Blockname)
+	versions, err := pm.metadataStore().Versions(Blockname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versions of block '%s': %w", Blockname, err)
+	}
+	if _, statErr := os.Stat(blockDir); os.IsNotExist(statErr) && len(versions) == 0 {
+		return nil, errNotInstalled(Blockname, "", nil)
+	}
+
+	if err := pm.checkFence(); err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{Blockname: Blockname}
+	slices.SortFunc(versions, func(a, b *BlockMetadata) int {
+		return compareVersions(a.Version, b.Version)
+	})
+	for _, metadata := range versions {
+		result.Versions = append(result.Versions, metadata.Version)
+	}
+
+	if result.Bytes, err = dirSize(blockDir); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(blockDir)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read block directory: %w", err)
+	default:
+		for _, entry := range entries {
+			result.Removed = append(result.Removed, filepath.Join(blockDir, entry.Name()))
+		}
+		if err := os.RemoveAll(blockDir); err != nil {
+			return nil, fmt.Errorf("failed to remove block directory: %w", err)
+		}
+		result.Removed = append(result.Removed, blockDir)
+	}
+
+	// Stores other than the per-file one keep metadata outside the block
+	// directory.
+	if _, ok := pm.metadataStore().(*FileMetadataStore); !ok {
+		for _, metadata := range versions {
+			if err := pm.metadataStore().Delete(Blockname, metadata.Version); err != nil {
+				return result, fmt.Errorf("failed to remove metadata of version '%s': %w", metadata.Version, err)
+			}
+		}
+	}
+
+	pm.commitMu.Lock()
+	shim := shimPath(pm.BinDir(), Blockname, hostPlatform())
+	if _, err := os.Lstat(shim); err == nil {
+		pm.removeShim(Blockname)
+		result.Removed = append(result.Removed, shim)
+	}
+	delete(pm.loadedBlocks, Blockname)
+	pm.commitMu.Unlock()
+
+	pm.log().Debug("purged block", "block", Blockname, "versions", result.Versions, "bytes", result.Bytes)
+	for _, version := range result.Versions {
+		event := UninstallEvent{Name: Blockname, Version: version}
+		pm.emit(func(l EventListener) { l.OnUninstall(event) })
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrQuotaExceeded is returned when an install would take the install dir
+// over the quota set with SetQuota.
+var ErrQuotaExceeded = errors.New("install dir quota exceeded")
+
+// largestBlocksShown is how many blocks Stats lists in LargestBlocks.
+const largestBlocksShown = 5
+
+// SetQuota limits the bytes the install dir may use. Installs that would
+// take it over the limit fail with ErrQuotaExceeded and leave nothing
+// behind. Zero removes the limit.
+func (pm *PackageManager) SetQuota(maxBytes int64) error {
+	if maxBytes < 0 {
+		return fmt.Errorf("invalid quota %d: must not be negative", maxBytes)
+	}
+	pm.quota = maxBytes
+	return nil
+}
+
+// accountSize records the disk used by the version of metadata, once its
+// hooks ran, and enforces the quota. A version that doesn't fit is removed
+// unless it was already installed, e.g. by a forced reinstall. The caller
+// holds commitMu.
+func (pm *PackageManager) accountSize(metadata *BlockMetadata) error {
+	binDir := pm.versionBinDir(metadata.Name, metadata.Version)
+	size, err := dirSize(binDir)
+	if err != nil {
+		return err
+	}
+	metadata.Size = size
+
+	if pm.quota == 0 {
+		return nil
+	}
+	usage, err := dirSize(pm.InstallDir)
+	if err != nil {
+		return err
+	}
+	if usage <= pm.quota {
+		return nil
+	}
+
+	if _, err := pm.metadataStore().Get(metadata.Name, metadata.Version); errors.Is(err, ErrMetadataNotFound) {
+		_ = os.RemoveAll(binDir)
+	}
+	return fmt.Errorf("%w: %s %s needs %s, bringing %s to %s of its %s quota; uninstall unused blocks (see Stats().LargestBlocks) or raise the quota",
+		ErrQuotaExceeded, metadata.Name, metadata.Version, formatSize(size), pm.InstallDir, formatSize(usage), formatSize(pm.quota))
+}
+
+// dirSize returns the bytes used by the files under dir, 0 when it doesn't
+// exist.
+func dirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", dir, err)
+	}
+	return total, nil
+}
+
+// formatSize renders bytes for humans, e.g. "12.5 MiB".
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrReadOnly is returned by operations that would modify an installation
+// opened with OpenReadOnly.
+var ErrReadOnly = errors.New("package manager is read-only")
+
+// readOnlyDetail is appended to the repairs that were only detected.
+const readOnlyDetail = " (not applied: read-only)"
+
+// OpenReadOnly opens the existing installation in installDir, or in the
+// default directory when empty, for inspection only: no directory is created
+// and nothing on disk is changed, not even to repair corrupted metadata.
+// Repairs that would have been made are still reported by MetadataRepairs.
+// Install, InstallAll, Uninstall and Use fail with ErrReadOnly. This suits
+// monitoring tools, doctors, and CI checks that must not alter state.
+func OpenReadOnly(installDir string) (*PackageManager, error) {
+	if installDir == "" {
+		installDir = getDefaultInstallDirPath()
+	}
+
+	info, err := os.Stat(installDir)
+	if err != nil {
+		return nil, fmt.Errorf("no installation found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("no installation found: %s is not a directory", installDir)
+	}
+
+	pm := &PackageManager{
+		InstallDir:   installDir,
+		preexisting:  true,
+		loadedBlocks: make(map[string]*BlockMetadata),
+		readOnly:     true,
+	}
+
+	if err := pm.loadExistingInstallation(); err != nil {
+		pm.log().Warn("failed to load existing installation", "dir", installDir, "error", err)
+	}
+
+	return pm, nil
+}
+
+// ReadOnly reports whether the package manager was opened with OpenReadOnly.
+func (pm *PackageManager) ReadOnly() bool {
+	return pm.readOnly
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RepairAction describes what was done about a damaged metadata file.
+type RepairAction string
+
+const (
+	// RepairQuarantined: the file could not be decoded and was renamed to
+	// <file>.corrupt so it no longer shadows valid versions.
+	RepairQuarantined RepairAction = "quarantined"
+	// RepairFellBack: an older valid version file was used instead.
+	RepairFellBack RepairAction = "fell_back"
+	// RepairRebuilt: no valid file remained and the metadata was rebuilt
+	// from the installed binary.
+	RepairRebuilt RepairAction = "rebuilt"
+)
+
+// corruptSuffix is appended to metadata files that failed to decode.
+const corruptSuffix = ".corrupt"
+
+// MetadataRepair records one recovery performed while reading metadata.
+type MetadataRepair struct {
+	Block  string       `json:"block"`
+	File   string       `json:"file"`
+	Action RepairAction `json:"action"`
+	Detail string       `json:"detail"`
+}
+
+// repairLog collects the repairs performed by a PackageManager.
+type repairLog struct {
+	mu      sync.Mutex
+	repairs []MetadataRepair
+}
+
+// MetadataRepairs returns every repair performed on corrupted metadata since
+// the package manager was created, including while loading the installation.
+func (pm *PackageManager) MetadataRepairs() []MetadataRepair {
+	pm.repairs.mu.Lock()
+	defer pm.repairs.mu.Unlock()
+
+	return slices.Clone(pm.repairs.repairs)
+}
+
+func (pm *PackageManager) reportRepair(repair MetadataRepair) {
+	pm.log().Warn("recovered corrupted block metadata", "block", repair.Block, "action", repair.Action, "detail", repair.Detail)
+
+	pm.repairs.mu.Lock()
+	defer pm.repairs.mu.Unlock()
+
+	pm.repairs.repairs = append(pm.repairs.repairs, repair)
+}
+
+// metadataFilesByRecency lists the version metadata files of a block, most
+// recently modified first.
+func metadataFilesByRecency(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type versionFile struct {
+		path    string
+		modTime int64
+	}
+
+	var files []versionFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, versionFile{filepath.Join(dir, e.Name()), info.ModTime().UnixNano()})
+	}
+
+	slices.SortStableFunc(files, func(a, b versionFile) int {
+		switch {
+		case a.modTime > b.modTime:
+			return -1
+		case a.modTime < b.modTime:
+			return 1
+		default:
+			return strings.Compare(b.path, a.path)
+		}
+	})
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// readMetadataFile decodes a metadata file, migrated to the current schema
+// version, and rejects ones missing the fields every install writes.
+func readMetadataFile(path string) (*BlockMetadata, error) {
+	metadata, _, err := readVersionedMetadataFile(path)
+	return metadata, err
+}
+
+// readVersionedMetadataFile is readMetadataFile also reporting the schema
+// version the file was written with.
+func readVersionedMetadataFile(path string) (*BlockMetadata, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open metadata file: %w", err)
+	}
+
+	metadata, from, err := decodeMetadata(data)
+	if errors.Is(err, ErrNewerMetadataSchema) {
+		return nil, from, fmt.Errorf("%s: %w", path, err)
+	}
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	if metadata.Name == "" || metadata.Version == "" || metadata.BinaryPath == "" {
+		return nil, from, errors.New("metadata is missing its name, version or binary path")
+	}
+
+	return metadata, from, nil
+}
+
+// recoverMetadata returns the newest valid metadata among paths (newest
+// first). Corrupted files are quarantined; when none is valid the metadata
+// is rebuilt from the installed binary if there is exactly one.
+func (pm *PackageManager) recoverMetadata(block string, paths []string) (*BlockMetadata, error) {
+	var firstErr error
+	for i, path := range paths {
+		metadata, from, err := readVersionedMetadataFile(path)
+		if errors.Is(err, ErrNewerMetadataSchema) {
+			return nil, err
+		}
+		if err == nil {
+			if from < MetadataSchemaVersion {
+				pm.persistMigration(path, metadata, from)
+			}
+			if i > 0 {
+				pm.reportRepair(MetadataRepair{block, path, RepairFellBack, fmt.Sprintf("using version %s", metadata.Version)})
+			}
+			return metadata, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		if pm.readOnly {
+			pm.reportRepair(MetadataRepair{block, path, RepairQuarantined, err.Error() + readOnlyDetail})
+			continue
+		}
+		if renameErr := os.Rename(path, path+corruptSuffix); renameErr != nil {
+			return nil, fmt.Errorf("%w (and failed to quarantine it: %v)", err, renameErr)
+		}
+		pm.reportRepair(MetadataRepair{block, path, RepairQuarantined, err.Error()})
+	}
+
+	version := strings.TrimSuffix(filepath.Base(paths[0]), ".json")
+	metadata, err := pm.rebuildMetadata(block, version)
+	if err != nil {
+		return nil, fmt.Errorf("%w; rebuild failed: %v", firstErr, err)
+	}
+
+	detail := fmt.Sprintf("from %s; source repository and entries are unknown until reinstalled", metadata.BinaryPath)
+	if pm.readOnly {
+		pm.reportRepair(MetadataRepair{block, paths[0], RepairRebuilt, detail + readOnlyDetail})
+		return metadata, nil
+	}
+
+	if err := pm.storeMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("failed to store rebuilt metadata: %w", err)
+	}
+	pm.reportRepair(MetadataRepair{block, paths[0], RepairRebuilt, detail})
+
+	return metadata, nil
+}
+
+// rebuildMetadata reconstructs the metadata of a block from the bin
+// directory of version, or from the shared bin directory of installations
+// that predate side-by-side versions.
+func (pm *PackageManager) rebuildMetadata(block, version string) (*BlockMetadata, error) {
+	binDir := pm.versionBinDir(block, version)
+	if _, err := os.Stat(binDir); err != nil {
+		binDir = filepath.Join(pm.InstallDir, block, "bin")
+	}
+	entries, err := os.ReadDir(binDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []os.DirEntry
+	for _, e := range entries {
+		if e.IsDir() || strings.Contains(e.Name(), partSuffix) {
+			continue
+		}
+		binaries = append(binaries, e)
+	}
+	if len(binaries) != 1 {
+		return nil, fmt.Errorf("expected one binary in %s, found %d", binDir, len(binaries))
+	}
+
+	binaryPath := filepath.Join(binDir, binaries[0].Name())
+	info, err := binaries[0].Info()
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := hashFile(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockMetadata{
+		Name:        block,
+		Version:     version,
+		BinaryPath:  binaryPath,
+		SHA256:      digest,
+		InstalledAt: info.ModTime(),
+		LastUpdated: info.ModTime(),
+		IsActive:    true,
+	}, nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultRegistryFile is read from GitHub registries given as
+	// "owner/repo" without a path.
+	defaultRegistryFile = "registry.yaml"
+	registryCacheFile   = ".cache/registry.yaml"
+)
+
+var (
+	// ErrNoRegistry is returned by Search and Info when no registry is set.
+	ErrNoRegistry = errors.New("no block registry configured")
+	// ErrNotInRegistry is returned by Info for unknown block names.
+	ErrNotInRegistry = errors.New("block not found in registry")
+)
+
+// RegistryEntry describes a known block in a registry index.
+type RegistryEntry struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description"`
+	Repo        string   `json:"repo" yaml:"repo"` // Install coordinates, as accepted by InstallRequest.Repo
+	Tags        []string `json:"tags,omitempty" yaml:"tags"`
+	// Deprecated and YankedVersions complement the block manifest's fields
+	// of the same name.
+	Deprecated     string   `json:"deprecated,omitempty" yaml:"deprecated"`
+	YankedVersions []string `json:"yanked_versions,omitempty" yaml:"yanked_versions"`
+}
+
+// RegistryIndex is the document served by a registry, in YAML or JSON.
+type RegistryIndex struct {
+	Blocks []RegistryEntry `json:"blocks" yaml:"blocks"`
+}
+
+// SetRegistry sets where Search and Info read the block index from: an
+// HTTPS URL, a file:// path, or GitHub coordinates "owner/repo" optionally
+// followed by the path of the index ("owner/repo/index/blocks.yaml"),
+// registry.yaml by default. An empty source falls back to ATOMOS_REGISTRY.
+func (pm *PackageManager) SetRegistry(source string) {
+	pm.registry = source
+}
+
+// Search looks query up in the names, descriptions, and tags of the blocks
+// listed by the registry, case-insensitively, and returns the matches
+// ranked. Each match's Location is the repository to install it from.
+func (pm *PackageManager) Search(ctx context.Context, query string) ([]Match, error) {
+	index, err := pm.registryIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil, nil
+	}
+
+	var matches []Match
+	for _, block := range index.Blocks {
+		switch name := strings.ToLower(block.Name); {
+		case name == q:
+			matches = append(matches, Match{MatchBlock, block.Name, "name", block.Name, block.Repo, ScoreExactName})
+		case strings.Contains(name, q):
+			matches = append(matches, Match{MatchBlock, block.Name, "name", block.Name, block.Repo, ScoreName})
+		}
+
+		if strings.Contains(strings.ToLower(block.Description), q) {
+			matches = append(matches, Match{MatchBlock, block.Name, "description", block.Description, block.Repo, ScoreDescription})
+		}
+
+		for _, tag := range block.Tags {
+			if strings.Contains(strings.ToLower(tag), q) {
+				matches = append(matches, Match{MatchBlock, block.Name, "tags", tag, block.Repo, ScoreOther})
+			}
+		}
+	}
+
+	SortMatches(matches)
+	return matches, nil
+}
+
+// Info returns the registry entry of the block named name.
+func (pm *PackageManager) Info(ctx context.Context, name string) (*RegistryEntry, error) {
+	index, err := pm.registryIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(index.Blocks, func(e RegistryEntry) bool { return strings.EqualFold(e.Name, name) })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotInRegistry, name)
+	}
+	return &index.Blocks[i], nil
+}
+
+// registryIndex fetches the registry index. The last fetched copy is cached
+// in the install dir and used when the registry can't be reached.
+func (pm *PackageManager) registryIndex(ctx context.Context) (*RegistryIndex, error) {
+	source := pm.registry
+	if source == "" {
+		source = os.Getenv("ATOMOS_REGISTRY")
+	}
+	if source == "" {
+		return nil, ErrNoRegistry
+	}
+
+	cachePath := filepath.Join(pm.InstallDir, registryCacheFile)
+	data, fetchErr := pm.fetchRegistry(ctx, source)
+	if fetchErr != nil {
+		cached, cacheErr := compression.ReadFile(cachePath)
+		if cacheErr != nil {
+			return nil, fmt.Errorf("failed to fetch registry %s: %w", source, fetchErr)
+		}
+		pm.log().Warn("registry unreachable, using cached index", "registry", source, "error", fetchErr)
+		data = cached
+	}
+
+	var index RegistryIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse registry index: %w", err)
+	}
+
+	if fetchErr == nil && !pm.readOnly {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			_ = compression.WriteFile(cachePath, data, compression.Zstd)
+		}
+	}
+
+	return &index, nil
+}
+
+// fetchRegistry reads the raw registry index from source.
+func (pm *PackageManager) fetchRegistry(ctx context.Context, source string) ([]byte, error) {
+	if path, ok := parseLocalRepo(source); ok {
+		return os.ReadFile(path)
+	}
+
+	if isURLAsset(source) {
+		var buf bytes.Buffer
+		if err := pm.fetchURL(ctx, source, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := strings.SplitN(source, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid registry %q: expected an https URL, a file:// path, or owner/repo[/path]", source)
+	}
+	path := defaultRegistryFile
+	if len(parts) == 3 && parts[2] != "" {
+		path = parts[2]
+	}
+	return pm.fetchRepoFile(ctx, parts[0]+"/"+parts[1], "", path)
+}
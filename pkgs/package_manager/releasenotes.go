@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReleaseNotes is the changelog a release of a block was published with.
+type ReleaseNotes struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	Body        string `json:"body"` // Markdown, as written on the release page
+	PublishedAt string `json:"published_at,omitempty"`
+}
+
+// GetReleaseNotes fetches the release notes of a version of an installed
+// block from its source, so CLIs and agents can show the changelog before
+// approving an update. version may be an exact tag, a range resolved to
+// the highest match, or empty for the latest release. Blocks installed from
+// a local directory or a vendor directory have no release notes.
+func (pm *PackageManager) GetReleaseNotes(ctx context.Context, Blockname, version string) (*ReleaseNotes, error) {
+	metadata, ok := pm.GetLoadedBlock(Blockname)
+	if !ok {
+		return nil, errNotInstalled(Blockname, "", nil)
+	}
+
+	if _, isLocal := parseLocalRepo(metadata.SourceRepo); isLocal || pm.vendorDir != "" {
+		return nil, fmt.Errorf("block '%s' has no releases to read notes from: installed from %s", Blockname, metadata.SourceRepo)
+	}
+
+	if src, ok := parseGitLabRepo(metadata.SourceRepo); ok {
+		releases, err := pm.listGitLabReleases(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		release, err := selectGitLabRelease(releases, version, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &ReleaseNotes{Version: release.TagName, Title: release.Name, Body: release.Description}, nil
+	}
+
+	repo, _ := splitRepoPath(metadata.SourceRepo)
+	tag, err := pm.resolveReleaseVersion(ctx, repo, version, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	release, err := pm.getReleaseByTag(ctx, repo, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release '%s': %w", tag, err)
+	}
+
+	return &ReleaseNotes{Version: release.TagName, Title: release.Name, Body: release.Body, PublishedAt: release.PublishedAt}, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrRequirementNotMet is returned when the host lacks a command a block
+// declares in requires, or has a version of it outside the declared range.
+var ErrRequirementNotMet = errors.New("runtime requirement not met")
+
+// requirementProbeTimeout bounds the `<command> --version` run probing the
+// version of a requirement.
+const requirementProbeTimeout = 5 * time.Second
+
+// probedVersion finds a version number in the output of --version, e.g.
+// "3.11.4" in "Python 3.11.4" or "2.43" in "dot - graphviz version 2.43.0".
+var probedVersion = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// Requirement is a host command a block needs at runtime, parsed from an
+// entry of the manifest's requires list such as "python3>=3.10".
+type Requirement struct {
+	Command    string
+	Constraint string // Semver range the command's version must satisfy, "" for any
+}
+
+// ParseRequirement parses a requires entry: a command name, optionally
+// followed by a version range ("graphviz", "python3>=3.10", "node ^20").
+func ParseRequirement(raw string) (Requirement, error) {
+	raw = strings.TrimSpace(raw)
+	command, constraint := raw, ""
+	if i := strings.IndexAny(raw, "<>=^~ "); i >= 0 {
+		command, constraint = raw[:i], strings.TrimSpace(raw[i:])
+	}
+	if command == "" || strings.ContainsAny(command, `/\`) {
+		return Requirement{}, fmt.Errorf("invalid requirement %q: expected a command name, optionally followed by a version range", raw)
+	}
+	if constraint != "" {
+		if _, err := ParseVersionConstraint(constraint); err != nil {
+			return Requirement{}, fmt.Errorf("invalid requirement %q: %w", raw, err)
+		}
+	}
+	return Requirement{Command: command, Constraint: constraint}, nil
+}
+
+func (r Requirement) String() string {
+	return r.Command + r.Constraint
+}
+
+// checkRequirements probes the host for the commands block requires and
+// fails the install with every unmet one, unless req ignores requirements,
+// in which case they are logged as warnings.
+func (pm *PackageManager) checkRequirements(ctx context.Context, req InstallRequest, block string, requires []string) error {
+	var unmet []string
+	for _, raw := range requires {
+		requirement, err := ParseRequirement(raw)
+		if err != nil {
+			return err
+		}
+		if err := probeRequirement(ctx, requirement); err != nil {
+			unmet = append(unmet, err.Error())
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: block '%s' needs %s", ErrRequirementNotMet, block, strings.Join(unmet, "; "))
+	if !req.IgnoreRequirements {
+		return err
+	}
+	pm.log().Warn("installing a block whose runtime requirements aren't met", "block", block, "error", err)
+	return nil
+}
+
+// probeRequirement looks requirement's command up on PATH and, when it has
+// a version range, checks the version the command reports for --version.
+func probeRequirement(ctx context.Context, requirement Requirement) error {
+	path, err := exec.LookPath(requirement.Command)
+	if err != nil {
+		return fmt.Errorf("%s, but %s was not found on PATH: install it and retry", requirement, requirement.Command)
+	}
+	if requirement.Constraint == "" {
+		return nil
+	}
+
+	vc, err := ParseVersionConstraint(requirement.Constraint)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requirementProbeTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s, but `%s --version` failed: %v", requirement, path, err)
+	}
+
+	version := probedVersion.FindString(string(output))
+	if version == "" {
+		return fmt.Errorf("%s, but `%s --version` reported no version", requirement, path)
+	}
+	if !vc.Matches(version) {
+		return fmt.Errorf("%s, but %s is version %s: upgrade it and retry", requirement, path, version)
+	}
+	return nil
+}
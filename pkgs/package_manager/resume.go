@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// partSuffix marks a download in progress. Its size is the number of
+	// bytes already received; the sidecar <name>.part.json identifies what
+	// is being downloaded so only the same asset is ever resumed.
+	partSuffix = ".part"
+
+	maxDownloadAttempts = 4
+	downloadRetryDelay  = time.Second
+)
+
+// partState is persisted next to a .part file.
+type partState struct {
+	Source       string `json:"source"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Size         int64  `json:"size,omitempty"` // Total size, when known
+}
+
+// retryableError marks a failure worth resuming the download for.
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// verifyDownload checks a complete download at path with the given SHA256
+// digest before it is moved into place.
+type verifyDownload func(path, digest string) error
+
+// downloadResumable downloads to localPath through a .part file. Interrupted
+// transfers are retried with exponential backoff, resuming from the bytes
+// already on disk with a Range request; a partial file left by an earlier
+// process is resumed the same way. The complete .part file is checked with
+// verify, when set, and only renamed over localPath once it passes, so a
+// failed check leaves whatever was installed there untouched. It returns the
+// SHA256 digest of the file.
+func (pm *PackageManager) downloadResumable(ctx context.Context, source string, newRequest func(context.Context) (*http.Request, error), localPath string, verify verifyDownload) (string, error) {
+	partPath := localPath + partSuffix
+	statePath := partPath + ".json"
+
+	policy := pm.retryPolicy()
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := pm.downloadPart(ctx, source, newRequest, partPath, statePath)
+		if err == nil {
+			break
+		}
+
+		var retryable retryableError
+		if !errors.As(err, &retryable) || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return "", err
+		}
+
+		pm.log().Warn("download interrupted, resuming", "source", source, "error", err, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	digest, err := hashFile(partPath)
+	if err != nil {
+		return "", err
+	}
+	if verify != nil {
+		if err := verify(partPath, digest); err != nil {
+			// Never resume from bytes that failed verification.
+			_ = os.Remove(partPath)
+			_ = os.Remove(statePath)
+			return "", err
+		}
+	}
+
+	if err := os.Rename(partPath, localPath); err != nil {
+		return "", fmt.Errorf("failed to move download into place: %w", err)
+	}
+	_ = os.Remove(statePath)
+	pm.log().Debug("downloaded binary", "source", source, "path", localPath, "sha256", digest)
+
+	return digest, nil
+}
+
+// downloadPart performs one attempt, appending to partPath when the server
+// honours the Range request and starting over otherwise.
+func (pm *PackageManager) downloadPart(ctx context.Context, source string, newRequest func(context.Context) (*http.Request, error), partPath, statePath string) error {
+	state := readPartState(statePath)
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil && state.Source == source {
+		offset = info.Size()
+	}
+
+	req, err := newRequest(ctx)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator := cmp.Or(state.ETag, state.LastModified); validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+
+	resp, err := pm.doDownload(req)
+	if err != nil {
+		return retryableError{fmt.Errorf("failed to download asset: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && contentRangeStart(resp) == offset:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+		offset = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && state.Size > 0 && offset == state.Size:
+		return nil // Already complete.
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable || resp.StatusCode == http.StatusPartialContent:
+		_ = os.Remove(partPath)
+		return retryableError{fmt.Errorf("server rejected resume at byte %d", offset)}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		err := fmt.Errorf("download failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			err = fmt.Errorf("%w: %w", ErrRateLimited, err)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			err = fmt.Errorf("%w: %w", ErrAuth, err)
+		}
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return retryableError{err}
+		}
+		return err
+	}
+
+	state = partState{
+		Source:       source,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.ContentLength >= 0 {
+		state.Size = offset + resp.ContentLength
+	}
+	if err := writePartState(statePath, state); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %w", err)
+	}
+	defer file.Close()
+
+	total := int64(-1)
+	if state.Size > 0 {
+		total = state.Size
+	}
+	written, err := io.Copy(pm.countDownload(ctx, file, offset, total), resp.Body)
+	if err != nil {
+		return retryableError{fmt.Errorf("failed to write to file: %w", err)}
+	}
+	if state.Size > 0 && offset+written != state.Size {
+		return retryableError{fmt.Errorf("download truncated at %d of %d bytes", offset+written, state.Size)}
+	}
+
+	return nil
+}
+
+// contentRangeStart returns the first byte of a "bytes start-end/size"
+// Content-Range, or -1 when it cannot be parsed.
+func contentRangeStart(resp *http.Response) int64 {
+	rest, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func readPartState(path string) partState {
+	var state partState
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func writePartState(path string, state partState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode download state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to persist download state: %w", err)
+	}
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open download: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to hash download: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import "context"
+
+// binaryAsset returns the release asset the binary of blockInfo comes from
+// on this platform: the inferred one, or the one the manifest lists. It's
+// empty for image blocks and URL assets.
+func binaryAsset(blockInfo *BlockInfo, inferred string) string {
+	if IsImageBlock(blockInfo) {
+		return ""
+	}
+	if inferred != "" {
+		return inferred
+	}
+	name, err := assetForPlatform(blockInfo, hostPlatform())
+	if err != nil || isURLAsset(name) {
+		return ""
+	}
+	return name
+}
+
+// checkRetagged compares an installed block with the release it came from
+// and warns when the release asset no longer matches the installed binary,
+// which happens when maintainers delete a release and publish the tag
+// again. Assets GitHub reports no digest for can't be compared, and failures
+// to fetch the release are only logged.
+func (pm *PackageManager) checkRetagged(ctx context.Context, repo string, blockInfo *BlockInfo, metadata *BlockMetadata) {
+	if metadata.BuiltFromSource || metadata.Commit != "" || metadata.SHA256 == "" {
+		return
+	}
+	assetName := metadata.Asset
+	if assetName == "" {
+		// Installed before the asset was recorded.
+		assetName = binaryAsset(blockInfo, metadata.InferredAsset)
+	}
+	if assetName == "" {
+		return
+	}
+
+	release, err := pm.getReleaseByTag(ctx, repo, metadata.Version)
+	if err != nil {
+		pm.log().Debug("failed to fetch release to compare the installed binary", "block", metadata.Name, "version", metadata.Version, "error", err)
+		return
+	}
+	asset, err := pm.findAsset(release, assetName)
+	if err != nil {
+		pm.log().Warn("installed release no longer matches what's on disk: its asset was removed upstream; reinstall with Force to pick up the current release",
+			"block", metadata.Name, "version", metadata.Version, "asset", assetName)
+		return
+	}
+	if asset.Digest == "" || normalizeDigest(asset.Digest) == normalizeDigest(metadata.SHA256) {
+		return
+	}
+
+	pm.log().Warn("installed release no longer matches what's on disk: the release was re-tagged upstream; reinstall with Force to pick up the current release",
+		"block", metadata.Name, "version", metadata.Version, "asset", assetName, "installed_sha256", metadata.SHA256, "release_sha256", normalizeDigest(asset.Digest))
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// activationHistoryFile lists the versions a block was switched to, oldest
+// first, one per line.
+const activationHistoryFile = "history"
+
+// ErrNoRollback is returned by Rollback when no previously active version
+// is still installed.
+var ErrNoRollback = errors.New("no previous version to roll back to")
+
+// Rollback reactivates the version that was active before the current one,
+// restoring its binary and metadata, so a bad update can be reverted.
+// Repeated rollbacks keep walking back through the activation history,
+// skipping versions that have since been uninstalled.
+func (pm *PackageManager) Rollback(ctx context.Context, Blockname string) (*BlockMetadata, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	history := pm.activationHistory(Blockname)
+	current := pm.activeVersion(Blockname)
+	if metadata, ok := pm.loadedBlocks[Blockname]; ok {
+		current = metadata.Version
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i] == current {
+			continue
+		}
+
+		metadata, err := pm.installedVersion(Blockname, history[i])
+		if err != nil {
+			continue
+		}
+
+		if err := pm.checkFence(); err != nil {
+			return nil, err
+		}
+		if err := pm.activateLocked(metadata); err != nil {
+			return nil, err
+		}
+		// Forget the versions rolled back from.
+		if err := pm.writeActivationHistory(Blockname, history[:i+1]); err != nil {
+			return nil, err
+		}
+		return metadata, nil
+	}
+
+	return nil, fmt.Errorf("%w: block '%s'", ErrNoRollback, Blockname)
+}
+
+// Downgrade reactivates an installed version older than the active one.
+// Use switches to any installed version regardless of ordering.
+func (pm *PackageManager) Downgrade(ctx context.Context, Blockname, version string) (*BlockMetadata, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	metadata, err := pm.installedVersion(Blockname, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if active, ok := pm.loadedBlocks[Blockname]; ok {
+		target, errTarget := parseSemver(version)
+		current, errCurrent := parseSemver(active.Version)
+		if errTarget == nil && errCurrent == nil && target.compare(current) >= 0 {
+			return nil, fmt.Errorf("version '%s' of block '%s' is not older than the active %s", version, Blockname, active.Version)
+		}
+	}
+
+	if err := pm.checkFence(); err != nil {
+		return nil, err
+	}
+	if err := pm.activateLocked(metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// installedVersion reads the metadata of an installed version whose binary
+// is still present.
+func (pm *PackageManager) installedVersion(block, version string) (*BlockMetadata, error) {
+	metadata, err := pm.metadataStore().Get(block, version)
+	if err != nil {
+		return nil, errNotInstalled(block, version, err)
+	}
+	if _, err := os.Stat(metadata.BinaryPath); err != nil {
+		return nil, fmt.Errorf("binary of version '%s' of block '%s' is missing: %w", version, block, err)
+	}
+	return metadata, nil
+}
+
+// activationHistory returns the recorded activations of block, oldest first.
+func (pm *PackageManager) activationHistory(block string) []string {
+	data, err := os.ReadFile(filepath.Join(pm.InstallDir, block, activationHistoryFile))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
+}
+
+// recordActivation appends version to the activation history of block,
+// unless it is already the last entry.
+func (pm *PackageManager) recordActivation(block, version string) error {
+	history := pm.activationHistory(block)
+	if len(history) > 0 && history[len(history)-1] == version {
+		return nil
+	}
+	return pm.writeActivationHistory(block, append(history, version))
+}
+
+func (pm *PackageManager) writeActivationHistory(block string, history []string) error {
+	path := filepath.Join(pm.InstallDir, block, activationHistoryFile)
+	if err := os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write activation history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"slices"
+)
+
+// AnyHost in Sandbox.Network lets a block connect to every host.
+const AnyHost = "*"
+
+// Sandbox is the runtime environment a block declares it needs, under
+// sandbox in its manifest:
+//
+//	sandbox:
+//	  network: [api.example.com, "*.s3.amazonaws.com"]
+//	  writable: [~/.cache/my-block]
+//	  env: [OPENAI_API_KEY]
+//
+// The workflow engine confines blocks to their profile, and List exposes it
+// so users can review what a block may do before running it. A block
+// without a profile runs unconfined.
+type Sandbox struct {
+	// Network lists the hosts the block connects to: "api.example.com",
+	// "*.example.com" for its subdomains, "host:port" to also pin the port,
+	// or AnyHost. An empty list means no network access.
+	Network []string `yaml:"network" json:"network,omitempty"`
+	// Writable lists the paths the block writes to besides its output and
+	// temp directories, absolute or starting with "~/".
+	Writable []string `yaml:"writable" json:"writable,omitempty"`
+	// Env lists the variables of the caller's environment the block reads.
+	Env []string `yaml:"env" json:"env,omitempty"`
+}
+
+// AnyNetwork reports whether the block may connect to every host.
+func (s *Sandbox) AnyNetwork() bool {
+	return slices.Contains(s.Network, AnyHost)
+}
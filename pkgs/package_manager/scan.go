@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ScanResult is the verdict of a ScanProvider on a single file.
+type ScanResult struct {
+	Clean  bool
+	Threat string // Name of the detected threat when not clean
+}
+
+// ScanProvider inspects a downloaded asset before it is activated. Enterprise
+// scanners can be plugged in by implementing it; ClamAVScanner is provided.
+type ScanProvider interface {
+	Scan(ctx context.Context, path string) (ScanResult, error)
+}
+
+// SetScanProvider enables scanning of every installed binary before its
+// metadata is written. Flagged binaries are moved to the quarantine directory
+// and the install fails. Passing nil disables scanning.
+func (pm *PackageManager) SetScanProvider(scanner ScanProvider) {
+	pm.scanner = scanner
+}
+
+// ClamAVScanner scans files with ClamAV's command-line scanner.
+type ClamAVScanner struct {
+	// Command is the scanner binary, "clamscan" by default. "clamdscan" can
+	// be used to go through a running clamd daemon.
+	Command string
+	Args    []string // Extra arguments passed before the file
+}
+
+// Scan runs ClamAV on path. Exit code 1 means a threat was found; any other
+// non-zero exit is reported as an error.
+func (c ClamAVScanner) Scan(ctx context.Context, path string) (ScanResult, error) {
+	command := c.Command
+	if command == "" {
+		command = "clamscan"
+	}
+
+	args := append([]string{"--no-summary"}, c.Args...)
+	cmd := exec.CommandContext(ctx, command, append(args, path)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return ScanResult{Clean: true}, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return ScanResult{Threat: parseClamAVThreat(stdout.String())}, nil
+	}
+
+	return ScanResult{}, fmt.Errorf("%s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+}
+
+// parseClamAVThreat extracts the signature name from a "<path>: <name> FOUND"
+// line.
+func parseClamAVThreat(output string) string {
+	for line := range strings.Lines(output) {
+		line = strings.TrimSpace(line)
+		rest, ok := strings.CutSuffix(line, " FOUND")
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(rest, ": "); i >= 0 {
+			return rest[i+2:]
+		}
+		return rest
+	}
+	return "unknown threat"
+}
+
+// scanBinary runs the configured scanner on the installed binary and
+// quarantines it when flagged.
+func (pm *PackageManager) scanBinary(ctx context.Context, metadata *BlockMetadata) error {
+	if pm.scanner == nil {
+		return nil
+	}
+
+	result, err := pm.scanner.Scan(ctx, metadata.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan '%s': %w", metadata.BinaryPath, err)
+	}
+	if result.Clean {
+		return nil
+	}
+
+	quarantined, err := pm.quarantine(metadata)
+	if err != nil {
+		return fmt.Errorf("block '%s' flagged as %s and could not be quarantined: %w", metadata.Name, result.Threat, err)
+	}
+
+	return fmt.Errorf("block '%s' flagged as %s; binary quarantined at %s", metadata.Name, result.Threat, quarantined)
+}
+
+// quarantine moves a flagged binary to <InstallDir>/quarantine/<block>/ and
+// strips its execute permission.
+func (pm *PackageManager) quarantine(metadata *BlockMetadata) (string, error) {
+	dir := filepath.Join(pm.InstallDir, "quarantine", metadata.Name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s-%s", time.Now().UTC().Format("20060102T150405Z"), metadata.Version, filepath.Base(metadata.BinaryPath))
+	dst := filepath.Join(dir, name)
+	if err := os.Rename(metadata.BinaryPath, dst); err != nil {
+		return "", fmt.Errorf("failed to move binary: %w", err)
+	}
+
+	if err := os.Chmod(dst, 0600); err != nil {
+		return "", fmt.Errorf("failed to strip permissions: %w", err)
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// MetadataSchemaVersion is the schema_version of the metadata files this
+// package writes. Files written before schema versions existed are version 0.
+const MetadataSchemaVersion = 1
+
+// ErrNewerMetadataSchema is returned for metadata files written by a newer
+// AtomOS, which this one can't read without losing information. They are
+// left untouched rather than quarantined.
+var ErrNewerMetadataSchema = errors.New("metadata written by a newer AtomOS")
+
+// metadataMigrations upgrade the fields of a decoded metadata file by one
+// schema version each: metadataMigrations[i] turns version i into i+1.
+// When a field is renamed, changes meaning, or needs a default other than
+// its zero value, bump MetadataSchemaVersion and append the migration.
+var metadataMigrations = [MetadataSchemaVersion]func(fields map[string]json.RawMessage) error{
+	// 0 → 1: schema_version is introduced; the fields are unchanged.
+	func(map[string]json.RawMessage) error { return nil },
+}
+
+// decodeMetadata decodes a metadata file of any schema version up to
+// MetadataSchemaVersion, migrating it to the current one. It reports the
+// version the file was written with.
+func decodeMetadata(data []byte) (*BlockMetadata, int, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, 0, err
+	}
+
+	var from int
+	if raw, ok := fields["schema_version"]; ok {
+		if err := json.Unmarshal(raw, &from); err != nil || from < 0 {
+			return nil, 0, fmt.Errorf("invalid schema_version %s", raw)
+		}
+	}
+	if from > MetadataSchemaVersion {
+		return nil, from, fmt.Errorf("%w: schema version %d, this version reads up to %d; upgrade AtomOS", ErrNewerMetadataSchema, from, MetadataSchemaVersion)
+	}
+
+	if from < MetadataSchemaVersion {
+		for version := from; version < MetadataSchemaVersion; version++ {
+			if err := metadataMigrations[version](fields); err != nil {
+				return nil, from, fmt.Errorf("failed to migrate metadata from schema version %d: %w", version, err)
+			}
+		}
+		fields["schema_version"] = json.RawMessage(fmt.Sprint(MetadataSchemaVersion))
+
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, from, err
+		}
+	}
+
+	var metadata BlockMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, from, err
+	}
+	return &metadata, from, nil
+}
+
+// persistMigration rewrites the metadata file at path, migrated from schema
+// version from, so it isn't migrated on every load. Its modification time
+// is kept: installations predating the active file order versions by it.
+func (pm *PackageManager) persistMigration(path string, metadata *BlockMetadata, from int) {
+	if pm.readOnly || path != pm.metadataPath(metadata.Name, metadata.Version) {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err == nil {
+		err = pm.storeMetadata(metadata)
+	}
+	if err == nil {
+		err = os.Chtimes(path, info.ModTime(), info.ModTime())
+	}
+	if err != nil {
+		pm.log().Warn("failed to persist migrated metadata", "block", metadata.Name, "file", path, "error", err)
+		return
+	}
+	pm.log().Debug("migrated block metadata", "block", metadata.Name, "file", path, "from", from, "to", MetadataSchemaVersion)
+}
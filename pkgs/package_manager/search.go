@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"cmp"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MatchKind tells what a search match refers to.
+type MatchKind string
+
+const (
+	MatchBlock    MatchKind = "block"
+	MatchEntry    MatchKind = "entry"
+	MatchWorkflow MatchKind = "workflow"
+)
+
+// Match is a single search hit, ranked by Score (higher is better).
+type Match struct {
+	Kind     MatchKind `json:"kind"`
+	Name     string    `json:"name"`     // Block, "block/entry", or workflow name
+	Field    string    `json:"field"`    // Field the query was found in
+	Text     string    `json:"text"`     // Matched value
+	Location string    `json:"location"` // Directory or "file:line" of the match
+	Score    int       `json:"score"`
+}
+
+// Scores given to the field a query matched, so names outrank descriptions.
+const (
+	ScoreExactName   = 100
+	ScoreName        = 60
+	ScoreEntryName   = 40
+	ScoreDescription = 20
+	ScoreOther       = 10
+)
+
+// Find searches the names, descriptions, and entry descriptions of installed
+// blocks for query, case-insensitively, and returns the matches ranked.
+func (pm *PackageManager) Find(query string) ([]Match, error) {
+	result, err := pm.list()
+	if err != nil {
+		return nil, err
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil, nil
+	}
+
+	var matches []Match
+	for _, block := range result.Blocks {
+		location := filepath.Join(pm.InstallDir, block.Name)
+
+		switch name := strings.ToLower(block.Name); {
+		case name == q:
+			matches = append(matches, Match{MatchBlock, block.Name, "name", block.Name, location, ScoreExactName})
+		case strings.Contains(name, q):
+			matches = append(matches, Match{MatchBlock, block.Name, "name", block.Name, location, ScoreName})
+		}
+
+		if strings.Contains(strings.ToLower(block.Description), q) {
+			matches = append(matches, Match{MatchBlock, block.Name, "description", block.Description, location, ScoreDescription})
+		}
+
+		for _, entryName := range slices.Sorted(maps.Keys(block.LSPEntries)) {
+			entry := block.LSPEntries[entryName]
+			name := block.Name + "/" + entryName
+			if strings.Contains(strings.ToLower(entryName), q) {
+				matches = append(matches, Match{MatchEntry, name, "name", entryName, location, ScoreEntryName})
+			}
+			if strings.Contains(strings.ToLower(entry.Description), q) {
+				matches = append(matches, Match{MatchEntry, name, "description", entry.Description, location, ScoreDescription})
+			}
+		}
+	}
+
+	SortMatches(matches)
+	return matches, nil
+}
+
+// SortMatches orders matches by descending score, then by name and location.
+func SortMatches(matches []Match) {
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Location, b.Location),
+		)
+	})
+}
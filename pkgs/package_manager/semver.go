@@ -0,0 +1,345 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. Missing minor/patch components are
+// recorded so partial versions like "1.7" can be used in constraints.
+type semver struct {
+	major, minor, patch int
+	prerelease          string
+	parts               int // Number of numeric components present (1-3)
+}
+
+// parseSemver parses versions such as "v1.8.1", "1.8", or "2.0.0-beta.1".
+func parseSemver(raw string) (semver, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	if v == "" {
+		return semver{}, fmt.Errorf("invalid version %q", raw)
+	}
+
+	v, _, _ = strings.Cut(v, "+") // Build metadata doesn't affect precedence
+	core, pre, _ := strings.Cut(v, "-")
+
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return semver{}, fmt.Errorf("invalid version %q", raw)
+	}
+
+	var nums [3]int
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version %q", raw)
+		}
+		nums[i] = n
+	}
+
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], prerelease: pre, parts: len(fields)}, nil
+}
+
+// compare returns -1, 0, or 1 following semver precedence rules.
+func (v semver) compare(o semver) int {
+	for _, pair := range [][2]int{{v.major, o.major}, {v.minor, o.minor}, {v.patch, o.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case v.prerelease == o.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case o.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.prerelease, o.prerelease)
+}
+
+// comparePrerelease compares dot-separated prerelease identifiers.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// comparator is a single "<op> <version>" condition.
+type comparator struct {
+	op      string
+	version semver
+}
+
+func (c comparator) matches(v semver) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// VersionConstraint is a parsed semver range such as "^1.8.0", "~1.7",
+// ">=1.7 <2.0", "1.x", or several of those joined with "||".
+type VersionConstraint struct {
+	raw  string
+	sets [][]comparator // Alternatives; every comparator in a set must match
+}
+
+// IsVersionConstraint reports whether version is a range rather than an exact
+// tag or "latest".
+func IsVersionConstraint(version string) bool {
+	return strings.ContainsAny(version, "^~<>=*| ,") ||
+		strings.Contains(version, ".x") || strings.Contains(version, ".X")
+}
+
+// ParseVersionConstraint parses a semver range.
+func ParseVersionConstraint(raw string) (*VersionConstraint, error) {
+	vc := &VersionConstraint{raw: raw}
+
+	for _, alternative := range strings.Split(raw, "||") {
+		var set []comparator
+		for _, term := range splitConstraintTerms(alternative) {
+			comparators, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", raw, err)
+			}
+			set = append(set, comparators...)
+		}
+		if len(set) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty range", raw)
+		}
+		vc.sets = append(vc.sets, set)
+	}
+
+	return vc, nil
+}
+
+// Matches reports whether version satisfies the constraint. Prerelease
+// versions only match when the constraint explicitly names a prerelease.
+func (vc *VersionConstraint) Matches(version string) bool {
+	return vc.matches(version, false)
+}
+
+// MatchesPrerelease is Matches with prereleases compared like any other
+// version, so "^1.2.0" also matches "1.3.0-rc.1".
+func (vc *VersionConstraint) MatchesPrerelease(version string) bool {
+	return vc.matches(version, true)
+}
+
+func (vc *VersionConstraint) matches(version string, includePrerelease bool) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false
+	}
+
+	for _, set := range vc.sets {
+		if v.prerelease != "" && !includePrerelease && !setAllowsPrerelease(set, v) {
+			continue
+		}
+		if v.prerelease != "" && includePrerelease && belowPrereleaseBound(set, v) {
+			continue
+		}
+
+		matched := true
+		for _, c := range set {
+			if !c.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (vc *VersionConstraint) String() string {
+	return vc.raw
+}
+
+// setAllowsPrerelease follows the npm convention: a prerelease only matches if
+// a comparator in the set has a prerelease on the same major.minor.patch.
+func setAllowsPrerelease(set []comparator, v semver) bool {
+	for _, c := range set {
+		cv := c.version
+		if cv.prerelease != "" && cv.major == v.major && cv.minor == v.minor && cv.patch == v.patch {
+			return true
+		}
+	}
+	return false
+}
+
+// belowPrereleaseBound reports whether v is a prerelease of the release an
+// upper bound of set excludes, e.g. 2.0.0-alpha for "<2.0.0", which is lower
+// than the bound but belongs to the excluded release.
+func belowPrereleaseBound(set []comparator, v semver) bool {
+	for _, c := range set {
+		cv := c.version
+		if c.op == "<" && cv.prerelease == "" && cv.major == v.major && cv.minor == v.minor && cv.patch == v.patch {
+			return true
+		}
+	}
+	return false
+}
+
+// splitConstraintTerms splits on spaces and commas while keeping an operator
+// attached to its version ("> = 1.0" is not supported, ">= 1.0" is).
+func splitConstraintTerms(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		if strings.Trim(fields[i], "<>=~^") == "" && i+1 < len(fields) {
+			terms = append(terms, fields[i]+fields[i+1])
+			i++
+			continue
+		}
+		terms = append(terms, fields[i])
+	}
+	return terms
+}
+
+// parseConstraintTerm expands a single term into primitive comparators.
+func parseConstraintTerm(term string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimPrefix(term, op)
+
+	if rest == "*" || rest == "x" || rest == "X" {
+		return []comparator{{op: ">=", version: semver{parts: 3}}}, nil
+	}
+
+	// Wildcards behave like a tilde/caret on the remaining components.
+	wildcard := false
+	if trimmed := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(rest, ".*"), ".x"), ".X"); trimmed != rest {
+		rest, wildcard = trimmed, true
+	}
+
+	v, err := parseSemver(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	if wildcard || (op == "" && v.parts < 3) || (op == "=" && v.parts < 3) {
+		return []comparator{{op: ">=", version: v}, {op: "<", version: nextBoundary(v, v.parts)}}, nil
+	}
+
+	switch op {
+	case "^":
+		return []comparator{{op: ">=", version: v}, {op: "<", version: caretUpperBound(v)}}, nil
+	case "~":
+		level := 2
+		if v.parts == 1 {
+			level = 1
+		}
+		return []comparator{{op: ">=", version: v}, {op: "<", version: nextBoundary(v, level)}}, nil
+	case "":
+		return []comparator{{op: "=", version: v}}, nil
+	default:
+		return []comparator{{op: op, version: v}}, nil
+	}
+}
+
+// nextBoundary bumps the component at the given level (1=major, 2=minor,
+// 3=patch) and zeroes everything below it.
+func nextBoundary(v semver, level int) semver {
+	switch level {
+	case 1:
+		return semver{major: v.major + 1, parts: 3}
+	case 2:
+		return semver{major: v.major, minor: v.minor + 1, parts: 3}
+	default:
+		return semver{major: v.major, minor: v.minor, patch: v.patch + 1, parts: 3}
+	}
+}
+
+// caretUpperBound allows changes that don't modify the left-most non-zero
+// component.
+func caretUpperBound(v semver) semver {
+	switch {
+	case v.major > 0 || v.parts == 1:
+		return nextBoundary(v, 1)
+	case v.minor > 0 || v.parts == 2:
+		return nextBoundary(v, 2)
+	default:
+		return nextBoundary(v, 3)
+	}
+}
+
+// highestMatchingTag returns the highest tag match accepts, such as
+// VersionConstraint.Matches, or "" if it accepts none.
+func highestMatchingTag(tags []string, match func(string) bool) string {
+	var bestTag string
+	var best semver
+	for _, tag := range tags {
+		if !match(tag) {
+			continue
+		}
+
+		v, err := parseSemver(tag)
+		if err != nil {
+			continue
+		}
+		if bestTag == "" || v.compare(best) > 0 {
+			bestTag, best = tag, v
+		}
+	}
+	return bestTag
+}
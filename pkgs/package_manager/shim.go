@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shimDirName is the directory of the install dir holding a shim for the
+// active binary of every installed block, e.g. ~/.atomos/bin.
+const shimDirName = "bin"
+
+// BinDir returns the directory holding a shim for every installed block.
+// Adding it to PATH makes each block runnable by name from a shell.
+func (pm *PackageManager) BinDir() string {
+	return filepath.Join(pm.InstallDir, shimDirName)
+}
+
+// RefreshShims rebuilds BinDir from the installed blocks: shims of blocks
+// that are no longer installed are removed and every active binary is
+// linked again, e.g. for installations that predate shims or were moved.
+func (pm *PackageManager) RefreshShims(ctx context.Context) error {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	entries, err := os.ReadDir(pm.BinDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read shim directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink != 0 || strings.EqualFold(filepath.Ext(entry.Name()), ".cmd") {
+			if err := os.Remove(filepath.Join(pm.BinDir(), entry.Name())); err != nil {
+				return fmt.Errorf("failed to remove shim: %w", err)
+			}
+		}
+	}
+
+	result, err := pm.list()
+	if err != nil {
+		return err
+	}
+	for i := range result.Blocks {
+		if err := pm.linkShim(&result.Blocks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkShim points the shim of the block in BinDir at the binary of
+// metadata. Container images have no binary to run and get none.
+func (pm *PackageManager) linkShim(metadata *BlockMetadata) error {
+	if _, isImage, _ := ImageRef(metadata.BinaryPath); isImage {
+		return nil
+	}
+	if err := os.MkdirAll(pm.BinDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create shim directory: %w", err)
+	}
+	_, err := writeShim(pm.BinDir(), metadata.Name, metadata.BinaryPath, hostPlatform())
+	return err
+}
+
+// removeShim deletes the shim of block from BinDir.
+func (pm *PackageManager) removeShim(block string) {
+	_ = os.Remove(shimPath(pm.BinDir(), block, hostPlatform()))
+}
+
+// WriteShim writes an entry point for the active version of Blockname into
+// dir, so putting dir on PATH makes the block runnable by its name. On Unix
+// the shim is a symlink to the binary; on Windows, where symlinks need
+// elevated rights, it is a <block>.cmd script forwarding its arguments. An
+// existing shim is replaced, so call it again after switching versions.
+// It returns the path of the shim.
+func (pm *PackageManager) WriteShim(Blockname, dir string) (string, error) {
+	metadata, err := pm.getMetadata(Blockname)
+	if err != nil {
+		return "", err
+	}
+	if _, isImage, _ := ImageRef(metadata.BinaryPath); isImage {
+		return "", fmt.Errorf("block '%s' is a container image and has no binary to shim", Blockname)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create shim directory: %w", err)
+	}
+	return writeShim(dir, Blockname, metadata.BinaryPath, hostPlatform())
+}
+
+// writeShim points the shim called name in dir at target, a binary of
+// platform, and returns the shim's path.
+func writeShim(dir, name, target, platform string) (string, error) {
+	path := shimPath(dir, name, platform)
+	if isWindowsPlatform(platform) {
+		if err := os.WriteFile(path, []byte(cmdShim(target)), 0644); err != nil {
+			return "", fmt.Errorf("failed to write shim: %w", err)
+		}
+		return path, nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to replace shim: %w", err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		return "", fmt.Errorf("failed to write shim: %w", err)
+	}
+	return path, nil
+}
+
+// shimPath is the path of the shim called name in dir for platform.
+func shimPath(dir, name, platform string) string {
+	if isWindowsPlatform(platform) {
+		return filepath.Join(dir, name+".cmd")
+	}
+	return filepath.Join(dir, name)
+}
+
+// cmdShim is a batch script running target with the arguments it was given
+// and exiting with target's exit code.
+func cmdShim(target string) string {
+	return strings.Join([]string{
+		"@echo off",
+		fmt.Sprintf(`"%s" %%*`, filepath.FromSlash(target)),
+		"exit /b %ERRORLEVEL%",
+		"",
+	}, "\r\n")
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Stats walks the install directory and reports how many blocks and
+// versions are installed, how much disk they use, and which blocks use
+// the most.
+func (pm *PackageManager) Stats() (*InstallationStats, error) {
+	entries, err := os.ReadDir(pm.InstallDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install dir: %w", err)
+	}
+
+	stats := &InstallationStats{
+		InstallDir: pm.InstallDir,
+		IsExisting: pm.preexisting,
+		Quota:      pm.quota,
+		Blocks:     map[string]BlockStats{},
+	}
+
+	for _, entry := range entries {
+		// Dot directories hold caches, not blocks.
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		name := entry.Name()
+
+		versions, err := pm.metadataStore().Versions(name)
+		if err != nil || len(versions) == 0 {
+			continue
+		}
+
+		block := BlockStats{ActiveVersion: pm.activeVersion(name)}
+		for _, metadata := range versions {
+			block.Versions++
+			if info, err := os.Stat(metadata.BinaryPath); err == nil {
+				block.BinarySize += info.Size()
+			}
+			if metadata.LastUsed.After(block.LastUsed) {
+				block.LastUsed = metadata.LastUsed
+			}
+			if block.ActiveVersion == "" && metadata.IsActive {
+				block.ActiveVersion = metadata.Version
+			}
+		}
+		if block.Versions == 0 {
+			continue
+		}
+
+		diskSize, err := dirSize(filepath.Join(pm.InstallDir, name))
+		if err != nil {
+			return nil, err
+		}
+		stats.LargestBlocks = append(stats.LargestBlocks, BlockUsage{Name: name, DiskSize: diskSize})
+
+		stats.Blocks[name] = block
+		stats.TotalBlocks++
+		stats.TotalVersions += block.Versions
+		stats.TotalBinarySize += block.BinarySize
+
+		if active, err := pm.getMetadata(name); err == nil {
+			stats.InstalledBlocks = append(stats.InstalledBlocks, *active)
+		}
+	}
+
+	if stats.TotalDiskSize, err = dirSize(pm.InstallDir); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(stats.LargestBlocks, func(a, b BlockUsage) int {
+		return cmp.Or(cmp.Compare(b.DiskSize, a.DiskSize), cmp.Compare(a.Name, b.Name))
+	})
+	if len(stats.LargestBlocks) > largestBlocksShown {
+		stats.LargestBlocks = stats.LargestBlocks[:largestBlocksShown]
+	}
+
+	return stats, nil
+}
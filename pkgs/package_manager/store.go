@@ -0,0 +1,452 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMetadataNotFound is returned by MetadataStore.Get for versions that
+// have no stored metadata.
+var ErrMetadataNotFound = errors.New("block metadata not found")
+
+// MetadataStore persists the metadata of installed block versions. The
+// default, FileMetadataStore, keeps one JSON file per version;
+// IndexedMetadataStore keeps them all in one indexed file. Pass another
+// implementation in Options.MetadataStore. Binaries, the active version,
+// and install history stay in the block directories whatever the store.
+type MetadataStore interface {
+	// Get returns the metadata of a version, or an error wrapping
+	// ErrMetadataNotFound.
+	Get(block, version string) (*BlockMetadata, error)
+	// Put stores metadata, replacing what was stored for its version.
+	Put(metadata *BlockMetadata) error
+	// Delete removes the metadata of a version. Missing versions are not
+	// an error.
+	Delete(block, version string) error
+	// Versions returns the readable metadata of every version of block,
+	// most recently installed first. It fails with ErrNewerMetadataSchema
+	// when a version was written by a newer AtomOS.
+	Versions(block string) ([]*BlockMetadata, error)
+	// Blocks returns the names of the blocks with stored metadata, sorted.
+	Blocks() ([]string, error)
+}
+
+// metadataStore returns the store in use, the per-file store of the
+// install directory unless Options.MetadataStore set another.
+func (pm *PackageManager) metadataStore() MetadataStore {
+	if pm.store == nil {
+		return NewFileMetadataStore(pm.InstallDir)
+	}
+	return pm.store
+}
+
+// FileMetadataStore stores the metadata of each version in
+// <block>/metadata/<version>.json under the install directory. Versions
+// are ordered by modification time, which rewrites of an installed
+// version leave untouched.
+type FileMetadataStore struct {
+	dir string
+}
+
+// NewFileMetadataStore returns the per-file store of an install directory.
+func NewFileMetadataStore(installDir string) *FileMetadataStore {
+	return &FileMetadataStore{dir: installDir}
+}
+
+func (s *FileMetadataStore) path(block, version string) string {
+	return filepath.Join(s.dir, block, "metadata", fmt.Sprintf("%s.json", version))
+}
+
+func (s *FileMetadataStore) Get(block, version string) (*BlockMetadata, error) {
+	path := s.path(block, version)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrMetadataNotFound, block, version)
+	}
+	return readMetadataFile(path)
+}
+
+func (s *FileMetadataStore) Put(metadata *BlockMetadata) error {
+	metadataDir := filepath.Join(s.dir, metadata.Name, "metadata")
+	if err := os.MkdirAll(metadataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	// Rewriting the same install, e.g. to flag it inactive or record a use,
+	// keeps its place in the version order.
+	metadataPath := s.path(metadata.Name, metadata.Version)
+	var keepModTime time.Time
+	if info, err := os.Stat(metadataPath); err == nil {
+		if previous, err := readMetadataFile(metadataPath); err == nil && previous.InstalledAt.Equal(metadata.InstalledAt) {
+			keepModTime = info.ModTime()
+		}
+	}
+
+	// Write to a temporary file and rename it into place so readers never
+	// see a partially written metadata file.
+	file, err := os.CreateTemp(metadataDir, ".metadata-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create metadata file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := json.NewEncoder(file).Encode(metadata); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set metadata permissions: %w", err)
+	}
+	if !keepModTime.IsZero() {
+		_ = os.Chtimes(file.Name(), keepModTime, keepModTime)
+	}
+
+	if err := os.Rename(file.Name(), metadataPath); err != nil {
+		return fmt.Errorf("failed to move metadata into place: %w", err)
+	}
+	return nil
+}
+
+func (s *FileMetadataStore) Delete(block, version string) error {
+	if err := os.Remove(s.path(block, version)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove metadata: %w", err)
+	}
+	return nil
+}
+
+func (s *FileMetadataStore) Versions(block string) ([]*BlockMetadata, error) {
+	paths, err := metadataFilesByRecency(filepath.Join(s.dir, block, "metadata"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*BlockMetadata
+	for _, path := range paths {
+		metadata, err := readMetadataFile(path)
+		if errors.Is(err, ErrNewerMetadataSchema) {
+			return nil, err
+		}
+		if err == nil {
+			versions = append(versions, metadata)
+		}
+	}
+	return versions, nil
+}
+
+func (s *FileMetadataStore) Blocks() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if s.hasMetadataFiles(entry.Name()) {
+			blocks = append(blocks, entry.Name())
+		}
+	}
+	return blocks, nil
+}
+
+// hasMetadataFiles reports whether block has a version file, readable or
+// not.
+func (s *FileMetadataStore) hasMetadataFiles(block string) bool {
+	entries, err := os.ReadDir(filepath.Join(s.dir, block, "metadata"))
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexedMetadataStore keeps the metadata of every version in a single
+// file, indexed in memory by block and by source repository. Versions are
+// ordered by an install sequence number rather than file times, and the
+// file is reloaded whenever another process rewrote it.
+type IndexedMetadataStore struct {
+	path string
+
+	mu      sync.Mutex
+	loaded  os.FileInfo
+	nextSeq int64
+	byBlock map[string]map[string]*indexedVersion
+	byRepo  map[string]map[string]bool // Source repo -> block names
+}
+
+// indexedVersion is a stored version with its install sequence number.
+type indexedVersion struct {
+	Seq      int64          `json:"seq"`
+	Metadata *BlockMetadata `json:"metadata"`
+}
+
+// indexedFile is the on-disk layout of an IndexedMetadataStore.
+type indexedFile struct {
+	NextSeq  int64             `json:"next_seq"`
+	Versions []*indexedVersion `json:"versions"`
+}
+
+// NewIndexedMetadataStore opens the indexed store at path, creating it on
+// the first write.
+func NewIndexedMetadataStore(path string) (*IndexedMetadataStore, error) {
+	s := &IndexedMetadataStore{path: path}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload reads the file again when it changed since it was last read. The
+// caller holds mu, except in NewIndexedMetadataStore.
+func (s *IndexedMetadataStore) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if s.byBlock == nil {
+			s.index(&indexedFile{})
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open metadata store: %w", err)
+	}
+	if s.loaded != nil && os.SameFile(s.loaded, info) && s.loaded.ModTime().Equal(info.ModTime()) && s.loaded.Size() == info.Size() {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open metadata store: %w", err)
+	}
+	var file indexedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to decode metadata store %s: %w", s.path, err)
+	}
+	s.index(&file)
+	s.loaded = info
+	return nil
+}
+
+func (s *IndexedMetadataStore) index(file *indexedFile) {
+	s.nextSeq = file.NextSeq
+	s.byBlock = map[string]map[string]*indexedVersion{}
+	s.byRepo = map[string]map[string]bool{}
+	for _, v := range file.Versions {
+		if v.Metadata == nil {
+			continue
+		}
+		s.add(v)
+	}
+}
+
+func (s *IndexedMetadataStore) add(v *indexedVersion) {
+	versions, ok := s.byBlock[v.Metadata.Name]
+	if !ok {
+		versions = map[string]*indexedVersion{}
+		s.byBlock[v.Metadata.Name] = versions
+	}
+	versions[v.Metadata.Version] = v
+
+	if repo := v.Metadata.SourceRepo; repo != "" {
+		if s.byRepo[repo] == nil {
+			s.byRepo[repo] = map[string]bool{}
+		}
+		s.byRepo[repo][v.Metadata.Name] = true
+	}
+}
+
+// save writes every version to the file, through a temporary file renamed
+// into place.
+func (s *IndexedMetadataStore) save() error {
+	file := indexedFile{NextSeq: s.nextSeq}
+	for _, versions := range s.byBlock {
+		for _, v := range versions {
+			file.Versions = append(file.Versions, v)
+		}
+	}
+	slices.SortFunc(file.Versions, func(a, b *indexedVersion) int { return int(a.Seq - b.Seq) })
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata store directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".metadata-store-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+	s.loaded = info
+	return nil
+}
+
+func (s *IndexedMetadataStore) Get(block, version string) (*BlockMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	v, ok := s.byBlock[block][version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrMetadataNotFound, block, version)
+	}
+	metadata := *v.Metadata
+	return &metadata, nil
+}
+
+func (s *IndexedMetadataStore) Put(metadata *BlockMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	stored := *metadata
+	v := &indexedVersion{Metadata: &stored}
+	// A rewrite of the same install keeps its place in the version order.
+	if previous, ok := s.byBlock[metadata.Name][metadata.Version]; ok && previous.Metadata.InstalledAt.Equal(metadata.InstalledAt) {
+		v.Seq = previous.Seq
+	} else {
+		s.nextSeq++
+		v.Seq = s.nextSeq
+	}
+	if previous, ok := s.byBlock[metadata.Name][metadata.Version]; ok {
+		s.remove(previous.Metadata)
+	}
+	s.add(v)
+	return s.save()
+}
+
+// remove drops metadata's version from the indexes.
+func (s *IndexedMetadataStore) remove(metadata *BlockMetadata) {
+	versions := s.byBlock[metadata.Name]
+	delete(versions, metadata.Version)
+	if len(versions) == 0 {
+		delete(s.byBlock, metadata.Name)
+	}
+
+	for _, v := range versions {
+		if v.Metadata.SourceRepo == metadata.SourceRepo {
+			return
+		}
+	}
+	if blocks := s.byRepo[metadata.SourceRepo]; blocks != nil {
+		delete(blocks, metadata.Name)
+		if len(blocks) == 0 {
+			delete(s.byRepo, metadata.SourceRepo)
+		}
+	}
+}
+
+func (s *IndexedMetadataStore) Delete(block, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return err
+	}
+	v, ok := s.byBlock[block][version]
+	if !ok {
+		return nil
+	}
+	s.remove(v.Metadata)
+	return s.save()
+}
+
+func (s *IndexedMetadataStore) Versions(block string) ([]*BlockMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	indexed := make([]*indexedVersion, 0, len(s.byBlock[block]))
+	for _, v := range s.byBlock[block] {
+		indexed = append(indexed, v)
+	}
+	slices.SortFunc(indexed, func(a, b *indexedVersion) int { return int(b.Seq - a.Seq) })
+
+	versions := make([]*BlockMetadata, len(indexed))
+	for i, v := range indexed {
+		metadata := *v.Metadata
+		versions[i] = &metadata
+	}
+	return versions, nil
+}
+
+func (s *IndexedMetadataStore) Blocks() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	blocks := make([]string, 0, len(s.byBlock))
+	for block := range s.byBlock {
+		blocks = append(blocks, block)
+	}
+	slices.Sort(blocks)
+	return blocks, nil
+}
+
+// BySourceRepo returns the names of the blocks with a version installed
+// from repo, sorted, without reading every block's metadata.
+func (s *IndexedMetadataStore) BySourceRepo(repo string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	blocks := make([]string, 0, len(s.byRepo[repo]))
+	for block := range s.byRepo[repo] {
+		blocks = append(blocks, block)
+	}
+	slices.Sort(blocks)
+	return blocks, nil
+}
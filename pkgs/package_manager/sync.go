@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Paths served by CatalogHandler.
+const (
+	catalogPath       = "/catalog"
+	catalogBinaryPath = "/blocks/" // /blocks/<name>/<version>
+)
+
+// CatalogEntry is an installed block version offered by a controller.
+type CatalogEntry struct {
+	Metadata BlockMetadata `json:"metadata"` // BinaryPath is the controller's
+	Active   bool          `json:"active"`
+}
+
+// Catalog lists every block version installed on a controller.
+type Catalog struct {
+	Blocks []CatalogEntry `json:"blocks"`
+}
+
+// SyncOptions tunes SyncFrom.
+type SyncOptions struct {
+	// Token is sent as a bearer token to controllers requiring one.
+	Token string
+	// Blocks restricts the sync to these block names; empty syncs all.
+	Blocks []string
+}
+
+// SyncResult reports what SyncFrom changed, as "name@version" items.
+type SyncResult struct {
+	Installed []string `json:"installed"`
+	Activated []string `json:"activated"`
+	UpToDate  []string `json:"up_to_date"`
+}
+
+// CatalogHandler serves this installation to workers calling SyncFrom: the
+// catalog of installed versions at /catalog and each binary at
+// /blocks/<name>/<version>. When token is set, requests must carry it as a
+// bearer token.
+func (pm *PackageManager) CatalogHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+catalogPath, func(w http.ResponseWriter, r *http.Request) {
+		catalog, err := pm.catalog()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(catalog)
+	})
+
+	mux.HandleFunc("GET "+catalogBinaryPath+"{name}/{version}", func(w http.ResponseWriter, r *http.Request) {
+		metadata, err := pm.installedVersion(r.PathValue("name"), r.PathValue("version"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.ServeFile(w, r, metadata.BinaryPath)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// catalog lists the installed versions whose binary is present, with their
+// SHA-256 digests.
+func (pm *PackageManager) catalog() (*Catalog, error) {
+	blocks, err := pm.metadataStore().Blocks()
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{Blocks: []CatalogEntry{}}
+	for _, block := range blocks {
+		versions, err := pm.metadataStore().Versions(block)
+		if err != nil {
+			continue
+		}
+		slices.SortFunc(versions, func(a, b *BlockMetadata) int { return strings.Compare(a.Version, b.Version) })
+
+		active := pm.activeVersion(block)
+		if active == "" {
+			pm.commitMu.Lock()
+			if loaded, ok := pm.loadedBlocks[block]; ok {
+				active = loaded.Version
+			}
+			pm.commitMu.Unlock()
+		}
+
+		for _, metadata := range versions {
+			if _, err := os.Stat(metadata.BinaryPath); err != nil {
+				continue
+			}
+			if metadata.SHA256 == "" {
+				if metadata.SHA256, err = hashFile(metadata.BinaryPath); err != nil {
+					return nil, err
+				}
+			}
+			catalog.Blocks = append(catalog.Blocks, CatalogEntry{Metadata: *metadata, Active: metadata.Version == active})
+		}
+	}
+
+	return catalog, nil
+}
+
+// SyncFrom pulls the blocks installed on a controller serving
+// CatalogHandler at baseURL, so a fleet of workers can be provisioned
+// without each of them calling GitHub. Versions already installed with the
+// same checksum are not downloaded again; every download is verified
+// against the controller's SHA-256. The controller's active versions become
+// active here.
+func (pm *PackageManager) SyncFrom(ctx context.Context, baseURL string, opts SyncOptions) (*SyncResult, error) {
+	if err := validateNetworkURL(baseURL); err != nil || baseURL == "" {
+		return nil, fmt.Errorf("invalid controller URL %q: expected an http(s) URL", baseURL)
+	}
+
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var catalog Catalog
+	data, err := pm.fetchFromController(ctx, baseURL, catalogPath, opts.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch catalog: %w", err)
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	fetch := func(remote BlockMetadata) ([]byte, error) {
+		path := catalogBinaryPath + url.PathEscape(remote.Name) + "/" + url.PathEscape(remote.Version)
+		return pm.fetchFromController(ctx, baseURL, path, opts.Token)
+	}
+	return pm.applyCatalog(ctx, &catalog, opts.Blocks, fetch)
+}
+
+// applyCatalog installs the versions of catalog missing here, or installed
+// with another checksum, reading their binaries with fetch, and activates
+// the ones marked active. blocks restricts it to some block names.
+func (pm *PackageManager) applyCatalog(ctx context.Context, catalog *Catalog, blocks []string, fetch func(BlockMetadata) ([]byte, error)) (*SyncResult, error) {
+	result := &SyncResult{}
+	for _, entry := range catalog.Blocks {
+		remote := entry.Metadata
+		if len(blocks) > 0 && !slices.Contains(blocks, remote.Name) {
+			continue
+		}
+		if err := validateBlockName(remote.Name, remote.Version); err != nil {
+			return result, err
+		}
+		item := remote.Name + "@" + remote.Version
+
+		local, err := pm.installedVersion(remote.Name, remote.Version)
+		if err != nil || !strings.EqualFold(local.SHA256, remote.SHA256) {
+			data, err := fetch(remote)
+			if err != nil {
+				return result, fmt.Errorf("failed to sync %s: %w", item, err)
+			}
+			if local, err = pm.storeSyncedVersion(remote, data); err != nil {
+				return result, fmt.Errorf("failed to sync %s: %w", item, err)
+			}
+			result.Installed = append(result.Installed, item)
+		} else {
+			result.UpToDate = append(result.UpToDate, item)
+		}
+
+		if !entry.Active {
+			continue
+		}
+		if current, ok := pm.GetLoadedBlock(remote.Name); ok && current.Version == remote.Version && current.SHA256 == local.SHA256 {
+			continue
+		}
+		if _, err := pm.commitInstall(ctx, local); err != nil {
+			return result, fmt.Errorf("failed to activate %s: %w", item, err)
+		}
+		result.Activated = append(result.Activated, item)
+	}
+
+	return result, nil
+}
+
+// storeSyncedVersion verifies the binary of one catalog version, writes it
+// and stores its metadata without activating it.
+func (pm *PackageManager) storeSyncedVersion(remote BlockMetadata, data []byte) (*BlockMetadata, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if remote.SHA256 == "" || !strings.EqualFold(remote.SHA256, digest) {
+		return nil, fmt.Errorf("%w: expected sha256 %s, got %s", ErrChecksumMismatch, remote.SHA256, digest)
+	}
+
+	binDir := pm.versionBinDir(remote.Name, remote.Version)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create bin directory: %w", err)
+	}
+	binaryPath := filepath.Join(binDir, filepath.Base(remote.BinaryPath))
+	if err := os.WriteFile(binaryPath, data, 0755); err != nil {
+		return nil, fmt.Errorf("failed to write binary: %w", err)
+	}
+
+	metadata := remote
+	metadata.BinaryPath = binaryPath
+	metadata.SHA256 = digest
+	metadata.InstalledAt = time.Now()
+	metadata.LastUpdated = time.Now()
+	metadata.IsActive = false
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+	if err := pm.checkFence(); err != nil {
+		return nil, err
+	}
+	if err := pm.storeMetadata(&metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// fetchFromController GETs path from a controller.
+func (pm *PackageManager) fetchFromController(ctx context.Context, baseURL, path, token string) ([]byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, pm.requestTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(baseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := pm.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to controller failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: controller returned HTTP %d: %s", ErrAuth, resp.StatusCode, strings.TrimSpace(string(body)))
+	default:
+		return nil, fmt.Errorf("controller returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// validateBlockName rejects names and versions from a catalog that would
+// escape the install dir.
+func validateBlockName(name, version string) error {
+	for _, part := range []string{name, version} {
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, `/\`) {
+			return fmt.Errorf("invalid block %q version %q in catalog", name, version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// repoTag is a tag of a GitHub repository.
+type repoTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// listTags fetches every tag of a repository, following pagination.
+func (pm *PackageManager) listTags(ctx context.Context, repo string) ([]repoTag, error) {
+	var tags []repoTag
+	for page := 1; ; page++ {
+		status, body, err := pm.github().get(ctx, pm.githubAPI("/repos/%s/tags?per_page=100&page=%d", repo, page))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("GitHub API error %d listing tags of %s: %s", status, repo, strings.TrimSpace(string(body)))
+		}
+
+		var pageTags []repoTag
+		if err := json.Unmarshal(body, &pageTags); err != nil {
+			return nil, fmt.Errorf("failed to decode tags JSON: %w", err)
+		}
+
+		tags = append(tags, pageTags...)
+		if len(pageTags) < 100 {
+			return tags, nil
+		}
+	}
+}
+
+// publishesReleases reports whether repo has a published release. Errors
+// count as releases, so they surface from the release lookup instead of
+// sending the install down the tag fallback.
+func (pm *PackageManager) publishesReleases(ctx context.Context, repo string) bool {
+	releases, err := pm.listReleases(ctx, repo)
+	if err != nil {
+		return true
+	}
+	for _, release := range releases {
+		if !release.Draft {
+			return true
+		}
+	}
+	return false
+}
+
+// buildsFromTags reports whether blockInfo of repo is installed from tag
+// tarballs: it can be built from source and repo publishes no releases.
+func (pm *PackageManager) buildsFromTags(ctx context.Context, repo string, blockInfo *BlockInfo) bool {
+	return blockInfo.Build.Command != "" && !IsImageBlock(blockInfo) && !pm.publishesReleases(ctx, repo)
+}
+
+// resolveTagVersion is resolveReleaseVersion for repositories without
+// releases: version is resolved against the repository's semver tags.
+func (pm *PackageManager) resolveTagVersion(ctx context.Context, repo, version string, yanked []string) (string, error) {
+	if IsChannel(version) {
+		return "", fmt.Errorf("channel '%s' needs releases, and %s has none", version, repo)
+	}
+
+	tags, err := pm.listTags(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+
+	if version != "" && !IsVersionConstraint(version) {
+		for _, name := range names {
+			if sameVersion(name, version) {
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("tag '%s' not found in %s", version, repo)
+	}
+
+	constraint := version
+	if constraint == "" {
+		constraint = "*"
+	}
+	vc, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	tag := highestMatchingTag(withoutYanked(names, yanked), vc.Matches)
+	if tag == "" {
+		return "", fmt.Errorf("%s has no releases and no tag satisfies version constraint '%s'", repo, constraint)
+	}
+	return tag, nil
+}
+
+// tarballCheckout downloads the source tarball of repo at tag and extracts
+// it into a temporary directory, so tags can be built without git.
+func (pm *PackageManager) tarballCheckout(repo, tag string) sourceCheckout {
+	return func(ctx context.Context) (string, func(), error) {
+		tarballURL := pm.githubAPI("/repos/%s/tarball/refs/tags/%s", repo, tag)
+		token, err := pm.token(ctx, ServiceGitHub, tarballURL)
+		if err != nil {
+			return "", nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", tarballURL, nil)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create tarball request: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := pm.httpClient().Do(req)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to download source of %s at %s: %w", repo, tag, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return "", nil, fmt.Errorf("download of the source of %s at %s failed: HTTP %d: %s", repo, tag, resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		dir, err := os.MkdirTemp("", "atomos-build-*")
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create build directory: %w", err)
+		}
+		cleanup := func() { _ = os.RemoveAll(dir) }
+		if err := extractSourceTarball(resp.Body, dir); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("failed to extract source of %s at %s: %w", repo, tag, err)
+		}
+		pm.log().Debug("extracted source tarball", "repo", repo, "tag", tag, "dir", dir)
+		return dir, cleanup, nil
+	}
+}
+
+// extractSourceTarball extracts a GitHub source tarball into dir, dropping
+// the "<owner>-<repo>-<commit>/" directory its entries are nested in.
+// Entries and symlinks leading outside dir are rejected.
+func extractSourceTarball(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, name, _ := strings.Cut(strings.TrimPrefix(header.Name, "./"), "/")
+		if name == "" {
+			continue
+		}
+		name = filepath.FromSlash(name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("entry %s leads outside the source tree", header.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm()|0600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) || !filepath.IsLocal(filepath.Join(filepath.Dir(name), filepath.FromSlash(header.Linkname))) {
+				return fmt.Errorf("symlink %s leads outside the source tree", header.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
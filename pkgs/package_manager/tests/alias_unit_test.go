@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallAlias(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/tool": "tool", "someone/tool": "tool"})
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "someone/tool"}); !errors.Is(err, packagemanager.ErrNameConflict) {
+		t.Fatalf("expected ErrNameConflict for a second block named tool, got %v", err)
+	}
+
+	aliased, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "someone/tool", Alias: "tool-fork"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() with an alias failed: %s", err)
+	}
+	if aliased.Name != "tool-fork" || aliased.AliasOf != "tool" || aliased.Alias() != "tool-fork" {
+		t.Errorf("expected tool installed as tool-fork, got name %q alias of %q", aliased.Name, aliased.AliasOf)
+	}
+	if !strings.Contains(filepath.ToSlash(aliased.BinaryPath), "/tool-fork/bin/") {
+		t.Errorf("expected the binary under the alias directory, got %s", aliased.BinaryPath)
+	}
+
+	original, ok := pkgm.GetLoadedBlock("tool")
+	if !ok || original.SourceRepo != "acme/tool" {
+		t.Errorf("expected the upstream block to stay installed, got %+v", original)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "someone/tool", Alias: "../escape"}); err == nil {
+		t.Error("expected an alias with a path separator to be rejected")
+	}
+}
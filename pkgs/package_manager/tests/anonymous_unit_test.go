@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// newPublicRepoManager serves acme/public, whose release asset is
+// downloadable anonymously from its browser URL, while the API asset
+// endpoint requires a token. It returns the counts of downloads through
+// each path.
+func newPublicRepoManager(t *testing.T, token string) (*packagemanager.PackageManager, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+
+	var browser, api atomic.Int32
+	manifest := fmt.Sprintf("name: public\nbinary:\n  assets:\n    %s-%s: public\n", runtime.GOOS, runtime.GOARCH)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/raw/acme/public/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	release := packagemanager.GitHubRelease{
+		TagName: "v1.0.0",
+		Assets:  []packagemanager.ReleaseAsset{{ID: 7, Name: "public", DownloadURL: server.URL + "/download/acme/public/v1.0.0/public"}},
+	}
+	for _, path := range []string{"/api/repos/acme/public/releases/latest", "/api/repos/acme/public/releases/tags/v1.0.0"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(release)
+		})
+	}
+	mux.HandleFunc("/api/repos/acme/public/releases/assets/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, `{"message":"Requires authentication"}`, http.StatusUnauthorized)
+			return
+		}
+		api.Add(1)
+		fmt.Fprint(w, "#!/bin/sh\necho api\n")
+	})
+	mux.HandleFunc("/download/acme/public/v1.0.0/public", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected the anonymous download to carry no Authorization header")
+		}
+		browser.Add(1)
+		fmt.Fprint(w, "#!/bin/sh\necho browser\n")
+	})
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return token, nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm, &browser, &api
+}
+
+func TestInstallPublicRepoWithoutToken(t *testing.T) {
+	t.Parallel()
+
+	pkgm, browser, api := newPublicRepoManager(t, "")
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/public"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() without a token failed: %s", err)
+	}
+	if browser.Load() != 1 || api.Load() != 0 {
+		t.Errorf("expected one anonymous browser download, got %d browser and %d API downloads", browser.Load(), api.Load())
+	}
+	if binary, _ := os.ReadFile(metadata.BinaryPath); string(binary) != "#!/bin/sh\necho browser\n" {
+		t.Errorf("expected the browser download, got %q", binary)
+	}
+}
+
+func TestInstallWithTokenUsesAssetAPI(t *testing.T) {
+	t.Parallel()
+
+	pkgm, browser, api := newPublicRepoManager(t, "test-token")
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/public"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if api.Load() != 1 || browser.Load() != 0 {
+		t.Errorf("expected one API download, got %d browser and %d API downloads", browser.Load(), api.Load())
+	}
+}
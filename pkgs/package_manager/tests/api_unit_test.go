@@ -49,7 +49,7 @@ func TestInstallWithTestDir(t *testing.T) {
 	t.Run("InstallSupportedBlock", func(t *testing.T) {
 		var err error
 		installReq := packagemanager.InstallRequest{Repo: "AlexsanderHamir/prof", Version: "1.8.1"}
-		blockMetaData, err = pkgm.Install(installReq)
+		blockMetaData, err = pkgm.Install(t.Context(), installReq)
 		if err != nil {
 			t.Fatalf("pkgm.Install() failed: %s", err)
 		}
@@ -64,7 +64,7 @@ func TestInstallWithTestDir(t *testing.T) {
 
 		t.Run("InstallNonSupportedBlock", func(t *testing.T) {
 			installReq := packagemanager.InstallRequest{Repo: "AlexsanderHamir/prof", Version: "1.8.0", Force: true}
-			blockMetaData, err := pkgm.Install(installReq)
+			blockMetaData, err := pkgm.Install(t.Context(), installReq)
 			if err == nil {
 				t.Fatal("Expected installation to fail for version 1.8.0 (no agentic_support.yaml), but it succeeded")
 			}
@@ -100,7 +100,7 @@ func TestInstallWithTestDir(t *testing.T) {
 	})
 
 	t.Run("Uninstall", func(t *testing.T) {
-		err := pkgm.Uninstall(blockMetaData.Name)
+		err := pkgm.Uninstall(t.Context(), blockMetaData.Name)
 		if err != nil {
 			t.Fatalf("pkgm.Uninstall() failed: %s", err)
 		}
@@ -120,7 +120,7 @@ func TestInstallWithTestDir(t *testing.T) {
 		}
 
 		t.Run("UninstallNonExistentBlock", func(t *testing.T) {
-			err := pkgm.Uninstall("non-existent-block")
+			err := pkgm.Uninstall(t.Context(), "non-existent-block")
 			if err == nil {
 				t.Fatal("Expected error when uninstalling non-existent block")
 			}
@@ -131,3 +131,45 @@ func TestInstallWithTestDir(t *testing.T) {
 	})
 
 }
+
+func TestNewPackageManagerWithOptions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(packagemanager.EnvAtomosHome, "")
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	explicit := filepath.Join(t.TempDir(), "blocks")
+	pkgm, err := packagemanager.NewPackageManagerWithOptions(packagemanager.Options{InstallDir: explicit})
+	if err != nil {
+		t.Fatalf("NewPackageManagerWithOptions failed: %v", err)
+	}
+	if pkgm.InstallDir != explicit {
+		t.Errorf("expected the explicit install dir %s, got %s", explicit, pkgm.InstallDir)
+	}
+	if _, err := os.Stat(explicit); err != nil {
+		t.Errorf("expected the install dir to be created: %v", err)
+	}
+
+	pkgm, err = packagemanager.NewPackageManagerWithOptions(packagemanager.Options{})
+	if err != nil {
+		t.Fatalf("NewPackageManagerWithOptions failed: %v", err)
+	}
+	if want := filepath.Join(home, "data", "atomos"); pkgm.InstallDir != want {
+		t.Errorf("expected the XDG data dir %s, got %s", want, pkgm.InstallDir)
+	}
+
+	if err := os.Mkdir(filepath.Join(home, ".atomos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	pkgm, _ = packagemanager.NewPackageManagerWithOptions(packagemanager.Options{})
+	if want := filepath.Join(home, ".atomos"); pkgm.InstallDir != want {
+		t.Errorf("expected an existing ~/.atomos to be kept, got %s", pkgm.InstallDir)
+	}
+
+	override := filepath.Join(t.TempDir(), "atomos-home")
+	t.Setenv(packagemanager.EnvAtomosHome, override)
+	pkgm, _ = packagemanager.NewPackageManagerWithOptions(packagemanager.Options{})
+	if pkgm.InstallDir != override {
+		t.Errorf("expected ATOMOS_HOME %s, got %s", override, pkgm.InstallDir)
+	}
+}
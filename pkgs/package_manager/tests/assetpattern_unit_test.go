@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// writePatternBlock creates a local block whose directory holds files, and
+// whose manifest maps every platform to pattern.
+func writePatternBlock(t *testing.T, pattern string, files ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := fmt.Sprintf("name: prof\nversion: v1.0.0\nbinary:\n  assets:\n    \"*\": %q\n", pattern)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("#!/bin/sh\necho "+file+"\n"), 0755); err != nil {
+			t.Fatalf("Failed to write %s: %s", file, err)
+		}
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestAssetPatterns(t *testing.T) {
+	t.Parallel()
+
+	// Spell the host's arch the way many release pipelines do.
+	arch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if arch == "" {
+		t.Skipf("no alternative spelling of %s to test with", runtime.GOARCH)
+	}
+	host := fmt.Sprintf("prof_%s_%s.tar", strings.ToUpper(runtime.GOOS), arch)
+	files := []string{host, host + ".sha256", "prof_macos_arm64.tar", "prof_freebsd_386.tar"}
+
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr string
+	}{
+		{name: "glob with placeholders", pattern: "prof_{os}_{arch}*"},
+		{name: "regexp", pattern: "/^prof_{os}_{arch}\\.tar$/"},
+		{name: "ambiguous", pattern: "prof_*.tar", wantErr: "ambiguous"},
+		{name: "no match", pattern: "tool_{os}_{arch}", wantErr: "no asset matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writePatternBlock(t, tt.pattern, files...)})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pkgm.Install() failed: %s", err)
+			}
+			if filepath.Base(metadata.BinaryPath) != host {
+				t.Errorf("expected %s to be installed, got %s", host, metadata.BinaryPath)
+			}
+			if metadata.InferredAsset != "" {
+				t.Errorf("expected a declared pattern not to count as a probed asset, got %s", metadata.InferredAsset)
+			}
+		})
+	}
+}
+
+func TestAssetPatternForOtherPlatforms(t *testing.T) {
+	t.Parallel()
+
+	repo := writePatternBlock(t, "prof_{os}_{arch}.tar", "prof_macos_arm64.tar", "prof_windows_x64.tar")
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	binaries, err := pkgm.InstallForPlatforms(t.Context(), packagemanager.InstallRequest{Repo: repo}, []string{"darwin-arm64", "windows-amd64"})
+	if err != nil {
+		t.Fatalf("InstallForPlatforms failed: %s", err)
+	}
+	for i, want := range []string{"prof_macos_arm64.tar", "prof_windows_x64.tar"} {
+		if got := filepath.Base(binaries[i].Path); got != want {
+			t.Errorf("expected %s for %s, got %s", want, binaries[i].Platform, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestAuthErrorDiagnosis(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		token   string
+		status  int
+		header  map[string]string // Headers of authenticated answers
+		message string
+		public  bool
+		want    packagemanager.AuthReason
+		check   func(t *testing.T, authErr *packagemanager.AuthError)
+	}{
+		{name: "no token", status: http.StatusUnauthorized, want: packagemanager.AuthNoToken},
+		{name: "bad credentials", token: "expired", status: http.StatusUnauthorized, message: "Bad credentials", want: packagemanager.AuthInvalidToken},
+		{
+			name:   "classic token without repo scope",
+			token:  "classic",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-OAuth-Scopes": "read:org, gist"},
+			want:   packagemanager.AuthMissingScope,
+			check: func(t *testing.T, authErr *packagemanager.AuthError) {
+				if !slices.Equal(authErr.Scopes, []string{"read:org", "gist"}) || !slices.Equal(authErr.Missing, []string{"repo"}) {
+					t.Errorf("expected scopes read:org, gist missing repo, got %v missing %v", authErr.Scopes, authErr.Missing)
+				}
+			},
+		},
+		{
+			name:    "SAML SSO",
+			token:   "classic",
+			status:  http.StatusForbidden,
+			header:  map[string]string{"X-OAuth-Scopes": "repo", "X-GitHub-SSO": "required; url=https://github.com/orgs/acme/sso?authorization_request=abc"},
+			message: "Resource protected by organization SAML enforcement.",
+			want:    packagemanager.AuthSSORequired,
+			check: func(t *testing.T, authErr *packagemanager.AuthError) {
+				if authErr.SSOURL != "https://github.com/orgs/acme/sso?authorization_request=abc" {
+					t.Errorf("expected the SSO authorization URL, got %q", authErr.SSOURL)
+				}
+			},
+		},
+		{name: "fine-grained token without the repository", token: "fine-grained", status: http.StatusForbidden, want: packagemanager.AuthNoAccess},
+		{name: "public repository", token: "classic", status: http.StatusForbidden, header: map[string]string{"X-OAuth-Scopes": ""}, public: true, want: packagemanager.AuthNoAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			refuse := func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprintf(w, `{"message": %q}`, tt.message)
+			}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/repos/acme/tool", func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Header.Get("Authorization") != "":
+					refuse(w, r)
+				case tt.public:
+					fmt.Fprint(w, `{"full_name": "acme/tool"}`)
+				default:
+					// GitHub hides private repositories from anonymous requests.
+					http.NotFound(w, r)
+				}
+			})
+			mux.HandleFunc("/api/repos/acme/tool/contents/", refuse)
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+			pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+				return tt.token, nil
+			}))
+			if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api"}); err != nil {
+				t.Fatalf("SetGitHubConfig failed: %v", err)
+			}
+
+			_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+			var authErr *packagemanager.AuthError
+			if !errors.As(err, &authErr) {
+				t.Fatalf("expected an AuthError, got %v", err)
+			}
+			if authErr.Reason != tt.want || authErr.Public != tt.public || authErr.Repo != "acme/tool" {
+				t.Errorf("expected %s for acme/tool (public: %v), got %+v", tt.want, tt.public, authErr)
+			}
+			if !strings.Contains(err.Error(), "acme/tool") {
+				t.Errorf("expected the error to name the repository, got %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, authErr)
+			}
+		})
+	}
+}
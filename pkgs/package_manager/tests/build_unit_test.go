@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestBuildFromSourceFallback(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the build command uses a POSIX shell")
+	}
+
+	blockDir := t.TempDir()
+	manifest := `name: built
+version: v0.3.0
+binary:
+  assets:
+    plan9-mips: built
+build:
+  command: mkdir -p out && printf "#!/bin/sh\necho built $ATOMOS_BLOCK_VERSION\n" > out/built && chmod +x out/built
+  output: out/built
+`
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	repo := "file://" + filepath.ToSlash(blockDir)
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err == nil || !strings.Contains(err.Error(), "no binary found") {
+		t.Fatalf("expected the install to fail without BuildFromSource, got %v", err)
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, BuildFromSource: true})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if !metadata.BuiltFromSource || metadata.SHA256 == "" {
+		t.Errorf("expected a binary built from source with a digest, got %+v", metadata)
+	}
+
+	out, err := exec.Command(metadata.BinaryPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to run built binary: %s", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "built v0.3.0" {
+		t.Errorf("expected the build to see its version, got %q", got)
+	}
+
+	log, err := os.ReadFile(filepath.Join(pkgm.InstallDir, "built", "install.log"))
+	if err != nil || !strings.Contains(string(log), "build built v0.3.0") {
+		t.Errorf("expected the build to be logged, got %q, %v", log, err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestExportAndImportBundle(t *testing.T) {
+	t.Parallel()
+
+	source := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	repo := writeLocalTestBlock(t, "bundled")
+	if _, err := source.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+		t.Fatalf("source.Install() failed: %s", err)
+	}
+	manifest := filepath.Join(strings.TrimPrefix(repo, "file://"), "agentic_support.yaml")
+	data, _ := os.ReadFile(manifest)
+	if err := os.WriteFile(manifest, []byte(strings.Replace(string(data), "v0.1.0", "v0.2.0", 1)), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if _, err := source.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Force: true}); err != nil {
+		t.Fatalf("source.Install() failed: %s", err)
+	}
+	// The older version stays active, so the bundle must carry that too.
+	active, err := source.Use(t.Context(), "bundled", "v0.1.0")
+	if err != nil {
+		t.Fatalf("source.Use() failed: %s", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "atomos.tar.gz")
+	if err := source.ExportBundle(bundle); err != nil {
+		t.Fatalf("source.ExportBundle() failed: %s", err)
+	}
+
+	target := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	result, err := target.ImportBundle(t.Context(), bundle)
+	if err != nil {
+		t.Fatalf("target.ImportBundle() failed: %s", err)
+	}
+	slices.Sort(result.Installed)
+	if !slices.Equal(result.Installed, []string{"bundled@v0.1.0", "bundled@v0.2.0"}) || !slices.Equal(result.Activated, []string{"bundled@v0.1.0"}) {
+		t.Errorf("unexpected import result %+v", result)
+	}
+
+	imported, ok := target.GetLoadedBlock("bundled")
+	if !ok {
+		t.Fatal("expected the imported block to be loaded")
+	}
+	if imported.Version != "v0.1.0" || imported.SHA256 != active.SHA256 || imported.SourceRepo != repo {
+		t.Errorf("expected the active version to match the exported one, got %+v", imported)
+	}
+	if !strings.HasPrefix(imported.BinaryPath, target.InstallDir) {
+		t.Errorf("expected the binary under %s, got %s", target.InstallDir, imported.BinaryPath)
+	}
+	if verified, err := target.Verify("bundled"); err != nil || !verified.OK() {
+		t.Errorf("expected the imported binary to verify, got %+v, %v", verified, err)
+	}
+
+	result, err = target.ImportBundle(t.Context(), bundle)
+	if err != nil {
+		t.Fatalf("target.ImportBundle() failed: %s", err)
+	}
+	if len(result.Installed) != 0 || len(result.UpToDate) != 2 {
+		t.Errorf("expected a second import to change nothing, got %+v", result)
+	}
+
+	if _, err := target.ImportBundle(t.Context(), manifest); err == nil {
+		t.Error("expected importing a file that isn't a bundle to fail")
+	}
+}
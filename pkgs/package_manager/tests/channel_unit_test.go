@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestReleaseChannels(t *testing.T) {
+	t.Parallel()
+
+	releases := []packagemanager.GitHubRelease{
+		{TagName: "v1.1.1"},
+		{TagName: "v2.0.0-beta.1", Prerelease: true},
+		{TagName: "v1.1.0"},
+	}
+
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{channel: packagemanager.ChannelStable, want: "v1.1.1"},
+		{channel: packagemanager.ChannelBeta, want: "v2.0.0-beta.1"},
+		{channel: packagemanager.ChannelNightly, want: "v1.1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm := newFakeReleasesManager(t, "acme/tool", "tool", slices.Clone(releases))
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: tt.channel})
+			if err != nil {
+				t.Fatalf("pkgm.Install(%s) failed: %s", tt.channel, err)
+			}
+			if metadata.Version != tt.want {
+				t.Errorf("expected the %s channel to resolve to %s, got %s", tt.channel, tt.want, metadata.Version)
+			}
+		})
+	}
+}
+
+func TestBetaChannelWithoutPrereleases(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeReleasesManager(t, "acme/tool", "tool", []packagemanager.GitHubRelease{{TagName: "v1.0.0"}})
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: packagemanager.ChannelBeta}); err == nil {
+		t.Error("expected the beta channel to fail without prereleases")
+	}
+}
+
+func TestAllowPrerelease(t *testing.T) {
+	t.Parallel()
+
+	releases := []packagemanager.GitHubRelease{
+		{TagName: "v3.0.0", Draft: true},
+		{TagName: "v1.2.0-rc.1", Prerelease: true},
+		{TagName: "v1.1.0"},
+	}
+
+	tests := []struct {
+		version    string
+		prerelease bool
+		want       string
+	}{
+		{version: "", prerelease: false, want: "v1.1.0"},
+		{version: "", prerelease: true, want: "v1.2.0-rc.1"},
+		{version: "^1.0.0", prerelease: false, want: "v1.1.0"},
+		{version: "^1.0.0", prerelease: true, want: "v1.2.0-rc.1"},
+		{version: ">=2.0.0", prerelease: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q/%t", tt.version, tt.prerelease), func(t *testing.T) {
+			t.Parallel()
+
+			pkgm := newFakeReleasesManager(t, "acme/tool", "tool", slices.Clone(releases))
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: tt.version, AllowPrerelease: tt.prerelease})
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("expected no release to be installed, got %s", metadata.Version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pkgm.Install() failed: %s", err)
+			}
+			if metadata.Version != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, metadata.Version)
+			}
+		})
+	}
+}
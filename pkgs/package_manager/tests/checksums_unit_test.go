@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// newChecksumsFileManager fakes a release of acme/tool publishing the binary
+// "tool" and a checksums file with the given name and contents, without any
+// checksum in the manifest.
+func newChecksumsFileManager(t *testing.T, checksumsName, checksums string) *packagemanager.PackageManager {
+	t.Helper()
+
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}, {ID: 2, Name: checksumsName}}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\nbinary:\n  assets:\n    %s-%s: tool\n", runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\n")
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, checksums)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm
+}
+
+func TestConventionalChecksumsFile(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte("#!/bin/sh\n"))
+	digest := hex.EncodeToString(sum[:])
+	wrong := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name          string
+		checksumsName string
+		checksums     string
+		wantErr       string
+	}{
+		{"matching checksums.txt", "checksums.txt", digest + "  tool\n" + wrong + "  other\n", ""},
+		{"matching SHA256SUMS", "SHA256SUMS", digest + " *tool\n", ""},
+		{"matching goreleaser file", "tool_1.0.0_checksums.txt", digest + "  tool\n", ""},
+		{"mismatch", "checksums.txt", wrong + "  tool\n", "checksum mismatch for 'tool'"},
+		{"no entry for the asset", "sha256sums.txt", digest + "  other\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm := newChecksumsFileManager(t, tt.checksumsName, tt.checksums)
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+			if metadata.SHA256 != digest {
+				t.Errorf("expected sha256 %s, got %s", digest, metadata.SHA256)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+const (
+	releasedCommit   = "1111111111111111111111111111111111111111"
+	unreleasedCommit = "2222222222222222222222222222222222222222"
+)
+
+// newFakeCommitsManager returns a package manager whose GitHub calls go to
+// a fake acme/tool with release v1.0.0 tagged at releasedCommit and a later
+// unreleased commit. The manifest at each commit describes itself.
+func newFakeCommitsManager(t *testing.T) *packagemanager.PackageManager {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/{ref}/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\ndescription: at %s\nbinary:\n  assets:\n    %s-%s: tool\n", r.PathValue("ref"), runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/commits/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		for _, commit := range []string{releasedCommit, unreleasedCommit} {
+			if strings.HasPrefix(commit, r.PathValue("sha")) {
+				fmt.Fprintf(w, `{"sha": %q}`, commit)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"name": "v1.0.0", "commit": {"sha": %q}}]`, releasedCommit)
+	})
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+	mux.HandleFunc("/api/repos/acme/tool/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]packagemanager.GitHubRelease{release})
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\necho v1.0.0\n")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm
+}
+
+func TestInstallFromCommit(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeCommitsManager(t)
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "sha:1111111"})
+	if err != nil {
+		t.Fatalf("Install of a released commit failed: %v", err)
+	}
+	if metadata.Version != "v1.0.0" || metadata.Commit != releasedCommit || metadata.BuiltFromSource {
+		t.Errorf("expected the v1.0.0 release of the commit, got %+v", metadata)
+	}
+	if metadata.Description != "at "+releasedCommit {
+		t.Errorf("expected the manifest at the commit, got description %q", metadata.Description)
+	}
+
+	_, err = pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "sha:2222222", Force: true})
+	if err == nil || !strings.Contains(err.Error(), "no release was published from commit "+unreleasedCommit) {
+		t.Errorf("expected an unreleased commit without a build command to fail, got %v", err)
+	}
+
+	for version, want := range map[string]string{
+		"sha:3333333": "commit 3333333 not found",
+		"sha:xyz":     "invalid commit version",
+	} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: version, Force: true}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s to fail with %q, got %v", version, want, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestCredentialProviderResolvesPerHost(t *testing.T) {
+	t.Parallel()
+
+	manifest := fmt.Sprintf("name: private\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: private\n", runtime.GOOS, runtime.GOARCH)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/private/contents/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"encoding": "base64", "content": base64.StdEncoding.EncodeToString([]byte(manifest))})
+	})
+	mux.HandleFunc("/repos/acme/private/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 3, Name: "private"}}})
+	})
+	mux.HandleFunc("/repos/acme/private/releases/assets/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\n")
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer host-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		if req.Host == serverURL.Hostname() && req.Service == packagemanager.ServiceGitHub {
+			return "host-token", nil
+		}
+		return "", nil
+	}))
+	if err := pkgm.SetNetworkConfig(packagemanager.NetworkConfig{MirrorURL: server.URL}); err != nil {
+		t.Fatalf("SetNetworkConfig failed: %v", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/private", Version: "v1.0.0"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+}
+
+func TestEnvAndFileCredentials(t *testing.T) {
+	t.Setenv("ATOMOS_TOKEN_GITHUB_EXAMPLE_COM", "enterprise-token")
+	t.Setenv("GITHUB_TOKEN", "public-token")
+	t.Setenv("GITLAB_TOKEN", "")
+
+	ctx := t.Context()
+	env := packagemanager.EnvCredentials{}
+	for _, tc := range []struct {
+		req  packagemanager.CredentialRequest
+		want string
+	}{
+		{packagemanager.CredentialRequest{Host: "github.example.com", Service: packagemanager.ServiceGitHub}, "enterprise-token"},
+		{packagemanager.CredentialRequest{Host: "github.com", Service: packagemanager.ServiceGitHub}, "public-token"},
+		{packagemanager.CredentialRequest{Host: "gitlab.com", Service: packagemanager.ServiceGitLab}, ""},
+	} {
+		if got, err := env.Token(ctx, tc.req); err != nil || got != tc.want {
+			t.Errorf("EnvCredentials.Token(%+v) = %q, %v; want %q", tc.req, got, err, tc.want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	if err := os.WriteFile(path, []byte("hosts:\n  gitlab.com: file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write credentials file: %s", err)
+	}
+	chain := packagemanager.ChainCredentials{env, packagemanager.FileCredentials{Path: path}}
+	gitlab := packagemanager.CredentialRequest{Host: "gitlab.com", Service: packagemanager.ServiceGitLab}
+	if got, err := chain.Token(ctx, gitlab); err != nil || got != "file-token" {
+		t.Errorf("expected the file to provide the GitLab token, got %q, %v", got, err)
+	}
+
+	missing := packagemanager.FileCredentials{Path: filepath.Join(t.TempDir(), "missing.yaml")}
+	if got, err := missing.Token(ctx, gitlab); err != nil || got != "" {
+		t.Errorf("expected a missing file to provide nothing, got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// writeDataBlock writes a local block that declares data assets with
+// the given manifest fragment and returns its file:// repo.
+func writeDataBlock(t *testing.T, data string) string {
+	t.Helper()
+
+	blockDir := t.TempDir()
+	files := map[string]string{
+		"databot":           "#!/bin/sh\n",
+		"model.bin":         "weights",
+		"words/english.txt": "alpha\nbeta\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(blockDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %s", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %s", name, err)
+		}
+	}
+	manifest := fmt.Sprintf("name: databot\nbinary:\n  assets:\n    %s-%s: databot\ndata:\n%s", runtime.GOOS, runtime.GOARCH, data)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	return "file://" + filepath.ToSlash(blockDir)
+}
+
+func TestInstallDataAssets(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte("weights"))
+	repo := writeDataBlock(t, fmt.Sprintf(`  - name: model
+    asset: model.bin
+    sha256: %s
+  - name: wordlist
+    asset: words/english.txt
+    path: lists/en.txt
+`, hex.EncodeToString(sum[:])))
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	want := map[string]struct{ path, content string }{
+		"model":    {filepath.Join(pkgm.InstallDir, "databot", "data", "model.bin"), "weights"},
+		"wordlist": {filepath.Join(pkgm.InstallDir, "databot", "data", "lists", "en.txt"), "alpha\nbeta\n"},
+	}
+	if len(metadata.DataFiles) != len(want) {
+		t.Fatalf("expected %d data files, got %v", len(want), metadata.DataFiles)
+	}
+	for name, file := range want {
+		if metadata.DataFiles[name] != file.path {
+			t.Errorf("expected %s at %s, got %s", name, file.path, metadata.DataFiles[name])
+		}
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			t.Errorf("Failed to read %s: %s", name, err)
+		} else if string(content) != file.content {
+			t.Errorf("expected %s to contain %q, got %q", name, file.content, content)
+		}
+	}
+
+	stored, err := pkgm.GetMetadata("databot", "")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %s", err)
+	}
+	if stored.DataFiles["wordlist"] != want["wordlist"].path {
+		t.Errorf("expected stored metadata to keep data files, got %v", stored.DataFiles)
+	}
+}
+
+func TestInstallDataAssetPrefixedDigest(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte("weights"))
+	for _, digest := range []string{"sha256:" + hex.EncodeToString(sum[:]), "sha256:" + strings.ToUpper(hex.EncodeToString(sum[:]))} {
+		repo := writeDataBlock(t, "  - name: model\n    asset: model.bin\n    sha256: "+digest+"\n")
+
+		pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+			t.Errorf("expected %s to match the data asset, got %s", digest, err)
+		}
+	}
+}
+
+func TestInstallDataAssetChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	repo := writeDataBlock(t, "  - name: model\n    asset: model.bin\n    sha256: "+strings.Repeat("0", 64)+"\n")
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+	if !errors.Is(err, packagemanager.ErrChecksumMismatch) || !strings.Contains(err.Error(), "checksum mismatch for data asset 'model'") {
+		t.Fatalf("expected a data checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pkgm.InstallDir, "databot", "data", "model.bin")); !os.IsNotExist(err) {
+		t.Errorf("expected the mismatched data file not to be installed, got %v", err)
+	}
+}
+
+func TestDataAssetManifestValidation(t *testing.T) {
+	t.Parallel()
+
+	repo := writeDataBlock(t, `  - name: model
+    asset: model.bin
+    path: ../escape.bin
+  - name: model
+    asset: words/english.txt
+`)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+
+	var manifestErr *packagemanager.ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected a ManifestError, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, issue := range manifestErr.Issues {
+		fields[issue.Field] = true
+	}
+	for _, field := range []string{"data[0].path", "data[1].name"} {
+		if !fields[field] {
+			t.Errorf("expected an issue for %s, got %+v", field, manifestErr.Issues)
+		}
+	}
+}
+
+// newDataReleaseManager serves a GitHub release of acme/tool holding the
+// binary and the given data assets, except those named missing-*, which are
+// only declared in the manifest. Downloads all wait until inFlight of them
+// are in progress at once, for at most a second. It returns the manager and
+// the highest number of concurrent downloads.
+func newDataReleaseManager(t *testing.T, data []string, inFlight int) (*packagemanager.PackageManager, func() int) {
+	t.Helper()
+
+	manifest := fmt.Sprintf("name: tool\nbinary:\n  assets:\n    %s-%s: tool\ndata:\n", runtime.GOOS, runtime.GOARCH)
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+	for _, name := range data {
+		manifest += fmt.Sprintf("  - name: %s\n    asset: %s\n", name, name)
+		if !strings.HasPrefix(name, "missing-") {
+			release.Assets = append(release.Assets, packagemanager.ReleaseAsset{ID: len(release.Assets) + 1, Name: name})
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		current int
+		peak    int
+		once    sync.Once
+	)
+	ready := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current++
+		peak = max(peak, current)
+		if current == inFlight {
+			once.Do(func() { close(ready) })
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			current--
+			mu.Unlock()
+		}()
+
+		select {
+		case <-ready:
+		case <-time.After(time.Second):
+		}
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		fmt.Fprintf(w, "#!/bin/sh\necho %s\n", release.Assets[id-1].Name)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return peak
+	}
+}
+
+func TestDataAssetsDownloadConcurrently(t *testing.T) {
+	t.Parallel()
+
+	pkgm, peak := newDataReleaseManager(t, []string{"model", "vocab", "config"}, packagemanager.DefaultAssetWorkers)
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if got := peak(); got != packagemanager.DefaultAssetWorkers {
+		t.Errorf("expected the binary and data assets to download %d at once, got %d", packagemanager.DefaultAssetWorkers, got)
+	}
+	for _, name := range []string{"model", "vocab", "config"} {
+		content, err := os.ReadFile(metadata.DataFiles[name])
+		if err != nil || string(content) != "#!/bin/sh\necho "+name+"\n" {
+			t.Errorf("expected data file %s to be downloaded, got %q (%v)", name, content, err)
+		}
+	}
+
+	// With two workers, one data asset downloads next to the binary.
+	pkgm, peak = newDataReleaseManager(t, []string{"model", "vocab", "config"}, 2)
+	pkgm.SetAssetWorkers(2)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if got := peak(); got != 2 {
+		t.Errorf("expected at most 2 downloads at once, got %d", got)
+	}
+}
+
+func TestDataAssetErrorsAreAggregated(t *testing.T) {
+	t.Parallel()
+
+	pkgm, _ := newDataReleaseManager(t, []string{"missing-vocab", "model", "missing-stopwords"}, 2)
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if err == nil {
+		t.Fatal("expected the install to fail")
+	}
+	for _, name := range []string{"missing-vocab", "missing-stopwords"} {
+		if !strings.Contains(err.Error(), "failed to download data asset '"+name+"'") {
+			t.Errorf("expected the error to report %s, got %v", name, err)
+		}
+	}
+	if _, err := pkgm.GetMetadata("tool", ""); err == nil {
+		t.Error("expected the failed install not to be recorded")
+	}
+}
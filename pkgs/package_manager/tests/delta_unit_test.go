@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/klauspost/compress/zstd"
+)
+
+// deltaServer publishes acme/tool at v1.0.0 and v2.0.0, with v2.0.0 also
+// shipping patch as the zstd patch from v1.0.0, and counts full downloads
+// of v2.0.0.
+func deltaServer(t *testing.T, v1, v2, patch []byte) (*packagemanager.PackageManager, *atomic.Int32) {
+	t.Helper()
+
+	asset := fmt.Sprintf("tool-%s-%s", runtime.GOOS, runtime.GOARCH)
+	manifest := fmt.Sprintf("name: tool\nbinary:\n  assets:\n    %s-%s: %s\n  patch: \"{asset}.{from}.zst\"\n", runtime.GOOS, runtime.GOARCH, asset)
+	releases := map[string]packagemanager.GitHubRelease{
+		"v1.0.0": {TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: asset}}},
+		"v2.0.0": {TagName: "v2.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 2, Name: asset}, {ID: 3, Name: asset + ".v1.0.0.zst"}}},
+	}
+	contents := map[string][]byte{"1": v1, "2": v2, "3": patch}
+
+	var fullDownloads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		release, ok := releases[r.PathValue("tag")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "2" {
+			fullDownloads.Add(1)
+		}
+		w.Write(contents[r.PathValue("id")])
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm, &fullDownloads
+}
+
+// makePatch encodes new as a zstd patch against old, like
+// `zstd --patch-from=old new`.
+func makePatch(t *testing.T, old, new []byte) []byte {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderDictRaw(0, old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll(new, nil)
+}
+
+func TestDeltaUpdate(t *testing.T) {
+	t.Parallel()
+
+	v1 := []byte("#!/bin/sh\n" + strings.Repeat("echo v1 does its job\n", 2000))
+	v2 := []byte("#!/bin/sh\n" + strings.Repeat("echo v1 does its job\n", 1999) + "echo v2\n")
+
+	tests := []struct {
+		name          string
+		patch         []byte
+		fullDownloads int32
+	}{
+		{name: "applies the patch", patch: makePatch(t, v1, v2), fullDownloads: 0},
+		{name: "falls back on a corrupt patch", patch: []byte("not a zstd frame"), fullDownloads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm, fullDownloads := deltaServer(t, v1, v2, tt.patch)
+			if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v1.0.0"}); err != nil {
+				t.Fatalf("installing v1.0.0 failed: %s", err)
+			}
+
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v2.0.0", Force: true})
+			if err != nil {
+				t.Fatalf("installing v2.0.0 failed: %s", err)
+			}
+
+			got, err := os.ReadFile(metadata.BinaryPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(v2) {
+				t.Error("expected the installed binary to be v2.0.0")
+			}
+			if n := fullDownloads.Load(); n != tt.fullDownloads {
+				t.Errorf("expected %d full downloads of v2.0.0, got %d", tt.fullDownloads, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallImageBlock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake container CLI is a POSIX shell script")
+	}
+
+	// The fake CLI logs its calls and reports a registry digest.
+	cliDir := t.TempDir()
+	cli := filepath.Join(cliDir, "docker")
+	script := "#!/bin/sh\necho \"$@\" >> " + filepath.Join(cliDir, "calls") + "\n[ \"$1\" = image ] && echo example.com/acme/upper@sha256:abc\nexit 0\n"
+	if err := os.WriteFile(cli, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake CLI: %s", err)
+	}
+	t.Setenv(packagemanager.EnvContainerCLI, cli)
+
+	blockDir := t.TempDir()
+	manifest := "name: upper\nversion: v1.2.0\nbinary:\n  from: docker\n  image: example.com/acme/upper:{version}\n"
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	image, ok, err := packagemanager.ImageRef(metadata.BinaryPath)
+	if err != nil || !ok || image != "example.com/acme/upper@sha256:abc" {
+		t.Errorf("expected the pinned image to be recorded, got %q (image block %v, %v)", image, ok, err)
+	}
+	calls, _ := os.ReadFile(filepath.Join(cliDir, "calls"))
+	if !strings.HasPrefix(string(calls), "pull example.com/acme/upper:v1.2.0\n") {
+		t.Errorf("expected the versioned image to be pulled, got calls:\n%s", calls)
+	}
+
+	result, err := pkgm.Verify("upper")
+	if err != nil || !result.OK() {
+		t.Errorf("expected the image block to verify, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallFromGitHubEnterprise(t *testing.T) {
+	t.Parallel()
+
+	manifest := fmt.Sprintf("name: internal\nversion: v2.0.0\nbinary:\n  assets:\n    %s-%s: internal\n", runtime.GOOS, runtime.GOARCH)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/corp/internal/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/v3/repos/corp/internal/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v2.0.0"})
+	})
+	mux.HandleFunc("/api/v3/repos/corp/internal/releases/tags/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v2.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 9, Name: "internal"}}})
+	})
+	mux.HandleFunc("/api/v3/repos/corp/internal/releases/assets/9", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\n")
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghe-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "ghe-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: "github.example.com"}); err == nil {
+		t.Error("expected an API URL without a scheme to be rejected")
+	}
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api/v3", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "corp/internal"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if metadata.Name != "internal" || metadata.Version != "v2.0.0" {
+		t.Errorf("expected internal v2.0.0, got %s %s", metadata.Name, metadata.Version)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestErrBlockNotFound(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "present")}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	for name, err := range map[string]error{
+		"Uninstall":        pkgm.Uninstall(t.Context(), "missing"),
+		"MarkUsed":         pkgm.MarkUsed("missing"),
+		"Pin":              pkgm.Pin("missing"),
+		"UninstallVersion": pkgm.UninstallVersion(t.Context(), "present", "v9.9.9"),
+	} {
+		if !errors.Is(err, packagemanager.ErrBlockNotFound) || !strings.Contains(fmt.Sprint(err), "is not installed") {
+			t.Errorf("%s: expected ErrBlockNotFound, got %v", name, err)
+		}
+	}
+	if _, err := pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "missing"}); !errors.Is(err, packagemanager.ErrBlockNotFound) {
+		t.Errorf("Update: expected ErrBlockNotFound, got %v", err)
+	}
+}
+
+func TestErrAssetMissingForPlatform(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte("name: elsewhere\nversion: v1.0.0\nbinary:\n  assets:\n    plan9-386: elsewhere\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(dir)})
+	if !errors.Is(err, packagemanager.ErrAssetMissingForPlatform) {
+		t.Fatalf("expected ErrAssetMissingForPlatform, got %v", err)
+	}
+}
+
+func TestErrAuthAndErrRateLimited(t *testing.T) {
+	t.Parallel()
+
+	if !errors.Is(fmt.Errorf("install: %w", &packagemanager.AuthError{Repo: "acme/tool"}), packagemanager.ErrAuth) {
+		t.Error("expected a wrapped AuthError to match ErrAuth")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, `{"message":"API rate limit exceeded"}`, http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if !errors.Is(err, packagemanager.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// recordingListener records the lifecycle events it receives.
+type recordingListener struct {
+	packagemanager.NopEventListener
+
+	mu        sync.Mutex
+	events    []string
+	completed []packagemanager.InstallEvent
+	downloads int
+}
+
+func (l *recordingListener) OnInstallStart(e packagemanager.InstallEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "start")
+}
+
+func (l *recordingListener) OnDownloadProgress(p packagemanager.Progress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.downloads++
+}
+
+func (l *recordingListener) OnInstallComplete(e packagemanager.InstallEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "complete")
+	l.completed = append(l.completed, e)
+}
+
+func (l *recordingListener) OnUninstall(e packagemanager.UninstallEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "uninstall "+e.Name+"@"+e.Version)
+}
+
+func TestEventListener(t *testing.T) {
+	t.Parallel()
+
+	listener := &recordingListener{}
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.AddEventListener(listener)
+
+	repo := writeLocalTestBlock(t, "observed")
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file:///does/not/exist"}); err == nil {
+		t.Fatal("expected installing a missing directory to fail")
+	}
+	if err := pkgm.Uninstall(t.Context(), "observed"); err != nil {
+		t.Fatalf("pkgm.Uninstall() failed: %s", err)
+	}
+
+	want := []string{"start", "complete", "start", "complete", "uninstall observed@v0.1.0"}
+	if !slices.Equal(listener.events, want) {
+		t.Errorf("expected events %v, got %v", want, listener.events)
+	}
+	if listener.downloads == 0 {
+		t.Error("expected download progress events")
+	}
+	if len(listener.completed) == 2 {
+		if ok := listener.completed[0]; ok.Err != nil || ok.Metadata == nil || ok.Metadata.Name != "observed" {
+			t.Errorf("expected a successful install of observed, got %+v", ok)
+		}
+		if failed := listener.completed[1]; failed.Err == nil || failed.Metadata != nil {
+			t.Errorf("expected a failed install event, got %+v", failed)
+		}
+	}
+}
+
+// reentrantListener reads the package manager from OnUninstall.
+type reentrantListener struct {
+	packagemanager.NopEventListener
+
+	pkgm   *packagemanager.PackageManager
+	mu     sync.Mutex
+	loaded []bool
+}
+
+func (l *reentrantListener) OnUninstall(e packagemanager.UninstallEvent) {
+	_, ok := l.pkgm.GetLoadedBlock(e.Name)
+	if _, err := l.pkgm.List(); err != nil {
+		panic(err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loaded = append(l.loaded, ok)
+}
+
+func TestListenerCallsBackOnUninstall(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	listener := &reentrantListener{pkgm: pkgm}
+	pkgm.AddEventListener(listener)
+
+	for _, name := range []string{"kept", "purged"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, name)}); err != nil {
+			t.Fatalf("pkgm.Install() failed: %s", err)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if err := pkgm.Uninstall(t.Context(), "kept"); err != nil {
+			done <- err
+			return
+		}
+		_, err := pkgm.Purge(t.Context(), "purged")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("uninstall failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("uninstall deadlocked on a listener reading the package manager")
+	}
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	if !slices.Equal(listener.loaded, []bool{false, false}) {
+		t.Errorf("expected listeners to see the blocks already removed, got %v", listener.loaded)
+	}
+}
+
+func TestAddEventListenerDuringInstalls(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	reqs := []packagemanager.InstallRequest{
+		{Repo: writeLocalTestBlock(t, "first")},
+		{Repo: writeLocalTestBlock(t, "second")},
+		{Repo: writeLocalTestBlock(t, "third")},
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 10 {
+			pkgm.AddEventListener(&recordingListener{})
+		}
+	})
+	if _, err := pkgm.InstallAll(t.Context(), reqs, 3); err != nil {
+		t.Fatalf("InstallAll failed: %v", err)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+const listingPath = "/api/repos/acme/suite/contents/agentic_support"
+
+// listingServer answers the agentic_support/ listing of acme/suite with
+// handle, and records the If-None-Match header of each listing request.
+type listingServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	conditions []string
+}
+
+func newListingServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request, n int)) *listingServer {
+	t.Helper()
+
+	s := &listingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != listingPath {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		s.conditions = append(s.conditions, r.Header.Get("If-None-Match"))
+		n := len(s.conditions)
+		s.mu.Unlock()
+		handle(w, r, n)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// requests returns the If-None-Match header of every listing request.
+func (s *listingServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.conditions)
+}
+
+// manager returns a package manager installing into installDir whose GitHub
+// calls go to s.
+func (s *listingServer) manager(t *testing.T, installDir string) *packagemanager.PackageManager {
+	t.Helper()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: s.URL + "/api", RawURL: s.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	if err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{Retry: packagemanager.RetryPolicy{Delay: time.Millisecond}}); err != nil {
+		t.Fatalf("SetHTTPConfig failed: %v", err)
+	}
+	return pkgm
+}
+
+const suiteListing = `[{"name":"alpha.yaml","type":"file"},{"name":"beta.yaml","type":"file"}]`
+
+func TestGitHubConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	server := newListingServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, suiteListing)
+	})
+
+	installDir := t.TempDir()
+	pkgm := server.manager(t, installDir)
+	for range 2 {
+		blocks, err := pkgm.ListRepoBlocks(t.Context(), "acme/suite")
+		if err != nil || !slices.Equal(blocks, []string{"alpha", "beta"}) {
+			t.Fatalf("expected blocks alpha and beta, got %v, %v", blocks, err)
+		}
+	}
+
+	// The cache is on disk, so a new manager revalidates it too.
+	blocks, err := server.manager(t, installDir).ListRepoBlocks(t.Context(), "acme/suite")
+	if err != nil || !slices.Equal(blocks, []string{"alpha", "beta"}) {
+		t.Fatalf("expected the cached blocks, got %v, %v", blocks, err)
+	}
+
+	if got, want := server.requests(), []string{"", `"v1"`, `"v1"`}; !slices.Equal(got, want) {
+		t.Errorf("expected If-None-Match headers %q, got %q", want, got)
+	}
+}
+
+func TestGitHubRateLimitRetries(t *testing.T) {
+	t.Parallel()
+
+	server := newListingServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"message":"You have exceeded a secondary rate limit"}`, http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, suiteListing)
+	})
+
+	blocks, err := server.manager(t, t.TempDir()).ListRepoBlocks(t.Context(), "acme/suite")
+	if err != nil || !slices.Equal(blocks, []string{"alpha", "beta"}) {
+		t.Fatalf("expected the retry to list alpha and beta, got %v, %v", blocks, err)
+	}
+	if n := len(server.requests()); n != 2 {
+		t.Errorf("expected one retry of the rate-limited request, got %d requests", n)
+	}
+}
+
+func TestGitHubForbiddenIsNotRetried(t *testing.T) {
+	t.Parallel()
+
+	server := newListingServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	})
+
+	_, err := server.manager(t, t.TempDir()).ListRepoBlocks(t.Context(), "acme/suite")
+	if !errors.Is(err, packagemanager.ErrAuth) {
+		t.Errorf("expected ErrAuth, got %v", err)
+	}
+	if n := len(server.requests()); n != 1 {
+		t.Errorf("expected a permission error to be returned as is, got %d requests", n)
+	}
+}
+
+func TestGitHubExhaustedRateLimit(t *testing.T) {
+	t.Parallel()
+
+	// The first answer spends the last request until the reset in an hour.
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	server := newListingServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		fmt.Fprint(w, suiteListing)
+	})
+
+	pkgm := server.manager(t, t.TempDir())
+	if _, err := pkgm.ListRepoBlocks(t.Context(), "acme/suite"); err != nil {
+		t.Fatalf("ListRepoBlocks failed: %v", err)
+	}
+
+	// Cached responses are served without a request until the reset.
+	blocks, err := pkgm.ListRepoBlocks(t.Context(), "acme/suite")
+	if err != nil || !slices.Equal(blocks, []string{"alpha", "beta"}) {
+		t.Fatalf("expected the cached blocks, got %v, %v", blocks, err)
+	}
+	if n := len(server.requests()); n != 1 {
+		t.Errorf("expected no request once the rate limit is exhausted, got %d requests", n)
+	}
+
+	// Uncached lookups fail fast rather than waiting an hour.
+	if _, err := pkgm.ListRepoBlocks(t.Context(), "acme/suite/tools"); !errors.Is(err, packagemanager.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
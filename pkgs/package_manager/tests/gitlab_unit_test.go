@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// newFakeGitLab serves the project group/tool with an upcoming release, a
+// prerelease, a yanked release and two stable releases, newest first. Every
+// request must carry the "gl-token" private token.
+func newFakeGitLab(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	manifest := fmt.Sprintf("name: tool\nversion: v1.1.0\nsource:\n  type: gitlab\nyanked_versions: [v1.2.0]\nbinary:\n  assets:\n    %s-%s: tool\n",
+		runtime.GOOS, runtime.GOARCH)
+	tags := []string{"v3.0.0", "v2.0.0-beta.1", "v1.2.0", "v1.1.0", "v1.0.0"}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "gl-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+
+		path := r.URL.EscapedPath()
+		switch {
+		case path == "/api/v4/projects/group%2Ftool/repository/files/agentic_support.yaml/raw":
+			fmt.Fprint(w, manifest)
+		case path == "/api/v4/projects/group%2Ftool/releases":
+			var releases []map[string]any
+			for _, tag := range tags {
+				releases = append(releases, map[string]any{
+					"tag_name":         tag,
+					"upcoming_release": tag == "v3.0.0",
+					"assets": map[string]any{"links": []map[string]string{{
+						"name":             "tool",
+						"url":              server.URL + "/downloads/unused",
+						"direct_asset_url": server.URL + "/downloads/" + tag,
+					}}},
+				})
+			}
+			json.NewEncoder(w).Encode(releases)
+		case strings.HasPrefix(path, "/downloads/v"):
+			fmt.Fprintf(w, "#!/bin/sh\necho %s\n", strings.TrimPrefix(path, "/downloads/"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitLabReleaseSelection(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGitLab(t)
+	tests := []struct {
+		version string
+		force   bool
+		want    string
+	}{
+		{version: "", want: "v2.0.0-beta.1"},
+		{version: packagemanager.ChannelStable, want: "v1.1.0"},
+		{version: "^1.0.0", want: "v1.1.0"},
+		{version: "1.0.0", want: "v1.0.0"},
+		{version: "v1.2.0", force: true, want: "v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+			pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+				if req.Service == packagemanager.ServiceGitLab {
+					return "gl-token", nil
+				}
+				return "", nil
+			}))
+
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "gitlab:" + server.URL + "/group/tool", Version: tt.version, Force: tt.force})
+			if err != nil {
+				t.Fatalf("pkgm.Install() failed: %s", err)
+			}
+			if metadata.Version != tt.want {
+				t.Errorf("expected %q to select %s, got %s", tt.version, tt.want, metadata.Version)
+			}
+			// Links are downloaded through their direct asset URL.
+			data, err := os.ReadFile(metadata.BinaryPath)
+			if err != nil {
+				t.Fatalf("Failed to read binary: %s", err)
+			}
+			if !strings.Contains(string(data), "echo "+tt.want) {
+				t.Errorf("expected the %s asset, got %q", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestGitLabReleaseSelectionErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeGitLab(t)
+	repo := "gitlab:" + server.URL + "/group/tool"
+	newManager := func(token string) *packagemanager.PackageManager {
+		pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+		pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+			return token, nil
+		}))
+		return pkgm
+	}
+
+	// Yanked releases are only installed when forced.
+	_, err := newManager("gl-token").Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Version: "v1.2.0"})
+	if !errors.Is(err, packagemanager.ErrYankedVersion) {
+		t.Errorf("expected ErrYankedVersion, got %v", err)
+	}
+
+	for _, version := range []string{"v3.0.0", "9.9.9", "^4.0.0"} {
+		if _, err := newManager("gl-token").Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Version: version}); err == nil {
+			t.Errorf("expected %q to match no published release", version)
+		}
+	}
+
+	_, err = newManager("").Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+	if !errors.Is(err, packagemanager.ErrAuth) {
+		t.Errorf("expected ErrAuth without a token, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test binary is a POSIX shell script")
+	}
+
+	writeBlock := func(expect string) string {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "checked"), []byte("#!/bin/sh\n[ \"$1\" = --version ] && echo checked 1.2.3\n"), 0755); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+		manifest := fmt.Sprintf("name: checked\nversion: v1.2.3\nbinary:\n  assets:\n    %s-%s: checked\nhealthcheck:\n  args: [--version]\n  expect: %q\n", runtime.GOOS, runtime.GOARCH, expect)
+		if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+		return "file://" + filepath.ToSlash(dir)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeBlock(`^checked 2\.`)})
+	if err == nil || !strings.Contains(err.Error(), "health check") {
+		t.Fatalf("expected the health check to fail the install, got %v", err)
+	}
+	if _, ok := pkgm.GetLoadedBlock("checked"); ok {
+		t.Error("expected a block failing its health check not to be installed")
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeBlock(`^checked 1\.\d+`)})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if metadata.HealthCheck == nil || metadata.HealthCheck.Args[0] != "--version" {
+		t.Errorf("expected the health check to be recorded, got %+v", metadata.HealthCheck)
+	}
+}
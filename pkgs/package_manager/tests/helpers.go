@@ -10,10 +10,18 @@
 package tests
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -311,3 +319,115 @@ func CompareBlockTimestamps(t *testing.T, original, retrieved *packagemanager.Bl
 		t.Errorf("LastUpdated time difference too large: %v", timeDiff)
 	}
 }
+
+// writeLocalTestBlock creates a local block named name and returns its repo.
+func writeLocalTestBlock(t *testing.T, name string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := fmt.Sprintf("name: %s\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: %s\n", name, runtime.GOOS, runtime.GOARCH, name)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+// newFakeGitHubManager returns a package manager whose GitHub calls go to
+// a fake server publishing each repo ("owner/name") with the given manifest
+// name at release v1.0.0.
+func newFakeGitHubManager(t *testing.T, repos map[string]string) *packagemanager.PackageManager {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for repo, name := range repos {
+		manifest := fmt.Sprintf("name: %s\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: %s\n", name, runtime.GOOS, runtime.GOARCH, name)
+		mux.HandleFunc("/raw/"+repo+"/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, manifest)
+		})
+		mux.HandleFunc("/api/repos/"+repo+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v1.0.0"})
+		})
+		mux.HandleFunc("/api/repos/"+repo+"/releases", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]packagemanager.GitHubRelease{{TagName: "v1.0.0"}})
+		})
+		mux.HandleFunc("/api/repos/"+repo+"/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v1.0.0", Body: "Changes in " + repo, Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: name}}})
+		})
+		mux.HandleFunc("/api/repos/"+repo+"/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "#!/bin/sh\necho %s\n", repo)
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm
+}
+
+// newFakeReleasesManager is like newFakeGitHubManager for a single repo
+// publishing releases, newest first. Each release's asset is a script
+// echoing its tag.
+func newFakeReleasesManager(t *testing.T, repo, name string, releases []packagemanager.GitHubRelease) *packagemanager.PackageManager {
+	t.Helper()
+
+	manifest := fmt.Sprintf("name: %s\nbinary:\n  assets:\n    %s-%s: %s\n", name, runtime.GOOS, runtime.GOARCH, name)
+	byTag := map[string]packagemanager.GitHubRelease{}
+	for i := range releases {
+		sum := sha256.Sum256(fmt.Appendf(nil, "#!/bin/sh\necho %s\n", releases[i].TagName))
+		releases[i].Assets = []packagemanager.ReleaseAsset{{ID: i + 1, Name: name, Digest: "sha256:" + hex.EncodeToString(sum[:])}}
+		byTag[releases[i].TagName] = releases[i]
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/"+repo+"/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/repos/"+repo+"/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(releases)
+	})
+	mux.HandleFunc("/api/repos/"+repo+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		for _, release := range releases {
+			if !release.Prerelease && !release.Draft {
+				json.NewEncoder(w).Encode(release)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/api/repos/"+repo+"/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		release, ok := byTag[r.PathValue("tag")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/"+repo+"/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		if id < 1 || id > len(releases) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "#!/bin/sh\necho %s\n", releases[id-1].TagName)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallHistory(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeReleasesManager(t, "acme/tool", "tool", []packagemanager.GitHubRelease{{TagName: "v1.1.0"}, {TagName: "v1.0.0"}})
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v1.0.0"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	// Already installed: nothing happens, and nothing is recorded.
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("second Install failed: %v", err)
+	}
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v9.9.9", Force: true}); err == nil {
+		t.Fatal("expected the install of a missing version to fail")
+	}
+	if _, err := pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "tool"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := pkgm.Uninstall(t.Context(), "tool"); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+
+	history, err := pkgm.GetInstallHistory("tool")
+	if err != nil {
+		t.Fatalf("GetInstallHistory failed: %v", err)
+	}
+	want := []packagemanager.InstallRecord{
+		{Operation: packagemanager.OperationInstall, Repo: "acme/tool", Requested: "v1.0.0", Version: "v1.0.0", Asset: "tool"},
+		{Operation: packagemanager.OperationInstall, Repo: "acme/tool", Requested: "v9.9.9"},
+		{Operation: packagemanager.OperationUpdate, Repo: "acme/tool", Requested: "v1.1.0", Version: "v1.1.0", PreviousVersion: "v1.0.0", Asset: "tool"},
+		{Operation: packagemanager.OperationUninstall, Repo: "acme/tool", Version: "v1.1.0"},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), history)
+	}
+	for i, record := range history {
+		if record.Time.IsZero() || (record.Error != "") != (i == 1) || (record.SHA256 != "") != (want[i].Asset != "") {
+			t.Errorf("record %d: unexpected time, error, or digest: %+v", i, record)
+		}
+		record.Time, record.Duration, record.Error, record.SHA256 = want[i].Time, 0, "", ""
+		if record != want[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, want[i], record)
+		}
+	}
+
+	if history, err := pkgm.GetInstallHistory("missing"); err != nil || history != nil {
+		t.Errorf("expected no history for a block never installed, got %v, %v", history, err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestPostInstallHooks(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test use sh syntax")
+	}
+
+	blockDir := t.TempDir()
+	writeManifest := func(version, hooks string) {
+		manifest := fmt.Sprintf("name: hooked\nversion: %s\nbinary:\n  assets:\n    %s-%s: hooked\nhooks:\n  post_install:\n%s",
+			version, runtime.GOOS, runtime.GOARCH, hooks)
+		if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(blockDir, "hooked"), []byte("#!/bin/sh\necho hooked\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	writeManifest("v1.0.0", `    - mkdir -p "$ATOMOS_BLOCK_DATA_DIR/models" && echo weights > "$ATOMOS_BLOCK_DATA_DIR/models/m.bin"
+    - echo "installed $ATOMOS_BLOCK_NAME $ATOMOS_BLOCK_VERSION"
+`)
+
+	installDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	repo := "file://" + filepath.ToSlash(blockDir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	blockRoot := filepath.Join(pkgm.InstallDir, "hooked")
+	if data, err := os.ReadFile(filepath.Join(blockRoot, "data", "models", "m.bin")); err != nil || string(data) != "weights\n" {
+		t.Errorf("expected the hook to create the model file, got %q (%v)", data, err)
+	}
+	log, err := os.ReadFile(filepath.Join(blockRoot, "install.log"))
+	if err != nil || !strings.Contains(string(log), "installed hooked v1.0.0") {
+		t.Errorf("expected the hook output in install.log, got %q (%v)", log, err)
+	}
+
+	writeManifest("v2.0.0", "    - echo broken >&2; exit 3\n")
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Force: true}); err == nil {
+		t.Fatal("expected a failing hook to fail the install")
+	}
+	if metadata, _ := pkgm.GetLoadedBlock("hooked"); metadata.Version != "v1.0.0" {
+		t.Errorf("expected v1.0.0 to stay active after the failed install, got %s", metadata.Version)
+	}
+	if log, _ := os.ReadFile(filepath.Join(blockRoot, "install.log")); !strings.Contains(string(log), "broken") {
+		t.Errorf("expected the failing hook's stderr in install.log, got %q", log)
+	}
+}
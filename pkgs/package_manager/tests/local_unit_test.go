@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallFromLocalDirectory(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	binary := []byte("#!/bin/sh\necho local\n")
+	sum := sha256.Sum256(binary)
+	digest := hex.EncodeToString(sum[:])
+
+	if err := os.WriteFile(filepath.Join(blockDir, "local-block"), binary, 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	manifest := fmt.Sprintf(`name: local-block
+description: A block installed from disk
+version: v0.1.0
+binary:
+  assets:
+    %s-%s: local-block
+checksums:
+  local-block: %s
+entries:
+  - name: run
+    description: Run the block
+`, runtime.GOOS, runtime.GOARCH, digest)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	if metadata.Name != "local-block" || metadata.Version != "v0.1.0" {
+		t.Errorf("expected local-block v0.1.0, got %s %s", metadata.Name, metadata.Version)
+	}
+	if metadata.SHA256 != digest {
+		t.Errorf("expected digest %s, got %s", digest, metadata.SHA256)
+	}
+	if _, ok := metadata.LSPEntries["run"]; !ok {
+		t.Error("expected entry 'run' to be recorded")
+	}
+
+	installed, err := os.ReadFile(metadata.BinaryPath)
+	if err != nil {
+		t.Fatalf("Failed to read installed binary: %s", err)
+	}
+	if string(installed) != string(binary) {
+		t.Error("installed binary differs from the local one")
+	}
+}
+
+func TestInstallAllFromLocalDirectories(t *testing.T) {
+	t.Parallel()
+
+	var reqs []packagemanager.InstallRequest
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		blockDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(blockDir, name), []byte("#!/bin/sh\n"), 0644); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+
+		manifest := fmt.Sprintf("name: %s\nbinary:\n  assets:\n    %s-%s: %s\n", name, runtime.GOOS, runtime.GOARCH, name)
+		if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+
+		reqs = append(reqs, packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+	}
+	reqs = append(reqs, reqs[0], packagemanager.InstallRequest{Repo: "file:///does/not/exist"})
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	results, err := pkgm.InstallAll(t.Context(), reqs, 2)
+	if err == nil {
+		t.Fatal("expected an error for the missing directory")
+	}
+	if len(results) != len(reqs) {
+		t.Fatalf("expected %d results, got %d", len(reqs), len(results))
+	}
+
+	for i, name := range []string{"alpha", "beta", "gamma", "alpha"} {
+		if results[i].Err != nil {
+			t.Fatalf("result %d failed: %s", i, results[i].Err)
+		}
+		if results[i].Metadata.Name != name {
+			t.Errorf("result %d: expected %s, got %s", i, name, results[i].Metadata.Name)
+		}
+	}
+	if results[4].Err == nil {
+		t.Error("expected the missing directory to fail")
+	}
+}
+
+// overlapListener records installs of a repository starting while another
+// install of it is still running. It slows every install down so that
+// concurrent ones overlap.
+type overlapListener struct {
+	packagemanager.NopEventListener
+
+	mu       sync.Mutex
+	running  map[string]int
+	overlaps []string
+}
+
+func (l *overlapListener) OnInstallStart(e packagemanager.InstallEvent) {
+	l.mu.Lock()
+	if l.running[e.Repo] > 0 {
+		l.overlaps = append(l.overlaps, e.Repo)
+	}
+	l.running[e.Repo]++
+	l.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+}
+
+func (l *overlapListener) OnInstallComplete(e packagemanager.InstallEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running[e.Repo]--
+}
+
+func TestInstallAllSerializesRepository(t *testing.T) {
+	t.Parallel()
+
+	alpha := writeLocalTestBlock(t, "alpha")
+	beta := writeLocalTestBlock(t, "beta")
+	reqs := []packagemanager.InstallRequest{
+		{Repo: alpha},
+		{Repo: alpha, Force: true},
+		{Repo: beta},
+		{Repo: alpha, Version: "v0.1.0"},
+		{Repo: alpha, Force: true},
+	}
+
+	listener := &overlapListener{running: map[string]int{}}
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.AddEventListener(listener)
+
+	results, err := pkgm.InstallAll(t.Context(), reqs, len(reqs))
+	if err != nil {
+		t.Fatalf("InstallAll failed: %v", err)
+	}
+	for i, want := range []string{"alpha", "alpha", "beta", "alpha", "alpha"} {
+		if results[i].Metadata == nil || results[i].Metadata.Name != want {
+			t.Errorf("result %d: expected %s, got %+v", i, want, results[i])
+		}
+	}
+	if results[4].Metadata != results[1].Metadata {
+		t.Error("expected identical requests to share their outcome")
+	}
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	if len(listener.overlaps) > 0 {
+		t.Errorf("expected installs of a repository to run one at a time, overlapped on %v", listener.overlaps)
+	}
+}
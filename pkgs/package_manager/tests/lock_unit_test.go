@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestConcurrentInstallAndUninstall(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+	// Two package managers on one directory stand in for two processes.
+	managers := []*packagemanager.PackageManager{
+		packagemanager.NewPackageManagerWithTestDir(testDir),
+		packagemanager.NewPackageManagerWithTestDir(testDir),
+	}
+	for _, pkgm := range managers {
+		locker := packagemanager.NewFileLocker(pkgm.InstallDir)
+		locker.PollInterval = 5 * time.Millisecond
+		pkgm.SetLocker(locker)
+	}
+
+	names := []string{"alpha", "beta", "gamma", "delta"}
+	repos := make(map[string]string, len(names))
+	for _, name := range names {
+		repos[name] = writeLocalTestBlock(t, name)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		pkgm := managers[i%len(managers)]
+		name := names[i%len(names)]
+		wg.Go(func() {
+			if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repos[name], Force: true}); err != nil {
+				t.Errorf("Install(%s) failed: %s", name, err)
+			}
+			if i%3 == 0 {
+				// The block may already be gone because of another goroutine.
+				_ = pkgm.Uninstall(t.Context(), name)
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, name := range names {
+		if _, err := managers[0].Install(t.Context(), packagemanager.InstallRequest{Repo: repos[name]}); err != nil {
+			t.Fatalf("Install(%s) after the concurrent run failed: %s", name, err)
+		}
+	}
+
+	reopened := packagemanager.NewPackageManagerWithTestDir(testDir)
+	for _, name := range names {
+		metadata, ok := reopened.GetLoadedBlock(name)
+		if !ok {
+			t.Errorf("expected %s to be installed", name)
+			continue
+		}
+		if _, err := os.Stat(metadata.BinaryPath); err != nil {
+			t.Errorf("expected the binary of %s to exist: %s", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(testDir, ".atomos", ".atomos.lock")); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be released, got %v", err)
+	}
+}
+
+func TestLockOfExitedProcessIsBroken(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell to produce an exited process")
+	}
+
+	cmd := exec.Command("sh", "-c", "exit 0")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run sh: %s", err)
+	}
+
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+
+	host, _ := os.Hostname()
+	lock, _ := json.Marshal(map[string]any{"token": 1, "host": host, "pid": cmd.Process.Pid, "acquired_at": time.Now()})
+	if err := os.WriteFile(filepath.Join(pkgm.InstallDir, ".atomos.lock"), lock, 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %s", err)
+	}
+
+	start := time.Now()
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "orphan")}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if waited := time.Since(start); waited > 30*time.Second {
+		t.Errorf("expected the orphaned lock to be broken right away, waited %s", waited)
+	}
+}
+
+func TestFileLockerFencing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := packagemanager.NewFileLocker(dir)
+	first.Timeout = 20 * time.Millisecond
+	first.PollInterval = 5 * time.Millisecond
+	second := packagemanager.NewFileLocker(dir)
+	second.Timeout = 20 * time.Millisecond
+	second.PollInterval = 5 * time.Millisecond
+
+	token, err := first.Lock(t.Context())
+	if err != nil {
+		t.Fatalf("Lock failed: %s", err)
+	}
+	if _, err := second.Lock(t.Context()); err == nil {
+		t.Fatal("expected a held lock to time out")
+	}
+	if err := first.Validate(token); err != nil {
+		t.Errorf("expected the holder to keep the lock, got %s", err)
+	}
+
+	// Another host breaks the lock once it outlives its TTL.
+	second.TTL = time.Nanosecond
+	next, err := second.Lock(t.Context())
+	if err != nil {
+		t.Fatalf("Lock of an expired lock failed: %s", err)
+	}
+	if next <= token {
+		t.Errorf("expected fencing tokens to increase, got %d after %d", next, token)
+	}
+
+	if err := first.Validate(token); err == nil {
+		t.Error("expected the stale holder to have lost the lock")
+	}
+	if err := first.Unlock(token); err == nil {
+		t.Error("expected the stale holder's unlock to fail")
+	}
+	if err := second.Validate(next); err != nil {
+		t.Errorf("expected the stale unlock to leave the new lock alone, got %s", err)
+	}
+	if err := second.Unlock(next); err != nil {
+		t.Errorf("Unlock failed: %s", err)
+	}
+}
+
+func TestFileLockerHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	holder := packagemanager.NewFileLocker(dir)
+	if _, err := holder.Lock(t.Context()); err != nil {
+		t.Fatalf("Lock failed: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	waiter := packagemanager.NewFileLocker(dir)
+	waiter.PollInterval = 5 * time.Millisecond
+	if _, err := waiter.Lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected waiting to stop with the context, got %v", err)
+	}
+}
+
+// lostLocker grants the lock but reports it lost once it has been taken.
+type lostLocker struct {
+	mu       sync.Mutex
+	unlocked int
+}
+
+func (l *lostLocker) Lock(ctx context.Context) (uint64, error) { return 1, nil }
+
+func (l *lostLocker) Validate(token uint64) error {
+	return errors.New("lock lost: fencing token superseded")
+}
+
+func (l *lostLocker) Unlock(token uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unlocked++
+	return nil
+}
+
+func TestLostLockPreventsWrites(t *testing.T) {
+	t.Parallel()
+
+	locker := &lostLocker{}
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+	pkgm.SetLocker(locker)
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "fenced")}); err == nil || !strings.Contains(err.Error(), "lock lost") {
+		t.Fatalf("expected the install to fail with the lost lock, got %v", err)
+	}
+	if _, ok := pkgm.GetLoadedBlock("fenced"); ok {
+		t.Error("expected nothing to be recorded after the lock was lost")
+	}
+	if _, ok := packagemanager.NewPackageManagerWithTestDir(testDir).GetLoadedBlock("fenced"); ok {
+		t.Error("expected no metadata on disk after the lock was lost")
+	}
+
+	locker.mu.Lock()
+	defer locker.mu.Unlock()
+	if locker.unlocked != 1 {
+		t.Errorf("expected the lock to be released once, got %d", locker.unlocked)
+	}
+}
+
+// contendingListener tries to take the install dir lock from another host
+// once the install has held it for longer than its TTL.
+type contendingListener struct {
+	packagemanager.NopEventListener
+
+	held      time.Duration
+	contender *packagemanager.FileLocker
+	err       error
+}
+
+func (l *contendingListener) OnInstallStart(packagemanager.InstallEvent) {
+	time.Sleep(l.held)
+	_, l.err = l.contender.Lock(context.Background())
+}
+
+func TestLockRenewedWhileHeld(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	locker := packagemanager.NewFileLocker(pkgm.InstallDir)
+	locker.TTL = 60 * time.Millisecond
+	pkgm.SetLocker(locker)
+
+	contender := packagemanager.NewFileLocker(pkgm.InstallDir)
+	contender.TTL = locker.TTL
+	contender.Timeout = 2 * locker.TTL
+	contender.PollInterval = 5 * time.Millisecond
+	listener := &contendingListener{held: 3 * locker.TTL, contender: contender}
+	pkgm.AddEventListener(listener)
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "slow")}); err != nil {
+		t.Fatalf("expected the renewed lock to survive the install, got %s", err)
+	}
+	if listener.err == nil {
+		t.Error("expected the renewed lock to keep another host out")
+	}
+
+	// Once released, the lock is free again.
+	token, err := contender.Lock(t.Context())
+	if err != nil {
+		t.Fatalf("Lock after release failed: %s", err)
+	}
+	if err := contender.Unlock(token); err != nil {
+		t.Errorf("Unlock failed: %s", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestLoggerReceivesDebugOutput(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: logged\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: logged\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(blockDir, "logged"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	var buf bytes.Buffer
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	req := packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}
+	for range 2 {
+		if _, err := pkgm.Install(t.Context(), req); err != nil {
+			t.Fatalf("pkgm.Install() failed: %s", err)
+		}
+	}
+
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="copied local binary"`,
+		`msg="wrote block metadata" block=logged version=v0.1.0`,
+		`msg="block already installed, skipping download" block=logged`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected log to contain %s, got:\n%s", want, logs)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestManifestValidation(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := `name: broken
+version: v0.1.0
+binary:
+  assets:
+    linux-amd64: broken
+    macos-arm64: broken-mac
+hooks:
+  post_install:
+    - "  "
+entries:
+  - name: run
+  - name: run
+  - description: nameless
+`
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+
+	var manifestErr *packagemanager.ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected a ManifestError, got %v", err)
+	}
+
+	want := map[string]int{
+		packagemanager.CodeUnknownPlatform:   6,
+		packagemanager.CodeEmptyCommand:      9,
+		packagemanager.CodeDuplicateEntry:    12,
+		packagemanager.CodeMissingEntryField: 13,
+	}
+	if len(manifestErr.Issues) != len(want) {
+		t.Errorf("expected %d issues, got %+v", len(want), manifestErr.Issues)
+	}
+	for _, issue := range manifestErr.Issues {
+		if line, ok := want[issue.Code]; !ok || issue.Line != line {
+			t.Errorf("unexpected issue %s, expected %s on line %d", issue, issue.Code, line)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte("name: [broken\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	_, err = pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+	if !errors.As(err, &manifestErr) || manifestErr.Issues[0].Code != packagemanager.CodeManifestSyntax {
+		t.Errorf("expected a syntax issue, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestMetadataStoresOrderVersionsByInstall(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	indexed, err := packagemanager.NewIndexedMetadataStore(filepath.Join(dir, "metadata.db"))
+	if err != nil {
+		t.Fatalf("NewIndexedMetadataStore failed: %v", err)
+	}
+	stores := map[string]packagemanager.MetadataStore{
+		"file":    packagemanager.NewFileMetadataStore(filepath.Join(dir, "files")),
+		"indexed": indexed,
+	}
+
+	for kind, store := range stores {
+		installed := time.Now().Add(-time.Hour)
+		older := &packagemanager.BlockMetadata{Name: "block", Version: "v0.1.0", BinaryPath: "/bin/true", SourceRepo: "owner/block", InstalledAt: installed}
+		newer := &packagemanager.BlockMetadata{Name: "block", Version: "v0.2.0", BinaryPath: "/bin/true", SourceRepo: "owner/block", InstalledAt: installed.Add(time.Minute)}
+		for _, metadata := range []*packagemanager.BlockMetadata{older, newer} {
+			if err := store.Put(metadata); err != nil {
+				t.Fatalf("%s: Put failed: %v", kind, err)
+			}
+			// Keep the installs apart on filesystems with coarse timestamps.
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// Rewriting an older install must not make it the newest.
+		older.IsActive = false
+		older.LastUsed = time.Now()
+		if err := store.Put(older); err != nil {
+			t.Fatalf("%s: Put failed: %v", kind, err)
+		}
+
+		versions, err := store.Versions("block")
+		if err != nil || len(versions) != 2 || versions[0].Version != "v0.2.0" || versions[1].LastUsed.IsZero() {
+			t.Fatalf("%s: expected v0.2.0 then the rewritten v0.1.0, got %+v, %v", kind, versions, err)
+		}
+
+		if err := store.Delete("block", "v0.2.0"); err != nil {
+			t.Fatalf("%s: Delete failed: %v", kind, err)
+		}
+		if _, err := store.Get("block", "v0.2.0"); !errors.Is(err, packagemanager.ErrMetadataNotFound) {
+			t.Errorf("%s: expected ErrMetadataNotFound after Delete, got %v", kind, err)
+		}
+		if blocks, err := store.Blocks(); err != nil || !slices.Equal(blocks, []string{"block"}) {
+			t.Errorf("%s: expected only block, got %v, %v", kind, blocks, err)
+		}
+	}
+
+	if blocks, err := indexed.BySourceRepo("owner/block"); err != nil || !slices.Equal(blocks, []string{"block"}) {
+		t.Errorf("expected block to be indexed by its repository, got %v, %v", blocks, err)
+	}
+	if err := indexed.Delete("block", "v0.1.0"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if blocks, err := indexed.BySourceRepo("owner/block"); err != nil || len(blocks) != 0 {
+		t.Errorf("expected no blocks from the repository once uninstalled, got %v, %v", blocks, err)
+	}
+}
+
+func TestInstallWithIndexedMetadataStore(t *testing.T) {
+	t.Parallel()
+
+	installDir := t.TempDir()
+	storePath := filepath.Join(installDir, "metadata.db")
+	open := func() *packagemanager.PackageManager {
+		t.Helper()
+		store, err := packagemanager.NewIndexedMetadataStore(storePath)
+		if err != nil {
+			t.Fatalf("NewIndexedMetadataStore failed: %v", err)
+		}
+		pkgm, err := packagemanager.NewPackageManagerWithOptions(packagemanager.Options{InstallDir: installDir, MetadataStore: store})
+		if err != nil {
+			t.Fatalf("NewPackageManagerWithOptions failed: %v", err)
+		}
+		return pkgm
+	}
+
+	pkgm := open()
+	installed, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "indexed")})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "indexed", "metadata", installed.Version+".json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no per-version metadata file, got %v", err)
+	}
+
+	// A new package manager loads the installation from the store.
+	reopened := open()
+	metadata, err := reopened.GetMetadata("indexed", "")
+	if err != nil || metadata.Version != installed.Version {
+		t.Fatalf("expected the installed version from the store, got %+v, %v", metadata, err)
+	}
+	if _, ok := reopened.GetLoadedBlock("indexed"); !ok {
+		t.Error("expected indexed to be loaded from the store")
+	}
+
+	if err := reopened.Uninstall(t.Context(), "indexed"); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if _, err := open().GetMetadata("indexed", ""); err == nil {
+		t.Error("expected the uninstalled block to be gone from the store")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallMonorepoBlocks(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/mono/HEAD/tools/{block}/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		block := r.PathValue("block")
+		fmt.Fprintf(w, "name: %s\nbinary:\n  assets:\n    %s-%s: %s\n", block, runtime.GOOS, runtime.GOARCH, block)
+	})
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Body: "Monorepo release", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "profiler"}, {ID: 2, Name: "tracer"}}}
+	mux.HandleFunc("/api/repos/acme/mono/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/mono/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/mono/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "#!/bin/sh\necho asset %s\n", r.PathValue("id"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	for i, block := range []string{"profiler", "tracer"} {
+		repo := "acme/mono//tools/" + block
+		metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+		if err != nil {
+			t.Fatalf("Install of %s failed: %v", repo, err)
+		}
+		if metadata.Name != block || metadata.SourceRepo != repo || metadata.Version != "v1.0.0" {
+			t.Errorf("expected %s v1.0.0 from %s, got %+v", block, repo, metadata)
+		}
+		if data, _ := os.ReadFile(metadata.BinaryPath); !strings.Contains(string(data), fmt.Sprintf("asset %d", i+1)) {
+			t.Errorf("expected the %s asset, got %q", block, data)
+		}
+	}
+
+	notes, err := pkgm.GetReleaseNotes(t.Context(), "tracer", "")
+	if err != nil || notes.Body != "Monorepo release" {
+		t.Errorf("expected the release notes of the monorepo, got %+v, %v", notes, err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/mono//../other"}); err == nil || !strings.Contains(err.Error(), "inside the repository") {
+		t.Errorf("expected a directory outside the repository to be rejected, got %v", err)
+	}
+}
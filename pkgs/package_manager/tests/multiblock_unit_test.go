@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallMultiBlockRepository(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/suite/HEAD/agentic_support/{file}", func(w http.ResponseWriter, r *http.Request) {
+		block := strings.TrimSuffix(r.PathValue("file"), ".yaml")
+		if block == "renamed" {
+			block = "other"
+		}
+		fmt.Fprintf(w, "name: %s\nbinary:\n  assets:\n    %s-%s: %s\n", block, runtime.GOOS, runtime.GOARCH, block)
+	})
+	mux.HandleFunc("/api/repos/acme/suite/contents/agentic_support", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"beta.yaml","type":"file"},{"name":"alpha.yaml","type":"file"},{"name":"README.md","type":"file"},{"name":"shared","type":"dir"}]`)
+	})
+	release := packagemanager.GitHubRelease{TagName: "v2.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "alpha"}, {ID: 2, Name: "beta"}}}
+	mux.HandleFunc("/api/repos/acme/suite/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/suite/releases/tags/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/suite/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "#!/bin/sh\necho asset %s\n", r.PathValue("id"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	blocks, err := pkgm.ListRepoBlocks(t.Context(), "acme/suite")
+	if err != nil || !slices.Equal(blocks, []string{"alpha", "beta"}) {
+		t.Fatalf("expected blocks alpha and beta, got %v, %v", blocks, err)
+	}
+
+	for i, block := range blocks {
+		repo := "acme/suite#" + block
+		metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+		if err != nil {
+			t.Fatalf("Install of %s failed: %v", repo, err)
+		}
+		if metadata.Name != block || metadata.SourceRepo != repo || metadata.Version != "v2.0.0" {
+			t.Errorf("expected %s v2.0.0 from %s, got %+v", block, repo, metadata)
+		}
+		if data, _ := os.ReadFile(metadata.BinaryPath); !strings.Contains(string(data), fmt.Sprintf("asset %d", i+1)) {
+			t.Errorf("expected the %s asset, got %q", block, data)
+		}
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/suite#renamed"}); err == nil || !strings.Contains(err.Error(), "names block 'other'") {
+		t.Errorf("expected a manifest naming another block to be rejected, got %v", err)
+	}
+	for _, repo := range []string{"acme/suite#", "acme/suite#a/b"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err == nil || !strings.Contains(err.Error(), "invalid block") {
+			t.Errorf("expected %s to be rejected, got %v", repo, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallThroughMirror(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "mirror-token")
+
+	manifest := fmt.Sprintf("name: mirrored\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: mirrored\n", runtime.GOOS, runtime.GOARCH)
+	binary := "#!/bin/sh\necho mirrored\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/mirrored", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"full_name": "acme/mirrored"})
+	})
+	mux.HandleFunc("/repos/acme/mirrored/contents/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"encoding": "base64", "content": base64.StdEncoding.EncodeToString([]byte(manifest))})
+	})
+	mux.HandleFunc("/repos/acme/mirrored/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 7, Name: "mirrored"}}})
+	})
+	mux.HandleFunc("/repos/acme/mirrored/releases/assets/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mirror-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, binary)
+	})
+	mirror := httptest.NewServer(mux)
+	defer mirror.Close()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if err := pkgm.SetNetworkConfig(packagemanager.NetworkConfig{ProxyURL: "ftp://proxy"}); err == nil {
+		t.Error("expected a non-http proxy URL to be rejected")
+	}
+	if err := pkgm.SetNetworkConfig(packagemanager.NetworkConfig{MirrorURL: mirror.URL + "/"}); err != nil {
+		t.Fatalf("SetNetworkConfig failed: %v", err)
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/mirrored", Version: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if metadata.Name != "mirrored" || metadata.Version != "v1.0.0" {
+		t.Errorf("expected mirrored v1.0.0, got %s %s", metadata.Name, metadata.Version)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestHTTPConfigTransportAndRetries(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/tool": "tool"})
+
+	var requests, failed atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		// Drop the first request and answer 503 to the second.
+		switch failed.Add(1) {
+		case 1:
+			return nil, errors.New("connection reset")
+		case 2:
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{
+		Transport:      transport,
+		RequestTimeout: 5 * time.Second,
+		Retry:          packagemanager.RetryPolicy{MaxAttempts: 3, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("SetHTTPConfig failed: %v", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("expected the install to survive transient failures, got %v", err)
+	}
+	if requests.Load() <= 2 {
+		t.Errorf("expected requests to go through the custom transport, got %d", requests.Load())
+	}
+
+	var viaClient atomic.Int32
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		viaClient.Add(1)
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	if err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{Client: client}); err != nil {
+		t.Fatalf("SetHTTPConfig failed: %v", err)
+	}
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Force: true}); err != nil {
+		t.Fatalf("pkgm.Install() with a custom client failed: %s", err)
+	}
+	if viaClient.Load() == 0 {
+		t.Error("expected requests to go through the custom client")
+	}
+
+	if err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{RequestTimeout: -time.Second}); err == nil {
+		t.Error("expected a negative timeout to be rejected")
+	}
+}
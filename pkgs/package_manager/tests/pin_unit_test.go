@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestPinnedBlockIsNotUpdated(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	release := func(version string) {
+		manifest := fmt.Sprintf("name: frozen\nversion: %s\nbinary:\n  assets:\n    %s-%s: frozen\n", version, runtime.GOOS, runtime.GOARCH)
+		if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+		if err := os.WriteFile(filepath.Join(blockDir, "frozen"), []byte("#!/bin/sh\necho "+version+"\n"), 0755); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+	}
+
+	release("v1.0.0")
+	dir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(dir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if err := pkgm.Pin("frozen"); err != nil {
+		t.Fatalf("Pin failed: %s", err)
+	}
+	if err := pkgm.Pin("missing"); err == nil {
+		t.Error("expected pinning a block that isn't installed to fail")
+	}
+
+	release("v1.1.0")
+	check, err := pkgm.CheckForUpdates(t.Context(), "frozen")
+	if err != nil {
+		t.Fatalf("CheckForUpdates failed: %s", err)
+	}
+	if !check.UpdateAvailable || !check.Pinned {
+		t.Errorf("expected an available update flagged as pinned, got %+v", check)
+	}
+
+	result, err := pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "frozen"})
+	if err != nil {
+		t.Fatalf("Update failed: %s", err)
+	}
+	if !result.Pinned || result.Success || result.NewVersion != "v1.0.0" {
+		t.Errorf("expected Update to skip the pinned block, got %+v", result)
+	}
+
+	// The pin is persisted, so a fresh manager honors it too.
+	reloaded := packagemanager.NewPackageManagerWithTestDir(dir)
+	if !reloaded.IsPinned("frozen") {
+		t.Fatal("expected the pin to survive a reload")
+	}
+	results, err := reloaded.UpdateAll(t.Context())
+	if err != nil {
+		t.Fatalf("UpdateAll failed: %s", err)
+	}
+	if len(results) != 1 || !results[0].Pinned || results[0].Blockname != "frozen" {
+		t.Errorf("expected UpdateAll to skip the pinned block, got %+v", results)
+	}
+	if metadata, _ := reloaded.GetLoadedBlock("frozen"); metadata.Version != "v1.0.0" {
+		t.Errorf("expected frozen to stay at v1.0.0, got %s", metadata.Version)
+	}
+
+	if err := reloaded.Unpin("frozen"); err != nil {
+		t.Fatalf("Unpin failed: %s", err)
+	}
+	results, err = reloaded.UpdateAll(t.Context())
+	if err != nil {
+		t.Fatalf("UpdateAll failed: %s", err)
+	}
+	if len(results) != 1 || results[0].Pinned || results[0].NewVersion != "v1.1.0" {
+		t.Errorf("expected UpdateAll to update the unpinned block to v1.1.0, got %+v", results)
+	}
+}
+
+func TestPinSurvivesVersionChanges(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeReleasesManager(t, "acme/frozen", "frozen", []packagemanager.GitHubRelease{{TagName: "v2.0.0"}, {TagName: "v1.0.0"}})
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/frozen", Version: "v1.0.0"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if err := pkgm.Pin("frozen"); err != nil {
+		t.Fatalf("Pin failed: %s", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/frozen", Version: "v2.0.0", Force: true}); err != nil {
+		t.Fatalf("explicit install failed: %s", err)
+	}
+	if !pkgm.IsPinned("frozen") {
+		t.Error("expected an explicit install to keep the block pinned")
+	}
+
+	if _, err := pkgm.Use(t.Context(), "frozen", "v1.0.0"); err != nil {
+		t.Fatalf("Use failed: %s", err)
+	}
+	if !pkgm.IsPinned("frozen") {
+		t.Error("expected switching versions to keep the block pinned")
+	}
+}
+
+func TestSyncSkipsPinnedBlocks(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/alpha": "alpha", "acme/stale": "stale"})
+	for _, repo := range []string{"acme/alpha", "acme/stale"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+			t.Fatalf("pkgm.Install() failed: %s", err)
+		}
+	}
+	if err := pkgm.Pin("stale"); err != nil {
+		t.Fatalf("Pin failed: %s", err)
+	}
+
+	manifestPath := filepath.Join(t.TempDir(), packagemanager.ProjectManifestFile)
+	if err := os.WriteFile(manifestPath, []byte("blocks:\n  - repo: acme/alpha\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pkgm.Sync(t.Context(), manifestPath)
+	if err != nil {
+		t.Fatalf("pkgm.Sync() failed: %s", err)
+	}
+	if len(result.Pruned) != 0 || !slices.Equal(result.Pinned, []string{"stale@v1.0.0"}) {
+		t.Errorf("expected the pinned block to be kept, got %+v", result)
+	}
+	if _, ok := pkgm.GetLoadedBlock("stale"); !ok {
+		t.Error("expected the pinned block to stay installed")
+	}
+}
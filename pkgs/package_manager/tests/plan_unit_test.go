@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestPlanLocalBlock(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	req := packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "planned")}
+
+	plan, err := pkgm.Plan(t.Context(), req)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	want := packagemanager.InstallPlan{Repo: req.Repo, Name: "planned", Version: "v0.1.0", Action: packagemanager.PlanInstall, Asset: "planned", DownloadSize: int64(len("#!/bin/sh\n"))}
+	if *plan != want {
+		t.Errorf("expected plan %+v, got %+v", want, *plan)
+	}
+	if _, err := os.Stat(filepath.Join(pkgm.InstallDir, "planned")); !os.IsNotExist(err) {
+		t.Errorf("expected Plan not to write the block, got %v", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), req); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if plan, err := pkgm.Plan(t.Context(), req); err != nil || plan.Action != packagemanager.PlanNone || plan.Version != "v0.1.0" {
+		t.Errorf("expected no change for an installed block, got %+v, %v", plan, err)
+	}
+	req.Force = true
+	if plan, err := pkgm.Plan(t.Context(), req); err != nil || plan.Action != packagemanager.PlanReinstall || plan.CurrentVersion != "v0.1.0" {
+		t.Errorf("expected a forced request to reinstall, got %+v, %v", plan, err)
+	}
+}
+
+func TestPlanGitHubVersions(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeReleasesManager(t, "acme/tool", "tool", []packagemanager.GitHubRelease{{TagName: "v2.0.0"}, {TagName: "v1.0.0"}})
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v1.0.0"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	tests := []struct {
+		version string
+		want    packagemanager.PlanAction
+	}{
+		{"", packagemanager.PlanUpgrade},
+		{"v1.0.0", packagemanager.PlanReinstall},
+	}
+	for _, tt := range tests {
+		plan, err := pkgm.Plan(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: tt.version, Force: true})
+		if err != nil {
+			t.Fatalf("Plan of %q failed: %v", tt.version, err)
+		}
+		if plan.Action != tt.want || plan.CurrentVersion != "v1.0.0" || plan.Asset != "tool" {
+			t.Errorf("expected %s of v1.0.0 with asset tool for %q, got %+v", tt.want, tt.version, plan)
+		}
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Force: true}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	plan, err := pkgm.Plan(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v1.0.0", Force: true})
+	if err != nil || plan.Action != packagemanager.PlanDowngrade {
+		t.Errorf("expected a downgrade, got %+v, %v", plan, err)
+	}
+	if _, err := pkgm.Plan(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v3.0.0", Force: true}); err == nil {
+		t.Error("expected planning a missing release to fail")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallForPlatforms(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	linux, darwin := []byte("linux binary"), []byte("darwin binary")
+	for name, data := range map[string][]byte{"tool-linux": linux, "tool-darwin": darwin} {
+		if err := os.WriteFile(filepath.Join(blockDir, name), data, 0755); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+	}
+	linuxSum := sha256.Sum256(linux)
+	manifest := "name: tool\nversion: v0.4.0\nbinary:\n  assets:\n    linux-amd64: tool-linux\n    darwin-arm64: tool-darwin\nchecksums:\n  linux-amd64: " + hex.EncodeToString(linuxSum[:]) + "\n"
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	installDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	repo := "file://" + filepath.ToSlash(blockDir)
+
+	binaries, err := pkgm.InstallForPlatforms(t.Context(), packagemanager.InstallRequest{Repo: repo}, []string{"linux-amd64", "darwin-arm64"})
+	if err != nil {
+		t.Fatalf("InstallForPlatforms failed: %v", err)
+	}
+	if len(binaries) != 2 {
+		t.Fatalf("expected 2 binaries, got %+v", binaries)
+	}
+	for _, binary := range binaries {
+		want := filepath.Join(installDir, ".atomos", "tool", "bin", "v0.4.0", "platforms", binary.Platform)
+		if filepath.Dir(binary.Path) != want {
+			t.Errorf("expected the %s binary in %s, got %s", binary.Platform, want, binary.Path)
+		}
+	}
+	if data, _ := os.ReadFile(binaries[1].Path); string(data) != string(darwin) {
+		t.Errorf("expected the darwin binary, got %q", data)
+	}
+	if _, ok := pkgm.GetLoadedBlock("tool"); ok {
+		t.Error("expected InstallForPlatforms not to install the block for the host")
+	}
+
+	if _, err := pkgm.InstallForPlatforms(t.Context(), packagemanager.InstallRequest{Repo: repo}, []string{"windows-amd64"}); err == nil || !strings.Contains(err.Error(), "no binary found") {
+		t.Errorf("expected an error for a platform without an asset, got %v", err)
+	}
+}
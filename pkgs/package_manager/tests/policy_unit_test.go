@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestRepoPolicy(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/tool": "tool", "acme/legacy-tool": "legacy", "evil/tool": "evil"})
+	if err := pkgm.SetRepoPolicy(packagemanager.RepoPolicy{Allow: []string{"ACME"}, Deny: []string{"acme/legacy-*"}}); err != nil {
+		t.Fatalf("SetRepoPolicy failed: %v", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("expected acme/tool to be allowed, got %v", err)
+	}
+	for _, repo := range []string{"evil/tool", "acme/legacy-tool", writeLocalTestBlock(t, "local")} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); !errors.Is(err, packagemanager.ErrRepoNotAllowed) {
+			t.Errorf("expected ErrRepoNotAllowed for %s, got %v", repo, err)
+		}
+	}
+
+	refusals, err := pkgm.PolicyRefusals()
+	if err != nil {
+		t.Fatalf("PolicyRefusals failed: %v", err)
+	}
+	if len(refusals) != 3 {
+		t.Fatalf("expected 3 recorded refusals, got %+v", refusals)
+	}
+	if refusals[0].Repo != "evil/tool" || refusals[0].Rule != "not allowed" {
+		t.Errorf("unexpected first refusal %+v", refusals[0])
+	}
+	if refusals[1].Rule != "denied by acme/legacy-*" {
+		t.Errorf("expected the deny rule to be recorded, got %+v", refusals[1])
+	}
+
+	if err := pkgm.SetRepoPolicy(packagemanager.RepoPolicy{Deny: []string{"acme/[tool"}}); err == nil {
+		t.Error("expected a malformed pattern to be rejected")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestProbePlatformAsset(t *testing.T) {
+	t.Parallel()
+
+	// Release assets spell platforms their own way.
+	arch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	otherOS := "windows"
+	if runtime.GOOS == "windows" {
+		otherOS = "linux"
+	}
+	native := "probed_" + runtime.GOOS + "_" + arch
+	files := map[string]string{
+		"agentic_support.yaml":              "name: probed\nversion: v0.1.0\nbinary:\n  assets:\n    plan9-mips: probed-plan9\n",
+		native:                              "#!/bin/sh\necho native\n",
+		"probed_" + otherOS + "_" + arch:    "foreign",
+		"probed_" + runtime.GOOS + "_s390x": "foreign",
+		"probed_checksums.txt":              "",
+		native + ".sha256":                  "",
+	}
+
+	blockDir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(blockDir, name), []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to write %s: %s", name, err)
+		}
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	req := packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}
+
+	_, err := pkgm.Install(t.Context(), req)
+	if err == nil || !strings.Contains(err.Error(), native) {
+		t.Fatalf("expected the install to fail suggesting %s, got %v", native, err)
+	}
+
+	req.ProbeAssets = true
+	metadata, err := pkgm.Install(t.Context(), req)
+	if err != nil {
+		t.Fatalf("pkgm.Install() with ProbeAssets failed: %s", err)
+	}
+	if metadata.InferredAsset != native {
+		t.Errorf("expected inferred asset %s, got %q", native, metadata.InferredAsset)
+	}
+	if binary, _ := os.ReadFile(metadata.BinaryPath); string(binary) != files[native] {
+		t.Errorf("expected the native binary to be installed, got %q", binary)
+	}
+}
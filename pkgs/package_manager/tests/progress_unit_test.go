@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: chatty\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: chatty\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	binary := "#!/bin/sh\n" + strings.Repeat("# padding\n", 20000)
+	if err := os.WriteFile(filepath.Join(blockDir, "chatty"), []byte(binary), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		reports []packagemanager.Progress
+	)
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetProgressReporter(packagemanager.ProgressFunc(func(p packagemanager.Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, p)
+	}))
+
+	repo := "file://" + filepath.ToSlash(blockDir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	var phases []packagemanager.InstallPhase
+	var last packagemanager.Progress
+	for _, p := range reports {
+		if p.Repo != repo {
+			t.Errorf("expected progress for %s, got %+v", repo, p)
+		}
+		if len(phases) == 0 || phases[len(phases)-1] != p.Phase {
+			phases = append(phases, p.Phase)
+		}
+		if p.Phase == packagemanager.PhaseDownloading {
+			if p.Downloaded < last.Downloaded {
+				t.Errorf("expected downloaded bytes to grow, got %d after %d", p.Downloaded, last.Downloaded)
+			}
+			last = p
+		}
+	}
+
+	want := []packagemanager.InstallPhase{packagemanager.PhaseResolving, packagemanager.PhaseDownloading, packagemanager.PhaseInstalling, packagemanager.PhaseDone}
+	if !slices.Equal(phases, want) {
+		t.Errorf("expected phases %v, got %v", want, phases)
+	}
+	if last.Downloaded != int64(len(binary)) || last.Total != int64(len(binary)) {
+		t.Errorf("expected the download to finish at %d bytes, got %+v", len(binary), last)
+	}
+}
+
+func TestInstallWithProgress(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: streamed\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: streamed\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	binary := "#!/bin/sh\n" + strings.Repeat("# padding\n", 20000)
+	if err := os.WriteFile(filepath.Join(blockDir, "streamed"), []byte(binary), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	repo := "file://" + filepath.ToSlash(blockDir)
+	events, err := pkgm.InstallWithProgress(t.Context(), packagemanager.InstallRequest{Repo: repo})
+	if err != nil {
+		t.Fatalf("InstallWithProgress failed: %s", err)
+	}
+
+	var phases []packagemanager.InstallPhase
+	var last packagemanager.InstallEvent
+	percent := -1
+	for event := range events {
+		if event.Repo != repo {
+			t.Errorf("expected events for %s, got %+v", repo, event)
+		}
+		if len(phases) == 0 || phases[len(phases)-1] != event.Phase {
+			phases = append(phases, event.Phase)
+		}
+		if event.Phase == packagemanager.PhaseDownloading {
+			if event.Percent < percent || event.Percent > 100 {
+				t.Errorf("expected the download percentage to grow up to 100, got %d after %d", event.Percent, percent)
+			}
+			percent = event.Percent
+		}
+		last = event
+	}
+
+	want := []packagemanager.InstallPhase{packagemanager.PhaseResolving, packagemanager.PhaseDownloading, packagemanager.PhaseInstalling, packagemanager.PhaseDone}
+	if !slices.Equal(phases, want) {
+		t.Errorf("expected phases %v, got %v", want, phases)
+	}
+	if last.Err != nil || last.Metadata == nil || last.Metadata.Name != "streamed" || last.Percent != 100 {
+		t.Errorf("expected the last event to carry the installed block, got %+v", last)
+	}
+
+	events, err = pkgm.InstallWithProgress(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(filepath.Join(blockDir, "missing"))})
+	if err != nil {
+		t.Fatalf("InstallWithProgress failed: %s", err)
+	}
+	for event := range events {
+		last = event
+	}
+	if last.Phase != packagemanager.PhaseFailed || last.Err == nil || last.Metadata != nil {
+		t.Errorf("expected the install to end with a failure, got %+v", last)
+	}
+
+	readOnly, err := packagemanager.OpenReadOnly(pkgm.InstallDir)
+	if err != nil {
+		t.Fatalf("OpenReadOnly failed: %s", err)
+	}
+	if _, err := readOnly.InstallWithProgress(t.Context(), packagemanager.InstallRequest{Repo: repo}); !errors.Is(err, packagemanager.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestSyncProjectManifest(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/alpha": "alpha", "acme/beta": "beta", "acme/stale": "stale"})
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/stale"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	manifestPath := filepath.Join(t.TempDir(), packagemanager.ProjectManifestFile)
+	manifest := "blocks:\n  - repo: acme/alpha\n    version: ^1.0.0\n  - repo: acme/beta\n    alias: beta-tool\n"
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pkgm.Sync(t.Context(), manifestPath)
+	if err != nil {
+		t.Fatalf("pkgm.Sync() failed: %s", err)
+	}
+	if !slices.Equal(result.Installed, []string{"alpha@v1.0.0", "beta-tool@v1.0.0"}) {
+		t.Errorf("expected alpha and beta-tool to be installed, got %v", result.Installed)
+	}
+	if !slices.Equal(result.Pruned, []string{"stale@v1.0.0"}) {
+		t.Errorf("expected stale to be pruned, got %v", result.Pruned)
+	}
+	if _, ok := pkgm.GetLoadedBlock("stale"); ok {
+		t.Error("expected the unlisted block to be uninstalled")
+	}
+
+	result, err = pkgm.Sync(t.Context(), manifestPath)
+	if err != nil {
+		t.Fatalf("second pkgm.Sync() failed: %s", err)
+	}
+	if len(result.Installed)+len(result.Updated)+len(result.Pruned) != 0 || len(result.UpToDate) != 2 {
+		t.Errorf("expected a second sync to change nothing, got %+v", result)
+	}
+
+	if err := os.WriteFile(manifestPath, []byte("blocks:\n  - repo: acme/alpha\n    version: ^2.0.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pkgm.Sync(t.Context(), manifestPath); err == nil {
+		t.Error("expected a sync to an unpublished range to fail")
+	}
+}
+
+func TestLoadProjectManifestRejectsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	manifestPath := filepath.Join(t.TempDir(), packagemanager.ProjectManifestFile)
+	if err := os.WriteFile(manifestPath, []byte("blocks:\n  - repo: acme/alpha\n  - repo: ACME/alpha\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := packagemanager.LoadProjectManifest(manifestPath); err == nil {
+		t.Error("expected a manifest listing a repo twice to be rejected")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func newThreeVersionManager(t *testing.T) *packagemanager.PackageManager {
+	t.Helper()
+
+	pkgm := newFakeReleasesManager(t, "acme/tool", "tool", []packagemanager.GitHubRelease{{TagName: "v3.0.0"}, {TagName: "v2.0.0"}, {TagName: "v1.0.0"}})
+	for _, version := range []string{"v1.0.0", "v2.0.0", "v3.0.0"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: version, Force: true}); err != nil {
+			t.Fatalf("pkgm.Install(%s) failed: %s", version, err)
+		}
+	}
+	return pkgm
+}
+
+func installedVersions(t *testing.T, pkgm *packagemanager.PackageManager, name string) []string {
+	t.Helper()
+
+	versions, err := pkgm.ListVersions(name)
+	if err != nil {
+		t.Fatalf("ListVersions failed: %s", err)
+	}
+	var names []string
+	for _, metadata := range versions {
+		names = append(names, metadata.Version)
+	}
+	return names
+}
+
+func TestUninstallVersion(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newThreeVersionManager(t)
+
+	if err := pkgm.UninstallVersion(t.Context(), "tool", "1.0.0"); err != nil {
+		t.Fatalf("UninstallVersion failed: %s", err)
+	}
+	if got := installedVersions(t, pkgm, "tool"); !slices.Equal(got, []string{"v2.0.0", "v3.0.0"}) {
+		t.Errorf("expected v2.0.0 and v3.0.0 to remain, got %v", got)
+	}
+	if metadata, _ := pkgm.GetLoadedBlock("tool"); metadata.Version != "v3.0.0" {
+		t.Errorf("expected v3.0.0 to stay active, got %s", metadata.Version)
+	}
+
+	if err := pkgm.UninstallVersion(t.Context(), "tool", "v3.0.0"); err != nil {
+		t.Fatalf("UninstallVersion of the active version failed: %s", err)
+	}
+	if metadata, _ := pkgm.GetLoadedBlock("tool"); metadata.Version != "v2.0.0" {
+		t.Errorf("expected v2.0.0 to become active, got %s", metadata.Version)
+	}
+
+	if err := pkgm.UninstallVersion(t.Context(), "tool", "v1.0.0"); err == nil {
+		t.Error("expected removing a version that isn't installed to fail")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newThreeVersionManager(t)
+	blockDir := filepath.Join(pkgm.InstallDir, "tool")
+	if err := os.MkdirAll(filepath.Join(blockDir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(blockDir, "data", "model.bin"), []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pkgm.Purge(t.Context(), "tool")
+	if err != nil {
+		t.Fatalf("Purge failed: %s", err)
+	}
+	if !slices.Equal(result.Versions, []string{"v1.0.0", "v2.0.0", "v3.0.0"}) {
+		t.Errorf("expected every version to be reported, got %v", result.Versions)
+	}
+	for _, path := range []string{filepath.Join(blockDir, "data"), filepath.Join(blockDir, "logs"), blockDir} {
+		if !slices.Contains(result.Removed, path) {
+			t.Errorf("expected %s to be reported as removed, got %v", path, result.Removed)
+		}
+	}
+	if result.Bytes == 0 {
+		t.Error("expected the freed bytes to be reported")
+	}
+	if _, err := os.Stat(blockDir); !os.IsNotExist(err) {
+		t.Errorf("expected the block directory to be gone, got %v", err)
+	}
+	if _, ok := pkgm.GetLoadedBlock("tool"); ok {
+		t.Error("expected the block to be unloaded")
+	}
+
+	if _, err := pkgm.Purge(t.Context(), "tool"); err == nil {
+		t.Error("expected purging a block that isn't installed to fail")
+	}
+	if _, err := pkgm.Purge(t.Context(), "../escape"); err == nil {
+		t.Error("expected a name with a path separator to be refused")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallDirQuota(t *testing.T) {
+	t.Parallel()
+
+	installDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	if err := pkgm.SetQuota(-1); err == nil {
+		t.Error("expected a negative quota to be rejected")
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "small")}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	stats, err := pkgm.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if err := pkgm.SetQuota(stats.TotalDiskSize + 1024); err != nil {
+		t.Fatalf("SetQuota failed: %v", err)
+	}
+
+	big := writeLocalTestBlock(t, "big")
+	binary := bytes.Repeat([]byte("x"), 8192)
+	if err := os.WriteFile(filepath.Join(strings.TrimPrefix(big, "file://"), "big"), binary, 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: big}); !errors.Is(err, packagemanager.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, ok := pkgm.GetLoadedBlock("big"); ok {
+		t.Error("expected the block over the quota not to be installed")
+	}
+	if _, err := os.Stat(filepath.Join(installDir, ".atomos", "big", "bin", "v0.1.0")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the binary over the quota to be removed, got %v", err)
+	}
+
+	if err := pkgm.SetQuota(0); err != nil {
+		t.Fatalf("SetQuota failed: %v", err)
+	}
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: big})
+	if err != nil {
+		t.Fatalf("Install without a quota failed: %v", err)
+	}
+	if metadata.Size != int64(len(binary)) {
+		t.Errorf("expected a size of %d bytes, got %d", len(binary), metadata.Size)
+	}
+
+	stats, err = pkgm.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if len(stats.LargestBlocks) != 2 || stats.LargestBlocks[0].Name != "big" {
+		t.Fatalf("expected big to be the largest block, got %+v", stats.LargestBlocks)
+	}
+	if size := stats.LargestBlocks[0].DiskSize; size <= int64(len(binary)) || stats.TotalDiskSize < size {
+		t.Errorf("expected the disk size of big to include its metadata and count in the total, got %d of %d", size, stats.TotalDiskSize)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// snapshotTree records every path under dir with its size and mod time.
+func snapshotTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	tree := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		tree[path] = fmt.Sprintf("%d %s", info.Size(), info.ModTime())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk %s: %s", dir, err)
+	}
+	return tree
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	t.Parallel()
+
+	if _, err := packagemanager.OpenReadOnly(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected opening a missing installation to fail")
+	}
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "watched"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: watched\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: watched\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	repo := "file://" + filepath.ToSlash(blockDir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	// A corrupted version file would be quarantined by a writable manager.
+	corrupted := filepath.Join(pkgm.InstallDir, "watched", "metadata", "v0.9.0.json")
+	if err := os.WriteFile(corrupted, []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to write corrupted metadata: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(pkgm.InstallDir, "watched", "active"), []byte("v0.9.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to activate corrupted version: %s", err)
+	}
+
+	before := snapshotTree(t, pkgm.InstallDir)
+
+	inspector, err := packagemanager.OpenReadOnly(pkgm.InstallDir)
+	if err != nil {
+		t.Fatalf("OpenReadOnly failed: %s", err)
+	}
+	if !inspector.ReadOnly() {
+		t.Error("expected ReadOnly to report true")
+	}
+	if metadata, ok := inspector.GetLoadedBlock("watched"); !ok || metadata.Version != "v1.0.0" {
+		t.Errorf("expected watched v1.0.0 to be loaded, got %+v", metadata)
+	}
+	repairs := inspector.MetadataRepairs()
+	if len(repairs) == 0 || !strings.Contains(repairs[0].Detail, "read-only") {
+		t.Errorf("expected the quarantine to be reported as not applied, got %+v", repairs)
+	}
+
+	if _, err := inspector.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Force: true}); !errors.Is(err, packagemanager.ErrReadOnly) {
+		t.Errorf("expected Install to fail with ErrReadOnly, got %v", err)
+	}
+	if err := inspector.Uninstall(t.Context(), "watched"); !errors.Is(err, packagemanager.ErrReadOnly) {
+		t.Errorf("expected Uninstall to fail with ErrReadOnly, got %v", err)
+	}
+
+	after := snapshotTree(t, pkgm.InstallDir)
+	if fmt.Sprint(before) != fmt.Sprint(after) {
+		t.Errorf("expected the installation to be untouched\nbefore: %v\nafter:  %v", before, after)
+	}
+}
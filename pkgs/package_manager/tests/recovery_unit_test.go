@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestCorruptedMetadataRecovery(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "sturdy"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: sturdy\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: sturdy\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	metadataDir := filepath.Join(pkgm.InstallDir, "sturdy", "metadata")
+	corrupted := filepath.Join(metadataDir, "v1.1.0.json")
+	if err := os.WriteFile(corrupted, []byte(`{"name": "sturdy", "vers`), 0644); err != nil {
+		t.Fatalf("Failed to write corrupted metadata: %s", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(corrupted, future, future); err != nil {
+		t.Fatalf("Failed to touch corrupted metadata: %s", err)
+	}
+	// The corrupted version is the active one, as after an interrupted update.
+	if err := os.WriteFile(filepath.Join(pkgm.InstallDir, "sturdy", "active"), []byte("v1.1.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to activate corrupted version: %s", err)
+	}
+
+	t.Run("FallBackToValidVersion", func(t *testing.T) {
+		reloaded := packagemanager.NewPackageManagerWithTestDir(testDir)
+		metadata, ok := reloaded.GetLoadedBlock("sturdy")
+		if !ok {
+			t.Fatal("expected block to stay visible")
+		}
+		if metadata.Version != "v1.0.0" {
+			t.Errorf("expected fallback to v1.0.0, got %s", metadata.Version)
+		}
+		if _, err := os.Stat(corrupted + ".corrupt"); err != nil {
+			t.Errorf("expected corrupted file to be quarantined: %s", err)
+		}
+
+		repairs := reloaded.MetadataRepairs()
+		if len(repairs) != 2 || repairs[0].Action != packagemanager.RepairQuarantined || repairs[1].Action != packagemanager.RepairFellBack {
+			t.Errorf("unexpected repairs: %+v", repairs)
+		}
+	})
+
+	t.Run("RebuildFromBinary", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(metadataDir, "v1.0.0.json"), []byte("\x00\x00"), 0644); err != nil {
+			t.Fatalf("Failed to corrupt metadata: %s", err)
+		}
+
+		reloaded := packagemanager.NewPackageManagerWithTestDir(testDir)
+		metadata, ok := reloaded.GetLoadedBlock("sturdy")
+		if !ok {
+			t.Fatal("expected block to be rebuilt")
+		}
+		if metadata.Version != "v1.0.0" || metadata.SHA256 == "" {
+			t.Errorf("unexpected rebuilt metadata: %+v", metadata)
+		}
+
+		repairs := reloaded.MetadataRepairs()
+		if len(repairs) == 0 || repairs[len(repairs)-1].Action != packagemanager.RepairRebuilt {
+			t.Errorf("expected a rebuild to be reported, got %+v", repairs)
+		}
+	})
+}
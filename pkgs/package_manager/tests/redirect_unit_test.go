@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// newRenamedRepoManager returns a package manager whose fake GitHub
+// publishes the block "tool" at new/name, and redirects old/name there.
+func newRenamedRepoManager(t *testing.T, logs *bytes.Buffer) *packagemanager.PackageManager {
+	t.Helper()
+
+	manifest := fmt.Sprintf("name: tool\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: tool\n", runtime.GOOS, runtime.GOARCH)
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+
+	mux := http.NewServeMux()
+	for _, repo := range []string{"old/name", "new/name"} {
+		mux.HandleFunc("/api/repos/"+repo, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{"full_name": "new/name"})
+		})
+	}
+	mux.HandleFunc("/raw/new/name/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\n")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetLogger(slog.New(slog.NewTextHandler(logs, nil)))
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	return pkgm
+}
+
+func TestInstallFollowsRenamedRepository(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	pkgm := newRenamedRepoManager(t, &logs)
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "old/name"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if metadata.SourceRepo != "new/name" || metadata.RedirectedFrom != "old/name" {
+		t.Errorf("expected new/name redirected from old/name, got %q from %q", metadata.SourceRepo, metadata.RedirectedFrom)
+	}
+	if !strings.Contains(logs.String(), `level=WARN msg="repository has moved; update workflows and lockfiles referencing the old name" repo=old/name moved_to=new/name`) {
+		t.Errorf("expected a warning about the stale coordinates, got:\n%s", logs.String())
+	}
+
+	// Installing from the canonical name redirects nothing.
+	if metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "new/name", Force: true}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	} else if metadata.RedirectedFrom != "" {
+		t.Errorf("expected no redirect for the canonical name, got %q", metadata.RedirectedFrom)
+	}
+}
+
+func TestProjectManifestResolvesRenamedRepository(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	pkgm := newRenamedRepoManager(t, &logs)
+
+	manifestPath := filepath.Join(t.TempDir(), packagemanager.ProjectManifestFile)
+	if err := os.WriteFile(manifestPath, []byte("blocks:\n  - repo: old/name\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pkgm.Sync(t.Context(), manifestPath)
+	if err != nil {
+		t.Fatalf("pkgm.Sync() failed: %s", err)
+	}
+	if !slices.Equal(result.Installed, []string{"tool@v1.0.0"}) {
+		t.Errorf("expected tool to be installed, got %+v", result)
+	}
+
+	// The entry still naming the old coordinates matches the installed block
+	// rather than pruning and reinstalling it.
+	result, err = pkgm.Sync(t.Context(), manifestPath)
+	if err != nil {
+		t.Fatalf("second pkgm.Sync() failed: %s", err)
+	}
+	if !slices.Equal(result.UpToDate, []string{"tool@v1.0.0"}) || len(result.Installed)+len(result.Pruned) != 0 {
+		t.Errorf("expected the old coordinates to resolve to the installed block, got %+v", result)
+	}
+	if metadata, ok := pkgm.GetLoadedBlock("tool"); !ok || metadata.SourceRepo != "new/name" {
+		t.Errorf("expected tool from new/name to stay installed, got %+v", metadata)
+	}
+}
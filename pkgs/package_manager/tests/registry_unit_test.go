@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestRegistrySearchAndInfo(t *testing.T) {
+	t.Parallel()
+
+	index := filepath.Join(t.TempDir(), "registry.yaml")
+	err := os.WriteFile(index, []byte(`blocks:
+  - name: profiler
+    description: Collects CPU profiles of Go programs
+    repo: AlexsanderHamir/profiler
+    tags: [performance]
+  - name: reporter
+    description: Renders profiles as reports
+    repo: gitlab:tools/reporter
+`), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write registry: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := pkgm.Search(t.Context(), "profile"); !errors.Is(err, packagemanager.ErrNoRegistry) {
+		t.Fatalf("expected ErrNoRegistry before SetRegistry, got %v", err)
+	}
+
+	pkgm.SetRegistry("file://" + filepath.ToSlash(index))
+	matches, err := pkgm.Search(t.Context(), "profile")
+	if err != nil {
+		t.Fatalf("Search failed: %s", err)
+	}
+	if len(matches) != 3 || matches[0].Name != "profiler" || matches[0].Field != "name" || matches[0].Location != "AlexsanderHamir/profiler" {
+		t.Errorf("expected the profiler name match first, got %+v", matches)
+	}
+
+	entry, err := pkgm.Info(t.Context(), "Reporter")
+	if err != nil {
+		t.Fatalf("Info failed: %s", err)
+	}
+	if entry.Repo != "gitlab:tools/reporter" {
+		t.Errorf("expected reporter's repo, got %+v", entry)
+	}
+	if _, err := pkgm.Info(t.Context(), "missing"); !errors.Is(err, packagemanager.ErrNotInRegistry) {
+		t.Errorf("expected ErrNotInRegistry, got %v", err)
+	}
+
+	// The last fetched index keeps working when the registry goes away.
+	if err := os.Remove(index); err != nil {
+		t.Fatalf("Failed to remove registry: %s", err)
+	}
+	if _, err := pkgm.Info(t.Context(), "profiler"); err != nil {
+		t.Errorf("expected the cached index to be used, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestGetReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	pkgm := newFakeGitHubManager(t, map[string]string{"acme/tool": "tool"})
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	for _, version := range []string{"", "v1.0.0", "^1.0.0"} {
+		notes, err := pkgm.GetReleaseNotes(t.Context(), "tool", version)
+		if err != nil {
+			t.Fatalf("GetReleaseNotes(%q) failed: %s", version, err)
+		}
+		if notes.Version != "v1.0.0" || notes.Body != "Changes in acme/tool" {
+			t.Errorf("GetReleaseNotes(%q): unexpected notes %+v", version, notes)
+		}
+	}
+
+	if _, err := pkgm.GetReleaseNotes(t.Context(), "tool", "v9.9.9"); err == nil {
+		t.Error("expected notes of a missing release to fail")
+	}
+	if _, err := pkgm.GetReleaseNotes(t.Context(), "missing", ""); err == nil {
+		t.Error("expected notes of a block that isn't installed to fail")
+	}
+
+	local := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := local.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "local")}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if _, err := local.GetReleaseNotes(t.Context(), "local", ""); err == nil {
+		t.Error("expected a local block to have no release notes")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestParseRequirement(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]packagemanager.Requirement{
+		"graphviz":       {Command: "graphviz"},
+		"python3>=3.10":  {Command: "python3", Constraint: ">=3.10"},
+		" node ^20 ":     {Command: "node", Constraint: "^20"},
+		"go >=1.21 <2.0": {Command: "go", Constraint: ">=1.21 <2.0"},
+	} {
+		got, err := packagemanager.ParseRequirement(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseRequirement(%q) = %+v, %v; want %+v", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", ">=1.0", "bin/python3", "python3>=banana"} {
+		if _, err := packagemanager.ParseRequirement(raw); err == nil {
+			t.Errorf("expected ParseRequirement(%q) to fail", raw)
+		}
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeRequiringBlock(t, "invalid", "python3>=banana")})
+	var manifestErr *packagemanager.ManifestError
+	if !errors.As(err, &manifestErr) || manifestErr.Issues[0].Field != "requires[0]" {
+		t.Errorf("expected a ManifestError for requires[0], got %v", err)
+	}
+}
+
+// writeRequiringBlock writes a local block declaring requires.
+func writeRequiringBlock(t *testing.T, name string, requires ...string) string {
+	t.Helper()
+
+	repo := writeLocalTestBlock(t, name)
+	manifest := filepath.Join(strings.TrimPrefix(repo, "file://"), "agentic_support.yaml")
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %s", err)
+	}
+	data = append(data, "requires:\n"...)
+	for _, requirement := range requires {
+		data = append(data, "  - \""+requirement+"\"\n"...)
+	}
+	if err := os.WriteFile(manifest, data, 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	return repo
+}
+
+func TestInstallChecksRequirements(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake commands are shell scripts")
+	}
+
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "fakepy"), []byte("#!/bin/sh\necho FakePy 3.11.4\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake command: %s", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeRequiringBlock(t, "met", "fakepy>=3.10", "fakepy")}); err != nil {
+		t.Fatalf("expected met requirements to install, got %v", err)
+	}
+
+	unmet := writeRequiringBlock(t, "unmet", "fakepy>=3.12", "atomos-missing-tool")
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: unmet})
+	if !errors.Is(err, packagemanager.ErrRequirementNotMet) {
+		t.Fatalf("expected ErrRequirementNotMet, got %v", err)
+	}
+	for _, want := range []string{"fakepy>=3.12", "version 3.11.4", "atomos-missing-tool was not found on PATH"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %q, got %v", want, err)
+		}
+	}
+	if _, ok := pkgm.GetLoadedBlock("unmet"); ok {
+		t.Error("expected a block with unmet requirements not to be installed")
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: unmet, IgnoreRequirements: true})
+	if err != nil {
+		t.Fatalf("expected IgnoreRequirements to install anyway, got %v", err)
+	}
+	if !slices.Equal(metadata.Requires, []string{"fakepy>=3.12", "atomos-missing-tool"}) {
+		t.Errorf("expected the requirements in metadata, got %v", metadata.Requires)
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+const resumeETag = `"v1"`
+
+// assetServer fakes release v1.0.0 of acme/tool, whose binary "tool" is
+// served by serveAsset. The manifest pins the binary to checksum.
+type assetServer struct {
+	mu       sync.Mutex
+	requests []http.Header
+}
+
+func (s *assetServer) headers() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func newAssetManager(t *testing.T, checksum string, serveAsset http.HandlerFunc) (*packagemanager.PackageManager, *assetServer) {
+	t.Helper()
+
+	s := &assetServer{}
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\nbinary:\n  assets:\n    %s-%s: tool\nchecksums:\n  tool: %s\n", runtime.GOOS, runtime.GOARCH, checksum)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Header.Clone())
+		s.mu.Unlock()
+		serveAsset(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	if err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{Retry: packagemanager.RetryPolicy{Delay: time.Millisecond}}); err != nil {
+		t.Fatalf("SetHTTPConfig failed: %v", err)
+	}
+	return pkgm, s
+}
+
+// serveContent serves content with an ETag, honouring Range and If-Range.
+func serveContent(content []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", resumeETag)
+		http.ServeContent(w, r, "tool", time.Time{}, bytes.NewReader(content))
+	}
+}
+
+// statusWriter records the status a handler answers with.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func toolPath(pkgm *packagemanager.PackageManager) string {
+	return filepath.Join(pkgm.InstallDir, "tool", "bin", "v1.0.0", "tool")
+}
+
+func TestChecksumMismatchKeepsInstalledBinary(t *testing.T) {
+	t.Parallel()
+
+	good := []byte("#!/bin/sh\necho good\n")
+	var (
+		mu      sync.Mutex
+		content = good
+	)
+	pkgm, _ := newAssetManager(t, sha256Hex(good), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(content)
+	})
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	mu.Lock()
+	content = []byte("#!/bin/sh\necho tampered\n")
+	mu.Unlock()
+
+	_, err = pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Force: true})
+	if !errors.Is(err, packagemanager.ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	installed, err := os.ReadFile(metadata.BinaryPath)
+	if err != nil {
+		t.Fatalf("installed binary is gone: %v", err)
+	}
+	if !bytes.Equal(installed, good) {
+		t.Errorf("installed binary was replaced: %q", installed)
+	}
+	if _, err := os.Stat(metadata.BinaryPath + ".part"); !os.IsNotExist(err) {
+		t.Errorf("expected the rejected download to be removed, got %v", err)
+	}
+}
+
+func TestResumeInterruptedDownload(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("#!/bin/sh\n" + strings.Repeat("# padding\n", 200))
+	var (
+		mu          sync.Mutex
+		interrupted bool
+	)
+	pkgm, server := newAssetManager(t, sha256Hex(content), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		first := !interrupted
+		interrupted = true
+		mu.Unlock()
+
+		if first {
+			// Promise the whole file, send half, and drop the connection.
+			w.Header().Set("ETag", resumeETag)
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.Write(content[:len(content)/2])
+			return
+		}
+		serveContent(content)(w, r)
+	})
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	installed, err := os.ReadFile(metadata.BinaryPath)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if !bytes.Equal(installed, content) {
+		t.Fatalf("resumed binary differs from the asset")
+	}
+
+	headers := server.headers()
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 asset requests, got %d", len(headers))
+	}
+	if got, want := headers[1].Get("Range"), fmt.Sprintf("bytes=%d-", len(content)/2); got != want {
+		t.Errorf("expected Range %q, got %q", want, got)
+	}
+	if got := headers[1].Get("If-Range"); got != resumeETag {
+		t.Errorf("expected If-Range %q, got %q", resumeETag, got)
+	}
+}
+
+// seedPart leaves a partial download of the tool binary behind, as an
+// interrupted process would.
+func seedPart(t *testing.T, pkgm *packagemanager.PackageManager, data []byte, etag string, size int) {
+	t.Helper()
+
+	part := toolPath(pkgm) + ".part"
+	if err := os.MkdirAll(filepath.Dir(part), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(part, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := fmt.Sprintf(`{"source":"github:acme/tool/1","etag":%q,"size":%d}`, etag, size)
+	if err := os.WriteFile(part+".json", []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumeLeftoverPart(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("#!/bin/sh\necho resumed\n")
+
+	tests := []struct {
+		name       string
+		part       []byte
+		etag       string
+		wantStatus int // Status of the single asset request
+	}{
+		{"complete part answered with 416", content, resumeETag, http.StatusRequestedRangeNotSatisfiable},
+		{"partial part resumed", content[:5], resumeETag, http.StatusPartialContent},
+		{"changed asset restarted through If-Range", []byte("#!/bin/sh\necho stale"), `"v0"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu     sync.Mutex
+				status int
+			)
+			pkgm, server := newAssetManager(t, sha256Hex(content), func(w http.ResponseWriter, r *http.Request) {
+				sw := &statusWriter{ResponseWriter: w}
+				serveContent(content)(sw, r)
+				mu.Lock()
+				status = sw.status
+				mu.Unlock()
+			})
+			seedPart(t, pkgm, tt.part, tt.etag, len(content))
+
+			metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+			if err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+			installed, err := os.ReadFile(metadata.BinaryPath)
+			if err != nil {
+				t.Fatalf("failed to read binary: %v", err)
+			}
+			if !bytes.Equal(installed, content) {
+				t.Fatalf("expected %q, got %q", content, installed)
+			}
+
+			headers := server.headers()
+			if len(headers) != 1 {
+				t.Fatalf("expected 1 asset request, got %d", len(headers))
+			}
+			if got, want := headers[0].Get("Range"), fmt.Sprintf("bytes=%d-", len(tt.part)); got != want {
+				t.Errorf("expected Range %q, got %q", want, got)
+			}
+			if got := headers[0].Get("If-Range"); got != tt.etag {
+				t.Errorf("expected If-Range %q, got %q", tt.etag, got)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if status != tt.wantStatus {
+				t.Errorf("expected the server to answer %d, got %d", tt.wantStatus, status)
+			}
+		})
+	}
+}
+
+func TestDownloadTimeoutCoversHeadersOnly(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("#!/bin/sh\n" + strings.Repeat("# padding\n", 10))
+	tests := []struct {
+		name    string
+		serve   http.HandlerFunc
+		wantErr bool
+	}{
+		{"slow body", func(w http.ResponseWriter, r *http.Request) {
+			// Headers arrive at once, the body takes several timeouts.
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.WriteHeader(http.StatusOK)
+			for _, b := range content {
+				w.Write([]byte{b})
+				w.(http.Flusher).Flush()
+				time.Sleep(5 * time.Millisecond)
+			}
+		}, false},
+		{"slow headers", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm, _ := newAssetManager(t, sha256Hex(content), tt.serve)
+			err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{
+				RequestTimeout: 50 * time.Millisecond,
+				Retry:          packagemanager.RetryPolicy{MaxAttempts: 1},
+			})
+			if err != nil {
+				t.Fatalf("SetHTTPConfig failed: %v", err)
+			}
+
+			_, err = pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+			if tt.wantErr {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected the header timeout to fail the download, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected the slow body to be downloaded, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestRetaggedReleaseWarning(t *testing.T) {
+	t.Parallel()
+
+	releases := []packagemanager.GitHubRelease{{TagName: "v1.8.1"}}
+	pkgm := newFakeReleasesManager(t, "acme/tool", "tool", releases)
+	var buf bytes.Buffer
+	pkgm.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	req := packagemanager.InstallRequest{Repo: "acme/tool"}
+	metadata, err := pkgm.Install(t.Context(), req)
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if metadata.Asset != "tool" {
+		t.Errorf("expected the release asset to be recorded, got %q", metadata.Asset)
+	}
+
+	if _, err := pkgm.Install(t.Context(), req); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if strings.Contains(buf.String(), "re-tagged") {
+		t.Errorf("expected no warning while the release matches, got:\n%s", buf.String())
+	}
+
+	// The maintainers publish v1.8.1 again with another binary.
+	releases[0].Assets[0].Digest = "sha256:" + strings.Repeat("ab", 32)
+	cached, err := pkgm.Install(t.Context(), req)
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	if cached.SHA256 != metadata.SHA256 {
+		t.Errorf("expected the installed binary to be kept, got %s", cached.SHA256)
+	}
+	logs := buf.String()
+	for _, want := range []string{"re-tagged upstream", "version=v1.8.1", "installed_sha256=" + metadata.SHA256, "release_sha256=" + strings.Repeat("ab", 32)} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected the warning to contain %s, got:\n%s", want, logs)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestRollbackAndDowngrade(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: steady\nbinary:\n  assets:\n    %s-%s: steady\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	for _, version := range []string{"v1.0.0", "v1.1.0", "v2.0.0"} {
+		if err := os.WriteFile(filepath.Join(blockDir, "steady"), []byte("#!/bin/sh\necho "+version+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir), Version: version, Force: true}); err != nil {
+			t.Fatalf("pkgm.Install(%s) failed: %s", version, err)
+		}
+	}
+
+	for _, want := range []string{"v1.1.0", "v1.0.0"} {
+		metadata, err := pkgm.Rollback(t.Context(), "steady")
+		if err != nil {
+			t.Fatalf("Rollback failed: %s", err)
+		}
+		if metadata.Version != want {
+			t.Errorf("expected rollback to %s, got %s", want, metadata.Version)
+		}
+		binary, _ := os.ReadFile(metadata.BinaryPath)
+		if string(binary) != "#!/bin/sh\necho "+want+"\n" {
+			t.Errorf("expected the %s binary, got %q", want, binary)
+		}
+	}
+	if _, err := pkgm.Rollback(t.Context(), "steady"); !errors.Is(err, packagemanager.ErrNoRollback) {
+		t.Errorf("expected ErrNoRollback at the oldest version, got %v", err)
+	}
+
+	if _, err := pkgm.Use(t.Context(), "steady", "v2.0.0"); err != nil {
+		t.Fatalf("Use failed: %s", err)
+	}
+	if _, err := pkgm.Downgrade(t.Context(), "steady", "v2.0.0"); err == nil {
+		t.Error("expected downgrading to the active version to fail")
+	}
+	if metadata, err := pkgm.Downgrade(t.Context(), "steady", "v1.1.0"); err != nil || metadata.Version != "v1.1.0" {
+		t.Fatalf("expected a downgrade to v1.1.0, got %v (%v)", metadata, err)
+	}
+	if metadata, _ := pkgm.GetLoadedBlock("steady"); metadata.Version != "v1.1.0" {
+		t.Errorf("expected v1.1.0 to be active, got %s", metadata.Version)
+	}
+	if metadata, err := pkgm.Rollback(t.Context(), "steady"); err != nil || metadata.Version != "v2.0.0" {
+		t.Errorf("expected the rollback to undo the downgrade, got %v (%v)", metadata, err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// writeSandboxedTestBlock writes a local block repository named name whose
+// manifest ends with the given sandbox section.
+func writeSandboxedTestBlock(t *testing.T, name, sandbox string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := fmt.Sprintf("name: %s\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: %s\nsandbox:\n%s", name, runtime.GOOS, runtime.GOARCH, name, sandbox)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestSandboxProfile(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	sandboxed := writeSandboxedTestBlock(t, "fetcher", "  network: [api.example.com, \"*.s3.amazonaws.com\"]\n  writable: [~/.cache/fetcher]\n  env: [OPENAI_API_KEY]\n")
+	for _, repo := range []string{sandboxed, writeLocalTestBlock(t, "plain")} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+			t.Fatalf("Install(%s) failed: %v", repo, err)
+		}
+	}
+
+	blocks, err := pkgm.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	profiles := map[string]*packagemanager.Sandbox{}
+	for _, md := range blocks {
+		profiles[md.Name] = md.Sandbox
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 blocks, got %+v", blocks)
+	}
+	if profiles["plain"] != nil {
+		t.Errorf("expected no profile for a block without one, got %+v", profiles["plain"])
+	}
+	fetcher := profiles["fetcher"]
+	if fetcher == nil {
+		t.Fatal("expected the sandbox profile to be stored in the block's metadata")
+	}
+	if !slices.Equal(fetcher.Network, []string{"api.example.com", "*.s3.amazonaws.com"}) ||
+		!slices.Equal(fetcher.Writable, []string{"~/.cache/fetcher"}) ||
+		!slices.Equal(fetcher.Env, []string{"OPENAI_API_KEY"}) {
+		t.Errorf("unexpected profile %+v", fetcher)
+	}
+	if fetcher.AnyNetwork() {
+		t.Error("expected a host list not to allow every host")
+	}
+}
+
+func TestSandboxValidation(t *testing.T) {
+	t.Parallel()
+
+	repo := writeSandboxedTestBlock(t, "broken", "  network: [\"https://api.example.com\"]\n  writable: [relative/dir]\n  env: [NOT-A-NAME]\n")
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo})
+
+	var manifestErr *packagemanager.ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected a ManifestError, got %v", err)
+	}
+	want := map[string]int{
+		"sandbox.network[0]":  7,
+		"sandbox.writable[0]": 8,
+		"sandbox.env[0]":      9,
+	}
+	if len(manifestErr.Issues) != len(want) {
+		t.Errorf("expected %d issues, got %+v", len(want), manifestErr.Issues)
+	}
+	for _, issue := range manifestErr.Issues {
+		if line, ok := want[issue.Field]; !ok || issue.Code != packagemanager.CodeInvalidField || issue.Line != line {
+			t.Errorf("unexpected issue %s, expected %s on line %d", issue, packagemanager.CodeInvalidField, line)
+		}
+	}
+}
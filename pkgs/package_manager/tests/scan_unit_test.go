@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+type flagEverything struct{}
+
+func (flagEverything) Scan(ctx context.Context, path string) (packagemanager.ScanResult, error) {
+	return packagemanager.ScanResult{Threat: "Eicar-Test-Signature"}, nil
+}
+
+func TestScanProviderQuarantinesFlaggedBinary(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "flagged-block"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	manifest := fmt.Sprintf("name: flagged-block\nbinary:\n  assets:\n    %s-%s: flagged-block\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetScanProvider(flagEverything{})
+
+	_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)})
+	if err == nil || !strings.Contains(err.Error(), "Eicar-Test-Signature") {
+		t.Fatalf("expected install to fail with the detected threat, got %v", err)
+	}
+
+	if _, ok := pkgm.GetLoadedBlock("flagged-block"); ok {
+		t.Error("flagged block must not be loaded")
+	}
+
+	if _, err := os.Stat(filepath.Join(pkgm.InstallDir, "flagged-block", "bin", "flagged-block")); !os.IsNotExist(err) {
+		t.Error("flagged binary should have been moved out of the bin directory")
+	}
+
+	quarantined, _ := os.ReadDir(filepath.Join(pkgm.InstallDir, "quarantine", "flagged-block"))
+	if len(quarantined) != 1 {
+		t.Errorf("expected one quarantined file, got %d", len(quarantined))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// rewriteSchemaVersion sets the schema_version of the metadata file at
+// path, removing it for version 0 like files written before it existed.
+func rewriteSchemaVersion(t *testing.T, path string, version int) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %s", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Failed to decode metadata: %s", err)
+	}
+	if version == 0 {
+		delete(fields, "schema_version")
+	} else {
+		fields["schema_version"] = version
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		t.Fatalf("Failed to encode metadata: %s", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write metadata: %s", err)
+	}
+}
+
+func TestMetadataSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	installDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "legacy")}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	path := filepath.Join(installDir, ".atomos", "legacy", "metadata", "v0.1.0.json")
+	if metadata, _ := pkgm.GetMetadata("legacy", ""); metadata == nil || metadata.SchemaVersion != packagemanager.MetadataSchemaVersion {
+		t.Fatalf("expected new metadata at schema version %d, got %+v", packagemanager.MetadataSchemaVersion, metadata)
+	}
+
+	rewriteSchemaVersion(t, path, 0)
+	written := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, written, written); err != nil {
+		t.Fatalf("Failed to age metadata: %s", err)
+	}
+
+	metadata, err := packagemanager.NewPackageManagerWithTestDir(installDir).GetMetadata("legacy", "")
+	if err != nil {
+		t.Fatalf("GetMetadata of legacy metadata failed: %v", err)
+	}
+	if metadata.SchemaVersion != packagemanager.MetadataSchemaVersion || metadata.Version != "v0.1.0" {
+		t.Errorf("expected legacy metadata migrated to schema version %d, got %+v", packagemanager.MetadataSchemaVersion, metadata)
+	}
+	data, _ := os.ReadFile(path)
+	var stored packagemanager.BlockMetadata
+	if err := json.Unmarshal(data, &stored); err != nil || stored.SchemaVersion != packagemanager.MetadataSchemaVersion {
+		t.Errorf("expected the migration to be persisted, got %s", data)
+	}
+	if info, err := os.Stat(path); err != nil || !info.ModTime().Equal(written) {
+		t.Errorf("expected the migration to keep the modification time %v, got %v", written, info.ModTime())
+	}
+
+	rewriteSchemaVersion(t, path, packagemanager.MetadataSchemaVersion+1)
+	if _, err := packagemanager.NewPackageManagerWithTestDir(installDir).GetMetadata("legacy", ""); !errors.Is(err, packagemanager.ErrNewerMetadataSchema) {
+		t.Errorf("expected ErrNewerMetadataSchema, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected metadata of a newer schema to be left in place, got %v", err)
+	}
+}
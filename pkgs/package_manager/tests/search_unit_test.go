@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestFindRanksInstalledBlocks(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+
+	blocks := map[string]string{
+		"profiler":  "Collects CPU profiles",
+		"formatter": "Formats profiler reports",
+	}
+	for name, description := range blocks {
+		blockDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(blockDir, name), []byte("#!/bin/sh\n"), 0644); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+
+		manifest := fmt.Sprintf("name: %s\ndescription: %s\nbinary:\n  assets:\n    %s-%s: %s\nentries:\n  - name: run\n    description: Run the %s\n",
+			name, description, runtime.GOOS, runtime.GOARCH, name, name)
+		if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}); err != nil {
+			t.Fatalf("pkgm.Install() failed: %s", err)
+		}
+	}
+
+	matches, err := pkgm.Find("Profiler")
+	if err != nil {
+		t.Fatalf("pkgm.Find() failed: %s", err)
+	}
+
+	expected := []struct {
+		name  string
+		field string
+	}{
+		{"profiler", "name"},
+		{"formatter", "description"},
+		{"profiler/run", "description"},
+	}
+	if len(matches) != len(expected) {
+		t.Fatalf("expected %d matches, got %d: %+v", len(expected), len(matches), matches)
+	}
+	for i, want := range expected {
+		if matches[i].Name != want.name || matches[i].Field != want.field {
+			t.Errorf("match %d: expected %s (%s), got %s (%s)", i, want.name, want.field, matches[i].Name, matches[i].Field)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		constraint string
+		version    string
+		matches    bool
+	}{
+		{"^1.8.0", "v1.8.1", true},
+		{"^1.8.0", "1.9.0", true},
+		{"^1.8.0", "2.0.0", false},
+		{"^1.8.0", "1.7.9", false},
+		{"^0.3.1", "0.3.9", true},
+		{"^0.3.1", "0.4.0", false},
+		{"~1.7", "1.7.5", true},
+		{"~1.7", "1.8.0", false},
+		{">=1.7 <2.0", "1.9.3", true},
+		{">=1.7 <2.0", "2.0.0", false},
+		{">=1.7, <2.0", "1.6.0", false},
+		{"1.x", "1.2.3", true},
+		{"1.x", "2.0.0", false},
+		{"^1.0.0 || ^3.0.0", "3.1.0", true},
+		{"^1.0.0 || ^3.0.0", "2.1.0", false},
+		{"^1.8.0", "1.9.0-beta.1", false},
+		{">=1.9.0-beta.1", "1.9.0-beta.2", true},
+		{"^1.8.0", "not-a-version", false},
+	}
+
+	for _, tc := range cases {
+		vc, err := packagemanager.ParseVersionConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("ParseVersionConstraint(%q) failed: %s", tc.constraint, err)
+		}
+
+		if got := vc.Matches(tc.version); got != tc.matches {
+			t.Errorf("constraint %q on version %q: expected %t, got %t", tc.constraint, tc.version, tc.matches, got)
+		}
+	}
+}
+
+func TestVersionConstraintMatchesPrerelease(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		constraint string
+		version    string
+		matches    bool
+	}{
+		{"^1.8.0", "1.9.0-beta.1", true},
+		{"^1.8.0", "1.8.0-rc.1", false},
+		{"^1.8.0", "2.0.0-alpha", false},
+		{"~1.7", "1.7.5", true},
+	}
+
+	for _, tc := range cases {
+		vc, err := packagemanager.ParseVersionConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("ParseVersionConstraint(%q) failed: %s", tc.constraint, err)
+		}
+
+		if got := vc.MatchesPrerelease(tc.version); got != tc.matches {
+			t.Errorf("constraint %q on version %q: expected %t, got %t", tc.constraint, tc.version, tc.matches, got)
+		}
+	}
+}
+
+func TestIsVersionConstraint(t *testing.T) {
+	t.Parallel()
+
+	for _, exact := range []string{"1.8.1", "v1.8.1", "latest"} {
+		if packagemanager.IsVersionConstraint(exact) {
+			t.Errorf("%q should be treated as an exact version", exact)
+		}
+	}
+
+	for _, constraint := range []string{"^1.8.0", ">=1.7 <2.0", "~1.7", "1.x"} {
+		if !packagemanager.IsVersionConstraint(constraint) {
+			t.Errorf("%q should be treated as a version constraint", constraint)
+		}
+	}
+
+	if _, err := packagemanager.ParseVersionConstraint(">=banana"); err == nil {
+		t.Fatal("expected an invalid constraint to fail parsing")
+	}
+}
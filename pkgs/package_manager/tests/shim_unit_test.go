@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// shimTarget returns the binary the shim of block in dir runs, "" when
+// there is no shim.
+func shimTarget(t *testing.T, dir, block string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		data, err := os.ReadFile(filepath.Join(dir, block+".cmd"))
+		if err != nil {
+			return ""
+		}
+		_, rest, _ := strings.Cut(string(data), `"`)
+		target, _, _ := strings.Cut(rest, `"`)
+		return target
+	}
+	target, err := os.Readlink(filepath.Join(dir, block))
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+func TestBinDirShims(t *testing.T) {
+	t.Parallel()
+
+	installDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(installDir)
+	if want := filepath.Join(installDir, ".atomos", "bin"); pkgm.BinDir() != want {
+		t.Fatalf("expected the bin dir %s, got %s", want, pkgm.BinDir())
+	}
+
+	for _, name := range []string{"alpha", "beta"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, name)}); err != nil {
+			t.Fatalf("Install %s failed: %v", name, err)
+		}
+	}
+	for _, name := range []string{"alpha", "beta"} {
+		metadata, _ := pkgm.GetLoadedBlock(name)
+		if target := shimTarget(t, pkgm.BinDir(), name); target != metadata.BinaryPath {
+			t.Errorf("expected the shim of %s to run %s, got %q", name, metadata.BinaryPath, target)
+		}
+	}
+
+	stats, err := pkgm.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if len(stats.Blocks) != 2 {
+		t.Errorf("expected the bin dir not to count as a block, got %v", stats.Blocks)
+	}
+
+	if err := pkgm.Uninstall(t.Context(), "beta"); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if target := shimTarget(t, pkgm.BinDir(), "beta"); target != "" {
+		t.Errorf("expected the shim of an uninstalled block to be removed, got %q", target)
+	}
+
+	if err := os.RemoveAll(pkgm.BinDir()); err != nil {
+		t.Fatalf("Failed to remove bin dir: %s", err)
+	}
+	if err := pkgm.RefreshShims(t.Context()); err != nil {
+		t.Fatalf("RefreshShims failed: %v", err)
+	}
+	if target := shimTarget(t, pkgm.BinDir(), "alpha"); target == "" {
+		t.Error("expected RefreshShims to restore the shim of alpha")
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "gamma"), Alias: "bin"}); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected the alias bin to be rejected, got %v", err)
+	}
+}
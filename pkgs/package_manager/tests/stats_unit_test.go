@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+
+	stats, err := pkgm.Stats()
+	if err != nil {
+		t.Fatalf("pkgm.Stats() failed: %s", err)
+	}
+	if stats.IsExisting || stats.TotalBlocks != 0 {
+		t.Errorf("expected a new, empty installation, got %+v", stats)
+	}
+
+	multi := writeLocalTestBlock(t, "multi")
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: multi}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	dir := strings.TrimPrefix(multi, "file://")
+	manifest, _ := os.ReadFile(filepath.Join(dir, "agentic_support.yaml"))
+	manifest = []byte(strings.Replace(string(manifest), "v0.1.0", "v0.2.0", 1))
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), manifest, 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: multi, Force: true}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+	single, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "single")})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	stats, err = packagemanager.NewPackageManagerWithTestDir(testDir).Stats()
+	if err != nil {
+		t.Fatalf("pkgm.Stats() failed: %s", err)
+	}
+	if !stats.IsExisting {
+		t.Error("expected the reopened installation to be reported as existing")
+	}
+	if stats.TotalBlocks != 2 || stats.TotalVersions != 3 || len(stats.InstalledBlocks) != 2 {
+		t.Errorf("expected 2 blocks with 3 versions, got %+v", stats)
+	}
+
+	info, err := os.Stat(single.BinaryPath)
+	if err != nil {
+		t.Fatalf("Failed to stat binary: %s", err)
+	}
+	want := map[string]packagemanager.BlockStats{
+		"multi":  {ActiveVersion: "v0.2.0", Versions: 2, BinarySize: 2 * info.Size()},
+		"single": {ActiveVersion: "v0.1.0", Versions: 1, BinarySize: info.Size()},
+	}
+	for name, block := range want {
+		if stats.Blocks[name] != block {
+			t.Errorf("expected %s to be %+v, got %+v", name, block, stats.Blocks[name])
+		}
+	}
+	if stats.TotalBinarySize != 3*info.Size() {
+		t.Errorf("expected a total size of %d, got %d", 3*info.Size(), stats.TotalBinarySize)
+	}
+}
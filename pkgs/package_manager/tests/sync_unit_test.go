@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestSyncFromController(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: fleet\nbinary:\n  assets:\n    %s-%s: fleet\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	controller := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		if err := os.WriteFile(filepath.Join(blockDir, "fleet"), []byte("#!/bin/sh\necho "+version+"\n"), 0755); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+		if _, err := controller.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir), Version: version, Force: true}); err != nil {
+			t.Fatalf("controller.Install(%s) failed: %s", version, err)
+		}
+	}
+	if _, err := controller.Use(t.Context(), "fleet", "v1.0.0"); err != nil {
+		t.Fatalf("Use failed: %s", err)
+	}
+
+	server := httptest.NewServer(controller.CatalogHandler("secret"))
+	defer server.Close()
+
+	worker := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := worker.SyncFrom(t.Context(), server.URL, packagemanager.SyncOptions{Token: "wrong"}); err == nil {
+		t.Fatal("expected a sync with the wrong token to fail")
+	}
+
+	result, err := worker.SyncFrom(t.Context(), server.URL, packagemanager.SyncOptions{Token: "secret"})
+	if err != nil {
+		t.Fatalf("SyncFrom failed: %s", err)
+	}
+	if len(result.Installed) != 2 || len(result.Activated) != 1 || result.Activated[0] != "fleet@v1.0.0" {
+		t.Errorf("expected both versions installed and v1.0.0 activated, got %+v", result)
+	}
+
+	metadata, ok := worker.GetLoadedBlock("fleet")
+	if !ok || metadata.Version != "v1.0.0" {
+		t.Fatalf("expected fleet v1.0.0 to be active on the worker, got %+v", metadata)
+	}
+	if binary, _ := os.ReadFile(metadata.BinaryPath); string(binary) != "#!/bin/sh\necho v1.0.0\n" {
+		t.Errorf("expected the controller's v1.0.0 binary, got %q", binary)
+	}
+	if _, err := worker.Use(t.Context(), "fleet", "v1.1.0"); err != nil {
+		t.Errorf("expected v1.1.0 to be installed side by side, got %v", err)
+	}
+
+	// Nothing is downloaded again; the controller's active version wins.
+	result, err = worker.SyncFrom(t.Context(), server.URL, packagemanager.SyncOptions{Token: "secret"})
+	if err != nil {
+		t.Fatalf("second SyncFrom failed: %s", err)
+	}
+	if len(result.Installed) != 0 || len(result.UpToDate) != 2 || len(result.Activated) != 1 {
+		t.Errorf("expected an incremental sync reactivating v1.0.0, got %+v", result)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// sourceTarball builds a gzipped tarball nesting files under a top-level
+// directory, as GitHub does.
+func sourceTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "acme-tool-1a2b3c/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: "acme-tool-1a2b3c/" + name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestBuildFromTagTarball(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the build command uses a POSIX shell")
+	}
+
+	tarballs := map[string][]byte{
+		"v1.0.0": sourceTarball(t, map[string]string{"tool.sh": "#!/bin/sh\necho v1.0.0\n"}),
+		"v1.2.0": sourceTarball(t, map[string]string{"tool.sh": "#!/bin/sh\necho v1.2.0\n"}),
+		"v0.9.0": sourceTarball(t, map[string]string{"../escape.sh": "#!/bin/sh\n"}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "name: tool\nbuild:\n  command: mkdir -p out && cp tool.sh out/tool\n  output: out/tool\n")
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", http.NotFound)
+	mux.HandleFunc("/api/repos/acme/tool/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"v1.2.0"},{"name":"v1.0.0"},{"name":"v0.9.0"},{"name":"nightly"}]`)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/tarball/refs/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tarball, ok := tarballs[r.PathValue("tag")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(tarball)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"}); err == nil || !strings.Contains(err.Error(), "no releases") {
+		t.Errorf("expected the install to fail without BuildFromSource, got %v", err)
+	}
+
+	for _, tt := range []struct{ version, want string }{{"", "v1.2.0"}, {"~1.0", "v1.0.0"}} {
+		metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: tt.version, BuildFromSource: true, Force: true})
+		if err != nil {
+			t.Fatalf("Install of %q failed: %v", tt.version, err)
+		}
+		if metadata.Version != tt.want || !metadata.BuiltFromSource {
+			t.Errorf("expected %s built from source, got %+v", tt.want, metadata)
+		}
+		if data, _ := os.ReadFile(metadata.BinaryPath); !strings.Contains(string(data), "echo "+tt.want) {
+			t.Errorf("expected the binary of %s, got %q", tt.want, data)
+		}
+	}
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool", Version: "v0.9.0", BuildFromSource: true, Force: true}); err == nil || !strings.Contains(err.Error(), "outside the source tree") {
+		t.Errorf("expected a tarball escaping its directory to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+const signedBinary = "#!/bin/sh\n"
+
+// newSignedReleaseManager fakes a release of acme/tool publishing the
+// binary "tool" and the given extra assets, checking publishers against a
+// fresh trust store.
+func newSignedReleaseManager(t *testing.T, assets map[string][]byte) (*packagemanager.PackageManager, *packagemanager.TrustStore) {
+	t.Helper()
+
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+	contents := map[string][]byte{"1": []byte(signedBinary)}
+	for name, data := range assets {
+		id := len(release.Assets) + 1
+		release.Assets = append(release.Assets, packagemanager.ReleaseAsset{ID: id, Name: name})
+		contents[fmt.Sprint(id)] = data
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\nbinary:\n  assets:\n    %s-%s: tool\n", runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(contents[r.PathValue("id")])
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+
+	store, err := packagemanager.OpenTrustStore(filepath.Join(t.TempDir(), "trust.json"))
+	if err != nil {
+		t.Fatalf("OpenTrustStore failed: %v", err)
+	}
+	pkgm.SetTrustStore(store)
+	return pkgm, store
+}
+
+// fakeSigstore accepts every bundle as signed by identity.
+type fakeSigstore struct {
+	identity packagemanager.SigstoreIdentity
+}
+
+func (f fakeSigstore) Verify(ctx context.Context, artifactPath string, bundle []byte) (packagemanager.SigstoreIdentity, error) {
+	return f.identity, nil
+}
+
+func TestTrustStoreKeys(t *testing.T) {
+	t.Parallel()
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "trust.json")
+	store, err := packagemanager.OpenTrustStore(path)
+	if err != nil {
+		t.Fatalf("OpenTrustStore failed: %v", err)
+	}
+	fingerprint, err := store.ImportKey("Acme", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil || fingerprint != packagemanager.KeyFingerprint(pub) {
+		t.Fatalf("expected fingerprint %s, got %s, %v", packagemanager.KeyFingerprint(pub), fingerprint, err)
+	}
+	// The same key as base64 isn't added twice.
+	if _, err := store.ImportKey("acme", []byte(base64.StdEncoding.EncodeToString(pub))); err != nil {
+		t.Fatalf("ImportKey failed: %v", err)
+	}
+	if _, err := store.ImportKey("acme", []byte("not a key")); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+	if err := store.SetLevel("acme", "maybe"); err == nil {
+		t.Error("expected an invalid trust level to be rejected")
+	}
+
+	reopened, err := packagemanager.OpenTrustStore(path)
+	if err != nil {
+		t.Fatalf("OpenTrustStore failed: %v", err)
+	}
+	publisher, err := reopened.Publisher("ACME")
+	if err != nil || publisher == nil || publisher.Level != packagemanager.TrustSigned || len(publisher.Keys) != 1 {
+		t.Fatalf("expected acme trusted at signed with one key, got %+v, %v", publisher, err)
+	}
+
+	if err := reopened.RemoveKey("acme", fingerprint); err != nil {
+		t.Fatalf("RemoveKey failed: %v", err)
+	}
+	if err := reopened.RemoveKey("acme", fingerprint); err == nil {
+		t.Error("expected removing a missing key to fail")
+	}
+	if err := reopened.Remove("acme"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if publishers, err := reopened.Publishers(); err != nil || len(publishers) != 0 {
+		t.Errorf("expected no publishers, got %+v, %v", publishers, err)
+	}
+}
+
+func TestInstallChecksPublisherTrust(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(signedBinary))))
+	release := packagemanager.SigstoreIdentity{Issuer: "https://token.actions.githubusercontent.com", Subject: "https://github.com/acme/tool/.github/workflows/release.yml@refs/tags/v1.0.0"}
+
+	tests := []struct {
+		name    string
+		assets  map[string][]byte
+		setup   func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error
+		req     packagemanager.InstallRequest
+		wantErr error
+	}{
+		{
+			name:    "unknown publisher",
+			wantErr: packagemanager.ErrUntrustedPublisher,
+		},
+		{
+			name: "unknown publisher allowed by the request",
+			req:  packagemanager.InstallRequest{AllowUnknownPublisher: true},
+		},
+		{
+			name: "unknown publisher confirmed",
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				pkgm.SetTrustPrompt(func(ctx context.Context, publisher, repo string) (bool, error) {
+					return publisher == "acme" && repo == "acme/tool", nil
+				})
+				return nil
+			},
+		},
+		{
+			name: "unknown publisher declined",
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				pkgm.SetTrustPrompt(func(ctx context.Context, publisher, repo string) (bool, error) { return false, nil })
+				return nil
+			},
+			wantErr: packagemanager.ErrUntrustedPublisher,
+		},
+		{
+			name: "blocked publisher",
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				return store.SetLevel("acme", packagemanager.TrustBlocked)
+			},
+			req:     packagemanager.InstallRequest{AllowUnknownPublisher: true},
+			wantErr: packagemanager.ErrUntrustedPublisher,
+		},
+		{
+			name: "trusted publisher",
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				return store.SetLevel("acme", packagemanager.TrustFull)
+			},
+		},
+		{
+			name:   "valid signature",
+			assets: map[string][]byte{"tool.sig": signature},
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				_, err := store.ImportKey("acme", []byte(base64.StdEncoding.EncodeToString(pub)))
+				return err
+			},
+		},
+		{
+			name:   "signature by another key",
+			assets: map[string][]byte{"tool.sig": signature},
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				_, err := store.ImportKey("acme", []byte(base64.StdEncoding.EncodeToString(otherPub)))
+				return err
+			},
+			wantErr: packagemanager.ErrSignatureVerification,
+		},
+		{
+			name: "missing signature",
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				_, err := store.ImportKey("acme", []byte(base64.StdEncoding.EncodeToString(pub)))
+				return err
+			},
+			wantErr: packagemanager.ErrSignatureVerification,
+		},
+		{
+			name:   "sigstore identity",
+			assets: map[string][]byte{"tool.sigstore.json": []byte("{}")},
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				pkgm.SetSigstoreVerifier(fakeSigstore{release})
+				return store.AddIdentity("acme", release)
+			},
+		},
+		{
+			name:   "sigstore identity of someone else",
+			assets: map[string][]byte{"tool.sigstore.json": []byte("{}")},
+			setup: func(pkgm *packagemanager.PackageManager, store *packagemanager.TrustStore) error {
+				pkgm.SetSigstoreVerifier(fakeSigstore{packagemanager.SigstoreIdentity{Issuer: release.Issuer, Subject: "https://github.com/evil/tool"}})
+				return store.AddIdentity("acme", release)
+			},
+			wantErr: packagemanager.ErrSignatureVerification,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm, store := newSignedReleaseManager(t, tt.assets)
+			if tt.setup != nil {
+				if err := tt.setup(pkgm, store); err != nil {
+					t.Fatalf("setup failed: %v", err)
+				}
+			}
+
+			req := tt.req
+			req.Repo = "acme/tool"
+			_, err := pkgm.Install(t.Context(), req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if _, installed := pkgm.GetLoadedBlock("tool"); installed {
+					t.Error("expected the refused block not to be installed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+		})
+	}
+}
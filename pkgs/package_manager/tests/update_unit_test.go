@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestUpdateKeepsPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	release := func(version string) {
+		manifest := fmt.Sprintf("name: fresh\nversion: %s\nbinary:\n  assets:\n    %s-%s: fresh\n", version, runtime.GOOS, runtime.GOARCH)
+		if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %s", err)
+		}
+		if err := os.WriteFile(filepath.Join(blockDir, "fresh"), []byte("#!/bin/sh\necho "+version+"\n"), 0755); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+	}
+
+	release("v1.0.0")
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}); err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	check, err := pkgm.CheckForUpdates(t.Context(), "fresh")
+	if err != nil {
+		t.Fatalf("CheckForUpdates failed: %s", err)
+	}
+	if check.UpdateAvailable {
+		t.Errorf("expected no update before a new release, got %+v", check)
+	}
+
+	release("v1.1.0")
+	check, err = pkgm.CheckForUpdates(t.Context(), "fresh")
+	if err != nil {
+		t.Fatalf("CheckForUpdates failed: %s", err)
+	}
+	if !check.UpdateAvailable || check.CurrentVersion != "v1.0.0" || check.LatestVersion != "v1.1.0" {
+		t.Errorf("expected an update from v1.0.0 to v1.1.0, got %+v", check)
+	}
+
+	result, err := pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "fresh"})
+	if err != nil {
+		t.Fatalf("Update failed: %s", err)
+	}
+	if !result.Success || result.OldVersion != "v1.0.0" || result.NewVersion != "v1.1.0" {
+		t.Errorf("expected an update from v1.0.0 to v1.1.0, got %+v", result)
+	}
+	if binary, _ := os.ReadFile(result.BinaryPath); string(binary) != "#!/bin/sh\necho v1.1.0\n" {
+		t.Errorf("expected the v1.1.0 binary, got %q", binary)
+	}
+
+	result, err = pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "fresh"})
+	if err != nil {
+		t.Fatalf("second Update failed: %s", err)
+	}
+	if result.OldVersion != "v1.1.0" || result.NewVersion != "v1.1.0" {
+		t.Errorf("expected an up-to-date block to stay at v1.1.0, got %+v", result)
+	}
+
+	metadata, err := pkgm.Rollback(t.Context(), "fresh")
+	if err != nil {
+		t.Fatalf("Rollback failed: %s", err)
+	}
+	if binary, _ := os.ReadFile(metadata.BinaryPath); metadata.Version != "v1.0.0" || string(binary) != "#!/bin/sh\necho v1.0.0\n" {
+		t.Errorf("expected the v1.0.0 binary to be preserved, got %s %q", metadata.Version, binary)
+	}
+
+	if _, err := pkgm.Update(t.Context(), packagemanager.UpdateRequest{Blockname: "missing"}); err == nil {
+		t.Error("expected updating a block that isn't installed to fail")
+	}
+}
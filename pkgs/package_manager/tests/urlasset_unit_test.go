@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+const urlAssetContent = "#!/bin/sh\necho downloaded\n"
+
+// newURLAssetManager returns a package manager installing acme/tool, whose
+// binary asset is the URL returned by assetURL for the fake server, with
+// checksum. It also returns the Authorization headers the file host saw.
+func newURLAssetManager(t *testing.T, checksum string, assetURL func(server string) string) (*packagemanager.PackageManager, func() []string) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0"}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\nbinary:\n  assets:\n    %s-%s: %s\nchecksums:\n  tool-bin: %s\n", runtime.GOOS, runtime.GOARCH, assetURL(server.URL), checksum)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/files/tool-bin", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+		fmt.Fprint(w, urlAssetContent)
+	})
+	server = httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+		return "test-token", nil
+	}))
+	if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{APIURL: server.URL + "/api", RawURL: server.URL + "/raw/"}); err != nil {
+		t.Fatalf("SetGitHubConfig failed: %v", err)
+	}
+	if err := pkgm.SetHTTPConfig(packagemanager.HTTPConfig{Client: server.Client(), Retry: packagemanager.RetryPolicy{Delay: time.Millisecond}}); err != nil {
+		t.Fatalf("SetHTTPConfig failed: %v", err)
+	}
+	return pkgm, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return auths
+	}
+}
+
+func TestURLAssetInstall(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(urlAssetContent))
+	digest := hex.EncodeToString(sum[:])
+	pkgm, auths := newURLAssetManager(t, digest, func(server string) string { return server + "/files/tool-bin" })
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	// The binary is stored under the URL's file name in the standard layout.
+	if !strings.HasSuffix(filepath.ToSlash(metadata.BinaryPath), "/tool/bin/v1.0.0/tool-bin") {
+		t.Errorf("expected the binary in <block>/bin/<version>/tool-bin, got %s", metadata.BinaryPath)
+	}
+	data, err := os.ReadFile(metadata.BinaryPath)
+	if err != nil {
+		t.Fatalf("Failed to read binary: %s", err)
+	}
+	if string(data) != urlAssetContent {
+		t.Errorf("expected the downloaded content, got %q", data)
+	}
+	if metadata.SHA256 != digest {
+		t.Errorf("expected digest %s, got %s", digest, metadata.SHA256)
+	}
+
+	got := auths()
+	if len(got) == 0 {
+		t.Fatal("expected the asset URL to be downloaded")
+	}
+	for _, auth := range got {
+		if auth != "" {
+			t.Errorf("expected no credentials sent to the asset host, got %q", auth)
+		}
+	}
+}
+
+func TestURLAssetRejected(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(urlAssetContent))
+	tests := []struct {
+		name     string
+		checksum string
+		assetURL func(server string) string
+		want     string
+	}{
+		{
+			name:     "checksum mismatch",
+			checksum: strings.Repeat("0", 64),
+			assetURL: func(server string) string { return server + "/files/tool-bin" },
+			want:     "checksum",
+		},
+		{
+			name:     "plain http",
+			checksum: hex.EncodeToString(sum[:]),
+			assetURL: func(server string) string { return "http" + strings.TrimPrefix(server, "https") + "/files/tool-bin" },
+			want:     "must use https",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgm, _ := newURLAssetManager(t, tt.checksum, tt.assetURL)
+			_, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error mentioning %q, got %v", tt.want, err)
+			}
+			if _, ok := pkgm.GetLoadedBlock("tool"); ok {
+				t.Error("expected a rejected asset to leave the block uninstalled")
+			}
+		})
+	}
+}
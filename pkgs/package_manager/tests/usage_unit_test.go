@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestMarkUsedAndPrune(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	for _, name := range []string{"used", "stale", "fresh"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, name)}); err != nil {
+			t.Fatalf("Install of %s failed: %v", name, err)
+		}
+	}
+
+	// Backdate the installs of used and stale, as if they were old.
+	for _, name := range []string{"used", "stale"} {
+		path := filepath.Join(pkgm.InstallDir, name, "metadata", "v0.1.0.json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			t.Fatal(err)
+		}
+		raw["installed_at"] = time.Now().Add(-48 * time.Hour)
+		if data, err = json.Marshal(raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := time.Now()
+	if err := pkgm.MarkUsed("used"); err != nil {
+		t.Fatalf("MarkUsed failed: %v", err)
+	}
+	if err := pkgm.MarkUsed("missing"); err == nil {
+		t.Error("expected MarkUsed of a block that isn't installed to fail")
+	}
+
+	metadata, err := pkgm.GetMetadata("used", "")
+	if err != nil || metadata.LastUsed.Before(before) {
+		t.Fatalf("expected the last use to be recorded, got %+v, %v", metadata, err)
+	}
+	stats, err := pkgm.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if !stats.Blocks["used"].LastUsed.Equal(metadata.LastUsed) || !stats.Blocks["stale"].LastUsed.IsZero() {
+		t.Errorf("expected only used to have a last use, got %+v", stats.Blocks)
+	}
+
+	pruned, err := pkgm.Prune(t.Context(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if !slices.Equal(pruned, []string{"stale"}) {
+		t.Errorf("expected only stale to be pruned, got %v", pruned)
+	}
+	if _, err := pkgm.GetMetadata("stale", ""); err == nil {
+		t.Error("expected stale to be uninstalled")
+	}
+	for _, name := range []string{"used", "fresh"} {
+		if _, err := pkgm.GetMetadata(name, ""); err != nil {
+			t.Errorf("expected %s to be kept: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestVendorAndInstallOffline(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "vendored"), []byte("#!/bin/sh\necho vendored\n"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: vendored\nbinary:\n  assets:\n    %s-%s: vendored\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	repo := "file://" + filepath.ToSlash(blockDir)
+
+	source := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	installed, err := source.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Version: "v1.2.0"})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	vendorDir := t.TempDir()
+	if err := source.Vendor(vendorDir); err != nil {
+		t.Fatalf("Vendor failed: %s", err)
+	}
+
+	// The original block directory is gone: only the vendor dir can serve it.
+	if err := os.RemoveAll(blockDir); err != nil {
+		t.Fatalf("Failed to remove block dir: %s", err)
+	}
+
+	offline := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	offline.SetOfflineMode(vendorDir)
+
+	metadata, err := offline.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Version: "^1.0.0"})
+	if err != nil {
+		t.Fatalf("offline Install failed: %s", err)
+	}
+	if metadata.Version != "v1.2.0" || metadata.SHA256 != installed.SHA256 {
+		t.Errorf("expected vendored v1.2.0 (%s), got %s (%s)", installed.SHA256, metadata.Version, metadata.SHA256)
+	}
+	if _, err := os.Stat(metadata.BinaryPath); err != nil {
+		t.Errorf("expected the binary to be installed: %s", err)
+	}
+
+	if _, err := offline.Install(t.Context(), packagemanager.InstallRequest{Repo: "vendored", Version: "v2.0.0", Force: true}); !errors.Is(err, packagemanager.ErrNotVendored) {
+		t.Errorf("expected ErrNotVendored for a missing version, got %v", err)
+	}
+	if _, err := offline.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/elsewhere"}); !errors.Is(err, packagemanager.ErrNotVendored) {
+		t.Errorf("expected ErrNotVendored for an unknown block, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestVerifyAndRepair(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+	installed, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "fragile")})
+	if err != nil {
+		t.Fatalf("pkgm.Install() failed: %s", err)
+	}
+
+	result, err := pkgm.Verify("fragile")
+	if err != nil {
+		t.Fatalf("pkgm.Verify() failed: %s", err)
+	}
+	if !result.OK() || result.ActualSHA256 != installed.SHA256 {
+		t.Fatalf("expected a fresh install to verify, got %+v", result)
+	}
+
+	if err := os.WriteFile(installed.BinaryPath, []byte("tampered"), 0644); err != nil {
+		t.Fatalf("Failed to tamper with binary: %s", err)
+	}
+	if err := os.Chmod(installed.BinaryPath, 0644); err != nil {
+		t.Fatalf("Failed to clear executable bit: %s", err)
+	}
+	result, err = pkgm.Verify("fragile")
+	if err != nil {
+		t.Fatalf("pkgm.Verify() failed: %s", err)
+	}
+	if !slices.Contains(result.Issues, packagemanager.IssueChecksumMismatch) {
+		t.Errorf("expected a checksum mismatch, got %v", result.Issues)
+	}
+	if runtime.GOOS != "windows" && !slices.Contains(result.Issues, packagemanager.IssueNotExecutable) {
+		t.Errorf("expected a missing executable bit, got %v", result.Issues)
+	}
+
+	if err := os.Remove(installed.BinaryPath); err != nil {
+		t.Fatalf("Failed to remove binary: %s", err)
+	}
+
+	// A missing binary no longer prevents loading the installation.
+	reopened := packagemanager.NewPackageManagerWithTestDir(testDir)
+	if _, ok := reopened.GetLoadedBlock("fragile"); !ok {
+		t.Fatal("expected the block to be loaded despite its missing binary")
+	}
+	result, err = reopened.Verify("fragile")
+	if err != nil {
+		t.Fatalf("pkgm.Verify() failed: %s", err)
+	}
+	if !slices.Equal(result.Issues, []packagemanager.VerifyIssue{packagemanager.IssueMissingBinary}) {
+		t.Errorf("expected only a missing binary, got %v", result.Issues)
+	}
+
+	repaired, err := reopened.Repair(t.Context(), "fragile")
+	if err != nil {
+		t.Fatalf("pkgm.Repair() failed: %s", err)
+	}
+	if repaired.Version != installed.Version || repaired.SHA256 != installed.SHA256 {
+		t.Errorf("expected %s with digest %s, got %s with %s", installed.Version, installed.SHA256, repaired.Version, repaired.SHA256)
+	}
+	if result, err := reopened.Verify("fragile"); err != nil || !result.OK() {
+		t.Errorf("expected the repaired block to verify, got %+v, %v", result, err)
+	}
+
+	if _, err := reopened.Verify("absent"); err == nil {
+		t.Error("expected verifying a block that isn't installed to fail")
+	}
+}
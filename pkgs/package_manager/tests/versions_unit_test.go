@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestSideBySideVersions(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: twin\nbinary:\n  assets:\n    %s-%s: twin\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	testDir := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(testDir)
+	installed := map[string]*packagemanager.BlockMetadata{}
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		if err := os.WriteFile(filepath.Join(blockDir, "twin"), []byte("#!/bin/sh\necho "+version+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+		metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir), Version: version, Force: true})
+		if err != nil {
+			t.Fatalf("pkgm.Install(%s) failed: %s", version, err)
+		}
+		installed[version] = metadata
+	}
+
+	if installed["v1.0.0"].BinaryPath == installed["v2.0.0"].BinaryPath {
+		t.Fatalf("expected each version to have its own binary, both use %s", installed["v1.0.0"].BinaryPath)
+	}
+	if active, _ := pkgm.GetLoadedBlock("twin"); active.Version != "v2.0.0" {
+		t.Errorf("expected the last install to be active, got %s", active.Version)
+	}
+
+	if _, err := pkgm.Use(t.Context(), "twin", "v1.0.0"); err != nil {
+		t.Fatalf("Use failed: %s", err)
+	}
+	if _, err := pkgm.Use(t.Context(), "twin", "v3.0.0"); err == nil {
+		t.Error("expected Use of a missing version to fail")
+	}
+
+	reloaded := packagemanager.NewPackageManagerWithTestDir(testDir)
+	active, ok := reloaded.GetLoadedBlock("twin")
+	if !ok || active.Version != "v1.0.0" || !active.IsActive {
+		t.Fatalf("expected v1.0.0 to stay active after reload, got %+v", active)
+	}
+	binary, err := os.ReadFile(active.BinaryPath)
+	if err != nil || string(binary) != "#!/bin/sh\necho v1.0.0\n" {
+		t.Errorf("expected the v1.0.0 binary to be intact, got %q (%v)", binary, err)
+	}
+
+	if err := reloaded.Uninstall(t.Context(), "twin"); err != nil {
+		t.Fatalf("Uninstall failed: %s", err)
+	}
+	if active, ok := reloaded.GetLoadedBlock("twin"); !ok || active.Version != "v2.0.0" {
+		t.Errorf("expected v2.0.0 to become active after uninstalling v1.0.0, got %+v", active)
+	}
+}
+
+func TestGetMetadataAndListVersions(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	manifest := fmt.Sprintf("name: multi\nbinary:\n  assets:\n    %s-%s: multi\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(blockDir, "multi"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	for _, version := range []string{"v1.10.0", "v1.2.0", "v1.9.0"} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir), Version: version, Force: true}); err != nil {
+			t.Fatalf("pkgm.Install(%s) failed: %s", version, err)
+		}
+	}
+
+	versions, err := pkgm.ListVersions("multi")
+	if err != nil {
+		t.Fatalf("ListVersions failed: %s", err)
+	}
+	var got []string
+	for _, metadata := range versions {
+		got = append(got, fmt.Sprintf("%s:%t", metadata.Version, metadata.IsActive))
+	}
+	if want := "[v1.2.0:false v1.9.0:true v1.10.0:false]"; fmt.Sprint(got) != want {
+		t.Errorf("expected versions %s, got %v", want, got)
+	}
+
+	metadata, err := pkgm.GetMetadata("multi", "1.10.0")
+	if err != nil || metadata.Version != "v1.10.0" {
+		t.Errorf("expected the metadata of v1.10.0, got %+v (%v)", metadata, err)
+	}
+	if active, err := pkgm.GetMetadata("multi", ""); err != nil || active.Version != "v1.9.0" {
+		t.Errorf("expected the active version v1.9.0, got %+v (%v)", active, err)
+	}
+	if _, err := pkgm.GetMetadata("multi", "v2.0.0"); err == nil {
+		t.Error("expected a missing version to fail")
+	}
+	if _, err := pkgm.ListVersions("missing"); err == nil {
+		t.Error("expected listing a missing block to fail")
+	}
+}
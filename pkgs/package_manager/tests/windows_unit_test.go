@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestInstallForWindowsPlatforms(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	assets := map[string]string{
+		"linux-amd64":   "tool-linux-amd64",
+		"windows-amd64": "tool-windows-amd64",
+		"windows-arm64": "tool_windows_arm64.zip",
+	}
+	manifest := "name: tool\nversion: v0.2.0\nbinary:\n  assets:\nchecksums:\n"
+	var assetLines, checksumLines string
+	for platform, asset := range assets {
+		data := []byte("binary for " + platform)
+		if err := os.WriteFile(filepath.Join(blockDir, asset), data, 0644); err != nil {
+			t.Fatalf("Failed to write binary: %s", err)
+		}
+		sum := sha256.Sum256(data)
+		assetLines += "    " + platform + ": " + asset + "\n"
+		checksumLines += "  " + platform + ": " + hex.EncodeToString(sum[:]) + "\n"
+	}
+	manifest = strings.Replace(manifest, "  assets:\n", "  assets:\n"+assetLines, 1) + checksumLines
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	binaries, err := pkgm.InstallForPlatforms(t.Context(), packagemanager.InstallRequest{Repo: "file://" + filepath.ToSlash(blockDir)}, []string{"linux-amd64", "windows-amd64", "windows-arm64"})
+	if err != nil {
+		t.Fatalf("InstallForPlatforms failed: %v", err)
+	}
+
+	want := map[string]string{
+		"linux-amd64":   "tool-linux-amd64",
+		"windows-amd64": "tool-windows-amd64.exe",
+		"windows-arm64": "tool_windows_arm64.zip",
+	}
+	for _, binary := range binaries {
+		if name := filepath.Base(binary.Path); name != want[binary.Platform] {
+			t.Errorf("expected the %s binary to be stored as %s, got %s", binary.Platform, want[binary.Platform], name)
+		}
+		data, err := os.ReadFile(binary.Path)
+		if err != nil || string(data) != "binary for "+binary.Platform {
+			t.Errorf("expected the %s binary, got %q (%v)", binary.Platform, data, err)
+		}
+		if runtime.GOOS == "windows" {
+			continue
+		}
+		info, err := os.Stat(binary.Path)
+		if err != nil {
+			t.Fatalf("Failed to stat binary: %s", err)
+		}
+		if executable := info.Mode().Perm()&0111 != 0; executable != (binary.Platform == "linux-amd64") {
+			t.Errorf("expected only the linux binary to be made executable, %s has mode %v", binary.Platform, info.Mode())
+		}
+	}
+}
+
+func TestWriteShim(t *testing.T) {
+	t.Parallel()
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: writeLocalTestBlock(t, "shimmed")}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	metadata, err := pkgm.GetMetadata("shimmed", "")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "bin")
+	for range 2 {
+		path, err := pkgm.WriteShim("shimmed", dir)
+		if err != nil {
+			t.Fatalf("WriteShim failed: %v", err)
+		}
+
+		if runtime.GOOS == "windows" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read shim: %s", err)
+			}
+			if filepath.Base(path) != "shimmed.cmd" || !strings.Contains(string(data), `"`+metadata.BinaryPath+`" %*`) {
+				t.Errorf("expected a .cmd shim running %s, got %s:\n%s", metadata.BinaryPath, path, data)
+			}
+			continue
+		}
+
+		if target, err := os.Readlink(path); err != nil || target != metadata.BinaryPath {
+			t.Errorf("expected %s to link to %s, got %q (%v)", path, metadata.BinaryPath, target, err)
+		}
+	}
+
+	if _, err := pkgm.WriteShim("missing", dir); err == nil {
+		t.Error("expected an error for a block that isn't installed")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+func TestYankedAndDeprecatedBlocks(t *testing.T) {
+	t.Parallel()
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "old"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: old\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: old\nyanked_versions: [v1.0.0]\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	repo := "file://" + filepath.ToSlash(blockDir)
+
+	// The registry deprecates the block on top of the manifest.
+	registry := filepath.Join(t.TempDir(), "registry.yaml")
+	index := fmt.Sprintf("blocks:\n  - name: old\n    repo: %s\n    deprecated: use acme/new instead\n", repo)
+	if err := os.WriteFile(registry, []byte(index), 0644); err != nil {
+		t.Fatalf("Failed to write registry: %s", err)
+	}
+
+	pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+	pkgm.SetRegistry("file://" + filepath.ToSlash(registry))
+
+	if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); !errors.Is(err, packagemanager.ErrYankedVersion) {
+		t.Fatalf("expected ErrYankedVersion, got %v", err)
+	}
+
+	metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo, Force: true})
+	if err != nil {
+		t.Fatalf("forced install of a yanked version failed: %v", err)
+	}
+	if !metadata.Yanked || metadata.Deprecated != "use acme/new instead" {
+		t.Errorf("expected a yanked, deprecated block, got yanked=%v deprecated=%q", metadata.Yanked, metadata.Deprecated)
+	}
+
+	outcomes, err := pkgm.InstallAll(t.Context(), []packagemanager.InstallRequest{{Repo: repo, Force: true}, {Repo: "file:///does/not/exist"}}, 0)
+	if err == nil {
+		t.Fatal("expected InstallAll to report the missing block")
+	}
+	if result := outcomes[0].Result(); !result.Success || result.Blockname != "old" || result.Deprecated != "use acme/new instead" {
+		t.Errorf("expected the install result to carry the deprecation notice, got %+v", result)
+	}
+	if result := outcomes[1].Result(); result.Success || result.Message == "" {
+		t.Errorf("expected a failed install result with its error, got %+v", result)
+	}
+}
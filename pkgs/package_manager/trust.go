@@ -0,0 +1,505 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUntrustedPublisher is returned when the trust store blocks the
+	// publisher of a block, or doesn't know it and the install wasn't
+	// confirmed.
+	ErrUntrustedPublisher = errors.New("publisher not trusted")
+	// ErrSignatureVerification is returned when a publisher trusted at
+	// TrustSigned published no signature of a binary that its keys or
+	// identities verify.
+	ErrSignatureVerification = errors.New("signature verification failed")
+)
+
+// TrustLevel is how far a publisher is trusted.
+type TrustLevel string
+
+const (
+	// TrustBlocked publishers are never installed.
+	TrustBlocked TrustLevel = "blocked"
+	// TrustSigned publishers are installed only when their release signs
+	// the binary with one of their keys or Sigstore identities.
+	TrustSigned TrustLevel = "signed"
+	// TrustFull publishers are installed without checking signatures.
+	TrustFull TrustLevel = "trusted"
+)
+
+// SigstoreIdentity is the identity a Sigstore signing certificate was
+// issued to, e.g. a release workflow of GitHub Actions.
+type SigstoreIdentity struct {
+	Issuer  string `json:"issuer"`  // OIDC issuer, e.g. https://token.actions.githubusercontent.com
+	Subject string `json:"subject"` // Certificate subject, e.g. a workflow URL or an email
+}
+
+// SigstoreVerifier verifies a Sigstore bundle, including its certificate
+// chain and transparency log entry, and returns the identity that signed
+// artifactPath. Plug in one built on sigstore-go to check Sigstore
+// signatures; without one, only key signatures are verified.
+type SigstoreVerifier interface {
+	Verify(ctx context.Context, artifactPath string, bundle []byte) (SigstoreIdentity, error)
+}
+
+// TrustPrompt asks the user whether to install repo from publisher, which
+// the trust store doesn't know. Returning false refuses the install.
+type TrustPrompt func(ctx context.Context, publisher, repo string) (bool, error)
+
+// Publisher is a trust store entry. GitHub publishers are named after the
+// repository owner ("acme"), GitLab ones after the project's namespace
+// prefixed with "gitlab:" ("gitlab:group/subgroup").
+type Publisher struct {
+	Name       string             `json:"name"`
+	Level      TrustLevel         `json:"level"`
+	Keys       []string           `json:"keys,omitempty"` // Base64 Ed25519 public keys
+	Identities []SigstoreIdentity `json:"identities,omitempty"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// TrustStore keeps the publishers a user trusts, with their signing keys,
+// in a JSON file that several package managers may share.
+type TrustStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OpenTrustStore opens the trust store at path, created on the first
+// change.
+func OpenTrustStore(path string) (*TrustStore, error) {
+	store := &TrustStore{path: path}
+	if _, err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *TrustStore) load() (map[string]*Publisher, error) {
+	publishers := map[string]*Publisher{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return publishers, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trust store: %w", err)
+	}
+
+	var list []*Publisher
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode trust store %s: %w", s.path, err)
+	}
+	for _, publisher := range list {
+		publishers[publisher.Name] = publisher
+	}
+	return publishers, nil
+}
+
+// update applies change to the stored publishers and writes them back,
+// through a temporary file renamed into place.
+func (s *TrustStore) update(change func(publishers map[string]*Publisher) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publishers, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := change(publishers); err != nil {
+		return err
+	}
+
+	list := make([]*Publisher, 0, len(publishers))
+	for _, publisher := range publishers {
+		list = append(list, publisher)
+	}
+	slices.SortFunc(list, func(a, b *Publisher) int { return strings.Compare(a.Name, b.Name) })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trust store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create trust store directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".trust-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	return nil
+}
+
+// entry returns the publisher called name, adding it at level when it
+// isn't stored yet.
+func entry(publishers map[string]*Publisher, name string, level TrustLevel) (*Publisher, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, errors.New("publisher name is empty")
+	}
+	publisher, ok := publishers[name]
+	if !ok {
+		publisher = &Publisher{Name: name, Level: level}
+		publishers[name] = publisher
+	}
+	publisher.UpdatedAt = time.Now().UTC()
+	return publisher, nil
+}
+
+// ImportKey adds an Ed25519 public key of publisher, PEM-encoded or as
+// base64, and returns its fingerprint. Publishers imported this way are
+// trusted at TrustSigned until SetLevel changes it.
+func (s *TrustStore) ImportKey(publisher string, key []byte) (string, error) {
+	pub, err := parsePublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(pub)
+	err = s.update(func(publishers map[string]*Publisher) error {
+		p, err := entry(publishers, publisher, TrustSigned)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(p.Keys, encoded) {
+			p.Keys = append(p.Keys, encoded)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return KeyFingerprint(pub), nil
+}
+
+// RemoveKey removes the key of publisher with the given fingerprint.
+func (s *TrustStore) RemoveKey(publisher, fingerprint string) error {
+	return s.update(func(publishers map[string]*Publisher) error {
+		p, ok := publishers[strings.ToLower(publisher)]
+		if !ok {
+			return fmt.Errorf("publisher '%s' is not in the trust store", publisher)
+		}
+		kept := slices.DeleteFunc(slices.Clone(p.Keys), func(key string) bool {
+			pub, err := base64.StdEncoding.DecodeString(key)
+			return err == nil && KeyFingerprint(pub) == fingerprint
+		})
+		if len(kept) == len(p.Keys) {
+			return fmt.Errorf("publisher '%s' has no key %s", publisher, fingerprint)
+		}
+		p.Keys = kept
+		p.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// AddIdentity adds a Sigstore identity allowed to sign the releases of
+// publisher, which is trusted at TrustSigned when it is new.
+func (s *TrustStore) AddIdentity(publisher string, identity SigstoreIdentity) error {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return errors.New("a Sigstore identity needs an issuer and a subject")
+	}
+	return s.update(func(publishers map[string]*Publisher) error {
+		p, err := entry(publishers, publisher, TrustSigned)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(p.Identities, identity) {
+			p.Identities = append(p.Identities, identity)
+		}
+		return nil
+	})
+}
+
+// SetLevel sets how far publisher is trusted, adding it when it is new.
+func (s *TrustStore) SetLevel(publisher string, level TrustLevel) error {
+	switch level {
+	case TrustBlocked, TrustSigned, TrustFull:
+	default:
+		return fmt.Errorf("invalid trust level '%s'", level)
+	}
+	return s.update(func(publishers map[string]*Publisher) error {
+		p, err := entry(publishers, publisher, level)
+		if err != nil {
+			return err
+		}
+		p.Level = level
+		return nil
+	})
+}
+
+// Remove forgets publisher, whose blocks then need confirming again.
+func (s *TrustStore) Remove(publisher string) error {
+	return s.update(func(publishers map[string]*Publisher) error {
+		delete(publishers, strings.ToLower(publisher))
+		return nil
+	})
+}
+
+// Publisher returns the entry of publisher, nil when the store doesn't
+// know it.
+func (s *TrustStore) Publisher(name string) (*Publisher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publishers, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return publishers[strings.ToLower(name)], nil
+}
+
+// Publishers returns every stored publisher, by name.
+func (s *TrustStore) Publishers() ([]Publisher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publishers, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Publisher, 0, len(publishers))
+	for _, publisher := range publishers {
+		list = append(list, *publisher)
+	}
+	slices.SortFunc(list, func(a, b Publisher) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+// KeyFingerprint identifies a public key as "SHA256:" followed by the
+// unpadded base64 digest of its bytes, like ssh-keygen -l.
+func KeyFingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// parsePublicKey decodes a PEM "PUBLIC KEY" block or a base64 raw key,
+// which must be Ed25519.
+func parsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported public key type %T: only Ed25519 keys are supported", parsed)
+		}
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key: expected a PEM block or a base64 Ed25519 key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// SetTrustStore makes installs from GitHub and GitLab check their
+// publisher against store. Passing nil, the default, trusts every
+// publisher.
+func (pm *PackageManager) SetTrustStore(store *TrustStore) {
+	pm.trust = store
+}
+
+// SetTrustPrompt sets the function asked to confirm installs from
+// publishers missing from the trust store. Without one, such installs fail
+// unless InstallRequest.AllowUnknownPublisher is set.
+func (pm *PackageManager) SetTrustPrompt(prompt TrustPrompt) {
+	pm.trustPrompt = prompt
+}
+
+// SetSigstoreVerifier sets the verifier of Sigstore bundles published with
+// release assets, checked for publishers with Sigstore identities.
+func (pm *PackageManager) SetSigstoreVerifier(verifier SigstoreVerifier) {
+	pm.sigstore = verifier
+}
+
+type signingPublisherKey struct{}
+
+// signingPublisher returns the publisher whose signatures the install
+// running under ctx must verify, nil when it needs none.
+func signingPublisher(ctx context.Context) *Publisher {
+	publisher, _ := ctx.Value(signingPublisherKey{}).(*Publisher)
+	return publisher
+}
+
+// publisherOf names the publisher of a GitHub or GitLab repository.
+func publisherOf(repo string) string {
+	if source, ok := parseGitLabRepo(repo); ok {
+		namespace := source.project
+		if i := strings.LastIndex(namespace, "/"); i >= 0 {
+			namespace = namespace[:i]
+		}
+		if source.baseURL != defaultGitLabHost {
+			// Namespaces of self-hosted instances are their own publishers.
+			namespace = strings.TrimPrefix(strings.TrimPrefix(source.baseURL, "https://"), "http://") + "/" + namespace
+		}
+		return strings.ToLower(gitLabRepoPrefix + namespace)
+	}
+	repo, _ = splitRepoPath(repo)
+	owner, _, _ := strings.Cut(repo, "/")
+	return strings.ToLower(owner)
+}
+
+// checkPublisher refuses repo when the trust store blocks its publisher,
+// or doesn't know it and neither req nor the trust prompt confirms it. The
+// returned context carries the publisher when its signatures must be
+// verified.
+func (pm *PackageManager) checkPublisher(ctx context.Context, req InstallRequest, repo string) (context.Context, error) {
+	if pm.trust == nil {
+		return ctx, nil
+	}
+
+	name := publisherOf(repo)
+	publisher, err := pm.trust.Publisher(name)
+	if err != nil {
+		return ctx, err
+	}
+
+	switch {
+	case publisher == nil && req.AllowUnknownPublisher:
+		pm.log().Warn("installing from a publisher missing from the trust store", "publisher", name, "repo", repo)
+		return ctx, nil
+	case publisher == nil && pm.trustPrompt != nil:
+		ok, err := pm.trustPrompt(ctx, name, repo)
+		if err != nil {
+			return ctx, fmt.Errorf("failed to confirm publisher '%s': %w", name, err)
+		}
+		if !ok {
+			return ctx, fmt.Errorf("%w: installing %s from '%s' was not confirmed", ErrUntrustedPublisher, repo, name)
+		}
+		return ctx, nil
+	case publisher == nil:
+		return ctx, fmt.Errorf("%w: '%s', the publisher of %s, is not in the trust store; trust it or set AllowUnknownPublisher", ErrUntrustedPublisher, name, repo)
+	case publisher.Level == TrustBlocked:
+		return ctx, fmt.Errorf("%w: '%s', the publisher of %s, is blocked", ErrUntrustedPublisher, name, repo)
+	case publisher.Level == TrustSigned:
+		return context.WithValue(ctx, signingPublisherKey{}, publisher), nil
+	}
+	return ctx, nil
+}
+
+// requireUnsigned fails when the install running under ctx must verify
+// signatures, which what it installs can't have.
+func requireUnsigned(ctx context.Context, what string) error {
+	if publisher := signingPublisher(ctx); publisher != nil {
+		return fmt.Errorf("%w: '%s' requires signed release assets, and %s can't be signed", ErrSignatureVerification, publisher.Name, what)
+	}
+	return nil
+}
+
+// verifySignature checks the binary at path, the release asset assetName
+// of repo at version, against the signatures published next to it when
+// ctx requires it: <asset>.sig, an Ed25519 signature of the binary, raw or
+// base64, and <asset>.sigstore.json, a Sigstore bundle.
+func (pm *PackageManager) verifySignature(ctx context.Context, repo, version, assetName, path string) error {
+	publisher := signingPublisher(ctx)
+	if publisher == nil {
+		return nil
+	}
+
+	release, err := pm.getReleaseByTag(ctx, repo, version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve release '%s': %w", version, err)
+	}
+	fetch := func(name string) ([]byte, bool, error) {
+		asset, err := pm.findAsset(release, name)
+		if err != nil {
+			return nil, false, nil
+		}
+		var buf bytes.Buffer
+		if err := pm.fetchAsset(ctx, repo, asset, &buf); err != nil {
+			return nil, false, fmt.Errorf("failed to download %s: %w", name, err)
+		}
+		return buf.Bytes(), true, nil
+	}
+
+	if len(publisher.Keys) > 0 {
+		signature, ok, err := fetch(assetName + ".sig")
+		if err != nil {
+			return err
+		}
+		if ok {
+			binary, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read binary: %w", err)
+			}
+			if verifyKeySignature(publisher.Keys, binary, signature) {
+				return nil
+			}
+			return fmt.Errorf("%w: %s.sig of %s %s matches no key of '%s'", ErrSignatureVerification, assetName, repo, version, publisher.Name)
+		}
+	}
+
+	if len(publisher.Identities) > 0 && pm.sigstore != nil {
+		bundle, ok, err := fetch(assetName + ".sigstore.json")
+		if err != nil {
+			return err
+		}
+		if ok {
+			identity, err := pm.sigstore.Verify(ctx, path, bundle)
+			if err != nil {
+				return fmt.Errorf("%w: %s.sigstore.json of %s %s: %v", ErrSignatureVerification, assetName, repo, version, err)
+			}
+			if slices.Contains(publisher.Identities, identity) {
+				return nil
+			}
+			return fmt.Errorf("%w: %s %s was signed by %s (%s), not an identity of '%s'", ErrSignatureVerification, repo, version, identity.Subject, identity.Issuer, publisher.Name)
+		}
+	}
+
+	return fmt.Errorf("%w: release %s of %s publishes no signature of '%s' that the keys or identities of '%s' can verify", ErrSignatureVerification, version, repo, assetName, publisher.Name)
+}
+
+// verifyKeySignature reports whether signature, raw or base64, signs
+// message with one of keys.
+func verifyKeySignature(keys []string, message, signature []byte) bool {
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+		if err != nil {
+			return false
+		}
+		signature = decoded
+	}
+	for _, key := range keys {
+		pub, err := base64.StdEncoding.DecodeString(key)
+		if err == nil && len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, message, signature) {
+			return true
+		}
+	}
+	return false
+}
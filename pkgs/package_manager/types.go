@@ -10,26 +10,96 @@
 package packagemanager
 
 import (
+	"log/slog"
+	"sync"
 	"time"
 )
 
 // BlockMetadata represents metadata about an installed block
 type BlockMetadata struct {
 	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
 	Version     string           `json:"version"`
 	SourceRepo  string           `json:"source_repo"`
 	BinaryPath  string           `json:"binary_path"`
+	SHA256      string           `json:"sha256,omitempty"` // Digest of the installed binary, and of the release asset it came from
 	InstalledAt time.Time        `json:"installed_at"`
 	LastUpdated time.Time        `json:"last_updated"`
 	IsActive    bool             `json:"is_active"`
 	LSPEntries  map[string]Entry `json:"lsp_entries,omitempty"`
+	// RedirectedFrom holds the repository coordinates originally requested
+	// when GitHub redirected them to SourceRepo (renamed or transferred repo).
+	RedirectedFrom string `json:"redirected_from,omitempty"`
+	// InferredAsset names the release asset picked by its os/arch name
+	// because the manifest listed no binary for this platform.
+	InferredAsset string `json:"inferred_asset,omitempty"`
+	// Asset names the release asset the binary was downloaded from, whose
+	// digest is compared with SHA256 to detect re-tagged releases.
+	Asset string `json:"asset,omitempty"`
+	// PostInstall holds the manifest's hooks.post_install commands, run
+	// whenever this version is installed.
+	PostInstall []string `json:"post_install,omitempty"`
+	// BuiltFromSource is set when the binary was built with the manifest's
+	// build command instead of downloaded.
+	BuiltFromSource bool `json:"built_from_source,omitempty"`
+	// Deprecated holds the block's deprecation notice when it has one.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Yanked is set when this version was yanked and installed anyway.
+	Yanked bool `json:"yanked,omitempty"`
+	// AliasOf holds the manifest name of a block installed under an alias,
+	// which is then its Name.
+	AliasOf string `json:"alias_of,omitempty"`
+	// HealthCheck holds the manifest's healthcheck, run whenever this
+	// version is installed.
+	HealthCheck *HealthCheck `json:"healthcheck,omitempty"`
+	// Requires holds the manifest's runtime requirements, checked again
+	// when the version is installed from a vendor directory.
+	Requires []string `json:"requires,omitempty"`
+	// Sandbox holds the manifest's sandbox profile, which workflows confine
+	// the block to; nil when the manifest declares none.
+	Sandbox *Sandbox `json:"sandbox,omitempty"`
+	// Size is the number of bytes the version's bin directory used once
+	// installed.
+	Size int64 `json:"size,omitempty"`
+	// Commit is the full SHA of the commit installed with a "sha:" version.
+	Commit string `json:"commit,omitempty"`
+	// DataFiles maps the names of the manifest's data assets to their
+	// paths under <block>/data/.
+	DataFiles map[string]string `json:"data_files,omitempty"`
+	// LastUsed is when a workflow last ran this version (see MarkUsed).
+	LastUsed time.Time `json:"last_used,omitzero"`
+	// Pinned freezes the block at its active version (see Pin).
+	Pinned bool `json:"pinned,omitempty"`
+	// SchemaVersion is the metadata schema the file was written with; older
+	// files are migrated on load (see MetadataSchemaVersion).
+	SchemaVersion int `json:"schema_version"`
 }
 
 // InstallRequest represents a request to install a block
 type InstallRequest struct {
 	Repo    string `json:"repo"`
-	Version string `json:"version"`
-	Force   bool   `json:"force"` // Force reinstall even if already installed
+	Version string `json:"version"` // Exact tag, semver range (e.g. "^1.8.0"), channel (stable, beta, nightly), or empty for latest
+	Force   bool   `json:"force"`   // Force reinstall even if already installed, or install a yanked version
+	// ProbeAssets installs the release asset whose name matches the current
+	// platform when the manifest lists no binary for it. Without it, such a
+	// match is only suggested in the error.
+	ProbeAssets bool `json:"probe_assets,omitempty"`
+	// BuildFromSource clones the repository at the version and runs the
+	// manifest's build command when no release asset fits the platform.
+	BuildFromSource bool `json:"build_from_source,omitempty"`
+	// Alias installs the block under another name, so blocks whose
+	// manifests share a name (e.g. forks) can be installed side by side.
+	Alias string `json:"alias,omitempty"`
+	// IgnoreRequirements installs the block even when commands it declares
+	// in requires are missing from the host, logging a warning instead.
+	IgnoreRequirements bool `json:"ignore_requirements,omitempty"`
+	// AllowUnknownPublisher installs the block when a trust store is set
+	// and doesn't know its publisher, without asking the trust prompt.
+	// Blocked publishers are still refused.
+	AllowUnknownPublisher bool `json:"allow_unknown_publisher,omitempty"`
+	// AllowPrerelease lets the latest release and version ranges resolve
+	// to prereleases; drafts are never installed.
+	AllowPrerelease bool `json:"allow_prerelease,omitempty"`
 }
 
 // UpdateRequest represents a request to update a block
@@ -40,9 +110,48 @@ type UpdateRequest struct {
 
 // PackageManager handles block installation, updates, and management
 type PackageManager struct {
-	InstallDir string
+	InstallDir  string
+	preexisting bool // InstallDir existed when the package manager was created
 	// Loaded state from existing installation
 	loadedBlocks map[string]*BlockMetadata // Cached map of installed blocks by name
+
+	locker Locker // Optional lock guarding the install dir across hosts
+	fence  uint64 // Fencing token of the currently held lock
+
+	scanner      ScanProvider  // Optional malware scanner run before activation
+	network      NetworkConfig // Proxy and mirror settings
+	http         HTTPConfig    // Client, timeout, and retries of outgoing requests
+	githubConfig GitHubConfig  // GitHub Enterprise API and raw content URLs
+
+	vendorDir string     // Offline mode: install only from this vendor directory
+	readOnly  bool       // Opened with OpenReadOnly: never write to InstallDir
+	registry  string     // Source of the block registry index
+	policy    RepoPolicy // Repositories allowed to be installed
+	quota     int64      // Bytes the install dir may use; 0 for no limit
+
+	store MetadataStore // Persistence of block metadata; per-file JSON when nil
+
+	trust       *TrustStore      // Publishers allowed to be installed; nil trusts all
+	trustPrompt TrustPrompt      // Confirms installs from publishers the trust store doesn't know
+	sigstore    SigstoreVerifier // Verifies Sigstore bundles of TrustSigned publishers
+
+	assetWorkers int // Assets of a block downloaded at once; DefaultAssetWorkers when <= 0
+
+	progress ProgressReporter // Optional receiver of install progress
+	logger   *slog.Logger     // Warnings, notices, and debug output; slog.Default() when nil
+
+	listenersMu sync.RWMutex
+	listeners   []EventListener // Receivers of lifecycle events
+
+	credMu      sync.Mutex
+	credentials CredentialProvider // Tokens for GitHub and GitLab; DefaultCredentials() when nil
+
+	opMu     sync.Mutex // Serializes mutating operations of this process
+	commitMu sync.Mutex // Guards loadedBlocks and serializes commits of concurrent installs
+	repairs  repairLog  // Recoveries performed on corrupted metadata
+
+	githubOnce   sync.Once
+	githubClient *githubClient // Rate-limit aware, caching GitHub API client
 }
 
 // BlockInfo represents the information from agentic_support.yaml
@@ -57,9 +166,52 @@ type BlockInfo struct {
 	Binary struct {
 		From   string            `yaml:"from"`
 		Assets map[string]string `yaml:"assets"`
+		// Image is the container image of blocks built from docker, with
+		// {version} replaced by the installed version.
+		Image string `yaml:"image"`
+		// ChecksumsAsset names a release asset in sha256sum format
+		// ("<hex digest>  <asset name>" per line).
+		ChecksumsAsset string `yaml:"checksums_asset"`
+		// Patch names the release asset holding a zstd patch from an older
+		// version, made with `zstd --patch-from=<old> <new>`; {asset} and
+		// {from} are replaced by the binary's asset name and the old version.
+		Patch string `yaml:"patch"`
 	} `yaml:"binary"`
-	Entries    []Entry `yaml:"entries"`
-	BinaryPath string  // Path to the downloaded binary
+	Hooks struct {
+		// PostInstall commands run after the binary is installed, e.g. to
+		// download models or create configuration directories.
+		PostInstall []string `yaml:"post_install"`
+	} `yaml:"hooks"`
+	// Build describes how to build the binary from a checkout of the
+	// repository, for platforms without a release asset.
+	Build struct {
+		Command string `yaml:"command"` // Run through the platform shell from the repository root
+		Output  string `yaml:"output"`  // Path of the produced binary, relative to the repository root
+	} `yaml:"build"`
+	// HealthCheck confirms the installed binary runs on this machine.
+	HealthCheck *HealthCheck `yaml:"healthcheck"`
+	// Requires lists host commands the block runs, optionally with a
+	// version range, e.g. ["python3>=3.10", "graphviz"] (see Requirement).
+	Requires   []string `yaml:"requires"`
+	Entries    []Entry  `yaml:"entries"`
+	BinaryPath string   // Path to the downloaded binary
+
+	// Checksums maps asset names (or platform keys) to SHA256 digests.
+	Checksums map[string]string `yaml:"checksums"`
+
+	// Deprecated tells users why the block shouldn't be used anymore and,
+	// usually, what replaces it.
+	Deprecated string `yaml:"deprecated"`
+	// YankedVersions lists withdrawn versions, which are never picked for
+	// a latest or constraint install and are refused unless forced.
+	YankedVersions []string `yaml:"yanked_versions"`
+	// Data lists files downloaded next to the binary (see DataAsset).
+	Data []DataAsset `yaml:"data"`
+	// Sandbox declares the network access, writable paths, and environment
+	// variables the block needs at runtime.
+	Sandbox *Sandbox `yaml:"sandbox"`
+
+	aliasOf string // Manifest name of a block installed under an alias
 }
 
 // Entry represents a CLI entry from the block
@@ -68,6 +220,9 @@ type Entry struct {
 	Description string   `yaml:"description"`
 	Inputs      []Input  `yaml:"inputs"`
 	Outputs     []Output `yaml:"outputs"`
+	// Tags declare the entry's side effects, e.g. "network" or
+	// "writes_files", so workflows using it can be reviewed before running.
+	Tags []string `yaml:"tags"`
 }
 
 // Input represents an input parameter for an entry
@@ -90,6 +245,8 @@ type GitHubRelease struct {
 	Assets      []ReleaseAsset `json:"assets"`
 	CreatedAt   string         `json:"created_at"`
 	PublishedAt string         `json:"published_at"`
+	Prerelease  bool           `json:"prerelease"`
+	Draft       bool           `json:"draft"`
 }
 
 // ReleaseAsset represents an asset in a GitHub release
@@ -102,6 +259,7 @@ type ReleaseAsset struct {
 	DownloadCount int    `json:"download_count"`
 	CreatedAt     string `json:"created_at"`
 	UpdatedAt     string `json:"updated_at"`
+	Digest        string `json:"digest"` // "sha256:<hex>", empty for assets uploaded before GitHub computed digests
 }
 
 // InstallResult represents the result of an installation
@@ -111,15 +269,18 @@ type InstallResult struct {
 	BinaryPath string `json:"binary_path,omitempty"`
 	Blockname  string `json:"block_name,omitempty"`
 	Version    string `json:"version,omitempty"`
+	Deprecated string `json:"deprecated,omitempty"` // Deprecation notice of the block
 }
 
 // UpdateResult represents the result of an update
 type UpdateResult struct {
+	Blockname  string `json:"block_name,omitempty"`
 	Success    bool   `json:"success"`
 	Message    string `json:"message"`
 	OldVersion string `json:"old_version,omitempty"`
 	NewVersion string `json:"new_version,omitempty"`
 	BinaryPath string `json:"binary_path,omitempty"`
+	Pinned     bool   `json:"pinned,omitempty"` // The block is pinned and was skipped
 }
 
 // listResult represents the result of listing installed blocks
@@ -130,9 +291,30 @@ type listResult struct {
 
 // InstallationStats represents statistics about the package manager installation
 type InstallationStats struct {
-	InstallDir      string          `json:"install_dir"`
-	IsExisting      bool            `json:"is_existing"`
-	TotalBlocks     int             `json:"total_blocks"`
-	TotalBinarySize int64           `json:"total_binary_size"`
-	InstalledBlocks []BlockMetadata `json:"installed_blocks,omitempty"`
+	InstallDir      string                `json:"install_dir"`
+	IsExisting      bool                  `json:"is_existing"` // The install dir existed before the package manager was created
+	TotalBlocks     int                   `json:"total_blocks"`
+	TotalVersions   int                   `json:"total_versions"`
+	TotalBinarySize int64                 `json:"total_binary_size"` // Bytes used by the binaries of every installed version
+	TotalDiskSize   int64                 `json:"total_disk_size"`   // Bytes used by the whole install dir, caches included
+	Quota           int64                 `json:"quota,omitempty"`   // Limit set with SetQuota, 0 for none
+	Blocks          map[string]BlockStats `json:"blocks"`
+	LargestBlocks   []BlockUsage          `json:"largest_blocks,omitempty"`   // Blocks using the most disk, largest first
+	InstalledBlocks []BlockMetadata       `json:"installed_blocks,omitempty"` // Metadata of the active versions
+}
+
+// BlockStats represents the disk usage of one installed block
+type BlockStats struct {
+	ActiveVersion string `json:"active_version"`
+	Versions      int    `json:"versions"`
+	BinarySize    int64  `json:"binary_size"` // Bytes used by the binaries of every installed version
+	// LastUsed is when a workflow last ran any version, zero if never.
+	LastUsed time.Time `json:"last_used,omitzero"`
+}
+
+// BlockUsage is the disk used by a block directory: the binaries, metadata,
+// data, and logs of every installed version.
+type BlockUsage struct {
+	Name     string `json:"name"`
+	DiskSize int64  `json:"disk_size"`
 }
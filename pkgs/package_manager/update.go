@@ -0,0 +1,216 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// UpdateCheck reports whether a newer release of an installed block exists.
+type UpdateCheck struct {
+	Blockname       string `json:"block_name"`
+	CurrentVersion  string `json:"current_version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+	Pinned          bool   `json:"pinned,omitempty"` // Update won't apply it until the block is unpinned
+}
+
+// CheckForUpdates compares the active version of a block against the latest
+// release of its source without installing anything.
+func (pm *PackageManager) CheckForUpdates(ctx context.Context, Blockname string) (*UpdateCheck, error) {
+	metadata, err := pm.getMetadata(Blockname)
+	if err != nil {
+		return nil, errNotInstalled(Blockname, "", err)
+	}
+
+	latest, err := pm.resolveSourceVersion(ctx, metadata.SourceRepo, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve latest version: %w", err)
+	}
+
+	return &UpdateCheck{
+		Blockname:       Blockname,
+		CurrentVersion:  metadata.Version,
+		LatestVersion:   latest,
+		UpdateAvailable: isNewerVersion(latest, metadata.Version),
+		Pinned:          metadata.Pinned,
+	}, nil
+}
+
+// Update installs the latest release of a block, or req.Version when set,
+// next to the active version and activates it. The previous version stays
+// installed so Rollback can restore it. A block already on the target
+// version, or pinned (see Pin), is left untouched.
+func (pm *PackageManager) Update(ctx context.Context, req UpdateRequest) (*UpdateResult, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	current, err := pm.getMetadata(req.Blockname)
+	if err != nil {
+		return nil, errNotInstalled(req.Blockname, "", err)
+	}
+
+	if current.Pinned {
+		return &UpdateResult{
+			Blockname:  req.Blockname,
+			Message:    fmt.Sprintf("block '%s' is pinned at %s; unpin it to update", req.Blockname, current.Version),
+			OldVersion: current.Version,
+			NewVersion: current.Version,
+			BinaryPath: current.BinaryPath,
+			Pinned:     true,
+		}, nil
+	}
+
+	target, err := pm.resolveSourceVersion(ctx, current.SourceRepo, req.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target version: %w", err)
+	}
+
+	// Without an explicit version, only move forward.
+	upToDate := sameVersion(target, current.Version) || (req.Version == "" && !isNewerVersion(target, current.Version))
+	if upToDate {
+		return &UpdateResult{
+			Blockname:  req.Blockname,
+			Success:    true,
+			Message:    fmt.Sprintf("block '%s' is already at %s", req.Blockname, current.Version),
+			OldVersion: current.Version,
+			NewVersion: current.Version,
+			BinaryPath: current.BinaryPath,
+		}, nil
+	}
+
+	updated, err := pm.install(withHistoryOperation(ctx, OperationUpdate, req.Blockname, current.Version), InstallRequest{Repo: current.SourceRepo, Version: target, Force: true, Alias: current.Alias(), BuildFromSource: current.BuiltFromSource})
+	if err != nil {
+		return nil, fmt.Errorf("failed to install %s: %w", target, err)
+	}
+
+	return &UpdateResult{
+		Blockname:  req.Blockname,
+		Success:    true,
+		Message:    fmt.Sprintf("updated block '%s' from %s to %s", req.Blockname, current.Version, updated.Version),
+		OldVersion: current.Version,
+		NewVersion: updated.Version,
+		BinaryPath: updated.BinaryPath,
+	}, nil
+}
+
+// UpdateAll updates every installed block to its latest release, in name
+// order, and returns one result per block. Pinned blocks are skipped with
+// Pinned set on their result. A block that fails to update doesn't stop the
+// others; the failures are returned joined.
+func (pm *PackageManager) UpdateAll(ctx context.Context) ([]*UpdateResult, error) {
+	pm.commitMu.Lock()
+	names := slices.Sorted(maps.Keys(pm.loadedBlocks))
+	pm.commitMu.Unlock()
+
+	var results []*UpdateResult
+	var errs []error
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		result, err := pm.Update(ctx, UpdateRequest{Blockname: name})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update '%s': %w", name, err))
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// resolveSourceVersion resolves version, which may be empty for the latest
+// release or a semver range, to a concrete tag available from repo.
+func (pm *PackageManager) resolveSourceVersion(ctx context.Context, repo, version string) (string, error) {
+	if pm.vendorDir != "" {
+		candidates, err := pm.vendoredVersions(repo)
+		if err != nil {
+			return "", err
+		}
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("%w: %s", ErrNotVendored, repo)
+		}
+		selected, err := selectVendoredVersion(candidates, version)
+		if err != nil {
+			return "", err
+		}
+		return selected.Version, nil
+	}
+
+	if dir, ok := parseLocalRepo(repo); ok {
+		if isExactVersion(version) {
+			return version, nil
+		}
+		blockInfo, err := readLocalBlockInfo(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to read block info: %w", err)
+		}
+		if blockInfo.Version == "" {
+			return localVersion, nil
+		}
+		return blockInfo.Version, nil
+	}
+
+	if src, ok := parseGitLabRepo(repo); ok {
+		releases, err := pm.listGitLabReleases(ctx, src)
+		if err != nil {
+			return "", fmt.Errorf("failed to list releases: %w", err)
+		}
+		blockInfo, err := pm.fetchGitLabBlockInfo(ctx, src)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch block info: %w", err)
+		}
+		pm.applyRegistryStatus(ctx, blockInfo, repo)
+		release, err := selectGitLabRelease(releases, version, blockInfo.YankedVersions)
+		if err != nil {
+			return "", err
+		}
+		return release.TagName, nil
+	}
+
+	source := pm.canonicalRepo(ctx, repo)
+	repo, _ = splitRepoPath(source)
+	if isExactVersion(version) {
+		return version, nil
+	}
+	blockInfo, err := pm.fetchBlockInfo(ctx, source)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch block info: %w", err)
+	}
+	pm.applyRegistryStatus(ctx, blockInfo, repo)
+	if pm.buildsFromTags(ctx, repo, blockInfo) {
+		return pm.resolveTagVersion(ctx, repo, version, blockInfo.YankedVersions)
+	}
+	return pm.resolveReleaseVersion(ctx, repo, version, blockInfo.YankedVersions, false)
+}
+
+// isNewerVersion reports whether candidate is newer than current. Versions
+// that aren't semver are considered newer whenever they differ.
+func isNewerVersion(candidate, current string) bool {
+	c, errCandidate := parseSemver(candidate)
+	v, errCurrent := parseSemver(current)
+	if errCandidate != nil || errCurrent != nil {
+		return !sameVersion(candidate, current)
+	}
+	return c.compare(v) > 0
+}
+
+// sameVersion compares tags with or without a leading 'v'.
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
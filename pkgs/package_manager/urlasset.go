@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// isURLAsset reports whether a binary asset in agentic_support.yaml is a URL
+// rather than the name of a release asset.
+func isURLAsset(asset string) bool {
+	u, err := url.Parse(asset)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// urlAssetName validates an asset URL and returns the file name the binary
+// is stored under in <block>/bin.
+func urlAssetName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset URL '%s': %w", rawURL, err)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("asset URL '%s' must use https", rawURL)
+	}
+
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", fmt.Errorf("asset URL '%s' does not name a file", rawURL)
+	}
+
+	return name, nil
+}
+
+// downloadURLAsset downloads a binary from a plain HTTPS URL to localPath,
+// checking it with verify first, and returns its SHA256 digest.
+func (pm *PackageManager) downloadURLAsset(ctx context.Context, rawURL, localPath string, verify verifyDownload) (string, error) {
+	newRequest := func(ctx context.Context) (*http.Request, error) {
+		return newURLRequest(ctx, rawURL)
+	}
+	return pm.downloadResumable(ctx, rawURL, newRequest, localPath, verify)
+}
+
+// newURLRequest builds an unauthenticated GET for an HTTPS asset URL. No
+// GitHub or GitLab credentials are ever sent to these hosts.
+func newURLRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	if _, err := urlAssetName(rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return req, nil
+}
+
+// fetchURL streams the body of an HTTPS asset URL into w.
+func (pm *PackageManager) fetchURL(ctx context.Context, rawURL string, w io.Writer) error {
+	req, err := newURLRequest(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := pm.doDownload(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("download of %s failed: HTTP %d: %s", rawURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	return nil
+}
+
+// withURLAssets lets a checksums asset be given as an HTTPS URL, falling back
+// to fetch for release asset names.
+func (pm *PackageManager) withURLAssets(ctx context.Context, fetch fetchNamedAsset) fetchNamedAsset {
+	return func(name string) ([]byte, error) {
+		if !isURLAsset(name) {
+			return fetch(name)
+		}
+
+		var buf bytes.Buffer
+		if err := pm.fetchURL(ctx, name, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// lastUsedResolution is how stale a recorded last use may get before
+// MarkUsed writes the metadata again, so blocks run in a loop don't
+// rewrite it on every execution.
+const lastUsedResolution = time.Minute
+
+// MarkUsed records that the active version of a block just ran. The
+// workflow engine calls it on every execution, so Stats and Prune can tell
+// blocks in use from stale ones. It does nothing on a read-only manager.
+func (pm *PackageManager) MarkUsed(Blockname string) error {
+	if pm.readOnly {
+		return nil
+	}
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	metadata, err := pm.getMetadata(Blockname)
+	if err != nil {
+		return errNotInstalled(Blockname, "", err)
+	}
+	now := time.Now()
+	if now.Sub(metadata.LastUsed) < lastUsedResolution {
+		return nil
+	}
+
+	metadata.LastUsed = now
+	if err := pm.storeMetadata(metadata); err != nil {
+		return fmt.Errorf("failed to mark '%s' as used: %w", Blockname, err)
+	}
+
+	if loaded, ok := pm.loadedBlocks[Blockname]; ok && loaded.Version == metadata.Version {
+		updated := *loaded
+		updated.LastUsed = now
+		pm.loadedBlocks[Blockname] = &updated
+	}
+	return nil
+}
+
+// Prune uninstalls every version of the blocks that neither ran nor were
+// installed within unusedFor, and returns their names, sorted.
+func (pm *PackageManager) Prune(ctx context.Context, unusedFor time.Duration) ([]string, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	listed, err := pm.list()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-unusedFor)
+	var pruned []string
+	for _, block := range listed.Blocks {
+		lastSeen, err := pm.lastSeen(block.Name)
+		if err != nil {
+			return pruned, err
+		}
+		if lastSeen.After(cutoff) {
+			continue
+		}
+
+		for pm.isBlockInstalled(block.Name) {
+			if err := pm.uninstall(block.Name); err != nil {
+				return pruned, fmt.Errorf("failed to prune %s: %w", block.Name, err)
+			}
+		}
+		pm.log().Debug("pruned unused block", "block", block.Name, "last_seen", lastSeen)
+		pruned = append(pruned, block.Name)
+	}
+
+	slices.Sort(pruned)
+	return pruned, nil
+}
+
+// lastSeen returns when any version of block last ran or was installed.
+func (pm *PackageManager) lastSeen(block string) (time.Time, error) {
+	versions, err := pm.metadataStore().Versions(block)
+	if errors.Is(err, ErrNewerMetadataSchema) {
+		// Written by a newer AtomOS, which may be using it.
+		return time.Now(), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, metadata := range versions {
+		for _, t := range []time.Time{metadata.LastUsed, metadata.InstalledAt} {
+			if t.After(last) {
+				last = t
+			}
+		}
+	}
+	return last, nil
+}
@@ -10,23 +10,18 @@
 package packagemanager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
-	"time"
 )
 
 // getReleaseByTag fetches a specific GitHub release by tag and is tolerant
 // to tags with or without a leading 'v'. Supports both public and private repos.
-func (pm *PackageManager) getReleaseByTag(repo, tag string) (*GitHubRelease, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	client := &http.Client{Timeout: 30 * time.Second}
-
+func (pm *PackageManager) getReleaseByTag(ctx context.Context, repo, tag string) (*GitHubRelease, error) {
 	withV := tag
 	if !strings.HasPrefix(tag, "v") {
 		withV = "v" + tag
@@ -34,29 +29,14 @@ func (pm *PackageManager) getReleaseByTag(repo, tag string) (*GitHubRelease, err
 	withoutV := strings.TrimPrefix(tag, "v")
 
 	for _, candidate := range []string{withV, withoutV} {
-		url := fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", repo, candidate)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("create request for tag '%s': %w", candidate, err)
-		}
-
-		if token != "" {
-			req.Header.Set("Authorization", "Bearer "+token)
-		}
-		req.Header.Set("Accept", "application/vnd.github+json")
+		url := pm.githubAPI("/repos/%s/releases/tags/%s", repo, candidate)
 
-		resp, err := client.Do(req)
+		status, body, err := pm.github().get(ctx, url)
 		if err != nil {
 			return nil, fmt.Errorf("fetch release by tag '%s': %w", candidate, err)
 		}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("read response for tag '%s': %w", candidate, err)
-		}
-
-		switch resp.StatusCode {
+		switch status {
 		case http.StatusOK:
 			var release GitHubRelease
 			if err := json.Unmarshal(body, &release); err != nil {
@@ -68,31 +48,60 @@ func (pm *PackageManager) getReleaseByTag(repo, tag string) (*GitHubRelease, err
 			continue
 
 		case http.StatusUnauthorized, http.StatusForbidden:
-			return nil, fmt.Errorf("authentication failed for %s - check GITHUB_TOKEN", repo)
+			return nil, pm.authError(ctx, repo, status, body)
 
 		default:
 			return nil, fmt.Errorf("GitHub API error %d for tag '%s': %s",
-				resp.StatusCode, candidate, strings.TrimSpace(string(body)))
+				status, candidate, strings.TrimSpace(string(body)))
 		}
 	}
 
 	return nil, fmt.Errorf("release not found for tag '%s' in %s (tried with/without 'v')", tag, repo)
 }
 
+// WasmAssetKey is the binary.assets key of a WebAssembly module, used on
+// every platform without an asset of its own. The workflow engine runs
+// binaries ending in .wasm through a WASI runtime.
+const WasmAssetKey = "wasm"
+
+// hostPlatform returns the binary.assets key of the current platform.
+func hostPlatform() string {
+	return fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+}
+
 // getBinaryNameForPlatform returns the binary name for the current platform
 func (pm *PackageManager) getBinaryNameForPlatform(blockInfo *BlockInfo) (string, error) {
-	osName := runtime.GOOS
-	arch := runtime.GOARCH
-	platformKey := fmt.Sprintf("%s-%s", osName, arch)
+	return assetForPlatform(blockInfo, hostPlatform())
+}
 
+// assetForPlatform returns the binary name for platformKey.
+func assetForPlatform(blockInfo *BlockInfo, platformKey string) (string, error) {
 	binaryName, exists := blockInfo.Binary.Assets[platformKey]
 	if !exists {
-		return "", fmt.Errorf("no binary found for platform %s", platformKey)
+		// A WebAssembly module runs on every platform.
+		if binaryName, exists = blockInfo.Binary.Assets[WasmAssetKey]; !exists {
+			return "", fmt.Errorf("%w %s", ErrAssetMissingForPlatform, platformKey)
+		}
 	}
 
 	return binaryName, nil
 }
 
+// releaseAssetNames lists the asset names of a GitHub release.
+func (pm *PackageManager) releaseAssetNames(ctx context.Context, repo, version string) func() ([]string, error) {
+	return func() ([]string, error) {
+		release, err := pm.getReleaseByTag(ctx, repo, version)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(release.Assets))
+		for _, asset := range release.Assets {
+			names = append(names, asset.Name)
+		}
+		return names, nil
+	}
+}
+
 // findAsset finds the asset by name and returns the asset object
 func (pm *PackageManager) findAsset(release *GitHubRelease, assetName string) (*ReleaseAsset, error) {
 	for _, asset := range release.Assets {
@@ -103,24 +112,13 @@ func (pm *PackageManager) findAsset(release *GitHubRelease, assetName string) (*
 	return nil, fmt.Errorf("asset '%s' not found in release %s", assetName, release.TagName)
 }
 
-// storeMetadata stores block metadata to disk
+// storeMetadata stores block metadata in the metadata store
 func (pm *PackageManager) storeMetadata(metadata *BlockMetadata) error {
-	// Store per-version at <block>/metadata/<version>.json
-	metadataDir := filepath.Join(pm.InstallDir, metadata.Name, "metadata")
-	if err := os.MkdirAll(metadataDir, 0755); err != nil {
-		return fmt.Errorf("failed to create metadata directory: %w", err)
-	}
-
-	metadataPath := filepath.Join(metadataDir, fmt.Sprintf("%s.json", metadata.Version))
-	file, err := os.Create(metadataPath)
-	if err != nil {
-		return fmt.Errorf("failed to create metadata file: %w", err)
-	}
-	defer file.Close()
-
-	if err := json.NewEncoder(file).Encode(metadata); err != nil {
-		return fmt.Errorf("failed to encode metadata: %w", err)
+	metadata.SchemaVersion = MetadataSchemaVersion
+	if err := pm.metadataStore().Put(metadata); err != nil {
+		return err
 	}
+	pm.log().Debug("wrote block metadata", "block", metadata.Name, "version", metadata.Version)
 
 	return nil
 }
@@ -141,8 +139,9 @@ func userHomeDir() string {
 	return os.TempDir()
 }
 
-// checkBinariesExistAndLoad verifies that binaries referenced by installed blocks exist,
-// and loads their metadata into memory if they do.
+// checkBinariesExistAndLoad loads the metadata of installed blocks into
+// memory, warning about blocks whose binary is missing so they can be fixed
+// with Repair.
 func (pm *PackageManager) checkBinariesExistAndLoad() error {
 	listResult, err := pm.list()
 	if err != nil {
@@ -151,16 +150,13 @@ func (pm *PackageManager) checkBinariesExistAndLoad() error {
 
 	for _, block := range listResult.Blocks {
 		if _, err := os.Stat(block.BinaryPath); os.IsNotExist(err) {
-			return fmt.Errorf("block '%s' metadata exists but binary is missing: %s", block.Name, block.BinaryPath)
-		}
-
-		for _, block := range listResult.Blocks {
-			pm.loadedBlocks[block.Name] = &block
+			pm.log().Warn("block metadata exists but binary is missing; run Repair to download it again", "block", block.Name, "binary", block.BinaryPath)
 		}
+		pm.loadedBlocks[block.Name] = &block
 	}
 
 	if len(listResult.Blocks) > 0 {
-		fmt.Printf("Loaded existing AtomOS installation with %d blocks\n", len(listResult.Blocks))
+		pm.log().Info("loaded existing AtomOS installation", "dir", pm.InstallDir, "blocks", len(listResult.Blocks))
 	}
 
 	return nil
@@ -168,55 +164,51 @@ func (pm *PackageManager) checkBinariesExistAndLoad() error {
 
 // isExistingInstallation checks if this package manager is working with an existing installation
 func (pm *PackageManager) isExistingInstallation() bool {
-	if pm.loadedBlocks != nil {
-		return len(pm.loadedBlocks) > 0
+	pm.commitMu.Lock()
+	loaded := len(pm.loadedBlocks)
+	pm.commitMu.Unlock()
+	if loaded > 0 {
+		return true
 	}
 
-	// Check if any block directory contains metadata files
-	files, err := os.ReadDir(pm.InstallDir)
+	// If any block has stored metadata, it's an existing installation
+	blocks, err := pm.metadataStore().Blocks()
+	return err == nil && len(blocks) > 0
+}
+
+// List returns the metadata of the active version of every installed block,
+// including the sandbox profile each one declares, so users can review what
+// blocks may do before running them. Blocks whose metadata can't be read are
+// left out.
+func (pm *PackageManager) List() ([]BlockMetadata, error) {
+	result, err := pm.list()
 	if err != nil {
-		return false
-	}
-
-	// If any block directory contains a versioned metadata file, it's an existing installation
-	for _, file := range files {
-		if file.IsDir() {
-			blockDir := filepath.Join(pm.InstallDir, file.Name())
-			metadataDir := filepath.Join(blockDir, "metadata")
-			entries, _ := os.ReadDir(metadataDir)
-			for _, e := range entries {
-				if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
-					return true
-				}
-			}
-		}
+		return nil, err
 	}
-
-	return false
+	return result.Blocks, nil
 }
 
 // list returns all installed blocks
 func (pm *PackageManager) list() (*listResult, error) {
 	// TODO: We likely don't want to do this on every call, make it a separate set up step instead.
-	if err := os.MkdirAll(pm.InstallDir, 0755); err != nil {
-		return nil, err
+	if !pm.readOnly {
+		if err := os.MkdirAll(pm.InstallDir, 0755); err != nil {
+			return nil, err
+		}
 	}
 
-	files, err := os.ReadDir(pm.InstallDir)
+	names, err := pm.metadataStore().Blocks()
 	if err != nil {
 		return nil, err
 	}
 
 	var blocks []BlockMetadata
-	for _, file := range files {
-		if file.IsDir() {
-			Blockname := file.Name()
-			metadata, err := pm.getMetadata(Blockname)
-			if err != nil {
-				continue
-			}
-			blocks = append(blocks, *metadata)
+	for _, Blockname := range names {
+		metadata, err := pm.getMetadata(Blockname)
+		if err != nil {
+			continue
 		}
+		blocks = append(blocks, *metadata)
 	}
 
 	return &listResult{
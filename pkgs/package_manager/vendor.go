@@ -0,0 +1,277 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// vendorMetadataFile holds the metadata of a vendored block version, next to
+// its binary in <vendor>/<block>/<version>/.
+const vendorMetadataFile = "metadata.json"
+
+// ErrNotVendored is returned by offline installs of blocks that are missing
+// from the vendor directory.
+var ErrNotVendored = errors.New("block is not vendored")
+
+// Vendor exports the installed blocks, binaries and metadata, into dir so it
+// can be copied to a machine without network access and installed from with
+// SetOfflineMode. Each version lives in <dir>/<block>/<version>/; exporting
+// into an existing vendor directory adds to it.
+func (pm *PackageManager) Vendor(dir string) error {
+	pm.commitMu.Lock()
+	blocks := slices.Collect(maps.Values(pm.loadedBlocks))
+	pm.commitMu.Unlock()
+
+	for _, metadata := range blocks {
+		if err := vendorBlock(dir, filepath.Join(pm.InstallDir, metadata.Name, blockDataDir), metadata); err != nil {
+			return fmt.Errorf("failed to vendor block '%s': %w", metadata.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// vendorBlock copies one installed block, with its data files from
+// dataDir, into the vendor directory. The vendored metadata records the
+// binary and data paths relative to its version dir.
+func vendorBlock(dir, dataDir string, metadata *BlockMetadata) error {
+	versionDir := filepath.Join(dir, metadata.Name, metadata.Version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return fmt.Errorf("failed to create vendor directory: %w", err)
+	}
+
+	binaryName := filepath.Base(metadata.BinaryPath)
+	if err := copyFile(metadata.BinaryPath, filepath.Join(versionDir, binaryName), 0755); err != nil {
+		return err
+	}
+
+	vendored := *metadata
+	vendored.BinaryPath = binaryName
+	vendored.SchemaVersion = MetadataSchemaVersion
+	vendored.DataFiles = nil
+	for name, path := range metadata.DataFiles {
+		rel, err := filepath.Rel(dataDir, path)
+		if err != nil || !filepath.IsLocal(rel) {
+			return fmt.Errorf("data file '%s' is outside the data directory", name)
+		}
+		vendoredPath := filepath.Join(versionDir, blockDataDir, rel)
+		if err := os.MkdirAll(filepath.Dir(vendoredPath), 0755); err != nil {
+			return fmt.Errorf("failed to create vendor directory: %w", err)
+		}
+		if err := copyFile(path, vendoredPath, 0644); err != nil {
+			return err
+		}
+		if vendored.DataFiles == nil {
+			vendored.DataFiles = map[string]string{}
+		}
+		vendored.DataFiles[name] = filepath.ToSlash(filepath.Join(blockDataDir, rel))
+	}
+
+	data, err := json.MarshalIndent(&vendored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return os.WriteFile(filepath.Join(versionDir, vendorMetadataFile), data, 0644)
+}
+
+// SetOfflineMode makes Install resolve every request from the vendor
+// directory written by Vendor, without any network access. Requests match a
+// vendored block by source repository (current or redirected) or by name.
+// Passing "" turns offline mode off.
+func (pm *PackageManager) SetOfflineMode(vendorDir string) {
+	pm.vendorDir = vendorDir
+}
+
+// installFromVendor installs the vendored version of a block best matching
+// req: the exact version, the highest one satisfying a constraint, or the
+// highest one when no version is given.
+func (pm *PackageManager) installFromVendor(ctx context.Context, req InstallRequest) (*BlockMetadata, error) {
+	candidates, err := pm.vendoredVersions(req.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s not found in %s", ErrNotVendored, req.Repo, pm.vendorDir)
+	}
+
+	if !req.Force {
+		if metadata, ok, err := pm.cachedBlock(candidates[0].Name); err != nil || ok {
+			return metadata, err
+		}
+	}
+
+	vendored, err := selectVendoredVersion(candidates, req.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %v", ErrNotVendored, req.Repo, req.Version, err)
+	}
+	if err := pm.checkRequirements(ctx, req, vendored.Name, vendored.Requires); err != nil {
+		return nil, err
+	}
+
+	binDir := pm.versionBinDir(vendored.Name, vendored.Version)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	binaryPath := filepath.Join(binDir, filepath.Base(vendored.BinaryPath))
+	if err := copyFile(filepath.Join(pm.vendorDir, vendored.Name, vendored.Version, vendored.BinaryPath), binaryPath, 0755); err != nil {
+		return nil, err
+	}
+
+	digest, err := hashFile(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash binary: %w", err)
+	}
+	if vendored.SHA256 != "" && !strings.EqualFold(vendored.SHA256, digest) {
+		_ = os.Remove(binaryPath)
+		return nil, fmt.Errorf("%w for vendored %s %s: expected %s, got %s", ErrChecksumMismatch, vendored.Name, vendored.Version, vendored.SHA256, digest)
+	}
+
+	dataFiles := make(map[string]string, len(vendored.DataFiles))
+	for name, rel := range vendored.DataFiles {
+		dataPath, ok := strings.CutPrefix(rel, blockDataDir+"/")
+		if !ok || !filepath.IsLocal(filepath.FromSlash(dataPath)) {
+			return nil, fmt.Errorf("vendored data file '%s' of %s is outside its data directory", name, vendored.Name)
+		}
+		dst := filepath.Join(pm.InstallDir, vendored.Name, blockDataDir, filepath.FromSlash(dataPath))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		if err := copyFile(filepath.Join(pm.vendorDir, vendored.Name, vendored.Version, filepath.FromSlash(rel)), dst, 0644); err != nil {
+			return nil, err
+		}
+		dataFiles[name] = dst
+	}
+
+	metadata := vendored
+	metadata.BinaryPath = binaryPath
+	if len(dataFiles) > 0 {
+		metadata.DataFiles = dataFiles
+	}
+	metadata.SHA256 = digest
+	metadata.InstalledAt = time.Now()
+	metadata.LastUpdated = time.Now()
+	metadata.IsActive = true
+
+	return pm.commitInstall(ctx, &metadata)
+}
+
+// vendoredVersions returns the vendored versions of the block repo refers
+// to, all belonging to the same block.
+func (pm *PackageManager) vendoredVersions(repo string) ([]BlockMetadata, error) {
+	var matches []BlockMetadata
+
+	paths, err := filepath.Glob(filepath.Join(pm.vendorDir, "*", "*", vendorMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vendored metadata: %w", err)
+		}
+
+		decoded, _, err := decodeMetadata(data)
+		if err != nil {
+			pm.log().Warn("skipping unreadable vendored metadata", "path", path, "error", err)
+			continue
+		}
+		metadata := *decoded
+
+		if strings.EqualFold(metadata.SourceRepo, repo) || strings.EqualFold(metadata.RedirectedFrom, repo) || metadata.Name == repo {
+			if len(matches) > 0 && matches[0].Name != metadata.Name {
+				return nil, fmt.Errorf("%s matches several vendored blocks: %s and %s", repo, matches[0].Name, metadata.Name)
+			}
+			matches = append(matches, metadata)
+		}
+	}
+
+	return matches, nil
+}
+
+// selectVendoredVersion picks the vendored version satisfying version.
+func selectVendoredVersion(candidates []BlockMetadata, version string) (BlockMetadata, error) {
+	byVersion := map[string]BlockMetadata{}
+	for _, c := range candidates {
+		byVersion[c.Version] = c
+	}
+
+	if IsChannel(version) {
+		return selectVendoredChannel(candidates, version)
+	}
+	if version != "" && !IsVersionConstraint(version) {
+		for _, tag := range []string{version, "v" + strings.TrimPrefix(version, "v"), strings.TrimPrefix(version, "v")} {
+			if c, ok := byVersion[tag]; ok {
+				return c, nil
+			}
+		}
+		return BlockMetadata{}, fmt.Errorf("available versions: %s", strings.Join(slices.Sorted(maps.Keys(byVersion)), ", "))
+	}
+
+	if version == "" {
+		version = "*"
+	}
+	vc, err := ParseVersionConstraint(version)
+	if err != nil {
+		return BlockMetadata{}, err
+	}
+
+	if tag := highestMatchingTag(slices.Collect(maps.Keys(byVersion)), vc.Matches); tag != "" {
+		return byVersion[tag], nil
+	}
+
+	// Blocks vendored with non-semver versions, e.g. local installs.
+	if version == "*" {
+		return slices.MaxFunc(candidates, func(a, b BlockMetadata) int { return a.InstalledAt.Compare(b.InstalledAt) }), nil
+	}
+
+	return BlockMetadata{}, fmt.Errorf("no vendored version satisfies %s", version)
+}
+
+// copyFile copies src to dst, creating or truncating dst with perm.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		return os.Chmod(dst, perm)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// VerifyIssue is a problem Verify found with an installed binary.
+type VerifyIssue string
+
+const (
+	IssueMissingBinary    VerifyIssue = "missing_binary"    // The binary file doesn't exist
+	IssueNotExecutable    VerifyIssue = "not_executable"    // The binary lacks the executable bit
+	IssueChecksumMismatch VerifyIssue = "checksum_mismatch" // The binary's SHA-256 differs from the metadata
+)
+
+// VerifyResult reports the state of the active version of an installed block.
+type VerifyResult struct {
+	Blockname  string
+	Version    string
+	BinaryPath string
+	Issues     []VerifyIssue
+	// ExpectedSHA256 is the digest recorded at install time, empty for
+	// installs that predate checksums. ActualSHA256 is empty when the
+	// binary couldn't be read.
+	ExpectedSHA256 string
+	ActualSHA256   string
+}
+
+// OK reports whether the block passed verification.
+func (r *VerifyResult) OK() bool { return len(r.Issues) == 0 }
+
+// Verify checks that the binary of the active version of a block exists, is
+// executable, and still matches the SHA-256 recorded in its metadata.
+func (pm *PackageManager) Verify(Blockname string) (*VerifyResult, error) {
+	metadata, err := pm.getMetadata(Blockname)
+	if err != nil {
+		return nil, errNotInstalled(Blockname, "", err)
+	}
+	return verifyBinary(metadata)
+}
+
+func verifyBinary(metadata *BlockMetadata) (*VerifyResult, error) {
+	result := &VerifyResult{
+		Blockname:      metadata.Name,
+		Version:        metadata.Version,
+		BinaryPath:     metadata.BinaryPath,
+		ExpectedSHA256: metadata.SHA256,
+	}
+
+	info, err := os.Stat(metadata.BinaryPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		result.Issues = append(result.Issues, IssueMissingBinary)
+		return result, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to stat binary: %w", err)
+	}
+
+	// Image blocks record a reference file, which isn't executed.
+	if _, isImage, _ := ImageRef(metadata.BinaryPath); !isImage && !isExecutable(metadata.BinaryPath, info.Mode()) {
+		result.Issues = append(result.Issues, IssueNotExecutable)
+	}
+
+	result.ActualSHA256, err = hashFile(metadata.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.SHA256 != "" && result.ActualSHA256 != metadata.SHA256 {
+		result.Issues = append(result.Issues, IssueChecksumMismatch)
+	}
+
+	return result, nil
+}
+
+// Repair verifies the active version of a block and, when verification
+// fails, downloads it again from the recorded SourceRepo and Version. A
+// block that passes verification is left untouched.
+func (pm *PackageManager) Repair(ctx context.Context, Blockname string) (*BlockMetadata, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	metadata, err := pm.getMetadata(Blockname)
+	if err != nil {
+		return nil, errNotInstalled(Blockname, "", err)
+	}
+
+	result, err := verifyBinary(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if result.OK() {
+		return metadata, nil
+	}
+	if metadata.SourceRepo == "" {
+		return nil, fmt.Errorf("block '%s' can't be repaired: its metadata records no source repository", Blockname)
+	}
+
+	pm.log().Warn("repairing block", "block", Blockname, "version", metadata.Version, "issues", result.Issues)
+	repaired, err := pm.install(ctx, InstallRequest{
+		Repo:            metadata.SourceRepo,
+		Version:         metadata.Version,
+		Force:           true,
+		ProbeAssets:     metadata.InferredAsset != "",
+		BuildFromSource: metadata.BuiltFromSource,
+		Alias:           metadata.Alias(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to repair block '%s': %w", Blockname, err)
+	}
+
+	if metadata.SHA256 != "" && repaired.SHA256 != metadata.SHA256 {
+		pm.log().Warn("repaired binary differs from the one originally installed", "block", Blockname, "version", metadata.Version, "expected", metadata.SHA256, "actual", repaired.SHA256)
+	}
+
+	return repaired, nil
+}
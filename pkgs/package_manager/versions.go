@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// activeVersionFile records the active version of a block, next to its
+// metadata and bin directories.
+const activeVersionFile = "active"
+
+// versionBinDir is where the binary of one version of a block lives, so
+// several versions can be installed side by side.
+func (pm *PackageManager) versionBinDir(block, version string) string {
+	return filepath.Join(pm.InstallDir, block, "bin", version)
+}
+
+// metadataPath is the metadata file of one version of a block.
+func (pm *PackageManager) metadataPath(block, version string) string {
+	return filepath.Join(pm.InstallDir, block, "metadata", fmt.Sprintf("%s.json", version))
+}
+
+// activeVersion returns the recorded active version of block, or "" for
+// installations that predate side-by-side versions.
+func (pm *PackageManager) activeVersion(block string) string {
+	data, err := os.ReadFile(filepath.Join(pm.InstallDir, block, activeVersionFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// setActiveVersion atomically records version as the active one of block.
+func (pm *PackageManager) setActiveVersion(block, version string) error {
+	dir := filepath.Join(pm.InstallDir, block)
+	file, err := os.CreateTemp(dir, ".active-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create active version file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(version + "\n"); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write active version: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write active version: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set active version permissions: %w", err)
+	}
+
+	return os.Rename(file.Name(), filepath.Join(dir, activeVersionFile))
+}
+
+// Use makes an installed version of a block the active one, returned by
+// GetLoadedBlock and used by workflows, without downloading anything.
+func (pm *PackageManager) Use(ctx context.Context, Blockname, version string) (*BlockMetadata, error) {
+	release, err := pm.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	metadata, err := pm.installedVersion(Blockname, version)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.commitMu.Lock()
+	defer pm.commitMu.Unlock()
+
+	if err := pm.checkFence(); err != nil {
+		return nil, err
+	}
+
+	if err := pm.activateLocked(metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// activateLocked stores metadata as the active version of its block and
+// flags the previously active version as inactive. The caller holds commitMu.
+func (pm *PackageManager) activateLocked(metadata *BlockMetadata) error {
+	if prev, ok := pm.loadedBlocks[metadata.Name]; ok && prev.Version != metadata.Version {
+		if _, err := pm.metadataStore().Get(prev.Name, prev.Version); err == nil {
+			inactive := *prev
+			inactive.IsActive = false
+			if err := pm.storeMetadata(&inactive); err != nil {
+				return fmt.Errorf("failed to deactivate version '%s': %w", prev.Version, err)
+			}
+		}
+	}
+
+	metadata.IsActive = true
+	if err := pm.storeMetadata(metadata); err != nil {
+		return fmt.Errorf("failed to store metadata: %w", err)
+	}
+	if err := pm.setActiveVersion(metadata.Name, metadata.Version); err != nil {
+		return err
+	}
+	if err := pm.recordActivation(metadata.Name, metadata.Version); err != nil {
+		return err
+	}
+
+	// Shims are a convenience: a block that can't get one still works.
+	if err := pm.linkShim(metadata); err != nil {
+		pm.log().Warn("failed to link shim", "block", metadata.Name, "dir", pm.BinDir(), "error", err)
+	}
+
+	pm.loadedBlocks[metadata.Name] = metadata
+	return nil
+}
+
+// GetMetadata returns the metadata of one installed version of a block, or
+// of its active version when version is empty. Versions match with or
+// without a leading 'v'.
+func (pm *PackageManager) GetMetadata(Blockname, version string) (*BlockMetadata, error) {
+	if version == "" {
+		metadata, err := pm.getMetadata(Blockname)
+		if err != nil {
+			return nil, errNotInstalled(Blockname, "", err)
+		}
+		return metadata, nil
+	}
+
+	versions, err := pm.ListVersions(Blockname)
+	if err != nil {
+		return nil, err
+	}
+	for _, metadata := range versions {
+		if sameVersion(metadata.Version, version) {
+			return metadata, nil
+		}
+	}
+	return nil, errNotInstalled(Blockname, version, nil)
+}
+
+// ListVersions returns the metadata of every installed version of a block,
+// oldest first by semver, with IsActive set on the active one. Versions that
+// aren't semver sort before the others, by name. Unreadable metadata files
+// are skipped.
+func (pm *PackageManager) ListVersions(Blockname string) ([]*BlockMetadata, error) {
+	if !pm.isBlockInstalled(Blockname) {
+		return nil, errNotInstalled(Blockname, "", nil)
+	}
+	versions, err := pm.metadataStore().Versions(Blockname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versions of block '%s': %w", Blockname, err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("block '%s' has no readable metadata", Blockname)
+	}
+
+	if active := pm.activeVersion(Blockname); active != "" {
+		for _, metadata := range versions {
+			metadata.IsActive = metadata.Version == active
+		}
+	}
+
+	slices.SortFunc(versions, func(a, b *BlockMetadata) int {
+		return compareVersions(a.Version, b.Version)
+	})
+	return versions, nil
+}
+
+// compareVersions orders semver versions by precedence, after the others,
+// which are ordered by name.
+func compareVersions(a, b string) int {
+	va, errA := parseSemver(a)
+	vb, errB := parseSemver(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Or(va.compare(vb), strings.Compare(a, b))
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
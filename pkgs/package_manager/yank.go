@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrYankedVersion is returned when installing a version its maintainers
+// yanked, unless the request is forced.
+var ErrYankedVersion = errors.New("version is yanked")
+
+// isYanked reports whether version is listed in yanked, with or without a
+// leading 'v'.
+func isYanked(yanked []string, version string) bool {
+	return slices.ContainsFunc(yanked, func(y string) bool {
+		return strings.TrimPrefix(y, "v") == strings.TrimPrefix(version, "v")
+	})
+}
+
+// withoutYanked returns the tags that aren't yanked.
+func withoutYanked(tags, yanked []string) []string {
+	return slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return isYanked(yanked, tag) })
+}
+
+// applyRegistryStatus merges the deprecation and yanked versions declared
+// for the block by the configured registry, if any, into blockInfo. The
+// registry is advisory here: when it can't be read, the manifest alone is
+// used.
+func (pm *PackageManager) applyRegistryStatus(ctx context.Context, blockInfo *BlockInfo, repo string) {
+	if pm.registry == "" && os.Getenv("ATOMOS_REGISTRY") == "" {
+		return
+	}
+
+	index, err := pm.registryIndex(ctx)
+	if err != nil {
+		pm.log().Debug("registry unavailable, skipping deprecation checks", "block", blockInfo.Name, "error", err)
+		return
+	}
+
+	for _, entry := range index.Blocks {
+		if !strings.EqualFold(entry.Name, blockInfo.Name) && !strings.EqualFold(entry.Repo, repo) {
+			continue
+		}
+		if blockInfo.Deprecated == "" {
+			blockInfo.Deprecated = entry.Deprecated
+		}
+		blockInfo.YankedVersions = append(blockInfo.YankedVersions, entry.YankedVersions...)
+	}
+}
+
+// checkYanked refuses to install a yanked version unless req is forced,
+// in which case the metadata records it.
+func (pm *PackageManager) checkYanked(req InstallRequest, blockInfo *BlockInfo, version string) error {
+	if !isYanked(blockInfo.YankedVersions, version) {
+		return nil
+	}
+	if !req.Force {
+		return fmt.Errorf("%w: %s %s was withdrawn by its maintainers; pick another version or force the install", ErrYankedVersion, blockInfo.Name, version)
+	}
+
+	pm.log().Warn("installing a yanked version", "block", blockInfo.Name, "version", version)
+	return nil
+}
+
+// markStatus records the block's deprecation, whether the installed version
+// is yanked, the manifest name of aliased blocks, the health check, and the
+// sandbox profile in metadata.
+func (pm *PackageManager) markStatus(metadata *BlockMetadata, blockInfo *BlockInfo) {
+	metadata.AliasOf = blockInfo.aliasOf
+	metadata.HealthCheck = blockInfo.HealthCheck
+	metadata.Requires = blockInfo.Requires
+	metadata.Sandbox = blockInfo.Sandbox
+	metadata.Deprecated = blockInfo.Deprecated
+	metadata.Yanked = isYanked(blockInfo.YankedVersions, metadata.Version)
+	if metadata.Deprecated != "" {
+		pm.log().Warn("block is deprecated", "block", metadata.Name, "message", metadata.Deprecated)
+	}
+}
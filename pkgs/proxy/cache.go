@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// entry describes a cached response. Its body is stored next to it.
+type entry struct {
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type,omitempty"`
+	ETag        string    `json:"etag,omitempty"`
+	Fetched     time.Time `json:"fetched"`
+
+	key string
+	// status and body hold an upstream error, which is passed through
+	// without being cached.
+	status int
+	body   []byte
+}
+
+// cache stores responses as <dir>/<key[:2]>/<key> with the entry in
+// <key>.json.
+type cache struct {
+	dir string
+}
+
+func openCache(dir string) (*cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create proxy cache directory: %w", err)
+	}
+	return &cache{dir: dir}, nil
+}
+
+func (c *cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// get returns the entry of key, if its body is present.
+func (c *cache) get(key string) (*entry, bool) {
+	data, err := os.ReadFile(c.path(key) + ".json")
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	if _, err := os.Stat(c.path(key)); err != nil {
+		return nil, false
+	}
+	e.key = key
+	return &e, true
+}
+
+// put streams body into the cache and records e once it's complete, so an
+// interrupted download never leaves a partial body behind.
+func (c *cache) put(key string, e *entry, body io.Reader) (*entry, error) {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", e.URL, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	e.key = key
+	return e, c.writeEntry(e)
+}
+
+// touch marks e as just revalidated.
+func (c *cache) touch(e *entry) *entry {
+	e.Fetched = time.Now()
+	_ = c.writeEntry(e)
+	return e
+}
+
+func (c *cache) writeEntry(e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := c.path(e.key) + ".json"
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (c *cache) open(e *entry) (*os.File, error) {
+	return os.Open(c.path(e.key))
+}
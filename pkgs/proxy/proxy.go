@@ -0,0 +1,330 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+// Package proxy serves a caching HTTP proxy in front of GitHub for package
+// managers, giving a team or CI fleet a single egress point. Release assets
+// are immutable and kept once downloaded; API responses and manifests are
+// kept for a while and revalidated with their ETag.
+package proxy
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultUpstream is the GitHub API proxied by default.
+	DefaultUpstream = "https://api.github.com"
+	// DefaultRawUpstream serves repository files by default.
+	DefaultRawUpstream = "https://raw.githubusercontent.com"
+	// DefaultMetadataTTL is how long API responses and manifests are
+	// served from the cache before being revalidated.
+	DefaultMetadataTTL = 5 * time.Minute
+
+	// RawPrefix is the path under which repository files are served, e.g.
+	// /raw/<owner>/<repo>/<ref>/agentic_support.yaml.
+	RawPrefix = "/raw/"
+)
+
+var (
+	// assetPath matches release asset downloads, whose content never
+	// changes for a given asset ID.
+	assetPath = regexp.MustCompile(`^/repos/[^/]+/[^/]+/releases/assets/\d+$`)
+	// commitRef matches raw file paths pinned to a full commit SHA.
+	commitRef = regexp.MustCompile(`^/[^/]+/[^/]+/[0-9a-fA-F]{40}/`)
+)
+
+// Config configures a Server.
+type Config struct {
+	CacheDir    string        // Directory holding cached responses (required)
+	Upstream    string        // GitHub API base, DefaultUpstream when empty
+	RawUpstream string        // Raw file base, DefaultRawUpstream when empty
+	Token       string        // Token sent upstream; clients' own tokens are forwarded when empty
+	ClientToken string        // When set, clients must send it as a bearer token
+	MetadataTTL time.Duration // Freshness of API responses and manifests, DefaultMetadataTTL when zero
+	Client      *http.Client  // Client used for upstream requests, http.DefaultClient when nil
+	Logger      *slog.Logger  // Destination of warnings, slog.Default() when nil
+}
+
+// Stats counts how requests were served since the server started.
+type Stats struct {
+	Hits        int64 // Served from the cache without asking upstream
+	Revalidated int64 // Served from the cache after upstream answered 304
+	Misses      int64 // Fetched from upstream
+	Stale       int64 // Served from an expired cache entry because upstream failed
+}
+
+// Server is an http.Handler proxying GitHub API and raw file requests.
+// Point a PackageManager at it with NetworkConfig.MirrorURL for API calls
+// and asset downloads, and GitHubConfig.RawURL set to <server>/raw/ for
+// manifests.
+type Server struct {
+	cfg      Config
+	upstream *url.URL
+	raw      *url.URL
+	cache    *cache
+
+	locksMu sync.Mutex
+	locks   map[string]*keyLock
+
+	hits, revalidated, misses, stale atomic.Int64
+}
+
+// New creates a Server caching into cfg.CacheDir.
+func New(cfg Config) (*Server, error) {
+	if cfg.CacheDir == "" {
+		return nil, errors.New("proxy cache directory is required")
+	}
+	if cfg.Upstream == "" {
+		cfg.Upstream = DefaultUpstream
+	}
+	if cfg.RawUpstream == "" {
+		cfg.RawUpstream = DefaultRawUpstream
+	}
+	if cfg.MetadataTTL <= 0 {
+		cfg.MetadataTTL = DefaultMetadataTTL
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	upstream, err := parseBase(cfg.Upstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream: %w", err)
+	}
+	raw, err := parseBase(cfg.RawUpstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw upstream: %w", err)
+	}
+	cache, err := openCache(cfg.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{cfg: cfg, upstream: upstream, raw: raw, cache: cache, locks: make(map[string]*keyLock)}, nil
+}
+
+// parseBase parses an absolute http(s) URL.
+func parseBase(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimRight(raw, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute http(s) URL", raw)
+	}
+	return u, nil
+}
+
+// Stats returns the request counters.
+func (s *Server) Stats() Stats {
+	return Stats{
+		Hits:        s.hits.Load(),
+		Revalidated: s.revalidated.Load(),
+		Misses:      s.misses.Load(),
+		Stale:       s.stale.Load(),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "only GET and HEAD are proxied", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auth := r.Header.Get("Authorization")
+	if s.cfg.ClientToken != "" {
+		got, _ := strings.CutPrefix(auth, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(s.cfg.ClientToken)) != 1 {
+			http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+			return
+		}
+		auth = ""
+	}
+	if s.cfg.Token != "" {
+		auth = "Bearer " + s.cfg.Token
+	}
+
+	target, immutable := s.route(r.URL)
+	accept := r.Header.Get("Accept")
+	// Responses fetched with different credentials aren't shared, so a
+	// client can't read what another client's token gave access to.
+	key := cacheKey(auth, accept, target)
+
+	unlock := s.lock(key)
+	entry, err := s.fetch(r, key, target, auth, accept, immutable)
+	unlock()
+	if err != nil {
+		s.cfg.Logger.Warn("proxy upstream request failed", "url", target, "error", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	s.serve(w, r, entry)
+}
+
+// route returns the upstream URL of a request and whether its response
+// never changes.
+func (s *Server) route(u *url.URL) (string, bool) {
+	if rest, ok := strings.CutPrefix(u.Path, RawPrefix); ok {
+		target := *s.raw
+		target.Path += "/" + rest
+		target.RawQuery = u.RawQuery
+		return target.String(), commitRef.MatchString("/" + rest)
+	}
+	target := *s.upstream
+	target.Path += u.Path
+	target.RawQuery = u.RawQuery
+	return target.String(), assetPath.MatchString(u.Path)
+}
+
+// keyLock serializes fetches of one cache key. refs counts the requests
+// holding or waiting for it.
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock acquires the lock of one cache key, so concurrent misses download an
+// asset once, and returns the function releasing it. The lock is dropped
+// once no request holds or waits for it, so the map doesn't grow with every
+// URL ever requested.
+func (s *Server) lock(key string) func() {
+	s.locksMu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		s.locks[key] = lock
+	}
+	lock.refs++
+	s.locksMu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.locksMu.Lock()
+		defer s.locksMu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(s.locks, key)
+		}
+	}
+}
+
+// fetch returns the cached entry of key, refreshing it from upstream when
+// it's missing or expired. An expired entry is still served when upstream
+// can't be reached or fails.
+func (s *Server) fetch(r *http.Request, key, target, auth, accept string, immutable bool) (*entry, error) {
+	cached, ok := s.cache.get(key)
+	if ok && (immutable || time.Since(cached.Fetched) < s.cfg.MetadataTTL) {
+		s.hits.Add(1)
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if ok && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		if ok {
+			return s.serveStale(target, cached, err), nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		s.revalidated.Add(1)
+		return s.cache.touch(cached), nil
+
+	case resp.StatusCode == http.StatusOK:
+		s.misses.Add(1)
+		entry := &entry{
+			URL:         target,
+			ContentType: resp.Header.Get("Content-Type"),
+			ETag:        resp.Header.Get("ETag"),
+			Fetched:     time.Now(),
+		}
+		return s.cache.put(key, entry, resp.Body)
+
+	case resp.StatusCode >= http.StatusInternalServerError && ok:
+		return s.serveStale(target, cached, errors.New(resp.Status)), nil
+
+	default:
+		// Errors aren't cached, so a 404 for a release that's about to be
+		// published doesn't stick.
+		s.misses.Add(1)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return &entry{status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), body: body}, nil
+	}
+}
+
+// serveStale records that an expired entry is served because of err.
+func (s *Server) serveStale(target string, cached *entry, err error) *entry {
+	s.stale.Add(1)
+	s.cfg.Logger.Warn("proxy upstream unavailable, serving stale cache", "url", target, "fetched", cached.Fetched, "error", err)
+	return cached
+}
+
+// serve writes entry to w. Cached bodies go through http.ServeContent, so
+// clients can resume downloads with Range requests and revalidate with
+// If-None-Match.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, e *entry) {
+	if e.ContentType != "" {
+		w.Header().Set("Content-Type", e.ContentType)
+	}
+	if e.status != 0 {
+		w.WriteHeader(e.status)
+		_, _ = w.Write(e.body)
+		return
+	}
+
+	f, err := s.cache.open(e)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	if e.ETag != "" {
+		w.Header().Set("ETag", e.ETag)
+	}
+	http.ServeContent(w, r, "", e.Fetched, f)
+}
+
+// cacheKey identifies a response by credentials, accepted media type, and
+// upstream URL.
+func cacheKey(auth, accept, target string) string {
+	sum := sha256.Sum256([]byte(auth + "\n" + accept + "\n" + target))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/proxy"
+)
+
+// newUpstream fakes GitHub with one release of acme/tool, counting the
+// asset downloads it serves.
+func newUpstream(t *testing.T, assetDownloads *atomic.Int64) *httptest.Server {
+	t.Helper()
+
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 7, Name: "tool"}}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/raw/acme/tool/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "name: tool\nbinary:\n  assets:\n    %s-%s: tool\n", runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"latest"`)
+		if r.Header.Get("If-None-Match") == `"latest"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/acme/tool/releases/assets/7", func(w http.ResponseWriter, r *http.Request) {
+		assetDownloads.Add(1)
+		fmt.Fprint(w, "#!/bin/sh\necho tool\n")
+	})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer upstream-token" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+func newProxy(t *testing.T, upstream *httptest.Server, ttl time.Duration) (*proxy.Server, *httptest.Server) {
+	t.Helper()
+
+	server, err := proxy.New(proxy.Config{
+		CacheDir:    t.TempDir(),
+		Upstream:    upstream.URL + "/api",
+		RawUpstream: upstream.URL + "/raw",
+		Token:       "upstream-token",
+		ClientToken: "team-token",
+		MetadataTTL: ttl,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	front := httptest.NewServer(server)
+	t.Cleanup(front.Close)
+	return server, front
+}
+
+func TestPackageManagersInstallThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	var assetDownloads atomic.Int64
+	server, front := newProxy(t, newUpstream(t, &assetDownloads), time.Hour)
+
+	for range 2 {
+		pkgm := packagemanager.NewPackageManagerWithTestDir(t.TempDir())
+		pkgm.SetCredentialProvider(packagemanager.CredentialFunc(func(ctx context.Context, req packagemanager.CredentialRequest) (string, error) {
+			return "team-token", nil
+		}))
+		if err := pkgm.SetNetworkConfig(packagemanager.NetworkConfig{MirrorURL: front.URL}); err != nil {
+			t.Fatalf("SetNetworkConfig failed: %v", err)
+		}
+		if err := pkgm.SetGitHubConfig(packagemanager.GitHubConfig{RawURL: front.URL + proxy.RawPrefix}); err != nil {
+			t.Fatalf("SetGitHubConfig failed: %v", err)
+		}
+
+		metadata, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: "acme/tool"})
+		if err != nil {
+			t.Fatalf("Install through the proxy failed: %v", err)
+		}
+		if data, _ := os.ReadFile(metadata.BinaryPath); string(data) != "#!/bin/sh\necho tool\n" {
+			t.Errorf("unexpected binary %q", data)
+		}
+	}
+
+	if got := assetDownloads.Load(); got != 1 {
+		t.Errorf("expected the asset to be downloaded upstream once, got %d", got)
+	}
+	if stats := server.Stats(); stats.Hits == 0 || stats.Misses == 0 {
+		t.Errorf("expected both cache hits and misses, got %+v", stats)
+	}
+}
+
+func TestProxyRequiresClientToken(t *testing.T) {
+	t.Parallel()
+
+	var assetDownloads atomic.Int64
+	_, front := newProxy(t, newUpstream(t, &assetDownloads), time.Hour)
+
+	resp, err := http.Get(front.URL + "/repos/acme/tool/releases/latest")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the client token, got %d", resp.StatusCode)
+	}
+}
+
+func TestProxyResumesAndRevalidates(t *testing.T) {
+	t.Parallel()
+
+	var assetDownloads atomic.Int64
+	upstream := newUpstream(t, &assetDownloads)
+	server, front := newProxy(t, upstream, time.Nanosecond)
+
+	get := func(path string, header http.Header) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, front.URL+path, nil)
+		req.Header = header
+		req.Header.Set("Authorization", "Bearer team-token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request of %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	if resp, body := get("/repos/acme/tool/releases/assets/7", http.Header{"Range": {"bytes=10-"}}); resp.StatusCode != http.StatusPartialContent || body != "echo tool\n" {
+		t.Errorf("expected the rest of the asset, got %d %q", resp.StatusCode, body)
+	}
+	if resp, _ := get("/repos/acme/tool/releases/assets/7", http.Header{}); resp.StatusCode != http.StatusOK || assetDownloads.Load() != 1 {
+		t.Errorf("expected the asset to be served from the cache, got %d after %d downloads", resp.StatusCode, assetDownloads.Load())
+	}
+
+	get("/repos/acme/tool/releases/latest", http.Header{})
+	get("/repos/acme/tool/releases/latest", http.Header{})
+	if stats := server.Stats(); stats.Revalidated != 1 {
+		t.Errorf("expected an expired release to be revalidated, got %+v", stats)
+	}
+
+	upstream.Close()
+	if resp, body := get("/repos/acme/tool/releases/latest", http.Header{}); resp.StatusCode != http.StatusOK || !strings.Contains(body, "v1.0.0") {
+		t.Errorf("expected the stale release while upstream is down, got %d %q", resp.StatusCode, body)
+	}
+	if stats := server.Stats(); stats.Stale != 1 {
+		t.Errorf("expected one stale response, got %+v", stats)
+	}
+}
+
+func TestProxyConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	var assetDownloads atomic.Int64
+	upstream := newUpstream(t, &assetDownloads)
+	_, front := newProxy(t, upstream, time.Hour)
+
+	for round := range 2 {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				req, _ := http.NewRequest(http.MethodGet, front.URL+"/repos/acme/tool/releases/assets/7", nil)
+				req.Header.Set("Authorization", "Bearer team-token")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Errorf("round %d: request failed: %v", round, err)
+					return
+				}
+				defer resp.Body.Close()
+				if body, _ := io.ReadAll(resp.Body); string(body) != "#!/bin/sh\necho tool\n" {
+					t.Errorf("round %d: unexpected body %q", round, body)
+				}
+			})
+		}
+		wg.Wait()
+	}
+
+	if n := assetDownloads.Load(); n != 1 {
+		t.Errorf("expected concurrent misses to download the asset once, got %d downloads", n)
+	}
+}
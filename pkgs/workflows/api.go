@@ -10,8 +10,12 @@
 package workflows
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
 	"github.com/dominikbraun/graph"
@@ -20,113 +24,341 @@ import (
 // NewWorkflowManager creates and returns a new WorkflowManager with a default PackageManager.
 func NewWorkflowManager(path string) *WorkflowManager {
 	return &WorkflowManager{
-		pkgmanager: packagemanager.NewPackageManagerWithTestDir(path),
-		metadata:   map[Blockname]*packagemanager.BlockMetadata{},
-		workflows:  map[Workflowname]graph.Graph[string, *Block]{},
-		results:    map[Outputkey]Outputres{},
+		pkgmanager:  packagemanager.NewPackageManagerWithTestDir(path),
+		metadata:    map[Blockname]*packagemanager.BlockMetadata{},
+		workflows:   map[Workflowname]graph.Graph[string, *Block]{},
+		definitions: map[Workflowname]*RawWorkflow{},
+		compiled:    map[Workflowname]map[Blockname]*packagemanager.BlockMetadata{},
+		recorded:    map[Workflowname]map[Outputkey]Outputres{},
 	}
 }
 
-func (wm *WorkflowManager) CompileWorkflow(workflowPath string) error {
-	rawWorkflow, err := parseWorkflow(workflowPath)
+// CompileWorkflow lints the workflow, installs its blocks, and builds its
+// graph. Problems are returned as a *DiagnosticsError listing every
+// diagnostic with its position; warnings alone are printed and don't fail.
+// Cancelling ctx, or its deadline passing, aborts the block downloads.
+func (wm *WorkflowManager) CompileWorkflow(ctx context.Context, workflowPath string) error {
+	rawWorkflow, l, err := lintWorkflow(workflowPath)
 	if err != nil {
 		return fmt.Errorf("parseWorkflow failed: %w", err)
 	}
+	if hasErrors(l.diags) {
+		return &DiagnosticsError{Diagnostics: l.diags}
+	}
 
+	installReqs := make([]packagemanager.InstallRequest, 0, len(rawWorkflow.Blocks))
 	for _, block := range rawWorkflow.Blocks {
-		installReq := packagemanager.InstallRequest{
+		installReqs = append(installReqs, packagemanager.InstallRequest{
 			Repo:    block.GitHub,
 			Version: block.Version,
 			Force:   block.Force,
-		}
+			Alias:   block.Alias,
+		})
+	}
 
-		blockMetadata, err := wm.pkgmanager.Install(installReq)
+	// Blocks are downloaded concurrently; failures are reported per block.
+	installs, err := wm.pkgmanager.InstallAll(ctx, installReqs, 0)
+	if installs == nil {
+		return fmt.Errorf("failed to install blocks: %w", err)
+	}
+
+	// Metadata is staged so a workflow that fails validation leaves the
+	// previously compiled version untouched.
+	staged := map[Blockname]*packagemanager.BlockMetadata{}
+	for i, block := range rawWorkflow.Blocks {
+		blockMetadata, err := installs[i].Metadata, installs[i].Err
+		if err == nil {
+			blockMetadata, err = wm.pinnedVersion(ctx, block, blockMetadata)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to install block '%s': %w", block.Name, err)
+			l.report(SeverityError, CodeInstallFailed, l.field(l.item("blocks", i), "github"), block.Name, "",
+				fmt.Sprintf("failed to install block '%s': %v", block.Name, err))
+			continue
 		}
 
-		wm.metadata[Blockname(block.Name)] = blockMetadata
+		if !strings.EqualFold(blockMetadata.SourceRepo, block.GitHub) {
+			fmt.Printf("Warning: block '%s' in workflow '%s' references %s, which now lives at %s\n",
+				block.Name, rawWorkflow.Name, block.GitHub, blockMetadata.SourceRepo)
+		}
+
+		staged[Blockname(block.Name)] = blockMetadata
+	}
+
+	l.checkEntries(rawWorkflow, staged)
+
+	if hasErrors(l.diags) {
+		return &DiagnosticsError{Diagnostics: l.diags}
+	}
+	for _, d := range l.diags {
+		fmt.Printf("Warning: %s\n", d)
+	}
+
+	if err := wm.checkConflicts(ctx, rawWorkflow, staged); err != nil {
+		return err
+	}
+
+	if err := wm.checkInstallQuota(); err != nil {
+		return err
 	}
 
 	g := buildGraph(rawWorkflow)
+
+	wm.compileMu.Lock()
+	maps.Copy(wm.metadata, staged)
 	wm.workflows[Workflowname(rawWorkflow.Name)] = g
+	wm.definitions[Workflowname(rawWorkflow.Name)] = rawWorkflow
+	wm.compiled[Workflowname(rawWorkflow.Name)] = staged
+	wm.compileMu.Unlock()
 
 	return nil
 }
 
-// BFS traversal with connection access
+// RunWorkFlow runs a compiled workflow, executing every block once all of
+// its upstream blocks are settled.
 func (wm *WorkflowManager) RunWorkFlow(wfn Workflowname) error {
+	_, err := wm.RunWorkFlowWithOptions(wfn, RunOptions{})
+	return err
+}
+
+// RunWorkFlowWithOptions runs a compiled workflow like RunWorkFlow, applying
+// the per-run options such as block overrides, and returns the status of every
+// block in the order they were scheduled. Blocks whose upstream failed under
+// continue_on_error or was skipped are marked skipped when all of their inputs
+// are missing; fan-in blocks with only some inputs missing still run and
+// receive AbsentInput for those.
+func (wm *WorkflowManager) RunWorkFlowWithOptions(wfn Workflowname, opts RunOptions) (*RunResult, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Snapshot the compiled workflow so a concurrent reload can't change it
+	// mid-run. Overrides may download blocks, so they are resolved after the
+	// lock is released.
+	wm.compileMu.RLock()
 	g, ok := wm.workflows[wfn]
+	compiled := wm.compiled[wfn]
+	wm.compileMu.RUnlock()
 	if !ok {
-		return errors.New("workflow doesn't exist")
+		return nil, errors.New("workflow doesn't exist")
+	}
+	runMetadata, err := wm.applyOverrides(ctx, compiled, opts.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("applying block overrides failed: %w", err)
+	}
+
+	stages, err := schedule(g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule workflow: %w", err)
+	}
+	if len(stages) == 0 {
+		return nil, errors.New("no root node found")
 	}
 
-	startNode := findRootNode(g)
-	if startNode == "" {
-		return errors.New("no root node found")
+	endRun, err := wm.beginRun(wfn)
+	if err != nil {
+		return nil, err
 	}
+	defer endRun()
 
-	visited := make(map[string]bool)
-	queue := []string{startNode}
-	level := 0
+	previous := wm.previousArtifacts(wfn, opts)
 
+	run, err := newRunEnv(wfn, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare run environment: %w", err)
+	}
+	defer run.close()
+	run.wasm = wm.wasmRuntime
+
+	result := newRunResult(wfn, run)
+	result.Stages = stages
+
+	defer func() {
+		wm.setRecorded(wfn, run.results)
+		artifacts, err := wm.saveArtifacts(wfn, run.id, run.results, run.artifacts)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else if err := wm.saveRunLog(run); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		result.Artifacts = artifacts
+		result.EgressViolations = run.egressViolations()
+		if err := wm.saveTelemetry(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}()
 	adjacencyMap, err := g.AdjacencyMap()
 	if err != nil {
-		return fmt.Errorf("error getting adjacency map: %v", err)
+		return result, fmt.Errorf("error getting adjacency map: %v", err)
 	}
 
-	for len(queue) > 0 {
-		levelSize := len(queue)
+	for _, name := range slices.Concat(stages...) {
+		block, err := g.Vertex(name)
+		if err != nil {
+			return result, fmt.Errorf("error getting block %s: %v", name, err)
+		}
 
-		for range levelSize {
-			currentNode := queue[0]
-			queue = queue[1:]
+		incomingConnections, incomingFromBlocks := getIncoming(adjacencyMap, name)
+		outgoingConnections, outgoingToBlocks := getOutGoing(adjacencyMap, name)
 
-			if visited[currentNode] {
-				continue
-			}
-			visited[currentNode] = true
+		if reason, skip := result.upstreamSkipReason(incomingFromBlocks); skip {
+			result.record(block.Name, BlockSkipped, reason, nil)
+			wm.logRun(run, block.Name, "block %s skipped: %s", block.Name, reason)
+		} else {
+			wm.logRun(run, block.Name, "block %s started", block.Name)
 
-			block, err := g.Vertex(currentNode)
-			if err != nil {
-				return fmt.Errorf("error getting block %s: %v", currentNode, err)
+			if err := wm.checkCPUQuota(wfn); err != nil {
+				result.record(block.Name, BlockFailed, err.Error(), err)
+				return result, err
+			}
+			if err := run.checkBudget(); err != nil {
+				return result, result.exceedBudget(block.Name, err)
 			}
 
-			incomingConnections, incomingFromBlocks := getIncoming(adjacencyMap, currentNode)
-			outgoingConnections, outgoingToBlocks := getOutGoing(adjacencyMap, currentNode)
+			wm.markAbsentInputs(result, run.results, incomingConnections, incomingFromBlocks)
 
-			blockMetadata := wm.metadata[Blockname(block.Name)]
-			excArgs := ExecuteArgs{block, blockMetadata, incomingConnections, incomingFromBlocks, outgoingConnections, outgoingToBlocks}
+			blockMetadata := runMetadata[Blockname(block.Name)]
+			run.startExecution(block.Name)
+			excArgs := ExecuteArgs{block, blockMetadata, incomingConnections, incomingFromBlocks, outgoingConnections, outgoingToBlocks, run.results, run}
 
 			err = wm.executeBlock(excArgs)
-			if err != nil {
-				return fmt.Errorf("error executing block %s: %v", block.Name, err)
+			if budgetErr := run.checkSpent(); budgetErr != nil {
+				// The budget ran out during the block, which may have been
+				// killed for it; either way the run stops here.
+				err := result.exceedBudget(block.Name, budgetErr)
+				result.Blocks[block.Name].Anomalies = run.takeAnomalies()
+				return result, err
 			}
-
-			for target := range adjacencyMap[currentNode] {
-				if !visited[target] {
-					queue = append(queue, target)
+			if err != nil {
+				br := result.record(block.Name, BlockFailed, err.Error(), err)
+				br.Anomalies = run.takeAnomalies()
+				br.Retries = run.retries[block.Name]
+				wm.logRun(run, block.Name, "block %s failed: %v", block.Name, err)
+				if !block.ContinueOnError {
+					return result, fmt.Errorf("error executing block %s: %w", block.Name, err)
 				}
+			} else {
+				br := result.record(block.Name, BlockSucceeded, "", nil)
+				br.Anomalies = run.takeAnomalies()
+				br.Retries = run.retries[block.Name]
+				wm.logRun(run, block.Name, "block %s succeeded", block.Name)
 			}
 		}
-		fmt.Println()
-		level++
 	}
 
-	return nil
+	if previous != nil {
+		result.OutputMismatches = compareOutputHashes(previous, run.results)
+		if len(result.OutputMismatches) > 0 {
+			return result, fmt.Errorf("deterministic run diverged from the previous run on %d output(s)", len(result.OutputMismatches))
+		}
+	}
+
+	return result, nil
+}
+
+// ReplayBlock re-executes a single block in isolation using the artifacts
+// recorded by the last run of the workflow. Upstream blocks are not rerun and
+// the recorded artifacts are left untouched; the returned map holds the
+// recorded inputs together with the outputs produced by the replay.
+func (wm *WorkflowManager) ReplayBlock(wfn Workflowname, name Blockname) (map[Outputkey]Outputres, error) {
+	wm.compileMu.RLock()
+	g, ok := wm.workflows[wfn]
+	blockMetadata := wm.compiled[wfn][name]
+	wm.compileMu.RUnlock()
+	if !ok {
+		return nil, errors.New("workflow doesn't exist")
+	}
+
+	recorded, ok := wm.lastRecorded(wfn)
+	if !ok {
+		persisted, err := wm.loadArtifacts(wfn)
+		if err != nil {
+			return nil, fmt.Errorf("workflow '%s' has no recorded run to replay: %w", wfn, err)
+		}
+		recorded = persisted
+	}
+
+	block, err := g.Vertex(string(name))
+	if err != nil {
+		return nil, fmt.Errorf("error getting block %s: %w", name, err)
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, fmt.Errorf("error getting adjacency map: %w", err)
+	}
+
+	incomingConnections, incomingFromBlocks := getIncoming(adjacencyMap, block.Name)
+	outgoingConnections, outgoingToBlocks := getOutGoing(adjacencyMap, block.Name)
+
+	run, err := newRunEnv(wfn, RunOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare run environment: %w", err)
+	}
+	defer run.close()
+	run.wasm = wm.wasmRuntime
+
+	sandbox := maps.Clone(recorded)
+	excArgs := ExecuteArgs{block, blockMetadata, incomingConnections, incomingFromBlocks, outgoingConnections, outgoingToBlocks, sandbox, run}
+
+	// Replays feed the telemetry baselines but don't belong to any run result,
+	// so the anomalies queued on the replay's run are dropped with it.
+	if err := wm.executeBlock(excArgs); err != nil {
+		return nil, fmt.Errorf("error replaying block %s: %w", name, err)
+	}
+
+	return sandbox, nil
+}
+
+// setRecorded keeps the outputs of the last run of wfn for replays.
+func (wm *WorkflowManager) setRecorded(wfn Workflowname, results map[Outputkey]Outputres) {
+	wm.stateMu.Lock()
+	defer wm.stateMu.Unlock()
+	wm.recorded[wfn] = results
+}
+
+// lastRecorded returns the outputs of the last run of wfn in this process.
+func (wm *WorkflowManager) lastRecorded(wfn Workflowname) (map[Outputkey]Outputres, bool) {
+	wm.stateMu.Lock()
+	defer wm.stateMu.Unlock()
+	recorded, ok := wm.recorded[wfn]
+	return recorded, ok
+}
+
+// markUsed records the execution of an installed block with the package
+// manager. Overridden blocks run another binary and aren't recorded.
+func (wm *WorkflowManager) markUsed(wfn Workflowname, name Blockname, md *packagemanager.BlockMetadata) {
+	wm.compileMu.RLock()
+	compiled := wm.compiled[wfn][name]
+	wm.compileMu.RUnlock()
+	if compiled != md {
+		return
+	}
+	if err := wm.pkgmanager.MarkUsed(md.Name); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 }
 
 // Execute block with access to all connections
 func (wm *WorkflowManager) executeBlock(excArgs ExecuteArgs) error {
 	shouldUseSource := len(excArgs.incon) <= 0
 	binary := excArgs.metadata.BinaryPath
+	wm.markUsed(excArgs.run.workflow, Blockname(excArgs.block.Name), excArgs.metadata)
+
+	if err := wm.startEgress(excArgs.run, excArgs.block.Name, egressPolicy(excArgs.block, excArgs.metadata)); err != nil {
+		return err
+	}
 
 	for _, edge := range excArgs.incon {
 		inputpath := edge.Properties.Attributes["input"]
 		outputpath := edge.Properties.Attributes["output"]
 		fromEntry := edge.Properties.Attributes["fromEntry"]
 
-		if err := wm.fromNode(binary, fromEntry, inputpath, outputpath); err != nil {
+		invs, err := newInvocations(excArgs, binary, fromEntry)
+		if err != nil {
+			return err
+		}
+		if err := wm.fromNode(excArgs.results, invs, inputpath, outputpath); err != nil {
 			return fmt.Errorf("fromNode failed: %w", err)
 		}
 	}
@@ -136,14 +368,18 @@ func (wm *WorkflowManager) executeBlock(excArgs ExecuteArgs) error {
 		outputpath := edge.Properties.Attributes["output"]
 		fromEntry := edge.Properties.Attributes["fromEntry"]
 		sourcePath := edge.Properties.Attributes["source"]
+		invs, err := newInvocations(excArgs, binary, fromEntry)
+		if err != nil {
+			return err
+		}
 
 		if shouldUseSource {
-			if err := wm.fromSource(binary, fromEntry, outputpath, sourcePath); err != nil {
+			if err := wm.fromSource(excArgs.results, invs, outputpath, sourcePath); err != nil {
 				return fmt.Errorf("fromSource failed: %w", err)
 			}
 		}
 
-		if err := wm.fromNode(binary, fromEntry, inputpath, outputpath); err != nil {
+		if err := wm.fromNode(excArgs.results, invs, inputpath, outputpath); err != nil {
 			return fmt.Errorf("fromNode failed: %w", err)
 		}
 	}
@@ -12,6 +12,7 @@ package workflows
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/dominikbraun/graph"
 	"gopkg.in/yaml.v3"
@@ -58,7 +59,7 @@ func buildGraph(rwf *RawWorkflow) graph.Graph[string, *Block] {
 			}
 
 			g.AddEdge(src.FromBlock, dst.FromBlock,
-				graph.EdgeAttribute("fromEntry", src.FromEntry),
+				graph.EdgeAttribute("fromEntry", strings.Join(connectionEntries(src), entrySeparator)),
 				graph.EdgeAttribute("output", src.Output),
 				graph.EdgeAttribute("input", dst.Input),
 				graph.EdgeAttribute("source", src.Source),
@@ -69,28 +70,6 @@ func buildGraph(rwf *RawWorkflow) graph.Graph[string, *Block] {
 	return g
 }
 
-func findRootNode(g graph.Graph[string, *Block]) string {
-	adjacencyMap, err := g.AdjacencyMap()
-	if err != nil {
-		return ""
-	}
-
-	hasIncomingEdge := make(map[string]bool)
-	for _, targets := range adjacencyMap {
-		for target := range targets {
-			hasIncomingEdge[target] = true
-		}
-	}
-
-	for nodeID := range adjacencyMap {
-		if !hasIncomingEdge[nodeID] {
-			return nodeID
-		}
-	}
-
-	return ""
-}
-
 func getIncoming(adjacencyMap map[string]map[string]graph.Edge[string], currentNode string) ([]graph.Edge[string], []string) {
 	// Get incoming connections
 	var incomingConnections []graph.Edge[string]
@@ -120,24 +99,103 @@ func getOutGoing(adjacencyMap map[string]map[string]graph.Edge[string], currentN
 
 // TODO: Both fromSource and fromNode are not completed, we're passing raw data
 // without any commands.
-func (wm *WorkflowManager) fromSource(binary, entry, outputpath, sourcePath string) error {
-	output, err := runBinaryWithPipe(binary, entry, sourcePath)
+func (wm *WorkflowManager) fromSource(results map[Outputkey]Outputres, invs []invocation, outputpath, sourcePath string) error {
+	var (
+		output string
+		err    error
+	)
+	// Sources referencing another workflow's artifact are resolved through
+	// the artifact store instead of the filesystem.
+	if ref, ok, refErr := ParseArtifactRef(sourcePath); refErr != nil {
+		return refErr
+	} else if ok {
+		data, _, loadErr := wm.LoadArtifact(ref)
+		if loadErr != nil {
+			return fmt.Errorf("resolving source %s failed: %w", sourcePath, loadErr)
+		}
+		output, err = wm.runRetried(invs[0], func() (string, execution, error) {
+			return runBinaryWithString(invs[0], data, wm.progressFor(invs[0]))
+		})
+	} else {
+		output, err = wm.runRetried(invs[0], func() (string, execution, error) {
+			return runBinaryWithPipe(invs[0], sourcePath, wm.progressFor(invs[0]))
+		})
+	}
 	if err != nil {
 		return fmt.Errorf("running binary failed: %w", err)
 	}
 
-	wm.results[Outputkey(outputpath)] = Outputres(output)
+	output, err = wm.continueChain(invs[1:], output)
+	if err != nil {
+		return err
+	}
+
+	results[Outputkey(outputpath)] = Outputres(output)
+	if run := invs[0].run; run != nil {
+		run.recordArtifact(invs[0].block, Outputkey(outputpath))
+	}
 	return nil
 }
 
-func (wm *WorkflowManager) fromNode(binary, entry, inputPath, outputpath string) error {
-	input := wm.results[Outputkey(inputPath)]
+func (wm *WorkflowManager) fromNode(results map[Outputkey]Outputres, invs []invocation, inputPath, outputpath string) error {
+	input := results[Outputkey(inputPath)]
 
-	output, err := runBinaryWithString(binary, entry, input)
+	output, err := wm.runRetried(invs[0], func() (string, execution, error) {
+		return runBinaryWithString(invs[0], input, wm.progressFor(invs[0]))
+	})
 	if err != nil {
 		return fmt.Errorf("running binary with string failed: %w", err)
 	}
 
-	wm.results[Outputkey(outputpath)] = Outputres(output)
+	output, err = wm.continueChain(invs[1:], output)
+	if err != nil {
+		return err
+	}
+
+	results[Outputkey(outputpath)] = Outputres(output)
+	if run := invs[0].run; run != nil {
+		run.recordArtifact(invs[0].block, Outputkey(outputpath))
+	}
 	return nil
 }
+
+// continueChain runs the remaining entries of a chain, piping each entry's
+// stdout into the next one, and returns the output of the last entry. The
+// entries share the working directory of the first one, set by
+// newInvocations, so files one entry leaves there are seen by the next.
+func (wm *WorkflowManager) continueChain(invs []invocation, output string) (string, error) {
+	for _, inv := range invs {
+		next, err := wm.runRetried(inv, func() (string, execution, error) {
+			return runBinaryWithString(inv, Outputres(output), wm.progressFor(inv))
+		})
+		if err != nil {
+			return "", fmt.Errorf("running chained entry '%s' failed: %w", inv.entry, err)
+		}
+		output = next
+	}
+
+	return output, nil
+}
+
+// progressFor returns the callback that forwards progress lines reported by
+// the invoked block to the registered handler, or nil when nobody listens.
+func (wm *WorkflowManager) progressFor(inv invocation) func(percent int, step string) {
+	if wm.progressHandler == nil {
+		return nil
+	}
+
+	return func(percent int, step string) {
+		wm.progressHandler(BlockProgress{Block: inv.block, ExecutionID: inv.execID, Entry: inv.entry, Percent: percent, Step: step})
+	}
+}
+
+// entrySeparator joins chained entries in the "fromEntry" edge attribute.
+const entrySeparator = ","
+
+// connectionEntries returns the entries a connection runs, in order.
+func connectionEntries(c Connection) []string {
+	if len(c.FromEntries) > 0 {
+		return c.FromEntries
+	}
+	return []string{c.FromEntry}
+}
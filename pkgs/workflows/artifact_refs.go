@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+)
+
+const (
+	// artifactSourcePrefix marks a connection source reading an artifact
+	// stored by a previous run, e.g. "artifact://profiling/latest/profile".
+	artifactSourcePrefix = "artifact://"
+
+	// LatestRun refers to the most recent stored run of a workflow.
+	LatestRun = "latest"
+)
+
+// ErrArtifactNotFound is returned when an artifact reference can't be
+// resolved in the artifact store.
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// ArtifactRef points at an output stored by a run of a workflow.
+type ArtifactRef struct {
+	Workflow Workflowname
+	RunID    string // A run ID or LatestRun
+	Output   Outputkey
+}
+
+func (r ArtifactRef) String() string {
+	return artifactSourcePrefix + url.PathEscape(string(r.Workflow)) + "/" + r.RunID + "/" + string(r.Output)
+}
+
+// ParseArtifactRef parses an "artifact://<workflow>/<run ID|latest>/<output>"
+// source. Workflow names containing slashes or spaces are path-escaped. The
+// boolean is false when source is not an artifact reference at all.
+func ParseArtifactRef(source string) (ArtifactRef, bool, error) {
+	rest, ok := strings.CutPrefix(source, artifactSourcePrefix)
+	if !ok {
+		return ArtifactRef{}, false, nil
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return ArtifactRef{}, true, fmt.Errorf("invalid artifact reference %q: expected %s<workflow>/<run ID|%s>/<output>", source, artifactSourcePrefix, LatestRun)
+	}
+
+	workflow, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return ArtifactRef{}, true, fmt.Errorf("invalid artifact reference %q: %w", source, err)
+	}
+
+	return ArtifactRef{Workflow: Workflowname(workflow), RunID: parts[1], Output: Outputkey(parts[2])}, true, nil
+}
+
+// LoadArtifact resolves ref through the artifact store, which is shared by
+// every manager using the same install directory.
+func (wm *WorkflowManager) LoadArtifact(ref ArtifactRef) (Outputres, Artifact, error) {
+	runDir, err := wm.storedRunDir(ref.Workflow, ref.RunID)
+	if err != nil {
+		return "", Artifact{}, err
+	}
+
+	index, err := readArtifactIndex(runDir)
+	if err != nil {
+		return "", Artifact{}, err
+	}
+
+	for _, artifact := range index {
+		if artifact.Output != ref.Output {
+			continue
+		}
+
+		data, err := compression.ReadFile(filepath.Join(runDir, artifact.ID))
+		if err != nil {
+			return "", Artifact{}, fmt.Errorf("failed to read artifact '%s': %w", artifact.Output, err)
+		}
+		return Outputres(data), artifact, nil
+	}
+
+	return "", Artifact{}, fmt.Errorf("%w: %s", ErrArtifactNotFound, ref)
+}
+
+// storedRunDir returns the directory of a stored run of wfn, the most
+// recent one for LatestRun.
+func (wm *WorkflowManager) storedRunDir(wfn Workflowname, runID string) (string, error) {
+	dir := wm.artifactsDir(wfn)
+
+	if runID != LatestRun {
+		runDir := filepath.Join(dir, runID)
+		if _, err := os.Stat(filepath.Join(runDir, artifactIndexFile)); err != nil {
+			return "", fmt.Errorf("%w: run %s of workflow '%s' is not stored", ErrArtifactNotFound, runID, wfn)
+		}
+		return runDir, nil
+	}
+
+	runs, err := wm.storedRuns(wfn)
+	if err != nil || len(runs) == 0 {
+		return "", fmt.Errorf("%w: workflow '%s' has no stored run", ErrArtifactNotFound, wfn)
+	}
+	return filepath.Join(dir, runs[len(runs)-1]), nil
+}
+
+// storedRuns lists the stored run IDs of wfn, oldest first.
+func (wm *WorkflowManager) storedRuns(wfn Workflowname) ([]string, error) {
+	entries, err := os.ReadDir(wm.artifactsDir(wfn))
+	if err != nil {
+		return nil, err
+	}
+
+	// Run IDs sort in creation order.
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	return runs, nil
+}
+
+func readArtifactIndex(runDir string) ([]Artifact, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, artifactIndexFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact index: %w", err)
+	}
+
+	var index []Artifact
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact index: %w", err)
+	}
+	return index, nil
+}
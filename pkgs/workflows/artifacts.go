@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+)
+
+// Store identifies a kind of data persisted by the workflow manager, each
+// with its own compression setting.
+type Store string
+
+const (
+	StoreArtifacts Store = "artifacts"
+	StoreTelemetry Store = "telemetry"
+	StoreLogs      Store = "logs"
+)
+
+const runsDirName = "runs"
+
+// defaultCompression holds the codec used by each store unless overridden.
+var defaultCompression = map[Store]compression.Codec{
+	StoreArtifacts: compression.Zstd,
+	StoreTelemetry: compression.None,
+	StoreLogs:      compression.Zstd,
+}
+
+// SetCompression sets the codec used when writing to store. Existing files
+// stay readable whatever codec they were written with.
+func (wm *WorkflowManager) SetCompression(store Store, codec compression.Codec) {
+	if wm.compression == nil {
+		wm.compression = map[Store]compression.Codec{}
+	}
+	wm.compression[store] = codec
+}
+
+func (wm *WorkflowManager) codecFor(store Store) compression.Codec {
+	if codec, ok := wm.compression[store]; ok {
+		return codec
+	}
+	return defaultCompression[store]
+}
+
+// Artifact identifies an output produced by a run. Artifacts are stored
+// under their ID, so outputs sharing a name never collide across runs or
+// workflows.
+type Artifact struct {
+	ID          string       `json:"id"`
+	Output      Outputkey    `json:"output"`
+	Workflow    Workflowname `json:"workflow"`
+	RunID       string       `json:"run_id"`
+	Block       string       `json:"block,omitempty"`        // Producer; empty for absent-input markers
+	ExecutionID string       `json:"execution_id,omitempty"` // Block execution that produced it
+}
+
+// artifactIndexFile lists the artifacts of a stored run.
+const artifactIndexFile = "index.json"
+
+// artifactsDir is where the runs of wfn are persisted, one directory per
+// run ID.
+func (wm *WorkflowManager) artifactsDir(wfn Workflowname) string {
+	return filepath.Join(wm.pkgmanager.InstallDir, runsDirName, url.PathEscape(string(wfn)))
+}
+
+// DefaultArtifactRetention is the number of runs whose artifacts are kept
+// per workflow unless changed with SetArtifactRetention.
+const DefaultArtifactRetention = 10
+
+// SetArtifactRetention sets how many runs of each workflow keep their stored
+// artifacts, so later runs and other workflows can still reference them.
+// Older runs are deleted when a new run is stored. Values below 1 restore
+// the default.
+func (wm *WorkflowManager) SetArtifactRetention(runs int) {
+	wm.artifactRetention = runs
+}
+
+// saveArtifacts persists the artifacts of a run of wfn in
+// runs/<workflow>/<run ID>/<artifact ID>, indexed by index.json, and drops
+// runs beyond the retention. Outputs missing from index get a fresh identity.
+func (wm *WorkflowManager) saveArtifacts(wfn Workflowname, runID string, artifacts map[Outputkey]Outputres, index map[Outputkey]Artifact) ([]Artifact, error) {
+	dir := filepath.Join(wm.artifactsDir(wfn), runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	codec := wm.codecFor(StoreArtifacts)
+	stored := make([]Artifact, 0, len(artifacts))
+	for key, value := range artifacts {
+		artifact, ok := index[key]
+		if !ok {
+			artifact = Artifact{ID: newID(), Output: key, Workflow: wfn, RunID: runID}
+		}
+
+		if err := compression.WriteFile(filepath.Join(dir, artifact.ID), []byte(value), codec); err != nil {
+			return nil, fmt.Errorf("failed to write artifact '%s': %w", key, err)
+		}
+		stored = append(stored, artifact)
+	}
+
+	slices.SortFunc(stored, func(a, b Artifact) int { return strings.Compare(a.ID, b.ID) })
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, artifactIndexFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write artifact index: %w", err)
+	}
+
+	if err := wm.pruneRuns(wfn); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// pruneRuns deletes the stored runs of wfn beyond the retention, oldest
+// first, along with files of the legacy flat layout.
+func (wm *WorkflowManager) pruneRuns(wfn Workflowname) error {
+	retention := wm.artifactRetention
+	if retention < 1 {
+		retention = DefaultArtifactRetention
+	}
+
+	entries, err := os.ReadDir(wm.artifactsDir(wfn))
+	if err != nil {
+		return fmt.Errorf("failed to read artifacts directory: %w", err)
+	}
+
+	var runs []string
+	for _, entry := range entries {
+		path := filepath.Join(wm.artifactsDir(wfn), entry.Name())
+		if !entry.IsDir() {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to clear legacy artifacts: %w", err)
+			}
+			continue
+		}
+		runs = append(runs, path)
+	}
+
+	for len(runs) > retention {
+		if err := os.RemoveAll(runs[0]); err != nil {
+			return fmt.Errorf("failed to clear previous artifacts: %w", err)
+		}
+		runs = runs[1:]
+	}
+
+	return nil
+}
+
+// loadArtifacts reads the artifacts persisted by the last run of wfn.
+func (wm *WorkflowManager) loadArtifacts(wfn Workflowname) (map[Outputkey]Outputres, error) {
+	dir := wm.artifactsDir(wfn)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts directory: %w", err)
+	}
+
+	runDir, err := wm.storedRunDir(wfn, LatestRun)
+	if err != nil {
+		return loadLegacyArtifacts(dir, entries)
+	}
+
+	index, err := readArtifactIndex(runDir)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := map[Outputkey]Outputres{}
+	for _, artifact := range index {
+		data, err := compression.ReadFile(filepath.Join(runDir, artifact.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact '%s': %w", artifact.Output, err)
+		}
+		artifacts[artifact.Output] = Outputres(data)
+	}
+
+	return artifacts, nil
+}
+
+// loadLegacyArtifacts reads artifacts stored one file per output key, as
+// written before artifacts had IDs.
+func loadLegacyArtifacts(dir string, entries []os.DirEntry) (map[Outputkey]Outputres, error) {
+	artifacts := map[Outputkey]Outputres{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		key, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		data, err := compression.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact '%s': %w", key, err)
+		}
+		artifacts[Outputkey(key)] = Outputres(data)
+	}
+
+	return artifacts, nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// Scores added by each kind of evidence that an output fits an input.
+const (
+	wireScoreType      = 50 // Declared types are equal
+	wireScoreName      = 40 // Input and output names are equal
+	wireScorePartial   = 15 // One name contains the other
+	wireScoreUntyped   = 10 // Either side declares no type
+	wireScoreThreshold = wireScoreUntyped + wireScorePartial
+)
+
+// Suggestion is a proposed connection feeding an unbound entry input with
+// a compatible output. Connection is ready to be written to the workflow:
+// it replaces connection #Replaces (0-based) or, when Replaces is -1, is
+// appended as a new connection.
+type Suggestion struct {
+	Connection Connection `json:"connection"`
+	Replaces   int        `json:"replaces"`
+	Input      string     `json:"input"`      // Declared input being bound
+	FromBlock  string     `json:"from_block"` // Producer of the output
+	FromEntry  string     `json:"from_entry"`
+	Output     string     `json:"output"` // Declared output matched
+	Score      int        `json:"score"`
+	Reason     string     `json:"reason"`
+}
+
+// wireConsumer is an entry whose inputs are not bound yet.
+type wireConsumer struct {
+	block, entry string
+	replaces     int // Index of its connection, -1 when it has none
+	connection   Connection
+	inputs       []packagemanager.Input
+}
+
+// wireProducer is a connection output together with its declared type.
+type wireProducer struct {
+	connection Connection
+	outputs    []packagemanager.Output
+}
+
+// SuggestConnections proposes connections for the unbound inputs of a
+// partially specified workflow, matching them to the outputs of other blocks
+// by declared type and name. Only the blocks listed are considered (all when
+// empty). Block entries are taken from the metadata of compiled or installed
+// blocks; blocks without metadata are skipped. Suggestions are ranked by
+// score, best first, for a human or agent to confirm.
+func (wm *WorkflowManager) SuggestConnections(workflowPath string, blocks []Blockname) ([]Suggestion, error) {
+	rwf, err := parseWorkflow(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("parseWorkflow failed: %w", err)
+	}
+
+	selected := func(name string) bool {
+		return len(blocks) == 0 || slices.Contains(blocks, Blockname(name))
+	}
+
+	entries := map[string]map[string]packagemanager.Entry{}
+	for _, block := range rwf.Blocks {
+		if md := wm.blockMetadata(block.Name); md != nil && selected(block.Name) {
+			entries[block.Name] = md.LSPEntries
+		}
+	}
+
+	var consumers []wireConsumer
+	var producers []wireProducer
+	used := map[string]bool{}
+
+	for i, c := range rwf.Connections {
+		chain := connectionEntries(c)
+		used[c.FromBlock+"/"+chain[0]] = true
+
+		blockEntries, ok := entries[c.FromBlock]
+		if !ok {
+			continue
+		}
+
+		if c.Output != "" {
+			producers = append(producers, wireProducer{c, blockEntries[chain[len(chain)-1]].Outputs})
+		}
+		if first := blockEntries[chain[0]]; c.Input == "" && c.Source == "" && len(first.Inputs) > 0 {
+			consumers = append(consumers, wireConsumer{c.FromBlock, chain[0], i, c, first.Inputs})
+		}
+	}
+
+	for _, block := range rwf.Blocks {
+		for _, name := range slices.Sorted(maps.Keys(entries[block.Name])) {
+			entry := entries[block.Name][name]
+			if used[block.Name+"/"+name] || len(entry.Inputs) == 0 {
+				continue
+			}
+
+			c := Connection{FromBlock: block.Name, FromEntry: name}
+			if len(entry.Outputs) > 0 {
+				c.Output = entry.Outputs[0].Name
+			}
+			consumers = append(consumers, wireConsumer{block.Name, name, -1, c, entry.Inputs})
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, consumer := range consumers {
+		for _, producer := range producers {
+			if producer.connection.FromBlock == consumer.block {
+				continue
+			}
+			for _, in := range consumer.inputs {
+				if s, ok := matchWire(consumer, producer, in); ok {
+					suggestions = append(suggestions, s)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b Suggestion) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			strings.Compare(a.Connection.FromBlock, b.Connection.FromBlock),
+			strings.Compare(a.FromBlock, b.FromBlock),
+		)
+	})
+
+	return suggestions, nil
+}
+
+// matchWire scores how well one output of producer fits input in.
+func matchWire(consumer wireConsumer, producer wireProducer, in packagemanager.Input) (Suggestion, bool) {
+	best := Suggestion{Score: -1}
+	outputs := producer.outputs
+	if len(outputs) == 0 {
+		// Undeclared outputs can still match on the connection's output key.
+		outputs = []packagemanager.Output{{Name: producer.connection.Output}}
+	}
+
+	for _, out := range outputs {
+		score, reasons := 0, []string{}
+
+		switch {
+		case in.Type == "" || out.Type == "":
+			score += wireScoreUntyped
+		case strings.EqualFold(in.Type, out.Type):
+			score += wireScoreType
+			reasons = append(reasons, fmt.Sprintf("type %s", in.Type))
+		default:
+			continue // Incompatible types.
+		}
+
+		inName, outName, key := strings.ToLower(in.Name), strings.ToLower(out.Name), strings.ToLower(producer.connection.Output)
+		switch {
+		case inName == outName || inName == key:
+			score += wireScoreName
+			reasons = append(reasons, fmt.Sprintf("name %s", in.Name))
+		case inName != "" && (strings.Contains(outName, inName) || strings.Contains(inName, outName) || strings.Contains(key, inName)):
+			score += wireScorePartial
+			reasons = append(reasons, fmt.Sprintf("similar names %s/%s", in.Name, out.Name))
+		}
+
+		if score > best.Score {
+			best = Suggestion{Output: out.Name, Score: score, Reason: strings.Join(reasons, ", ")}
+		}
+	}
+
+	if best.Score < wireScoreThreshold {
+		return Suggestion{}, false
+	}
+
+	best.Connection = consumer.connection
+	best.Connection.Input = producer.connection.Output
+	best.Replaces = consumer.replaces
+	best.Input = in.Name
+	best.FromBlock = producer.connection.FromBlock
+	best.FromEntry = producer.connection.FromEntry
+	best.Reason = fmt.Sprintf("%s.%s <- %s.%s: %s", consumer.block, in.Name, producer.connection.FromBlock, best.Output, best.Reason)
+
+	return best, true
+}
+
+// blockMetadata returns the metadata of a compiled block, falling back to
+// the installed block of the same name.
+func (wm *WorkflowManager) blockMetadata(name string) *packagemanager.BlockMetadata {
+	wm.compileMu.RLock()
+	md, ok := wm.metadata[Blockname(name)]
+	wm.compileMu.RUnlock()
+	if ok {
+		return md
+	}
+	if md, ok := wm.pkgmanager.GetLoadedBlock(name); ok {
+		return md
+	}
+	return nil
+}
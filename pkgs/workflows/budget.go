@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBudgetExceeded is wrapped by the error of a run stopped by its budget.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget bounds a single run, as a safety rail for generated workflows that
+// could otherwise run away. Zero fields are unlimited.
+type Budget struct {
+	MaxWallTime        time.Duration // Total duration of the run
+	MaxBlockExecutions int           // Blocks started by the run
+	MaxArtifactBytes   int64         // Total size of the outputs produced by the run
+}
+
+// runContext returns the context bounding the block processes of a run,
+// which is cancelled with parent and expires with the wall-time budget.
+func runContext(parent context.Context, budget *Budget) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	if budget == nil || budget.MaxWallTime <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, budget.MaxWallTime)
+}
+
+// checkBudget reports whether starting another block would exceed the
+// budget of the run.
+func (re *runEnv) checkBudget() error {
+	if err := re.checkSpent(); err != nil {
+		return err
+	}
+
+	if b := re.budget; b != nil && b.MaxBlockExecutions > 0 && len(re.executions) >= b.MaxBlockExecutions {
+		return budgetError("block executions", float64(len(re.executions)), float64(b.MaxBlockExecutions))
+	}
+	return nil
+}
+
+// checkSpent reports whether the run already used up its wall time or
+// artifact budget.
+func (re *runEnv) checkSpent() error {
+	b := re.budget
+	if b == nil {
+		return nil
+	}
+
+	if b.MaxWallTime > 0 {
+		if elapsed := time.Since(re.started); elapsed >= b.MaxWallTime || errors.Is(re.ctx.Err(), context.DeadlineExceeded) {
+			return budgetError("wall time", elapsed.Seconds(), b.MaxWallTime.Seconds())
+		}
+	}
+
+	if b.MaxArtifactBytes > 0 {
+		var size int64
+		for _, value := range re.results {
+			size += int64(len(value))
+		}
+		if size > b.MaxArtifactBytes {
+			return budgetError("artifact bytes", float64(size), float64(b.MaxArtifactBytes))
+		}
+	}
+
+	return nil
+}
+
+func budgetError(resource string, used, limit float64) error {
+	return fmt.Errorf("%w: run used %g %s, limit is %g", ErrBudgetExceeded, used, resource, limit)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// ConflictPolicy decides what CompileWorkflow does when the workflow needs
+// another version of a block than a workflow compiled before it.
+type ConflictPolicy int
+
+const (
+	// ConflictSideBySide keeps both versions installed; every workflow runs
+	// the version it was compiled against.
+	ConflictSideBySide ConflictPolicy = iota
+	// ConflictFail rejects the workflow with a *VersionConflictError and
+	// leaves the previously active versions in place.
+	ConflictFail
+)
+
+// SetConflictPolicy sets how version conflicts between compiled workflows
+// are handled; ConflictSideBySide by default.
+func (wm *WorkflowManager) SetConflictPolicy(policy ConflictPolicy) {
+	wm.conflictPolicy = policy
+}
+
+// BlockRequirement is the version of a block one compiled workflow uses.
+type BlockRequirement struct {
+	Workflow  Workflowname
+	Block     Blockname // Name of the block in the workflow
+	Requested string    // Version or constraint in the workflow file, empty for the latest
+	Version   string    // Version installed for it
+}
+
+// VersionConflict lists the workflows that use different versions of the
+// same installed block.
+type VersionConflict struct {
+	Block        string // Installed block name
+	Requirements []BlockRequirement
+}
+
+func (c VersionConflict) String() string {
+	reqs := make([]string, 0, len(c.Requirements))
+	for _, r := range c.Requirements {
+		requested := r.Requested
+		if requested == "" {
+			requested = "latest"
+		}
+		reqs = append(reqs, fmt.Sprintf("workflow '%s' uses %s (%s)", r.Workflow, r.Version, requested))
+	}
+	return fmt.Sprintf("block '%s': %s", c.Block, strings.Join(reqs, ", "))
+}
+
+// VersionConflictError is returned by CompileWorkflow under ConflictFail
+// when the workflow needs other versions of blocks than compiled workflows.
+type VersionConflictError struct {
+	Workflow  Workflowname
+	Conflicts []VersionConflict
+}
+
+func (e *VersionConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+	return fmt.Sprintf("workflow '%s' conflicts with compiled workflows on %d block(s): %s; pin the same version in every workflow, or use ConflictSideBySide to run each workflow on its own version",
+		e.Workflow, len(e.Conflicts), strings.Join(conflicts, "; "))
+}
+
+// VersionConflicts returns the blocks that compiled workflows use in
+// different versions, sorted by block name, with the requirements sorted by
+// workflow.
+func (wm *WorkflowManager) VersionConflicts() []VersionConflict {
+	wm.compileMu.RLock()
+	defer wm.compileMu.RUnlock()
+	return findConflicts(wm.requirements(maps.Keys(wm.compiled)))
+}
+
+// requirements collects the block versions used by the given compiled
+// workflows, by installed block name.
+func (wm *WorkflowManager) requirements(workflows iter.Seq[Workflowname]) map[string][]BlockRequirement {
+	reqs := map[string][]BlockRequirement{}
+	for wfn := range workflows {
+		wm.addRequirements(reqs, wm.definitions[wfn], wm.compiled[wfn])
+	}
+	return reqs
+}
+
+// addRequirements adds the block versions of one workflow to reqs.
+func (wm *WorkflowManager) addRequirements(reqs map[string][]BlockRequirement, rwf *RawWorkflow, metadata map[Blockname]*packagemanager.BlockMetadata) {
+	if rwf == nil {
+		return
+	}
+	for _, block := range rwf.Blocks {
+		md, ok := metadata[Blockname(block.Name)]
+		if !ok {
+			continue
+		}
+		reqs[md.Name] = append(reqs[md.Name], BlockRequirement{
+			Workflow:  Workflowname(rwf.Name),
+			Block:     Blockname(block.Name),
+			Requested: block.Version,
+			Version:   md.Version,
+		})
+	}
+}
+
+// findConflicts returns the blocks of reqs required in more than one
+// version.
+func findConflicts(reqs map[string][]BlockRequirement) []VersionConflict {
+	var conflicts []VersionConflict
+	for _, name := range slices.Sorted(maps.Keys(reqs)) {
+		blockReqs := reqs[name]
+		if !slices.ContainsFunc(blockReqs, func(r BlockRequirement) bool { return r.Version != blockReqs[0].Version }) {
+			continue
+		}
+		slices.SortStableFunc(blockReqs, func(a, b BlockRequirement) int {
+			return strings.Compare(string(a.Workflow), string(b.Workflow))
+		})
+		conflicts = append(conflicts, VersionConflict{Block: name, Requirements: blockReqs})
+	}
+	return conflicts
+}
+
+// checkConflicts compares the staged blocks of a workflow being compiled
+// with the other compiled workflows. Under ConflictFail, the versions the
+// other workflows use are made active again and an error is returned.
+func (wm *WorkflowManager) checkConflicts(ctx context.Context, rwf *RawWorkflow, staged map[Blockname]*packagemanager.BlockMetadata) error {
+	wm.compileMu.RLock()
+	others := func(yield func(Workflowname) bool) {
+		for wfn := range wm.compiled {
+			if wfn != Workflowname(rwf.Name) && !yield(wfn) {
+				return
+			}
+		}
+	}
+	reqs := wm.requirements(others)
+	wm.compileMu.RUnlock()
+
+	var conflicts []VersionConflict
+	wm.addRequirements(reqs, rwf, staged)
+	for _, c := range findConflicts(reqs) {
+		if slices.ContainsFunc(c.Requirements, func(r BlockRequirement) bool { return r.Workflow == Workflowname(rwf.Name) }) {
+			conflicts = append(conflicts, c)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if wm.conflictPolicy != ConflictFail {
+		for _, c := range conflicts {
+			fmt.Printf("Warning: keeping side-by-side versions of %s\n", c)
+		}
+		return nil
+	}
+
+	for _, c := range conflicts {
+		for _, r := range c.Requirements {
+			if r.Workflow == Workflowname(rwf.Name) {
+				continue
+			}
+			if _, err := wm.pkgmanager.Use(ctx, c.Block, r.Version); err != nil {
+				fmt.Printf("Warning: failed to reactivate %s %s: %v\n", c.Block, r.Version, err)
+			}
+			break
+		}
+	}
+	return &VersionConflictError{Workflow: Workflowname(rwf.Name), Conflicts: conflicts}
+}
+
+// pinnedVersion returns the metadata of the version a workflow block asks
+// for. Installs reuse the active version of a block, so when that version
+// doesn't satisfy the block's version, the highest installed version that
+// does is used, or the requested version is installed next to the others.
+func (wm *WorkflowManager) pinnedVersion(ctx context.Context, block Block, md *packagemanager.BlockMetadata) (*packagemanager.BlockMetadata, error) {
+	if satisfiesVersion(block.Version, md.Version) {
+		return md, nil
+	}
+
+	if versions, err := wm.pkgmanager.ListVersions(md.Name); err == nil {
+		for _, installed := range slices.Backward(versions) {
+			if satisfiesVersion(block.Version, installed.Version) {
+				return installed, nil
+			}
+		}
+	}
+
+	return wm.pkgmanager.Install(ctx, packagemanager.InstallRequest{
+		Repo:    block.GitHub,
+		Version: block.Version,
+		Alias:   block.Alias,
+		Force:   true,
+	})
+}
+
+// satisfiesVersion reports whether an installed version matches the version
+// or constraint a workflow requests; an empty request or "latest" matches
+// any version.
+func satisfiesVersion(requested, version string) bool {
+	if requested == "" || strings.EqualFold(requested, "latest") {
+		return true
+	}
+	if packagemanager.IsVersionConstraint(requested) {
+		vc, err := packagemanager.ParseVersionConstraint(requested)
+		return err == nil && vc.Matches(version)
+	}
+	return strings.TrimPrefix(requested, "v") == strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// newContainerCommand prepares the command running the invoked entry of an
+// image block in a throwaway container. Only the workflow environment is
+// passed in, and the working directory of chained entries is mounted at the
+// same path. Sandboxed blocks get no network unless their profile or the
+// workflow allows some hosts, and their writable paths are mounted.
+func newContainerCommand(inv invocation, image string) *exec.Cmd {
+	ctx := context.Background()
+	if inv.run != nil {
+		ctx = inv.run.ctx
+	}
+
+	args := []string{"run", "--rm", "-i"}
+	network := ""
+	for _, kv := range inv.env {
+		args = append(args, "-e", kv)
+		// The egress proxy listens on the host's loopback interface.
+		if strings.HasPrefix(kv, "HTTP_PROXY=") {
+			network = "host"
+		}
+	}
+	if inv.sandbox != nil {
+		// Without any allowed host there is no point reaching the proxy.
+		denied := inv.run != nil && inv.run.egressDeniesAll(inv.block)
+		if (network == "" && !inv.sandbox.AnyNetwork()) || denied {
+			network = "none"
+		}
+		for _, path := range writablePaths(inv.sandbox) {
+			args = append(args, "-v", path+":"+path)
+		}
+	}
+	if network != "" {
+		args = append(args, "--network="+network)
+	}
+	if inv.dir != "" {
+		args = append(args, "-v", inv.dir+":"+inv.dir, "-w", inv.dir)
+	}
+	args = append(args, image, inv.entry)
+
+	return exec.CommandContext(ctx, packagemanager.ContainerCLI(), args...)
+}
+
+// runContainer runs the invoked entry of an image block, wiring stdin,
+// stdout, and stderr like a native binary.
+func runContainer(inv invocation, image string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	cmd := newContainerCommand(inv, image)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	err := cmd.Run()
+	if cmd.ProcessState == nil {
+		return 0, err
+	}
+	return cmd.ProcessState.ExitCode(), err
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Determinism configures the deterministic execution mode. Blocks receive a
+// fixed seed and a pinned timestamp through the environment, and the outputs
+// can be checked against the previous run of the same workflow.
+type Determinism struct {
+	// Seed is exposed as ATOMOS_SEED for blocks that make randomized choices.
+	Seed int64
+	// Timestamp is exposed as ATOMOS_TIMESTAMP and SOURCE_DATE_EPOCH; the
+	// Unix epoch is used when it is zero.
+	Timestamp time.Time
+	// VerifyOutputs compares the hash of every output with the previous run
+	// and fails the run when they differ.
+	VerifyOutputs bool
+}
+
+// OutputMismatch is an output whose content changed between two runs.
+type OutputMismatch struct {
+	Output       Outputkey
+	PreviousHash string
+	CurrentHash  string // Empty when the output wasn't produced
+}
+
+func (d *Determinism) environ() []string {
+	ts := d.Timestamp
+	if ts.IsZero() {
+		ts = time.Unix(0, 0)
+	}
+
+	return []string{
+		EnvSeed + "=" + strconv.FormatInt(d.Seed, 10),
+		EnvTimestamp + "=" + ts.UTC().Format(time.RFC3339),
+		EnvSourceDateEpoch + "=" + strconv.FormatInt(ts.Unix(), 10),
+	}
+}
+
+// previousArtifacts returns the artifacts of the previous run to verify
+// against, or nil when verification is off or there is no previous run.
+func (wm *WorkflowManager) previousArtifacts(wfn Workflowname, opts RunOptions) map[Outputkey]Outputres {
+	if opts.Determinism == nil || !opts.Determinism.VerifyOutputs {
+		return nil
+	}
+
+	if recorded, ok := wm.lastRecorded(wfn); ok {
+		return recorded
+	}
+
+	persisted, err := wm.loadArtifacts(wfn)
+	if err != nil {
+		return nil
+	}
+	return persisted
+}
+
+// compareOutputHashes reports every output of the previous run whose hash
+// differs in the current one, sorted by output name.
+func compareOutputHashes(previous, current map[Outputkey]Outputres) []OutputMismatch {
+	var mismatches []OutputMismatch
+	for key, prev := range previous {
+		prevHash := hashOutput(prev)
+
+		var currHash string
+		if curr, ok := current[key]; ok {
+			currHash = hashOutput(curr)
+		}
+
+		if prevHash != currHash {
+			mismatches = append(mismatches, OutputMismatch{Output: key, PreviousHash: prevHash, CurrentHash: currHash})
+		}
+	}
+
+	slices.SortFunc(mismatches, func(a, b OutputMismatch) int {
+		return strings.Compare(string(a.Output), string(b.Output))
+	})
+	return mismatches
+}
+
+func hashOutput(output Outputres) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
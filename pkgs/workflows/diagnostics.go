@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/dominikbraun/graph"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a Diagnostic. Errors stop compilation; warnings don't.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic codes reported by LintWorkflow and CompileWorkflow.
+const (
+	CodeYAMLSyntax          = "yaml-syntax"
+	CodeMissingWorkflowName = "missing-workflow-name"
+	CodeMissingBlockName    = "missing-block-name"
+	CodeDuplicateBlock      = "duplicate-block"
+	CodeMissingSource       = "missing-source"
+	CodeInvalidPlacement    = "invalid-placement"
+	CodeInvalidRetry        = "invalid-retry"
+	CodeUnknownBlock        = "unknown-block"
+	CodeMissingEntry        = "missing-entry"
+	CodeConflictingEntries  = "conflicting-entries"
+	CodeMissingOutput       = "missing-output"
+	CodeUnboundInput        = "unbound-input"
+	CodeCycle               = "cycle"
+	CodeInstallFailed       = "install-failed"
+	CodeUnknownEntry        = "unknown-entry"
+	CodeInvalidArtifactRef  = "invalid-artifact-ref"
+)
+
+// Diagnostic is a single problem found in a workflow file, positioned so
+// tools can point at (and fix) the offending YAML.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Block    string   `json:"block,omitempty"`
+	Entry    string   `json:"entry,omitempty"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", d.File, d.Line, d.Column, d.Severity, d.Message, d.Code)
+}
+
+// DiagnosticsError is returned by CompileWorkflow when the workflow has
+// errors. It carries every diagnostic, warnings included.
+type DiagnosticsError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *DiagnosticsError) Error() string {
+	var errs []string
+	for _, d := range e.Diagnostics {
+		if d.Severity == SeverityError {
+			errs = append(errs, d.String())
+		}
+	}
+	return fmt.Sprintf("workflow has %d error(s): %s", len(errs), strings.Join(errs, "; "))
+}
+
+// LintWorkflow checks a workflow file without installing anything and
+// returns every problem found. The error is only set when the file cannot
+// be read.
+func (wm *WorkflowManager) LintWorkflow(workflowPath string) ([]Diagnostic, error) {
+	_, l, err := lintWorkflow(workflowPath)
+	if err != nil {
+		return nil, err
+	}
+	return l.diags, nil
+}
+
+func hasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlLine extracts the position from yaml.v3 error messages ("line 7: ...").
+var yamlLine = regexp.MustCompile(`line (\d+)`)
+
+// lintWorkflow parses and statically checks a workflow file. The returned
+// linter holds the diagnostics and can report further ones.
+func lintWorkflow(path string) (*RawWorkflow, *linter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read workflow file: %w", err)
+	}
+
+	l := &linter{file: path}
+
+	var root yaml.Node
+	var rwf RawWorkflow
+	err = yaml.Unmarshal(data, &root)
+	if err == nil {
+		err = root.Decode(&rwf)
+	}
+	if err != nil {
+		d := Diagnostic{Severity: SeverityError, Code: CodeYAMLSyntax, Message: err.Error(), File: path}
+		if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+		}
+		l.diags = append(l.diags, d)
+		return nil, l, nil
+	}
+	l.root = documentRoot(&root)
+
+	if rwf.Name == "" {
+		l.report(SeverityError, CodeMissingWorkflowName, l.root, "", "", "workflow_name is required")
+	}
+
+	blocks := map[string]bool{}
+	for i, block := range rwf.Blocks {
+		node := l.item("blocks", i)
+		switch {
+		case block.Name == "":
+			l.report(SeverityError, CodeMissingBlockName, node, "", "", fmt.Sprintf("block #%d has no name", i+1))
+		case blocks[block.Name]:
+			l.report(SeverityError, CodeDuplicateBlock, l.field(node, "name"), block.Name, "", fmt.Sprintf("block '%s' is declared more than once", block.Name))
+		}
+		blocks[block.Name] = true
+
+		if block.GitHub == "" {
+			l.report(SeverityError, CodeMissingSource, node, block.Name, "", fmt.Sprintf("block '%s' has no github source", block.Name))
+		}
+		if err := block.Placement.validate(); err != nil {
+			l.report(SeverityError, CodeInvalidPlacement, l.field(node, "placement"), block.Name, "", fmt.Sprintf("invalid placement for block '%s': %v", block.Name, err))
+		}
+		if err := block.Retry.validate(); err != nil {
+			l.report(SeverityError, CodeInvalidRetry, l.field(node, "retry"), block.Name, "", fmt.Sprintf("invalid retry policy for block '%s': %v", block.Name, err))
+		}
+	}
+
+	outputs := map[string]bool{}
+	for _, c := range rwf.Connections {
+		if c.Output != "" {
+			outputs[c.Output] = true
+		}
+	}
+
+	for i, c := range rwf.Connections {
+		node := l.item("connections", i)
+		entry := strings.Join(connectionEntries(c), entrySeparator)
+
+		if !blocks[c.FromBlock] {
+			l.report(SeverityError, CodeUnknownBlock, l.field(node, "from_block"), c.FromBlock, entry, fmt.Sprintf("connection #%d references unknown block '%s'", i+1, c.FromBlock))
+		}
+		switch {
+		case c.FromEntry != "" && len(c.FromEntries) > 0:
+			l.report(SeverityError, CodeConflictingEntries, l.field(node, "from_entries"), c.FromBlock, entry, "from_entry and from_entries are mutually exclusive")
+		case c.FromEntry == "" && len(c.FromEntries) == 0:
+			l.report(SeverityError, CodeMissingEntry, node, c.FromBlock, "", fmt.Sprintf("connection #%d has no from_entry", i+1))
+		}
+		if c.Output == "" {
+			l.report(SeverityError, CodeMissingOutput, node, c.FromBlock, entry, fmt.Sprintf("connection #%d has no output", i+1))
+		}
+		if c.Input != "" && !outputs[c.Input] {
+			l.report(SeverityWarning, CodeUnboundInput, l.field(node, "input"), c.FromBlock, entry, fmt.Sprintf("input '%s' is not produced by any connection", c.Input))
+		}
+		if _, _, err := ParseArtifactRef(c.Source); err != nil {
+			l.report(SeverityError, CodeInvalidArtifactRef, l.field(node, "source"), c.FromBlock, entry, err.Error())
+		}
+	}
+
+	l.checkCycles(&rwf)
+
+	return &rwf, l, nil
+}
+
+// checkEntries type-checks connections against the entries declared by the
+// installed blocks. Blocks whose metadata lists no entries are not checked.
+func (l *linter) checkEntries(rwf *RawWorkflow, metadata map[Blockname]*packagemanager.BlockMetadata) {
+	for i, c := range rwf.Connections {
+		md, ok := metadata[Blockname(c.FromBlock)]
+		if !ok || len(md.LSPEntries) == 0 {
+			continue
+		}
+
+		for _, entry := range connectionEntries(c) {
+			if entry == "" {
+				continue
+			}
+			if _, ok := md.LSPEntries[entry]; !ok {
+				l.report(SeverityError, CodeUnknownEntry, l.entryNode(l.item("connections", i)), c.FromBlock, entry,
+					fmt.Sprintf("block '%s' %s has no entry '%s'", c.FromBlock, md.Version, entry))
+			}
+		}
+	}
+}
+
+// entryNode points at from_entries when set, from_entry otherwise.
+func (l *linter) entryNode(node *yaml.Node) *yaml.Node {
+	if n := l.field(node, "from_entries"); n != node {
+		return n
+	}
+	return l.field(node, "from_entry")
+}
+
+// checkCycles reports connections whose edge would close a cycle; such
+// edges are dropped when the graph is built.
+func (l *linter) checkCycles(rwf *RawWorkflow) {
+	g := graph.New(func(name string) string { return name }, graph.Directed(), graph.Acyclic())
+	for _, block := range rwf.Blocks {
+		_ = g.AddVertex(block.Name)
+	}
+
+	for _, src := range rwf.Connections {
+		for i, dst := range rwf.Connections {
+			if src.Output == "" || dst.Input != src.Output {
+				continue
+			}
+			err := g.AddEdge(src.FromBlock, dst.FromBlock)
+			if errors.Is(err, graph.ErrEdgeCreatesCycle) {
+				l.report(SeverityWarning, CodeCycle, l.field(l.item("connections", i), "input"), dst.FromBlock, dst.FromEntry,
+					fmt.Sprintf("input '%s' creates a cycle %s -> %s; the edge is ignored", dst.Input, src.FromBlock, dst.FromBlock))
+			}
+		}
+	}
+}
+
+// linter accumulates diagnostics and resolves their YAML positions.
+type linter struct {
+	file  string
+	root  *yaml.Node
+	diags []Diagnostic
+}
+
+func (l *linter) report(severity Severity, code string, node *yaml.Node, block, entry, message string) {
+	d := Diagnostic{Severity: severity, Code: code, Message: message, File: l.file, Block: block, Entry: entry}
+	if node != nil {
+		d.Line, d.Column = node.Line, node.Column
+	}
+	l.diags = append(l.diags, d)
+}
+
+// item returns the node of the i-th element of a top-level sequence.
+func (l *linter) item(key string, i int) *yaml.Node {
+	seq := l.field(l.root, key)
+	if seq == nil || seq.Kind != yaml.SequenceNode || i >= len(seq.Content) {
+		return l.root
+	}
+	return seq.Content[i]
+}
+
+// field returns the value node of key in a mapping, or the mapping itself
+// when the key is absent so diagnostics still point nearby.
+func (l *linter) field(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return node
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
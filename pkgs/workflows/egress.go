@@ -0,0 +1,256 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Egress restricts the hosts a block may reach over the network. The block
+// runs with its proxy variables pointing at a local proxy that only lets
+// the allowed hosts through, so tools honouring HTTP_PROXY/HTTPS_PROXY are
+// confined; it doesn't stop a binary that opens raw sockets itself.
+type Egress struct {
+	// Allow lists the permitted hosts: "api.example.com", "*.example.com"
+	// for its subdomains, or "host:port" to also pin the port. An empty
+	// list denies all traffic.
+	Allow []string `yaml:"allow"`
+}
+
+// EgressViolation records a connection a block attempted to a host outside
+// its egress allowlist.
+type EgressViolation struct {
+	Block       string
+	ExecutionID string
+	Host        string
+	Time        time.Time
+}
+
+// allows reports whether hostport ("host:port") is on the allowlist.
+func (e *Egress) allows(hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	for _, rule := range e.Allow {
+		rule = strings.ToLower(rule)
+		ruleHost, rulePort, err := net.SplitHostPort(rule)
+		if err != nil {
+			ruleHost, rulePort = rule, ""
+		}
+		if rulePort != "" && rulePort != port {
+			continue
+		}
+
+		if suffix, ok := strings.CutPrefix(ruleHost, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if strings.Trim(ruleHost, "[]") == host {
+			return true
+		}
+	}
+	return false
+}
+
+// egressProxy is a local HTTP proxy enforcing the egress policy of a block.
+type egressProxy struct {
+	policy   *Egress
+	listener net.Listener
+	server   *http.Server
+	onDeny   func(host string)
+}
+
+// startEgressProxy listens on a loopback port and serves the proxy until
+// close is called.
+func startEgressProxy(policy *Egress, onDeny func(host string)) (*egressProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start egress proxy: %w", err)
+	}
+
+	p := &egressProxy{policy: policy, listener: listener, onDeny: onDeny}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go p.server.Serve(listener)
+
+	return p, nil
+}
+
+func (p *egressProxy) url() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+func (p *egressProxy) close() {
+	_ = p.server.Close()
+}
+
+// environ points the proxy variables of a block process at the proxy.
+func (p *egressProxy) environ() []string {
+	var env []string
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY"} {
+		env = append(env, name+"="+p.url(), strings.ToLower(name)+"="+p.url())
+	}
+	// Nothing bypasses the proxy, loopback included.
+	return append(env, "NO_PROXY=", "no_proxy=")
+}
+
+func (p *egressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if r.Method != http.MethodConnect {
+		host = r.URL.Host
+		if r.URL.Port() == "" {
+			host = net.JoinHostPort(r.URL.Hostname(), "80")
+		}
+	}
+
+	if !p.policy.allows(host) {
+		p.onDeny(host)
+		http.Error(w, fmt.Sprintf("egress to %s is not allowed", host), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	p.forward(w, r)
+}
+
+// tunnel relays a CONNECT request, used for HTTPS, to the target host.
+func (p *egressProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	_, _ = client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	relay := func(dst, src net.Conn) {
+		defer wg.Done()
+		_, _ = io.Copy(dst, src)
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+	}
+	go relay(upstream, client)
+	go relay(client, upstream)
+	wg.Wait()
+
+	client.Close()
+	upstream.Close()
+}
+
+// forward relays a plain HTTP request to the target host.
+func (p *egressProxy) forward(w http.ResponseWriter, r *http.Request) {
+	out := r.Clone(context.WithoutCancel(r.Context()))
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Connection")
+	out.Header.Del("Proxy-Authorization")
+
+	transport := &http.Transport{Proxy: nil}
+	defer transport.CloseIdleConnections()
+
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// startEgress starts the egress proxy of block enforcing policy, unless
+// policy is nil or the proxy is already running for this run.
+func (wm *WorkflowManager) startEgress(run *runEnv, block string, policy *Egress) error {
+	if run == nil || policy == nil {
+		return nil
+	}
+
+	run.egressMu.Lock()
+	defer run.egressMu.Unlock()
+	if _, ok := run.proxies[block]; ok {
+		return nil
+	}
+
+	proxy, err := startEgressProxy(policy, func(host string) {
+		run.egressMu.Lock()
+		run.violations = append(run.violations, EgressViolation{
+			Block:       block,
+			ExecutionID: run.executions[block],
+			Host:        host,
+			Time:        time.Now(),
+		})
+		run.egressMu.Unlock()
+		wm.logRun(run, block, "block %s denied egress to %s", block, host)
+	})
+	if err != nil {
+		return err
+	}
+
+	run.proxies[block] = proxy
+	return nil
+}
+
+// egressEnviron returns the proxy variables of block, if it is restricted.
+func (re *runEnv) egressEnviron(block string) []string {
+	re.egressMu.Lock()
+	defer re.egressMu.Unlock()
+	if proxy, ok := re.proxies[block]; ok {
+		return proxy.environ()
+	}
+	return nil
+}
+
+// egressDeniesAll reports whether block is restricted to no hosts at all.
+func (re *runEnv) egressDeniesAll(block string) bool {
+	re.egressMu.Lock()
+	defer re.egressMu.Unlock()
+	proxy, ok := re.proxies[block]
+	return ok && len(proxy.policy.Allow) == 0
+}
+
+// egressViolations returns the violations recorded so far.
+func (re *runEnv) egressViolations() []EgressViolation {
+	re.egressMu.Lock()
+	defer re.egressMu.Unlock()
+	return append([]EgressViolation(nil), re.violations...)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/logship"
+)
+
+// Environment variables injected into every block process.
+const (
+	EnvRunID       = "ATOMOS_RUN_ID"
+	EnvExecutionID = "ATOMOS_EXECUTION_ID"
+	EnvBlock       = "ATOMOS_BLOCK"
+	EnvEntry       = "ATOMOS_ENTRY"
+	EnvWorkDir     = "ATOMOS_WORKDIR"
+	EnvOutputDir   = "ATOMOS_OUTPUT_DIR"
+	EnvParamPrefix = "ATOMOS_PARAM_"
+	EnvSeed        = "ATOMOS_SEED"
+	EnvTimestamp   = "ATOMOS_TIMESTAMP"
+	// EnvSourceDateEpoch follows the reproducible-builds convention so tools
+	// that already honour it pin their timestamps without changes.
+	EnvSourceDateEpoch = "SOURCE_DATE_EPOCH"
+)
+
+// runEnv holds the per-run state exposed to blocks through the environment.
+type runEnv struct {
+	workflow    Workflowname
+	id          string
+	workDir     string
+	params      map[string]string
+	determinism *Determinism
+
+	budget  *Budget
+	started time.Time
+	ctx     context.Context // Cancels block processes with the caller or once the wall time is spent
+	cancel  context.CancelFunc
+
+	results    map[Outputkey]Outputres // Outputs produced during this run
+	executions map[string]string       // Execution ID of each started block
+	artifacts  map[Outputkey]Artifact  // Identity of each output of the run
+	retries    map[string]int          // Failed executions retried per block
+	anomalies  []Anomaly               // Anomalies detected for the block being executed
+
+	logMu sync.Mutex
+	logs  []logship.Record // Block output and orchestrator lines of the run
+
+	egressMu   sync.Mutex
+	proxies    map[string]*egressProxy // Egress proxy of each restricted block
+	violations []EgressViolation
+
+	wasm WasmRuntime // Runtime of WebAssembly blocks, nil for CLIWasmRuntime
+}
+
+// newRunEnv allocates a run ID and a scratch work directory for a run.
+func newRunEnv(wfn Workflowname, opts RunOptions) (*runEnv, error) {
+	id := newID()
+
+	workDir, err := os.MkdirTemp("", "atomos-run-"+id+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	ctx, cancel := runContext(opts.Context, opts.Budget)
+
+	return &runEnv{
+		budget:      opts.Budget,
+		started:     time.Now(),
+		ctx:         ctx,
+		cancel:      cancel,
+		workflow:    wfn,
+		id:          id,
+		workDir:     workDir,
+		params:      opts.Params,
+		determinism: opts.Determinism,
+		results:     map[Outputkey]Outputres{},
+		executions:  map[string]string{},
+		artifacts:   map[Outputkey]Artifact{},
+		retries:     map[string]int{},
+		proxies:     map[string]*egressProxy{},
+	}, nil
+}
+
+// close stops the block processes still running and the egress proxies.
+func (re *runEnv) close() {
+	re.cancel()
+
+	re.egressMu.Lock()
+	defer re.egressMu.Unlock()
+	for _, proxy := range re.proxies {
+		proxy.close()
+	}
+}
+
+// startExecution allocates the execution ID of block for this run.
+func (re *runEnv) startExecution(block string) string {
+	id := newID()
+	re.executions[block] = id
+	return id
+}
+
+// recordArtifact assigns an ID to an output produced by block.
+func (re *runEnv) recordArtifact(block string, key Outputkey) {
+	re.artifacts[key] = Artifact{
+		ID:          newID(),
+		Output:      key,
+		Workflow:    re.workflow,
+		RunID:       re.id,
+		Block:       block,
+		ExecutionID: re.executions[block],
+	}
+}
+
+// outputDir returns (and creates) the directory where block writes artifacts.
+func (re *runEnv) outputDir(block string) (string, error) {
+	dir := filepath.Join(re.workDir, "outputs", block)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return dir, nil
+}
+
+// environ returns the variables describing the run to a block entry.
+func (re *runEnv) environ(block, entry string) []string {
+	env := []string{
+		EnvRunID + "=" + re.id,
+		EnvExecutionID + "=" + re.executions[block],
+		EnvBlock + "=" + block,
+		EnvEntry + "=" + entry,
+		EnvWorkDir + "=" + re.workDir,
+	}
+
+	if dir, err := re.outputDir(block); err == nil {
+		env = append(env, EnvOutputDir+"="+dir)
+	}
+
+	if re.determinism != nil {
+		env = append(env, re.determinism.environ()...)
+	}
+
+	env = append(env, re.egressEnviron(block)...)
+
+	names := make([]string, 0, len(re.params))
+	for name := range re.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, EnvParamPrefix+paramEnvName(name)+"="+re.params[name])
+	}
+
+	return env
+}
+
+// paramEnvName upper-cases a parameter name and replaces characters that are
+// not valid in environment variable names.
+func paramEnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// newInvocations describes the execution of the entry (or chain of entries)
+// attached to an edge by the block being executed. Every entry of a chain
+// runs in the block's output directory, so state written by one entry is
+// found by the next; a chain fails rather than run without it.
+func newInvocations(excArgs ExecuteArgs, binary, fromEntry string) ([]invocation, error) {
+	entries := strings.Split(fromEntry, entrySeparator)
+
+	var dir string
+	if len(entries) > 1 && excArgs.run != nil {
+		var err error
+		if dir, err = excArgs.run.outputDir(excArgs.block.Name); err != nil {
+			return nil, fmt.Errorf("failed to prepare the working directory of the chain: %w", err)
+		}
+	}
+
+	invs := make([]invocation, 0, len(entries))
+	for _, entry := range entries {
+		inv := invocation{block: excArgs.block.Name, binary: binary, entry: entry, dir: dir, run: excArgs.run, retry: excArgs.block.Retry}
+		if excArgs.run != nil {
+			inv.execID = excArgs.run.executions[inv.block]
+			inv.env = excArgs.run.environ(inv.block, entry)
+			if excArgs.metadata != nil && excArgs.metadata.Sandbox != nil {
+				inv.sandbox = excArgs.metadata.Sandbox
+				inv.env = append(inv.env, sandboxEnviron(inv.sandbox)...)
+			}
+		}
+		invs = append(invs, inv)
+	}
+
+	return invs, nil
+}
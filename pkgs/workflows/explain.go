@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/dominikbraun/graph"
+)
+
+// sideEffectTags describes the entry tags Explain recognizes. Other tags
+// are reported as they are.
+var sideEffectTags = map[string]string{
+	"network":      "reaches the network",
+	"reads_files":  "reads files",
+	"writes_files": "writes files",
+	"destructive":  "may delete or overwrite data",
+	"secrets":      "reads credentials",
+	"exec":         "runs other programs",
+	"pure":         "has no side effects",
+}
+
+// Explanation summarizes what a compiled workflow does.
+type Explanation struct {
+	Workflow       Workflowname
+	Text           string   // Deterministic summary built from the workflow and block metadata
+	Blocks         []string // Blocks in execution order
+	ExternalInputs []string // Files and artifacts read by root connections
+	SideEffects    []string
+	// Polished is Text rewritten by the ExplainPolisher, empty without one.
+	Polished string
+}
+
+// ExplainPolisher rewrites the deterministic summary, e.g. with an LLM, to
+// read better. Explain keeps the original in Explanation.Text.
+type ExplainPolisher func(text string) (string, error)
+
+// SetExplainPolisher sets the polisher used by Explain; nil disables it.
+func (wm *WorkflowManager) SetExplainPolisher(polisher ExplainPolisher) {
+	wm.explainPolisher = polisher
+}
+
+// Explain describes a compiled workflow for review before running it: the
+// blocks it uses, how data flows between them, what it reads from outside,
+// and the side effects declared by the tags of the entries it runs. The
+// summary only depends on the workflow and the installed metadata.
+func (wm *WorkflowManager) Explain(wfn Workflowname) (*Explanation, error) {
+	wm.compileMu.RLock()
+	rwf, ok := wm.definitions[wfn]
+	g := wm.workflows[wfn]
+	metadata := maps.Clone(wm.compiled[wfn])
+	wm.compileMu.RUnlock()
+	if !ok {
+		return nil, errors.New("workflow doesn't exist")
+	}
+
+	order := executionOrder(g)
+	blocks := map[string]Block{}
+	for _, block := range rwf.Blocks {
+		blocks[block.Name] = block
+	}
+
+	ex := &Explanation{Workflow: wfn, Blocks: order}
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "Workflow '%s' runs %d block(s) in this order: %s.\n", wfn, len(order), strings.Join(order, ", "))
+	if rwf.Description != "" {
+		fmt.Fprintf(&text, "%s\n", rwf.Description)
+	}
+
+	text.WriteString("\nBlocks:\n")
+	for _, name := range order {
+		text.WriteString("- " + describeBlock(blocks[name], metadata[Blockname(name)]) + "\n")
+	}
+
+	text.WriteString("\nData flow:\n")
+	step := 0
+	for _, name := range order {
+		for _, c := range rwf.Connections {
+			if c.FromBlock != name {
+				continue
+			}
+			step++
+			fmt.Fprintf(&text, "%d. %s\n", step, describeConnection(c, rwf.Connections))
+			if c.Input == "" && c.Source != "" {
+				ex.ExternalInputs = append(ex.ExternalInputs, fmt.Sprintf("%s, read by %s", describeSource(c.Source), c.FromBlock))
+			}
+		}
+	}
+
+	text.WriteString("\nExternal inputs:\n")
+	writeList(&text, ex.ExternalInputs, "none")
+
+	for _, name := range order {
+		ex.SideEffects = append(ex.SideEffects, blockSideEffects(blocks[name], metadata[Blockname(name)], rwf.Connections)...)
+	}
+	text.WriteString("\nSide effects:\n")
+	writeList(&text, ex.SideEffects, "none declared")
+
+	ex.Text = text.String()
+
+	if wm.explainPolisher != nil {
+		polished, err := wm.explainPolisher(ex.Text)
+		if err != nil {
+			fmt.Printf("Warning: failed to polish the explanation of '%s': %v\n", wfn, err)
+		} else {
+			ex.Polished = polished
+		}
+	}
+
+	return ex, nil
+}
+
+// executionOrder returns the blocks a run executes, in the order it does.
+func executionOrder(g graph.Graph[string, *Block]) []string {
+	stages, err := schedule(g)
+	if err != nil {
+		return nil
+	}
+	return slices.Concat(stages...)
+}
+
+func describeBlock(block Block, md *packagemanager.BlockMetadata) string {
+	var b strings.Builder
+	b.WriteString(block.Name)
+	if md != nil {
+		fmt.Fprintf(&b, ": %s %s from %s", md.Name, md.Version, md.SourceRepo)
+		if md.Description != "" {
+			fmt.Fprintf(&b, ", %s", strings.TrimSuffix(md.Description, "."))
+		}
+	} else {
+		fmt.Fprintf(&b, ": from %s", block.GitHub)
+	}
+	if block.Retry != nil && block.Retry.Attempts > 1 {
+		fmt.Fprintf(&b, "; failed entries are executed up to %d times", block.Retry.Attempts)
+	}
+	if block.ContinueOnError {
+		b.WriteString("; the run continues if it fails")
+	}
+	return b.String()
+}
+
+func describeConnection(c Connection, connections []Connection) string {
+	entries := connectionEntries(c)
+	what := fmt.Sprintf("entry '%s'", entries[0])
+	if len(entries) > 1 {
+		what = fmt.Sprintf("entries '%s' in sequence", strings.Join(entries, "', then '"))
+	}
+
+	var from string
+	switch {
+	case c.Input != "":
+		var producers []string
+		for _, p := range connections {
+			if p.Output == c.Input && p.FromBlock != c.FromBlock && !slices.Contains(producers, p.FromBlock) {
+				producers = append(producers, p.FromBlock)
+			}
+		}
+		if len(producers) == 0 {
+			from = fmt.Sprintf("'%s', which no connection produces", c.Input)
+		} else {
+			from = fmt.Sprintf("'%s' from %s", c.Input, strings.Join(producers, ", "))
+		}
+	case c.Source != "":
+		from = describeSource(c.Source)
+	default:
+		from = "no input"
+	}
+
+	return fmt.Sprintf("%s runs %s on %s and produces '%s'.", c.FromBlock, what, from, c.Output)
+}
+
+func describeSource(source string) string {
+	ref, ok, err := ParseArtifactRef(source)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("invalid artifact reference %s", source)
+	case !ok:
+		return "file " + source
+	case ref.RunID == LatestRun:
+		return fmt.Sprintf("artifact '%s' of the latest run of workflow '%s'", ref.Output, ref.Workflow)
+	default:
+		return fmt.Sprintf("artifact '%s' of run %s of workflow '%s'", ref.Output, ref.RunID, ref.Workflow)
+	}
+}
+
+// blockSideEffects lists the side effects declared by the entries block
+// runs, its egress restrictions, and the rest of its sandbox profile.
+func blockSideEffects(block Block, md *packagemanager.BlockMetadata, connections []Connection) []string {
+	var effects []string
+
+	var entries []string
+	for _, c := range connections {
+		if c.FromBlock != block.Name {
+			continue
+		}
+		for _, entry := range connectionEntries(c) {
+			if entry != "" && !slices.Contains(entries, entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		var tags []string
+		if md != nil {
+			tags = md.LSPEntries[entry].Tags
+		}
+		if len(tags) == 0 {
+			effects = append(effects, fmt.Sprintf("%s '%s': not declared", block.Name, entry))
+			continue
+		}
+
+		descriptions := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if d, ok := sideEffectTags[tag]; ok {
+				descriptions = append(descriptions, d)
+			} else {
+				descriptions = append(descriptions, "tagged "+tag)
+			}
+		}
+		effects = append(effects, fmt.Sprintf("%s '%s': %s", block.Name, entry, strings.Join(descriptions, ", ")))
+	}
+
+	if egress := egressPolicy(&block, md); egress != nil {
+		if len(egress.Allow) == 0 {
+			effects = append(effects, fmt.Sprintf("%s: network access denied", block.Name))
+		} else {
+			effects = append(effects, fmt.Sprintf("%s: network restricted to %s", block.Name, strings.Join(egress.Allow, ", ")))
+		}
+	}
+	if md != nil && md.Sandbox != nil {
+		if len(md.Sandbox.Writable) > 0 {
+			effects = append(effects, fmt.Sprintf("%s: writes to %s", block.Name, strings.Join(md.Sandbox.Writable, ", ")))
+		}
+		if len(md.Sandbox.Env) > 0 {
+			effects = append(effects, fmt.Sprintf("%s: reads %s from the environment", block.Name, strings.Join(md.Sandbox.Env, ", ")))
+		}
+	}
+
+	return effects
+}
+
+func writeList(b *strings.Builder, items []string, empty string) {
+	if len(items) == 0 {
+		b.WriteString("- " + empty + "\n")
+		return
+	}
+	for _, item := range items {
+		b.WriteString("- " + item + "\n")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// Find searches the installed blocks and every workflow file (*.yaml, *.yml)
+// under dir for query, case-insensitively. Matches in workflow files carry a
+// "file:line" location and are ranked with the block matches: names first,
+// then descriptions, then any other line. An empty dir searches blocks only.
+func (wm *WorkflowManager) Find(query, dir string) ([]packagemanager.Match, error) {
+	matches, err := wm.pkgmanager.Find(query)
+	if err != nil {
+		return nil, fmt.Errorf("searching installed blocks failed: %w", err)
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" || dir == "" {
+		return matches, nil
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		fileMatches, err := findInWorkflowFile(path, q)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, fileMatches...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching workflow files failed: %w", err)
+	}
+
+	packagemanager.SortMatches(matches)
+	return matches, nil
+}
+
+// findInWorkflowFile returns a match for every line of a workflow file that
+// contains q. Files that are not workflows are ignored.
+func findInWorkflowFile(path, q string) ([]packagemanager.Match, error) {
+	rwf, err := parseWorkflow(path)
+	if err != nil || rwf.Name == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var matches []packagemanager.Match
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.Contains(strings.ToLower(text), q) {
+			continue
+		}
+
+		key, value, _ := strings.Cut(strings.TrimPrefix(text, "- "), ":")
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		field, score := "line", packagemanager.ScoreOther
+		switch key {
+		case "workflow_name":
+			field, score = "name", packagemanager.ScoreName
+			if strings.ToLower(value) == q {
+				score = packagemanager.ScoreExactName
+			}
+		case "name", "from_block", "from_entry":
+			field, score = key, packagemanager.ScoreEntryName
+		case "description":
+			field, score = key, packagemanager.ScoreDescription
+		}
+
+		matches = append(matches, packagemanager.Match{
+			Kind:     packagemanager.MatchWorkflow,
+			Name:     rwf.Name,
+			Field:    field,
+			Text:     text,
+			Location: fmt.Sprintf("%s:%d", path, line),
+			Score:    score,
+		})
+	}
+
+	return matches, scanner.Err()
+}
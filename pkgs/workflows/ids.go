@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// crockford is the base32 alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	idMu      sync.Mutex
+	idLastMs  uint64
+	idLastRnd [10]byte
+)
+
+// newID returns a ULID: 26 characters encoding a millisecond timestamp and
+// 80 random bits. IDs sort lexically in creation order, also within the same
+// millisecond, so they double as stable, collision-free keys for runs, block
+// executions, and artifacts in logs, APIs, and storage paths.
+func newID() string {
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= idLastMs {
+		// Same millisecond (or a clock step back): increment the random part
+		// to stay monotonic.
+		ms = idLastMs
+		for i := len(idLastRnd) - 1; i >= 0; i-- {
+			idLastRnd[i]++
+			if idLastRnd[i] != 0 {
+				break
+			}
+		}
+	} else {
+		rand.Read(idLastRnd[:])
+	}
+	idLastMs = ms
+
+	var raw [16]byte
+	binary.BigEndian.PutUint64(raw[:8], ms<<16)
+	copy(raw[6:], idLastRnd[:])
+
+	return encodeULID(raw)
+}
+
+// encodeULID renders 128 bits as 26 Crockford base32 characters, 5 bits at a
+// time starting from the most significant (the first character carries 3).
+func encodeULID(raw [16]byte) string {
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
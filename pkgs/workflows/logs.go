@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/logship"
+)
+
+// runLogFile is the log of a run, stored with its artifacts.
+const runLogFile = "run.log"
+
+// logShipTimeout bounds a single delivery to the log sinks so a slow sink
+// cannot stall the run.
+const logShipTimeout = 10 * time.Second
+
+// AddLogSink ships the stdout and stderr of every block execution, along
+// with the orchestrator's per-block log lines, to sink. Records are labelled
+// with the workflow, run ID, block, and entry.
+func (wm *WorkflowManager) AddLogSink(sink logship.Sink) {
+	wm.logSinks = append(wm.logSinks, sink)
+}
+
+// recordExecution folds an execution into the telemetry and the CPU quota,
+// and logs its output.
+func (wm *WorkflowManager) recordExecution(inv invocation, stats execution) {
+	wm.observe(inv, stats)
+	if inv.run != nil {
+		wm.chargeCPU(inv.run.workflow, stats.cpu)
+	}
+
+	labels := inv.run.labels(inv.block)
+	labels[logship.LabelEntry] = inv.entry
+
+	now := time.Now()
+	records := append(
+		splitRecords(now, logship.Stdout, stats.stdout, labels),
+		splitRecords(now, logship.Stderr, stats.stderr, labels)...,
+	)
+	wm.emitLogs(inv.run, records)
+}
+
+// logRun logs an orchestrator line about block.
+func (wm *WorkflowManager) logRun(run *runEnv, block string, format string, args ...any) {
+	wm.emitLogs(run, []logship.Record{{
+		Time:   time.Now(),
+		Stream: logship.Orchestrator,
+		Line:   fmt.Sprintf(format, args...),
+		Labels: run.labels(block),
+	}})
+}
+
+// emitLogs keeps records for the run log stored with the artifacts of run
+// and ships them to the log sinks.
+func (wm *WorkflowManager) emitLogs(run *runEnv, records []logship.Record) {
+	if run != nil {
+		run.logMu.Lock()
+		run.logs = append(run.logs, records...)
+		run.logMu.Unlock()
+	}
+	if len(wm.logSinks) > 0 {
+		wm.shipLogs(records)
+	}
+}
+
+// saveRunLog stores the log of a run next to its artifacts, as JSON lines
+// compressed with the codec of StoreLogs.
+func (wm *WorkflowManager) saveRunLog(run *runEnv) error {
+	run.logMu.Lock()
+	defer run.logMu.Unlock()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, record := range run.logs {
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode run log: %w", err)
+		}
+	}
+
+	path := filepath.Join(wm.artifactsDir(run.workflow), run.id, runLogFile)
+	if err := compression.WriteFile(path, buf.Bytes(), wm.codecFor(StoreLogs)); err != nil {
+		return fmt.Errorf("failed to write run log: %w", err)
+	}
+	return nil
+}
+
+// RunLogs returns the block output and orchestrator lines logged by a
+// stored run of wfn, or by its most recent one for LatestRun.
+func (wm *WorkflowManager) RunLogs(wfn Workflowname, runID string) ([]logship.Record, error) {
+	runDir, err := wm.storedRunDir(wfn, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := compression.ReadFile(filepath.Join(runDir, runLogFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run log: %w", err)
+	}
+
+	var records []logship.Record
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var record logship.Record
+		if err := dec.Decode(&record); err != nil {
+			return nil, fmt.Errorf("failed to parse run log: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (wm *WorkflowManager) shipLogs(records []logship.Record) {
+	if len(records) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), logShipTimeout)
+	defer cancel()
+
+	if err := wm.logSinks.Ship(ctx, records); err != nil {
+		fmt.Printf("Warning: failed to ship logs: %v\n", err)
+	}
+}
+
+// labels identifies the run and block a record belongs to.
+func (re *runEnv) labels(block string) map[string]string {
+	labels := map[string]string{}
+	if block != "" {
+		labels[logship.LabelBlock] = block
+	}
+	if re != nil {
+		labels[logship.LabelWorkflow] = string(re.workflow)
+		labels[logship.LabelRunID] = re.id
+		if id, ok := re.executions[block]; ok {
+			labels[logship.LabelExecutionID] = id
+		}
+	}
+	return labels
+}
+
+// splitRecords turns captured output into one record per line.
+func splitRecords(t time.Time, stream logship.Stream, output string, labels map[string]string) []logship.Record {
+	var records []logship.Record
+	for line := range strings.Lines(output) {
+		records = append(records, logship.Record{
+			Time:   t,
+			Stream: stream,
+			Line:   strings.TrimRight(line, "\r\n"),
+			Labels: labels,
+		})
+	}
+	return records
+}
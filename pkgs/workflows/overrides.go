@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// overridesDirName is the scratch directory, under the install dir of the
+// workflow manager, where alternate block versions requested through
+// overrides are installed. Like the other dot directories there, it's not
+// mistaken for a block.
+const overridesDirName = ".overrides"
+
+// applyOverrides returns the block metadata to use for a run of a workflow
+// compiled against compiled. Overridden blocks get a copy of their metadata
+// pointing at the alternate binary; the compiled metadata is left untouched.
+func (wm *WorkflowManager) applyOverrides(ctx context.Context, compiled map[Blockname]*packagemanager.BlockMetadata, overrides map[Blockname]BlockOverride) (map[Blockname]*packagemanager.BlockMetadata, error) {
+	if len(overrides) == 0 {
+		return maps.Clone(compiled), nil
+	}
+
+	runMetadata := maps.Clone(compiled)
+	for name, override := range overrides {
+		original, ok := compiled[name]
+		if !ok {
+			return nil, fmt.Errorf("block '%s' is not part of the workflow", name)
+		}
+
+		overridden := *original
+		switch {
+		case override.BinaryPath != "":
+			if _, err := os.Stat(override.BinaryPath); err != nil {
+				return nil, fmt.Errorf("override binary for block '%s': %w", name, err)
+			}
+			overridden.BinaryPath = override.BinaryPath
+		case override.Version != "":
+			metadata, err := wm.installOverride(ctx, original, override.Version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to install override version '%s' of block '%s': %w", override.Version, name, err)
+			}
+			overridden = *metadata
+		default:
+			return nil, errors.New("block override needs either a binary path or a version")
+		}
+
+		runMetadata[name] = &overridden
+	}
+
+	return runMetadata, nil
+}
+
+// installOverride installs version of the block original into the scratch
+// directory. An earlier override of the block may have left another
+// version there, which is replaced.
+func (wm *WorkflowManager) installOverride(ctx context.Context, original *packagemanager.BlockMetadata, version string) (*packagemanager.BlockMetadata, error) {
+	req := packagemanager.InstallRequest{
+		Repo:    original.SourceRepo,
+		Version: version,
+		Alias:   original.Alias(),
+	}
+
+	pkgm := wm.overrideManager()
+	metadata, err := pkgm.Install(ctx, req)
+	if err != nil || sameVersion(metadata.Version, version) {
+		return metadata, err
+	}
+
+	req.Force = true
+	return pkgm.Install(ctx, req)
+}
+
+// sameVersion compares release tags, ignoring a leading "v".
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// overrideManager returns the package manager used for alternate versions,
+// creating it on first use.
+func (wm *WorkflowManager) overrideManager() *packagemanager.PackageManager {
+	wm.overrideMu.Lock()
+	defer wm.overrideMu.Unlock()
+
+	if wm.overridePkgManager == nil {
+		dir := filepath.Join(wm.pkgmanager.InstallDir, overridesDirName)
+		wm.overridePkgManager = packagemanager.NewPackageManagerWithTestDir(dir)
+	}
+	return wm.overridePkgManager
+}
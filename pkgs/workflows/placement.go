@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Placement holds the hints a block gives remote and Kubernetes executors
+// about where it should run. The local executor runs every block on the
+// current host and ignores them.
+type Placement struct {
+	// NodeSelector lists labels the worker must carry, e.g. "disk: ssd".
+	NodeSelector map[string]string `yaml:"node_selector"`
+	// GPU requires a worker with at least one GPU.
+	GPU bool `yaml:"gpu"`
+	// Region pins the block to workers in the given region.
+	Region string `yaml:"region"`
+	// Priority orders blocks competing for the same workers; higher runs first.
+	Priority int `yaml:"priority"`
+}
+
+// validate rejects hints no executor could satisfy.
+func (p *Placement) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for key := range p.NodeSelector {
+		if key == "" {
+			return errors.New("node_selector has an empty label")
+		}
+	}
+
+	if p.Priority < 0 {
+		return fmt.Errorf("priority must not be negative, got %d", p.Priority)
+	}
+
+	return nil
+}
+
+// BlockPlacement returns the placement hints of a block in a compiled
+// workflow, or nil when the block declares none.
+func (wm *WorkflowManager) BlockPlacement(wfn Workflowname, name Blockname) (*Placement, error) {
+	wm.compileMu.RLock()
+	g, ok := wm.workflows[wfn]
+	wm.compileMu.RUnlock()
+	if !ok {
+		return nil, errors.New("workflow doesn't exist")
+	}
+
+	block, err := g.Vertex(string(name))
+	if err != nil {
+		return nil, fmt.Errorf("error getting block %s: %w", name, err)
+	}
+
+	return block.Placement, nil
+}
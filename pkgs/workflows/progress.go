@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProgressLinePrefix marks a stderr line as a progress report. Blocks opt in
+// to the protocol by writing lines of the form
+//
+//	ATOMOS_PROGRESS <percent> [step description]
+//
+// to stderr. Those lines are forwarded to the registered ProgressHandler and
+// are not part of the stderr reported on failure.
+const ProgressLinePrefix = "ATOMOS_PROGRESS"
+
+// SetProgressHandler registers the handler that receives progress reported by
+// running blocks. Passing nil disables progress forwarding.
+func (wm *WorkflowManager) SetProgressHandler(handler ProgressHandler) {
+	wm.progressHandler = handler
+}
+
+// progressWriter splits a block's stderr into lines, forwarding progress lines
+// to onProgress, or dropping them when it is nil, and writing everything else
+// to out.
+type progressWriter struct {
+	out        io.Writer
+	onProgress func(percent int, step string)
+	partial    []byte
+}
+
+func newProgressWriter(out io.Writer, onProgress func(percent int, step string)) *progressWriter {
+	return &progressWriter{out: out, onProgress: onProgress}
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	pw.partial = append(pw.partial, p...)
+
+	for {
+		idx := bytes.IndexByte(pw.partial, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := pw.partial[:idx+1]
+		pw.partial = pw.partial[idx+1:]
+		if err := pw.handleLine(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// flush handles a trailing line that was not terminated by a newline.
+func (pw *progressWriter) flush() error {
+	if len(pw.partial) == 0 {
+		return nil
+	}
+
+	line := pw.partial
+	pw.partial = nil
+	return pw.handleLine(line)
+}
+
+func (pw *progressWriter) handleLine(line []byte) error {
+	if percent, step, ok := parseProgressLine(string(line)); ok {
+		if pw.onProgress != nil {
+			pw.onProgress(percent, step)
+		}
+		return nil
+	}
+
+	_, err := pw.out.Write(line)
+	return err
+}
+
+// parseProgressLine extracts the percentage and step from a progress line.
+func parseProgressLine(line string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), ProgressLinePrefix+" ")
+	if !ok {
+		return 0, "", false
+	}
+
+	percentField, step, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	percent, err := strconv.Atoi(strings.TrimSuffix(percentField, "%"))
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, "", false
+	}
+
+	return percent, strings.TrimSpace(step), true
+}
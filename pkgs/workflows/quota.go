@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrQuotaExceeded is wrapped by every error caused by a quota limit.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Workspace is the quota key for limits covering every workflow of the
+// manager rather than a single one.
+const Workspace Workflowname = ""
+
+// Quota limits the resources used by a workflow, or by the whole workspace
+// when set for Workspace. Zero fields are unlimited.
+type Quota struct {
+	MaxConcurrentRuns int     // Runs allowed in flight at the same time
+	MaxCPUSeconds     float64 // Total CPU time (user + system) of block processes
+	MaxArtifactBytes  int64   // Stored artifacts of the last runs
+	MaxInstallBytes   int64   // Disk used by installed blocks (workspace only)
+}
+
+// QuotaUsage reports current consumption against a Quota.
+type QuotaUsage struct {
+	ActiveRuns    int     `json:"active_runs"`
+	CPUSeconds    float64 `json:"cpu_seconds"`
+	ArtifactBytes int64   `json:"artifact_bytes"`
+	InstallBytes  int64   `json:"install_bytes,omitempty"`
+}
+
+const quotaFileName = "quota_usage.json"
+
+// SetQuota sets the limits of a workflow, or of the workspace for Workspace.
+// Runs beyond a limit are rejected with an error wrapping ErrQuotaExceeded.
+func (wm *WorkflowManager) SetQuota(wfn Workflowname, quota Quota) {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	if wm.quotas == nil {
+		wm.quotas = map[Workflowname]Quota{}
+	}
+	wm.quotas[wfn] = quota
+}
+
+// QuotaUsage returns the resources currently used by a workflow, or by the
+// whole workspace for Workspace.
+func (wm *WorkflowManager) QuotaUsage(wfn Workflowname) (QuotaUsage, error) {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	return wm.usageLocked(wfn)
+}
+
+// ResetCPUUsage clears the CPU time accumulated by a workflow, or by every
+// workflow for Workspace, e.g. at the start of a billing period.
+func (wm *WorkflowManager) ResetCPUUsage(wfn Workflowname) error {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	wm.loadCPUUsageLocked()
+	if wfn == Workspace {
+		clear(wm.cpuUsage)
+	} else {
+		delete(wm.cpuUsage, wfn)
+	}
+
+	return wm.saveCPUUsageLocked()
+}
+
+// beginRun admits a run of wfn, checking the concurrency, CPU, and artifact
+// quotas. The returned function must be called when the run ends.
+func (wm *WorkflowManager) beginRun(wfn Workflowname) (func(), error) {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	for _, key := range []Workflowname{wfn, Workspace} {
+		quota, ok := wm.quotas[key]
+		if !ok {
+			continue
+		}
+
+		usage, err := wm.usageLocked(key)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case quota.MaxConcurrentRuns > 0 && usage.ActiveRuns >= quota.MaxConcurrentRuns:
+			return nil, quotaError(key, "concurrent runs", float64(usage.ActiveRuns), float64(quota.MaxConcurrentRuns))
+		case quota.MaxCPUSeconds > 0 && usage.CPUSeconds >= quota.MaxCPUSeconds:
+			return nil, quotaError(key, "CPU seconds", usage.CPUSeconds, quota.MaxCPUSeconds)
+		case quota.MaxArtifactBytes > 0 && usage.ArtifactBytes >= quota.MaxArtifactBytes:
+			return nil, quotaError(key, "artifact bytes", float64(usage.ArtifactBytes), float64(quota.MaxArtifactBytes))
+		}
+	}
+
+	if wm.activeRuns == nil {
+		wm.activeRuns = map[Workflowname]int{}
+	}
+	wm.activeRuns[wfn]++
+
+	return func() {
+		wm.quotaMu.Lock()
+		defer wm.quotaMu.Unlock()
+
+		wm.activeRuns[wfn]--
+		if err := wm.saveCPUUsageLocked(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}, nil
+}
+
+// checkCPUQuota stops a run between blocks once it has used up its CPU time.
+func (wm *WorkflowManager) checkCPUQuota(wfn Workflowname) error {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	for _, key := range []Workflowname{wfn, Workspace} {
+		quota, ok := wm.quotas[key]
+		if !ok || quota.MaxCPUSeconds <= 0 {
+			continue
+		}
+
+		if used := wm.cpuSecondsLocked(key); used >= quota.MaxCPUSeconds {
+			return quotaError(key, "CPU seconds", used, quota.MaxCPUSeconds)
+		}
+	}
+
+	return nil
+}
+
+// checkInstallQuota enforces the workspace limit on installed-block disk.
+func (wm *WorkflowManager) checkInstallQuota() error {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	quota, ok := wm.quotas[Workspace]
+	if !ok || quota.MaxInstallBytes <= 0 {
+		return nil
+	}
+
+	used, err := wm.installBytes()
+	if err != nil {
+		return err
+	}
+	if used > quota.MaxInstallBytes {
+		return quotaError(Workspace, "installed block bytes", float64(used), float64(quota.MaxInstallBytes))
+	}
+
+	return nil
+}
+
+// chargeCPU adds the CPU time of an execution to its workflow.
+func (wm *WorkflowManager) chargeCPU(wfn Workflowname, cpu time.Duration) {
+	wm.quotaMu.Lock()
+	defer wm.quotaMu.Unlock()
+
+	wm.loadCPUUsageLocked()
+	wm.cpuUsage[wfn] += cpu.Seconds()
+}
+
+func quotaError(wfn Workflowname, resource string, used, limit float64) error {
+	scope := "workspace"
+	if wfn != Workspace {
+		scope = fmt.Sprintf("workflow '%s'", wfn)
+	}
+	return fmt.Errorf("%w: %s uses %g %s, limit is %g", ErrQuotaExceeded, scope, used, resource, limit)
+}
+
+func (wm *WorkflowManager) usageLocked(wfn Workflowname) (QuotaUsage, error) {
+	var usage QuotaUsage
+	var err error
+
+	usage.CPUSeconds = wm.cpuSecondsLocked(wfn)
+
+	if wfn == Workspace {
+		for _, n := range wm.activeRuns {
+			usage.ActiveRuns += n
+		}
+		if usage.ArtifactBytes, err = dirSize(filepath.Join(wm.pkgmanager.InstallDir, runsDirName)); err != nil {
+			return usage, err
+		}
+		if usage.InstallBytes, err = wm.installBytes(); err != nil {
+			return usage, err
+		}
+		return usage, nil
+	}
+
+	usage.ActiveRuns = wm.activeRuns[wfn]
+	if usage.ArtifactBytes, err = dirSize(wm.artifactsDir(wfn)); err != nil {
+		return usage, err
+	}
+	return usage, nil
+}
+
+func (wm *WorkflowManager) cpuSecondsLocked(wfn Workflowname) float64 {
+	wm.loadCPUUsageLocked()
+	if wfn != Workspace {
+		return wm.cpuUsage[wfn]
+	}
+
+	var total float64
+	for _, seconds := range wm.cpuUsage {
+		total += seconds
+	}
+	return total
+}
+
+// installBytes sums the size of every block directory in the install dir,
+// leaving out the run artifacts and workflow state files.
+func (wm *WorkflowManager) installBytes() (int64, error) {
+	entries, err := os.ReadDir(wm.pkgmanager.InstallDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read install directory: %w", err)
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == runsDirName {
+			continue
+		}
+		size, err := dirSize(filepath.Join(wm.pkgmanager.InstallDir, entry.Name()))
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", dir, err)
+	}
+	return total, nil
+}
+
+func (wm *WorkflowManager) quotaPath() string {
+	return filepath.Join(wm.pkgmanager.InstallDir, quotaFileName)
+}
+
+// loadCPUUsageLocked reads the persisted CPU usage the first time it is needed.
+func (wm *WorkflowManager) loadCPUUsageLocked() {
+	if wm.cpuUsage != nil {
+		return
+	}
+
+	wm.cpuUsage = map[Workflowname]float64{}
+	data, err := os.ReadFile(wm.quotaPath())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &wm.cpuUsage); err != nil {
+		fmt.Printf("Warning: Failed to parse quota usage: %v\n", err)
+		wm.cpuUsage = map[Workflowname]float64{}
+	}
+}
+
+func (wm *WorkflowManager) saveCPUUsageLocked() error {
+	if wm.cpuUsage == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(wm.cpuUsage)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota usage: %w", err)
+	}
+	if err := os.WriteFile(wm.quotaPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write quota usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dominikbraun/graph"
+)
+
+// BlockStatus is the outcome of a block within a run.
+type BlockStatus string
+
+const (
+	BlockSucceeded BlockStatus = "succeeded"
+	BlockFailed    BlockStatus = "failed"
+	BlockSkipped   BlockStatus = "skipped"
+	// BlockBudgetExceeded marks the block at which the run budget ran out.
+	BlockBudgetExceeded BlockStatus = "budget_exceeded"
+)
+
+// AbsentInput is the value piped into a fan-in block for every input whose
+// producer failed or was skipped, so the block can tell a missing input apart
+// from an empty one.
+const AbsentInput Outputres = "ATOMOS_ABSENT_INPUT"
+
+// BlockResult describes what happened to a single block during a run. For
+// skipped blocks, Reason chains the reasons of the upstream blocks.
+type BlockResult struct {
+	Block       string
+	ExecutionID string // Empty for blocks that never started
+	Status      BlockStatus
+	Reason      string
+	Err         error
+	Anomalies   []Anomaly // Executions that deviated from their telemetry baseline
+	Retries     int       // Failed executions that the block's retry policy re-ran
+}
+
+// RunResult is the structured outcome of a workflow run.
+type RunResult struct {
+	Workflow  Workflowname
+	RunID     string
+	WorkDir   string // Scratch directory shared by the blocks of the run
+	Blocks    map[string]*BlockResult
+	Order     []string   // Blocks in the order they were settled
+	Artifacts []Artifact // Outputs stored by the run, sorted by ID
+
+	// Stages groups the blocks of the workflow by depth, in the order they
+	// are scheduled: a block only consumes outputs of earlier stages, so the
+	// blocks of a stage don't depend on each other.
+	Stages [][]string
+
+	// OutputMismatches lists outputs whose hash differs from the previous
+	// run when running in deterministic mode with VerifyOutputs.
+	OutputMismatches []OutputMismatch
+
+	// BudgetExceeded is set when the run was stopped by RunOptions.Budget.
+	BudgetExceeded bool
+
+	// EgressViolations lists the connections blocks attempted to hosts
+	// outside their egress allowlist.
+	EgressViolations []EgressViolation
+
+	executions map[string]string // Execution ID of each started block
+}
+
+func newRunResult(wfn Workflowname, run *runEnv) *RunResult {
+	return &RunResult{
+		Workflow: wfn,
+		RunID:    run.id,
+		WorkDir:  run.workDir,
+		Blocks:   map[string]*BlockResult{},
+
+		executions: run.executions,
+	}
+}
+
+func (rr *RunResult) record(block string, status BlockStatus, reason string, err error) *BlockResult {
+	br := &BlockResult{Block: block, ExecutionID: rr.executions[block], Status: status, Reason: reason, Err: err}
+	rr.Blocks[block] = br
+	rr.Order = append(rr.Order, block)
+	return br
+}
+
+// Anomalies returns every anomaly flagged during the run.
+func (rr *RunResult) Anomalies() []Anomaly {
+	var anomalies []Anomaly
+	for _, name := range rr.Order {
+		anomalies = append(anomalies, rr.Blocks[name].Anomalies...)
+	}
+	return anomalies
+}
+
+// Status returns the status of block, and false when it was never reached.
+func (rr *RunResult) Status(block string) (BlockStatus, bool) {
+	br, ok := rr.Blocks[block]
+	if !ok {
+		return "", false
+	}
+	return br.Status, true
+}
+
+// upstreamSkipReason reports whether a block must be skipped because none of
+// its upstream blocks produced output, along with the chained reason.
+func (rr *RunResult) upstreamSkipReason(upstream []string) (string, bool) {
+	if len(upstream) == 0 {
+		return "", false
+	}
+
+	var reasons []string
+	for _, name := range upstream {
+		br, ok := rr.Blocks[name]
+		if !ok || br.Status == BlockSucceeded {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("upstream '%s' %s: %s", name, br.Status, br.Reason))
+	}
+
+	return strings.Join(reasons, "; "), true
+}
+
+// markAbsentInputs replaces the inputs coming from failed or skipped upstream
+// blocks with AbsentInput.
+func (wm *WorkflowManager) markAbsentInputs(rr *RunResult, results map[Outputkey]Outputres, incoming []graph.Edge[string], fromBlocks []string) {
+	for i, edge := range incoming {
+		br, ok := rr.Blocks[fromBlocks[i]]
+		if !ok || br.Status == BlockSucceeded {
+			continue
+		}
+		results[Outputkey(edge.Properties.Attributes["input"])] = AbsentInput
+	}
+}
+
+// exceedBudget settles block as the one at which the budget ran out and
+// marks the run as stopped by its budget.
+func (rr *RunResult) exceedBudget(block string, err error) error {
+	br := rr.record(block, BlockBudgetExceeded, err.Error(), err)
+	rr.BudgetExceeded = true
+	return fmt.Errorf("run stopped at block %s: %w", br.Block, err)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// maxRetryAttempts bounds Retry.Attempts, so a typo can't keep a failing
+	// block running for hours.
+	maxRetryAttempts = 20
+	// maxRetryDelay caps the doubled backoff between two executions.
+	maxRetryDelay = 10 * time.Minute
+)
+
+// Retry re-executes a block's entries when they fail, for binaries that
+// depend on flaky networks or APIs:
+//
+//	retry:
+//	  attempts: 3
+//	  backoff: 2s
+//	  retry_on_exit_codes: [75]
+type Retry struct {
+	// Attempts is the maximum number of executions of each entry, the
+	// first one included, at most 20; 0 and 1 never retry.
+	Attempts int `yaml:"attempts"`
+	// Backoff is the delay before the first retry, doubled before each
+	// following one up to 10 minutes.
+	Backoff time.Duration `yaml:"backoff"`
+	// RetryOnExitCodes limits retries to failures with one of these exit
+	// codes; any failure is retried when empty.
+	RetryOnExitCodes []int `yaml:"retry_on_exit_codes"`
+}
+
+// validate rejects policies that can't be applied.
+func (r *Retry) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Attempts < 0 {
+		return fmt.Errorf("attempts must not be negative, got %d", r.Attempts)
+	}
+	if r.Attempts > maxRetryAttempts {
+		return fmt.Errorf("attempts must be at most %d, got %d", maxRetryAttempts, r.Attempts)
+	}
+	if r.Backoff < 0 {
+		return fmt.Errorf("backoff must not be negative, got %s", r.Backoff)
+	}
+	if slices.Contains(r.RetryOnExitCodes, 0) {
+		return errors.New("retry_on_exit_codes can't contain 0, which means success")
+	}
+
+	return nil
+}
+
+// retries reports whether the failed execution number attempt, counted from
+// 1, is retried given the exit code it ended with.
+func (r *Retry) retries(attempt, exitCode int) bool {
+	if r == nil || attempt >= r.Attempts {
+		return false
+	}
+	return len(r.RetryOnExitCodes) == 0 || slices.Contains(r.RetryOnExitCodes, exitCode)
+}
+
+// delay returns how long to wait before retrying the failed execution
+// number attempt, capped at maxRetryDelay.
+func (r *Retry) delay(attempt int) time.Duration {
+	delay := r.Backoff
+	for range attempt - 1 {
+		if delay >= maxRetryDelay/2 {
+			return maxRetryDelay
+		}
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// runRetried runs an invocation through execute, executing it again as its
+// block's retry policy allows while it fails. Every execution is recorded.
+func (wm *WorkflowManager) runRetried(inv invocation, execute func() (string, execution, error)) (string, error) {
+	ctx := context.Background()
+	if inv.run != nil {
+		ctx = inv.run.ctx
+	}
+
+	for attempt := 1; ; attempt++ {
+		output, stats, err := execute()
+		wm.recordExecution(inv, stats)
+		if err == nil || !inv.retry.retries(attempt, stats.exitCode) {
+			return output, err
+		}
+
+		delay := inv.retry.delay(attempt)
+		if inv.run != nil {
+			inv.run.retries[inv.block]++
+			wm.logRun(inv.run, inv.block, "block %s entry %s failed with exit code %d (attempt %d of %d), retrying in %s",
+				inv.block, inv.entry, stats.exitCode, attempt, inv.retry.Attempts, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", err
+		case <-timer.C:
+		}
+	}
+}
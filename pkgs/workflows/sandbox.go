@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+)
+
+// EnvWritable lists the writable paths of a sandboxed block's profile,
+// expanded and separated by os.PathListSeparator.
+const EnvWritable = "ATOMOS_WRITABLE"
+
+// sandboxBaseEnv are the variables of the orchestrator's environment every
+// sandboxed process keeps, on top of those its profile declares, so it can
+// still find programs and format text.
+var sandboxBaseEnv = []string{"PATH", "LANG", "LC_ALL", "TZ", "USER", "SYSTEMROOT", "WINDIR", "COMSPEC", "PATHEXT"}
+
+// egressPolicy returns the egress policy a block runs under: the workflow's
+// when it sets one, else the network access declared by the block's sandbox
+// profile. nil means unrestricted.
+func egressPolicy(block *Block, md *packagemanager.BlockMetadata) *Egress {
+	if block.Egress != nil {
+		return block.Egress
+	}
+	if md == nil || md.Sandbox == nil || md.Sandbox.AnyNetwork() {
+		return nil
+	}
+	return &Egress{Allow: md.Sandbox.Network}
+}
+
+// sandboxEnviron returns the variables a sandboxed block receives from the
+// orchestrator's environment: those its profile declares and that are set,
+// and its expanded writable paths. Container and WebAssembly blocks get
+// only these.
+func sandboxEnviron(sandbox *packagemanager.Sandbox) []string {
+	var env []string
+	for _, name := range sandbox.Env {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	if paths := writablePaths(sandbox); len(paths) > 0 {
+		env = append(env, EnvWritable+"="+strings.Join(paths, string(os.PathListSeparator)))
+	}
+	return env
+}
+
+// sandboxProcessEnv returns the base environment of a sandboxed native
+// process, which replaces the orchestrator's: the sandboxBaseEnv variables,
+// and temp and home directories of its own under the run's work directory.
+// The real home directory is kept when the profile lets the block write
+// under it.
+func (re *runEnv) sandboxProcessEnv(block string, sandbox *packagemanager.Sandbox) []string {
+	var env []string
+	for _, name := range sandboxBaseEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	scratch := filepath.Join(re.workDir, "sandbox", block)
+	tmp, home := filepath.Join(scratch, "tmp"), filepath.Join(scratch, "home")
+	if realHome, err := os.UserHomeDir(); err == nil && slices.ContainsFunc(writablePaths(sandbox), func(path string) bool {
+		return isWithin(path, realHome)
+	}) {
+		home = realHome
+	}
+	for _, dir := range []string{tmp, home} {
+		_ = os.MkdirAll(dir, 0700)
+	}
+
+	return append(env, "TMPDIR="+tmp, "TMP="+tmp, "TEMP="+tmp, "HOME="+home, "USERPROFILE="+home)
+}
+
+// writablePaths returns the writable paths of a profile with a leading "~"
+// expanded to the home directory.
+func writablePaths(sandbox *packagemanager.Sandbox) []string {
+	home, _ := os.UserHomeDir()
+	paths := make([]string, 0, len(sandbox.Writable))
+	for _, path := range sandbox.Writable {
+		if rest, ok := strings.CutPrefix(path, "~"); ok && home != "" {
+			path = filepath.Join(home, rest)
+		}
+		paths = append(paths, filepath.Clean(path))
+	}
+	return paths
+}
+
+// isWithin reports whether path is dir or lies under it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
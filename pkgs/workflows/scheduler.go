@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/dominikbraun/graph"
+)
+
+// schedule orders the blocks of a workflow for a run. Blocks are grouped
+// into stages: the first holds the blocks without upstream blocks, and each
+// later one the blocks whose upstream blocks all belong to earlier stages.
+// A block therefore only runs once every output it consumes is available,
+// however many paths lead to it. Each stage is sorted so runs of the same
+// workflow always execute in the same order.
+func schedule(g graph.Graph[string, *Block]) ([][]string, error) {
+	predecessors, err := g.PredecessorMap()
+	if err != nil {
+		return nil, err
+	}
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	waiting := make(map[string]int, len(predecessors))
+	var ready []string
+	for name, upstream := range predecessors {
+		waiting[name] = len(upstream)
+		if len(upstream) == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	var stages [][]string
+	scheduled := 0
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		stages = append(stages, ready)
+		scheduled += len(ready)
+
+		var next []string
+		for _, name := range ready {
+			for target := range adjacencyMap[name] {
+				waiting[target]--
+				if waiting[target] == 0 {
+					next = append(next, target)
+				}
+			}
+		}
+		ready = next
+	}
+
+	if scheduled != len(waiting) {
+		return nil, errors.New("workflow has a cycle")
+	}
+	return stages, nil
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+)
+
+const (
+	telemetryFileName = "telemetry.json"
+
+	// minBaselineSamples is the number of executions needed before an entry's
+	// baseline is trusted enough to flag anomalies.
+	minBaselineSamples = 3
+	// slowdownFactor flags executions this many times slower than the baseline.
+	slowdownFactor = 10
+)
+
+// AnomalyKind classifies an execution that deviates from its baseline.
+type AnomalyKind string
+
+const (
+	AnomalySlow        AnomalyKind = "slow"
+	AnomalyExitCode    AnomalyKind = "unexpected_exit_code"
+	AnomalyEmptyOutput AnomalyKind = "empty_output"
+)
+
+// Anomaly describes an execution of a block entry that deviates from the
+// baseline recorded across previous runs.
+type Anomaly struct {
+	Block       string
+	ExecutionID string
+	Entry       string
+	Kind        AnomalyKind
+	Message     string
+}
+
+// execution holds the measurements of a single binary execution.
+type execution struct {
+	duration   time.Duration
+	exitCode   int
+	outputSize int
+	cpu        time.Duration // User plus system time of the process
+
+	stdout, stderr string // Captured output, shipped to the log sinks
+}
+
+// entryBaseline aggregates the executions of one block entry.
+type entryBaseline struct {
+	Samples       int           `json:"samples"`
+	TotalDuration time.Duration `json:"total_duration"`
+	TotalOutput   int64         `json:"total_output"`
+	ExitCodes     []int         `json:"exit_codes"`
+}
+
+func (eb *entryBaseline) meanDuration() time.Duration {
+	return eb.TotalDuration / time.Duration(eb.Samples)
+}
+
+func (eb *entryBaseline) meanOutput() int64 {
+	return eb.TotalOutput / int64(eb.Samples)
+}
+
+// observe compares an execution against its baseline, queues any anomaly for
+// the block result on the run, and folds the execution into the baseline.
+func (wm *WorkflowManager) observe(inv invocation, stats execution) {
+	wm.stateMu.Lock()
+	defer wm.stateMu.Unlock()
+
+	if wm.telemetry == nil {
+		wm.telemetry = wm.loadTelemetry()
+	}
+
+	key := inv.block + "/" + inv.entry
+	baseline, ok := wm.telemetry[key]
+	if !ok {
+		baseline = &entryBaseline{}
+		wm.telemetry[key] = baseline
+	}
+
+	if baseline.Samples >= minBaselineSamples && inv.run != nil {
+		inv.run.anomalies = append(inv.run.anomalies, detectAnomalies(inv, stats, baseline)...)
+	}
+
+	baseline.Samples++
+	baseline.TotalDuration += stats.duration
+	baseline.TotalOutput += int64(stats.outputSize)
+	if !slices.Contains(baseline.ExitCodes, stats.exitCode) {
+		baseline.ExitCodes = append(baseline.ExitCodes, stats.exitCode)
+	}
+}
+
+func detectAnomalies(inv invocation, stats execution, baseline *entryBaseline) []Anomaly {
+	var anomalies []Anomaly
+	add := func(kind AnomalyKind, format string, args ...any) {
+		anomalies = append(anomalies, Anomaly{Block: inv.block, ExecutionID: inv.execID, Entry: inv.entry, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if mean := baseline.meanDuration(); mean > 0 && stats.duration > mean*slowdownFactor {
+		add(AnomalySlow, "took %s, baseline is %s", stats.duration, mean)
+	}
+	if !slices.Contains(baseline.ExitCodes, stats.exitCode) {
+		add(AnomalyExitCode, "exited with %d, baseline exit codes are %v", stats.exitCode, baseline.ExitCodes)
+	}
+	if stats.outputSize == 0 && baseline.meanOutput() > 0 {
+		add(AnomalyEmptyOutput, "produced no output, baseline is %d bytes", baseline.meanOutput())
+	}
+
+	return anomalies
+}
+
+// takeAnomalies returns and clears the anomalies queued since the last call.
+func (re *runEnv) takeAnomalies() []Anomaly {
+	anomalies := re.anomalies
+	re.anomalies = nil
+	return anomalies
+}
+
+func (wm *WorkflowManager) telemetryPath() string {
+	return filepath.Join(wm.pkgmanager.InstallDir, telemetryFileName)
+}
+
+// loadTelemetry reads the persisted baselines, starting fresh when there are
+// none or they can't be read.
+func (wm *WorkflowManager) loadTelemetry() map[string]*entryBaseline {
+	telemetry := map[string]*entryBaseline{}
+
+	data, err := compression.ReadFile(wm.telemetryPath())
+	if err != nil {
+		return telemetry
+	}
+	if err := json.Unmarshal(data, &telemetry); err != nil {
+		fmt.Printf("Warning: Failed to parse telemetry baselines: %v\n", err)
+		return map[string]*entryBaseline{}
+	}
+
+	return telemetry
+}
+
+// saveTelemetry persists the baselines so they carry over across processes.
+func (wm *WorkflowManager) saveTelemetry() error {
+	wm.stateMu.Lock()
+	defer wm.stateMu.Unlock()
+
+	if wm.telemetry == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(wm.telemetry)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	if err := compression.WriteFile(wm.telemetryPath(), data, wm.codecFor(StoreTelemetry)); err != nil {
+		return fmt.Errorf("failed to write telemetry: %w", err)
+	}
+
+	return nil
+}
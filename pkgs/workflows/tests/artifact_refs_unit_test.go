@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/logship"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeChainedWorkflow writes a workflow named "chained" whose root reads
+// source, and returns its path.
+func writeChainedWorkflow(t *testing.T, source string) string {
+	t.Helper()
+
+	block := writeLocalBlock(t, "upper", "  - name: run\n")
+	path := filepath.Join(t.TempDir(), "chained.yaml")
+	workflow := fmt.Sprintf(`workflow_name: chained
+blocks:
+  - name: reader
+    github: %q
+  - name: writer
+    github: %q
+connections:
+  - from_block: reader
+    from_entry: run
+    output: copied
+    source: %q
+  - from_block: writer
+    from_entry: run
+    input: copied
+    output: written
+`, block, block, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path
+}
+
+func TestArtifactRefSources(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	wm := workflows.NewWorkflowManager(root)
+	if err := wm.CompileWorkflow(t.Context(), writeEchoWorkflow(t)); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	upstream, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("upstream run failed: %v", err)
+	}
+
+	for _, runID := range []string{upstream.RunID, workflows.LatestRun} {
+		ref := workflows.ArtifactRef{Workflow: "ids", RunID: runID, Output: "greeting"}
+
+		// A separate manager sharing the install dir sees the same store.
+		downstream := workflows.NewWorkflowManager(root)
+		if err := downstream.CompileWorkflow(t.Context(), writeChainedWorkflow(t, ref.String())); err != nil {
+			t.Fatalf("CompileWorkflow failed: %v", err)
+		}
+		if _, err := downstream.RunWorkFlowWithOptions("chained", workflows.RunOptions{}); err != nil {
+			t.Fatalf("downstream run with %s failed: %v", ref, err)
+		}
+
+		data, _, err := downstream.LoadArtifact(workflows.ArtifactRef{Workflow: "chained", RunID: workflows.LatestRun, Output: "copied"})
+		if err != nil {
+			t.Fatalf("LoadArtifact failed: %v", err)
+		}
+		if data != "hello\n" {
+			t.Errorf("expected the upstream greeting to be copied, got %q", data)
+		}
+	}
+
+	// The upstream run is still stored after the downstream ran.
+	if _, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "ids", RunID: upstream.RunID, Output: "greeting"}); err != nil {
+		t.Errorf("expected upstream artifact to be retained, got %v", err)
+	}
+
+	missing := workflows.NewWorkflowManager(root)
+	if err := missing.CompileWorkflow(t.Context(), writeChainedWorkflow(t, "artifact://ids/01ARZ3NDEKTSV4RRFFQ69G5FAV/greeting")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	if _, err := missing.RunWorkFlowWithOptions("chained", workflows.RunOptions{}); !errors.Is(err, workflows.ErrArtifactNotFound) {
+		t.Errorf("expected ErrArtifactNotFound for an unknown run, got %v", err)
+	}
+}
+
+func TestArtifactRetention(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	wm.SetArtifactRetention(2)
+	if err := wm.CompileWorkflow(t.Context(), writeEchoWorkflow(t)); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	var runIDs []string
+	for range 3 {
+		result, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		runIDs = append(runIDs, result.RunID)
+	}
+
+	if _, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "ids", RunID: runIDs[0], Output: "greeting"}); !errors.Is(err, workflows.ErrArtifactNotFound) {
+		t.Errorf("expected the oldest run to be pruned, got %v", err)
+	}
+	for _, runID := range runIDs[1:] {
+		if _, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "ids", RunID: runID, Output: "greeting"}); err != nil {
+			t.Errorf("expected run %s to be retained, got %v", runID, err)
+		}
+	}
+}
+
+func TestLintInvalidArtifactRef(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	diags, err := wm.LintWorkflow(writeChainedWorkflow(t, "artifact://ids/greeting"))
+	if err != nil {
+		t.Fatalf("LintWorkflow failed: %v", err)
+	}
+
+	for _, d := range diags {
+		if d.Code == workflows.CodeInvalidArtifactRef && d.Severity == workflows.SeverityError {
+			return
+		}
+	}
+	t.Errorf("expected an %s diagnostic, got %v", workflows.CodeInvalidArtifactRef, diags)
+}
+
+func TestUncompressedArtifactsKeepCompressedData(t *testing.T) {
+	t.Parallel()
+
+	gzipped, err := compression.Gzip.Compress([]byte("archive contents"))
+	if err != nil {
+		t.Fatalf("gzip failed: %v", err)
+	}
+	source := filepath.Join(t.TempDir(), "archive.tar.gz")
+	if err := os.WriteFile(source, gzipped, 0644); err != nil {
+		t.Fatalf("Failed to write archive: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	wm.SetCompression(workflows.StoreArtifacts, compression.None)
+	if err := wm.CompileWorkflow(t.Context(), writeChainedWorkflow(t, source)); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	if _, err := wm.RunWorkFlowWithOptions("chained", workflows.RunOptions{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "chained", RunID: workflows.LatestRun, Output: "copied"})
+	if err != nil {
+		t.Fatalf("LoadArtifact failed: %v", err)
+	}
+	if string(data) != string(gzipped) {
+		t.Errorf("expected the gzip archive back unchanged, got %q", data)
+	}
+}
+
+func TestRunLogs(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writeEchoWorkflow(t)); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	for _, runID := range []string{result.RunID, workflows.LatestRun} {
+		records, err := wm.RunLogs("ids", runID)
+		if err != nil {
+			t.Fatalf("RunLogs(%s) failed: %v", runID, err)
+		}
+		blocks := map[string]bool{}
+		for _, record := range records {
+			if record.Labels[logship.LabelRunID] != result.RunID {
+				t.Errorf("expected run_id %s, got %v", result.RunID, record.Labels)
+			}
+			blocks[record.Labels[logship.LabelBlock]] = true
+		}
+		if !blocks["first"] || !blocks["second"] {
+			t.Errorf("expected logs of both blocks, got %v", records)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeLocalBlock lays out a block on disk that can be installed via file://.
+func writeLocalBlock(t *testing.T, name, entries string) string {
+	t.Helper()
+	return writeScriptBlock(t, name, "#!/bin/sh\ncat\n", entries)
+}
+
+// writeScriptBlock is writeLocalBlock with script as the block's binary.
+func writeScriptBlock(t *testing.T, name, script, entries string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+
+	manifest := fmt.Sprintf("name: %s\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: %s\nentries:\n%s",
+		name, runtime.GOOS, runtime.GOARCH, name, entries)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestSuggestConnections(t *testing.T) {
+	t.Parallel()
+
+	profiler := writeLocalBlock(t, "profiler", `  - name: run
+    inputs:
+      - {name: target, type: path}
+    outputs:
+      - {name: profile, type: file}
+`)
+	reporter := writeLocalBlock(t, "reporter", `  - name: report
+    inputs:
+      - {name: profile, type: file}
+    outputs:
+      - {name: summary, type: string}
+  - name: lint
+    inputs:
+      - {name: config, type: yaml}
+`)
+
+	root := t.TempDir()
+	pkgm := packagemanager.NewPackageManagerWithTestDir(root)
+	for _, repo := range []string{profiler, reporter} {
+		if _, err := pkgm.Install(t.Context(), packagemanager.InstallRequest{Repo: repo}); err != nil {
+			t.Fatalf("pkgm.Install() failed: %s", err)
+		}
+	}
+
+	workflow := fmt.Sprintf(`workflow_name: partial
+blocks:
+  - name: profiler
+    github: %q
+  - name: reporter
+    github: %q
+connections:
+  - from_block: profiler
+    from_entry: run
+    output: cpu_profile
+    source: ./main.go
+  - from_block: reporter
+    from_entry: report
+    output: summary
+`, profiler, reporter)
+	path := filepath.Join(t.TempDir(), "partial.yaml")
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(root)
+	suggestions, err := wm.SuggestConnections(path, nil)
+	if err != nil {
+		t.Fatalf("SuggestConnections failed: %v", err)
+	}
+
+	if len(suggestions) != 1 {
+		t.Fatalf("expected 1 suggestion, got %d: %+v", len(suggestions), suggestions)
+	}
+
+	s := suggestions[0]
+	if s.Replaces != 1 || s.Connection.FromBlock != "reporter" || s.Connection.Input != "cpu_profile" {
+		t.Errorf("expected reporter to take cpu_profile in place of connection 1, got %+v", s)
+	}
+	if s.Input != "profile" || s.Output != "profile" || s.FromBlock != "profiler" {
+		t.Errorf("expected profile <- profiler.profile, got %+v", s)
+	}
+
+	none, err := wm.SuggestConnections(path, []workflows.Blockname{"reporter"})
+	if err != nil {
+		t.Fatalf("SuggestConnections failed: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("expected no suggestions without the profiler selected, got %+v", none)
+	}
+}
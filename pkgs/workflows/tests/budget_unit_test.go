@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestRunBudgets(t *testing.T) {
+	t.Parallel()
+
+	path := writeEchoWorkflow(t)
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		budget  workflows.Budget
+		stopped string // Block settled as budget_exceeded
+	}{
+		{"executions", workflows.Budget{MaxBlockExecutions: 1}, "second"},
+		{"artifacts", workflows.Budget{MaxArtifactBytes: 1}, "first"},
+		{"wall time", workflows.Budget{MaxWallTime: 1}, "first"},
+	}
+	for _, tc := range cases {
+		result, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{Budget: &tc.budget})
+		if !errors.Is(err, workflows.ErrBudgetExceeded) {
+			t.Fatalf("%s: expected ErrBudgetExceeded, got %v", tc.name, err)
+		}
+		if !result.BudgetExceeded {
+			t.Errorf("%s: expected the run to be marked as stopped by its budget", tc.name)
+		}
+		if status, _ := result.Status(tc.stopped); status != workflows.BlockBudgetExceeded {
+			t.Errorf("%s: expected block %s to be %s, got %s", tc.name, tc.stopped, workflows.BlockBudgetExceeded, status)
+		}
+	}
+
+	if _, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{Budget: &workflows.Budget{MaxBlockExecutions: 2}}); err != nil {
+		t.Errorf("expected the run to fit its budget, got %v", err)
+	}
+}
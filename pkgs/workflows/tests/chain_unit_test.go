@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// profilerScript writes a profile to the working directory on "run", and
+// prints it along with the name of the working directory on "report".
+const profilerScript = `#!/bin/sh
+case "$1" in
+run) cat > /dev/null; echo profiled > profile.txt; echo recorded ;;
+report) cat > /dev/null; echo "$(cat profile.txt) $(basename "$PWD")" ;;
+esac
+`
+
+func TestChainedEntriesShareWorkingDirectory(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test block is a shell script")
+	}
+
+	blockDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blockDir, "prof"), []byte(profilerScript), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: prof\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: prof\nentries:\n  - name: run\n  - name: report\n",
+		runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(blockDir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("samples\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+	path := filepath.Join(dir, "chain.yaml")
+	workflow := fmt.Sprintf(`workflow_name: chain
+blocks:
+  - name: prof
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: prof
+    from_entries: [run, report]
+    output: report
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: report
+    output: stored
+`, "file://"+filepath.ToSlash(blockDir), writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("chain", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "chain", RunID: result.RunID, Output: "report"})
+	if err != nil {
+		t.Fatalf("LoadArtifact failed: %v", err)
+	}
+	// Chained entries run in the block's output directory.
+	if string(data) != "profiled prof\n" {
+		t.Errorf("expected report to read the profile written by run, got %q", data)
+	}
+}
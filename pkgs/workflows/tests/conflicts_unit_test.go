@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writePinnedWorkflow writes a workflow running one entry of block pinned
+// to version, and returns its path.
+func writePinnedWorkflow(t *testing.T, name, block, version string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, name+".yaml")
+	workflow := fmt.Sprintf(`workflow_name: %s
+blocks:
+  - name: echo
+    github: %q
+    version: %s
+connections:
+  - from_block: echo
+    from_entry: run
+    output: echoed
+    source: %q
+`, name, block, version, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	return path
+}
+
+func TestVersionConflictsSideBySide(t *testing.T) {
+	t.Parallel()
+
+	block := writeLocalBlock(t, "echo", "  - name: run\n")
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	for _, wf := range []struct{ name, version string }{{"legacy", "v1.0.0"}, {"current", "v2.0.0"}} {
+		if err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, wf.name, block, wf.version)); err != nil {
+			t.Fatalf("CompileWorkflow(%s) failed: %v", wf.name, err)
+		}
+	}
+
+	conflicts := wm.VersionConflicts()
+	if len(conflicts) != 1 || conflicts[0].Block != "echo" || len(conflicts[0].Requirements) != 2 {
+		t.Fatalf("expected one conflict on echo, got %+v", conflicts)
+	}
+	want := []workflows.BlockRequirement{
+		{Workflow: "current", Block: "echo", Requested: "v2.0.0", Version: "v2.0.0"},
+		{Workflow: "legacy", Block: "echo", Requested: "v1.0.0", Version: "v1.0.0"},
+	}
+	for i, req := range conflicts[0].Requirements {
+		if req != want[i] {
+			t.Errorf("requirement %d: expected %+v, got %+v", i, want[i], req)
+		}
+	}
+
+	// Each workflow keeps running the version it was compiled against.
+	for _, wf := range []struct {
+		name    workflows.Workflowname
+		version string
+	}{{"legacy", "v1.0.0"}, {"current", "v2.0.0"}} {
+		ex, err := wm.Explain(wf.name)
+		if err != nil {
+			t.Fatalf("Explain(%s) failed: %v", wf.name, err)
+		}
+		if want := "echo " + wf.version + " from"; !strings.Contains(ex.Text, want) {
+			t.Errorf("expected %s to use %s, got %q", wf.name, wf.version, ex.Text)
+		}
+		if _, err := wm.RunWorkFlowWithOptions(wf.name, workflows.RunOptions{}); err != nil {
+			t.Errorf("running %s failed: %v", wf.name, err)
+		}
+	}
+}
+
+func TestVersionConflictsFail(t *testing.T) {
+	t.Parallel()
+
+	block := writeLocalBlock(t, "echo", "  - name: run\n")
+	root := t.TempDir()
+	wm := workflows.NewWorkflowManager(root)
+	wm.SetConflictPolicy(workflows.ConflictFail)
+
+	if err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, "legacy", block, "v1.0.0")); err != nil {
+		t.Fatalf("CompileWorkflow(legacy) failed: %v", err)
+	}
+	// Recompiling a workflow on another version doesn't conflict with itself.
+	if err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, "legacy", block, "v1.1.0")); err != nil {
+		t.Fatalf("recompiling legacy failed: %v", err)
+	}
+
+	err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, "current", block, "v2.0.0"))
+	var conflictErr *workflows.VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected a VersionConflictError, got %v", err)
+	}
+	if conflictErr.Workflow != "current" || len(conflictErr.Conflicts) != 1 {
+		t.Errorf("expected one conflict for current, got %+v", conflictErr)
+	}
+	if !strings.Contains(err.Error(), "workflow 'legacy' uses v1.1.0") {
+		t.Errorf("expected the error to name the conflicting workflow, got %q", err)
+	}
+
+	if _, err := wm.Explain("current"); err == nil {
+		t.Error("expected the conflicting workflow not to be compiled")
+	}
+	if conflicts := wm.VersionConflicts(); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts between compiled workflows, got %+v", conflicts)
+	}
+
+	active, err := packagemanager.NewPackageManagerWithTestDir(root).GetMetadata("echo", "")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if active.Version != "v1.1.0" {
+		t.Errorf("expected legacy's version to stay active, got %s", active.Version)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestImageBlock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake container CLI is a POSIX shell script")
+	}
+
+	// The fake CLI logs its calls and upper-cases stdin for "run".
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\necho \"$@\" >> " + filepath.Join(dir, "calls") + "\n[ \"$1\" = run ] && tr a-z A-Z\nexit 0\n"
+	if err := os.WriteFile(cli, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake CLI: %s", err)
+	}
+	t.Setenv(packagemanager.EnvContainerCLI, cli)
+
+	manifest := "name: upper\nversion: v0.1.0\nbinary:\n  from: docker\n  image: example.com/acme/upper:latest\nentries:\n  - name: run\n"
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	source := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(source, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to write source: %s", err)
+	}
+
+	path := filepath.Join(dir, "image.yaml")
+	workflow := fmt.Sprintf(`workflow_name: image
+blocks:
+  - name: upper
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: upper
+    from_entry: run
+    output: shout
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: shout
+    output: done
+`, "file://"+filepath.ToSlash(dir), "file://"+filepath.ToSlash(dir), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	if _, err := wm.RunWorkFlowWithOptions("image", workflows.RunOptions{}); err != nil {
+		t.Fatalf("RunWorkFlowWithOptions failed: %v", err)
+	}
+
+	calls, _ := os.ReadFile(filepath.Join(dir, "calls"))
+	if !strings.Contains(string(calls), "run --rm -i") || !strings.Contains(string(calls), "example.com/acme/upper:latest run\n") {
+		t.Errorf("expected the entry to run in a container, got calls:\n%s", calls)
+	}
+}
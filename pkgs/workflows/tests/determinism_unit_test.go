@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeSeededWorkflow writes a workflow named "seeded" whose root block
+// prints the pinned seed and timestamps followed by its input, read from the
+// returned source file.
+func writeSeededWorkflow(t *testing.T) (path, source string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	source = filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("v1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	script := "#!/bin/sh\necho \"seed=$ATOMOS_SEED timestamp=$ATOMOS_TIMESTAMP epoch=$SOURCE_DATE_EPOCH\"\ncat\n"
+	path = filepath.Join(dir, "seeded.yaml")
+	workflow := fmt.Sprintf(`workflow_name: seeded
+blocks:
+  - name: sampler
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: sampler
+    from_entry: run
+    output: sample
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: sample
+    output: stored
+`, writeScriptBlock(t, "sampler", script, "  - name: run\n"), writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	return path, source
+}
+
+func TestDeterministicEnvironment(t *testing.T) {
+	t.Parallel()
+
+	path, _ := writeSeededWorkflow(t)
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		determinism *workflows.Determinism
+		want        string
+	}{
+		{"off", nil, "seed= timestamp= epoch=\n"},
+		{"epoch by default", &workflows.Determinism{Seed: 7}, "seed=7 timestamp=1970-01-01T00:00:00Z epoch=0\n"},
+		{"pinned", &workflows.Determinism{Seed: 42, Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}, "seed=42 timestamp=2025-03-01T12:00:00Z epoch=1740830400\n"},
+	}
+	for _, tt := range tests {
+		result, err := wm.RunWorkFlowWithOptions("seeded", workflows.RunOptions{Determinism: tt.determinism})
+		if err != nil {
+			t.Fatalf("%s: run failed: %v", tt.name, err)
+		}
+		data, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "seeded", RunID: result.RunID, Output: "sample"})
+		if err != nil {
+			t.Fatalf("%s: LoadArtifact failed: %v", tt.name, err)
+		}
+		if got := string(data); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: expected the output to start with %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestVerifyOutputs(t *testing.T) {
+	t.Parallel()
+
+	path, source := writeSeededWorkflow(t)
+	root := t.TempDir()
+	opts := workflows.RunOptions{Determinism: &workflows.Determinism{Seed: 1, VerifyOutputs: true}}
+
+	wm := workflows.NewWorkflowManager(root)
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	for range 2 {
+		result, err := wm.RunWorkFlowWithOptions("seeded", opts)
+		if err != nil {
+			t.Fatalf("expected identical runs to verify, got %v", err)
+		}
+		if len(result.OutputMismatches) > 0 {
+			t.Fatalf("expected no mismatches, got %+v", result.OutputMismatches)
+		}
+	}
+
+	if err := os.WriteFile(source, []byte("v2\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	// The previous run is read from the artifact store by a new manager.
+	restarted := workflows.NewWorkflowManager(root)
+	if err := restarted.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := restarted.RunWorkFlowWithOptions("seeded", opts)
+	if err == nil {
+		t.Fatal("expected a diverging run to fail")
+	}
+	var outputs []workflows.Outputkey
+	for _, m := range result.OutputMismatches {
+		if m.PreviousHash == m.CurrentHash {
+			t.Errorf("expected %s to have a different hash, got %+v", m.Output, m)
+		}
+		outputs = append(outputs, m.Output)
+	}
+	if len(outputs) == 0 || outputs[0] != "sample" {
+		t.Errorf("expected sample to be reported first, got %v", outputs)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeFetchWorkflow writes a workflow named "fetch" whose block downloads
+// the URL read from its source with curl, restricted to allow.
+func writeFetchWorkflow(t *testing.T, target, allow string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	// The block is fed its own output downstream, which it passes through.
+	binary := "#!/bin/sh\nread url\ncase \"$url\" in\n  http*) curl -sf --max-time 10 \"$url\" ;;\n  *) echo \"$url\" ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "fetcher"), []byte(binary), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: fetcher\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: fetcher\nentries:\n  - name: run\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	source := filepath.Join(dir, "url.txt")
+	if err := os.WriteFile(source, []byte(target+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write source: %s", err)
+	}
+
+	path := filepath.Join(dir, "fetch.yaml")
+	workflow := fmt.Sprintf(`workflow_name: fetch
+blocks:
+  - name: fetcher
+    github: %q
+    egress:
+      allow: [%q]
+  - name: sink
+    github: %q
+connections:
+  - from_block: fetcher
+    from_entry: run
+    output: body
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: body
+    output: done
+`, "file://"+filepath.ToSlash(dir), allow, "file://"+filepath.ToSlash(dir), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path
+}
+
+func TestEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl is not installed")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "secret")
+	}))
+	defer server.Close()
+
+	allowed := workflows.NewWorkflowManager(t.TempDir())
+	if err := allowed.CompileWorkflow(t.Context(), writeFetchWorkflow(t, server.URL, "127.0.0.1")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := allowed.RunWorkFlowWithOptions("fetch", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run with an allowed host failed: %v", err)
+	}
+	if len(result.EgressViolations) != 0 {
+		t.Errorf("expected no violations, got %+v", result.EgressViolations)
+	}
+
+	denied := workflows.NewWorkflowManager(t.TempDir())
+	if err := denied.CompileWorkflow(t.Context(), writeFetchWorkflow(t, server.URL, "*.example.com")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err = denied.RunWorkFlowWithOptions("fetch", workflows.RunOptions{})
+	if err == nil {
+		t.Fatal("expected the block to fail when its host is denied")
+	}
+	if len(result.EgressViolations) != 1 || result.EgressViolations[0].Block != "fetcher" || result.EgressViolations[0].Host != server.Listener.Addr().String() {
+		t.Errorf("expected one violation by fetcher to %s, got %+v", server.Listener.Addr(), result.EgressViolations)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// envScript prints the workflow environment it runs with, one variable per
+// line, and checks that the output directory exists.
+const envScript = `#!/bin/sh
+cat > /dev/null
+echo "run=$ATOMOS_RUN_ID"
+echo "execution=$ATOMOS_EXECUTION_ID"
+echo "block=$ATOMOS_BLOCK"
+echo "entry=$ATOMOS_ENTRY"
+echo "workdir=$ATOMOS_WORKDIR"
+echo "outputs=$ATOMOS_OUTPUT_DIR"
+echo "target=$ATOMOS_PARAM_TARGET_HOST"
+[ -d "$ATOMOS_OUTPUT_DIR" ] && echo "outputs-exist=yes"
+`
+
+func TestBlockEnvironment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "env.yaml")
+	workflow := fmt.Sprintf(`workflow_name: env
+blocks:
+  - name: inspector
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: inspector
+    from_entry: run
+    output: environment
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: environment
+    output: stored
+`, writeScriptBlock(t, "inspector", envScript, "  - name: run\n"), writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("env", workflows.RunOptions{Params: map[string]string{"target-host": "db.internal"}})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "env", RunID: result.RunID, Output: "environment"})
+	if err != nil {
+		t.Fatalf("LoadArtifact failed: %v", err)
+	}
+	got := map[string]string{}
+	for line := range strings.Lines(string(data)) {
+		name, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		got[name] = value
+	}
+
+	want := map[string]string{
+		"run":           result.RunID,
+		"execution":     result.Blocks["inspector"].ExecutionID,
+		"block":         "inspector",
+		"entry":         "run",
+		"workdir":       result.WorkDir,
+		"outputs":       filepath.Join(result.WorkDir, "outputs", "inspector"),
+		"target":        "db.internal",
+		"outputs-exist": "yes",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("expected %s=%q, got %q", name, value, got[name])
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	fetcher := writeLocalBlock(t, "fetcher", `  - name: fetch
+    tags: [network, writes_files]
+`)
+	reporter := writeLocalBlock(t, "reporter", "  - name: report\n")
+
+	path := filepath.Join(t.TempDir(), "review.yaml")
+	workflow := fmt.Sprintf(`workflow_name: review
+blocks:
+  - name: download
+    github: %q
+    egress:
+      allow: [api.example.com]
+  - name: summarize
+    github: %q
+    continue_on_error: true
+connections:
+  - from_block: download
+    from_entry: fetch
+    output: page
+    source: artifact://crawl/latest/urls
+  - from_block: summarize
+    from_entry: report
+    input: page
+    output: summary
+`, fetcher, reporter)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if _, err := wm.Explain("review"); err == nil {
+		t.Error("expected Explain to fail before the workflow is compiled")
+	}
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	var polished string
+	wm.SetExplainPolisher(func(text string) (string, error) {
+		polished = text
+		return "", errors.New("unavailable")
+	})
+
+	ex, err := wm.Explain("review")
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if !slices.Equal(ex.Blocks, []string{"download", "summarize"}) {
+		t.Errorf("expected blocks in execution order, got %v", ex.Blocks)
+	}
+	for _, want := range []string{
+		"download runs entry 'fetch' on artifact 'urls' of the latest run of workflow 'crawl' and produces 'page'.",
+		"summarize runs entry 'report' on 'page' from download and produces 'summary'.",
+		"the run continues if it fails",
+		"download 'fetch': reaches the network, writes files",
+		"download: network restricted to api.example.com",
+		"summarize 'report': not declared",
+	} {
+		if !strings.Contains(ex.Text, want) {
+			t.Errorf("expected the explanation to contain %q, got:\n%s", want, ex.Text)
+		}
+	}
+	if len(ex.ExternalInputs) != 1 {
+		t.Errorf("expected one external input, got %v", ex.ExternalInputs)
+	}
+
+	// The summary is deterministic and a failing polisher leaves it alone.
+	again, err := wm.Explain("review")
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if again.Text != ex.Text || polished != ex.Text || ex.Polished != "" {
+		t.Error("expected the same unpolished explanation on every call")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeEchoWorkflow writes a workflow named "ids" whose two blocks echo
+// "hello" from a source file, and returns its path.
+func writeEchoWorkflow(t *testing.T) string {
+	t.Helper()
+
+	block := writeLocalBlock(t, "upper", "  - name: run\n")
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "ids.yaml")
+	workflow := fmt.Sprintf(`workflow_name: ids
+blocks:
+  - name: first
+    github: %q
+  - name: second
+    github: %q
+connections:
+  - from_block: first
+    from_entry: run
+    output: greeting
+    source: %q
+  - from_block: second
+    from_entry: run
+    input: greeting
+    output: echoed
+`, block, block, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path
+}
+
+func TestRunsAssignStableIDs(t *testing.T) {
+	t.Parallel()
+
+	path := writeEchoWorkflow(t)
+	root := t.TempDir()
+	wm := workflows.NewWorkflowManager(root)
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	first, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	second, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+
+	if len(first.RunID) != 26 || second.RunID <= first.RunID {
+		t.Errorf("expected increasing 26-character run IDs, got %q then %q", first.RunID, second.RunID)
+	}
+
+	seen := map[string]bool{}
+	for _, rr := range []*workflows.RunResult{first, second} {
+		for _, name := range rr.Order {
+			id := rr.Blocks[name].ExecutionID
+			if id == "" || seen[id] {
+				t.Errorf("block %s: expected a unique execution ID, got %q", name, id)
+			}
+			seen[id] = true
+		}
+
+		if len(rr.Artifacts) == 0 {
+			t.Fatal("expected the run to store artifacts")
+		}
+		for _, a := range rr.Artifacts {
+			if a.RunID != rr.RunID || a.ExecutionID != rr.Blocks[a.Block].ExecutionID || seen[a.ID] {
+				t.Errorf("artifact %+v does not belong to run %s", a, rr.RunID)
+			}
+			seen[a.ID] = true
+		}
+	}
+
+	replayed, err := wm.ReplayBlock("ids", "second")
+	if err != nil {
+		t.Fatalf("ReplayBlock failed: %v", err)
+	}
+	if replayed["greeting"] != "hello\n" {
+		t.Errorf("expected the replay to echo the recorded input, got %q", replayed["greeting"])
+	}
+
+	// A fresh manager replays from the artifacts stored under the run ID.
+	restarted := workflows.NewWorkflowManager(root)
+	if err := restarted.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	persisted, err := restarted.ReplayBlock("ids", "second")
+	if err != nil {
+		t.Fatalf("ReplayBlock from disk failed: %v", err)
+	}
+	if persisted["greeting"] != "hello\n" {
+		t.Errorf("expected the stored input to be replayed, got %q", persisted["greeting"])
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestLintWorkflow(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+
+	diags, err := wm.LintWorkflow(filepath.Join("invalidcases", "broken_workflow_atoms.yaml"))
+	if err != nil {
+		t.Fatalf("LintWorkflow failed: %v", err)
+	}
+
+	expected := []struct {
+		code string
+		line int
+	}{
+		{workflows.CodeDuplicateBlock, 17},
+		{workflows.CodeMissingSource, 20},
+		{workflows.CodeUnknownBlock, 27},
+		{workflows.CodeMissingEntry, 32},
+		{workflows.CodeUnboundInput, 34},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diags), diags)
+	}
+	for i, want := range expected {
+		if diags[i].Code != want.code || diags[i].Line != want.line {
+			t.Errorf("diagnostic %d: expected %s at line %d, got %s", i, want.code, want.line, diags[i])
+		}
+	}
+
+	if err := wm.CompileWorkflow(t.Context(), filepath.Join("invalidcases", "broken_workflow_atoms.yaml")); err == nil {
+		t.Fatal("expected CompileWorkflow to fail")
+	} else if _, ok := err.(*workflows.DiagnosticsError); !ok {
+		t.Fatalf("expected a *DiagnosticsError, got %T", err)
+	}
+}
+
+func TestCompileWorkflowHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	err := wm.CompileWorkflow(ctx, writeEchoWorkflow(t))
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("expected a cancelled compile to fail its installs, got %v", err)
+	}
+	if _, err := wm.BlockPlacement("ids", "first"); err == nil {
+		t.Error("expected a cancelled compile to leave the workflow uncompiled")
+	}
+}
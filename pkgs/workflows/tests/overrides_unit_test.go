@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestOverrideVersion(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	wm := workflows.NewWorkflowManager(root)
+	if err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, "pinned", writeLocalBlock(t, "echo", "  - name: run\n"), "v1.0.0")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	// Each override installs the version it asks for, not whichever one an
+	// earlier override left in the scratch directory.
+	for _, version := range []string{"v2.0.0", "v3.0.0"} {
+		result, err := wm.RunWorkFlowWithOptions("pinned", workflows.RunOptions{
+			Overrides: map[workflows.Blockname]workflows.BlockOverride{"echo": {Version: version}},
+		})
+		if err != nil {
+			t.Fatalf("run overriding %s failed: %v", version, err)
+		}
+		if result.Blocks["echo"].Status != workflows.BlockSucceeded {
+			t.Errorf("expected echo to succeed, got %s", result.Blocks["echo"].Status)
+		}
+
+		binDir := filepath.Join(root, ".atomos", ".overrides", ".atomos", "echo", "bin", version)
+		if _, err := os.Stat(binDir); err != nil {
+			t.Errorf("expected %s to be installed under the install dir: %v", version, err)
+		}
+	}
+}
+
+func TestOverrideErrors(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writePinnedWorkflow(t, "pinned", writeLocalBlock(t, "echo", "  - name: run\n"), "v1.0.0")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name string
+		opts workflows.RunOptions
+	}{
+		{"missing binary", workflows.RunOptions{Overrides: map[workflows.Blockname]workflows.BlockOverride{"echo": {BinaryPath: filepath.Join(t.TempDir(), "missing")}}}},
+		{"unknown block", workflows.RunOptions{Overrides: map[workflows.Blockname]workflows.BlockOverride{"other": {Version: "v2.0.0"}}}},
+		{"empty override", workflows.RunOptions{Overrides: map[workflows.Blockname]workflows.BlockOverride{"echo": {}}}},
+		{"cancelled install", workflows.RunOptions{Context: cancelled, Overrides: map[workflows.Blockname]workflows.BlockOverride{"echo": {Version: "v2.0.0"}}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := wm.RunWorkFlowWithOptions("pinned", tt.opts); err == nil {
+			t.Errorf("%s: expected the run to fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writePlacementWorkflow writes a workflow named "placed" whose trainer
+// block declares placement, and returns its path.
+func writePlacementWorkflow(t *testing.T, placement string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "placed.yaml")
+	workflow := fmt.Sprintf(`workflow_name: placed
+blocks:
+  - name: trainer
+    github: %q
+    placement:
+%s
+  - name: sink
+    github: %q
+connections:
+  - from_block: trainer
+    from_entry: run
+    output: model
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: model
+    output: stored
+`, writeLocalBlock(t, "trainer", "  - name: run\n"), placement, writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	return path
+}
+
+func TestBlockPlacement(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	path := writePlacementWorkflow(t, "      gpu: true\n      region: eu-west-1\n      priority: 5\n      node_selector:\n        disk: ssd\n")
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	placement, err := wm.BlockPlacement("placed", "trainer")
+	if err != nil {
+		t.Fatalf("BlockPlacement failed: %v", err)
+	}
+	if placement == nil || !placement.GPU || placement.Region != "eu-west-1" || placement.Priority != 5 || placement.NodeSelector["disk"] != "ssd" {
+		t.Errorf("expected the declared hints, got %+v", placement)
+	}
+
+	if placement, err := wm.BlockPlacement("placed", "sink"); err != nil || placement != nil {
+		t.Errorf("expected no hints for sink, got %+v, %v", placement, err)
+	}
+	if _, err := wm.BlockPlacement("placed", "missing"); err == nil {
+		t.Error("expected an unknown block to fail")
+	}
+	if _, err := wm.BlockPlacement("unknown", "trainer"); err == nil {
+		t.Error("expected an unknown workflow to fail")
+	}
+
+	// The local executor ignores the hints.
+	if _, err := wm.RunWorkFlowWithOptions("placed", workflows.RunOptions{}); err != nil {
+		t.Errorf("expected the local run to succeed, got %v", err)
+	}
+}
+
+func TestInvalidPlacement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		placement string
+	}{
+		{"negative priority", "      priority: -1\n"},
+		{"empty label", "      node_selector:\n        \"\": ssd\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wm := workflows.NewWorkflowManager(t.TempDir())
+			path := writePlacementWorkflow(t, tt.placement)
+
+			diags, err := wm.LintWorkflow(path)
+			if err != nil {
+				t.Fatalf("LintWorkflow failed: %v", err)
+			}
+			if len(diags) != 1 || diags[0].Code != workflows.CodeInvalidPlacement || diags[0].Block != "trainer" {
+				t.Errorf("expected a single %s diagnostic for trainer, got %v", workflows.CodeInvalidPlacement, diags)
+			}
+
+			var diagErr *workflows.DiagnosticsError
+			if err := wm.CompileWorkflow(t.Context(), path); !errors.As(err, &diagErr) {
+				t.Errorf("expected CompileWorkflow to fail with a *DiagnosticsError, got %v", err)
+			}
+		})
+	}
+}
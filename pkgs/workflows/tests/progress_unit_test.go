@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// progressScript reports progress on stderr around echoing its input, the
+// last report without a trailing newline. On "crash" it fails instead of
+// reporting completion.
+const progressScript = `#!/bin/sh
+echo "ATOMOS_PROGRESS 10 reading input" >&2
+echo "ATOMOS_PROGRESS 150 out of range" >&2
+echo "ATOMOS_PROGRESSING 20" >&2
+cat
+if [ "$1" = crash ]; then echo boom >&2; exit 1; fi
+printf 'ATOMOS_PROGRESS 100%%' >&2
+`
+
+// writeProgressWorkflow writes a workflow named "progress" running entry of
+// a progressScript block, and returns its path.
+func writeProgressWorkflow(t *testing.T, entry string) string {
+	t.Helper()
+
+	block := writeScriptBlock(t, "reporter", progressScript, "  - name: run\n  - name: crash\n")
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "progress.yaml")
+	workflow := fmt.Sprintf(`workflow_name: progress
+blocks:
+  - name: reporter
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: reporter
+    from_entry: %s
+    output: report
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: report
+    output: stored
+`, block, writeLocalBlock(t, "sink", "  - name: run\n"), entry, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	return path
+}
+
+func TestProgressLinesForwarded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		updates []workflows.BlockProgress
+	)
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	wm.SetProgressHandler(func(p workflows.BlockProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, p)
+	})
+	if err := wm.CompileWorkflow(t.Context(), writeProgressWorkflow(t, "run")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("progress", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates) == 0 {
+		t.Fatal("expected progress updates")
+	}
+	var got []string
+	for _, p := range updates {
+		if p.Block != "reporter" || p.Entry != "run" || p.ExecutionID != result.Blocks["reporter"].ExecutionID {
+			t.Errorf("unexpected origin of %+v", p)
+		}
+		got = append(got, fmt.Sprintf("%d %s", p.Percent, p.Step))
+	}
+	// Every execution of the entry reports the same two valid lines.
+	want := []string{"10 reading input", "100 "}
+	for i := 0; i < len(got); i += len(want) {
+		if !slices.Equal(got[i:min(i+len(want), len(got))], want) {
+			t.Errorf("expected updates %v per execution, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestProgressLinesStrippedFromStderr(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writeProgressWorkflow(t, "crash")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	_, err := wm.RunWorkFlowWithOptions("progress", workflows.RunOptions{})
+	if err == nil {
+		t.Fatal("expected the crashing entry to fail the run")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "boom") {
+		t.Errorf("expected the block's stderr in the error, got %q", msg)
+	}
+	if strings.Contains(msg, "ATOMOS_PROGRESS 10 ") {
+		t.Errorf("expected progress lines to be stripped from stderr, got %q", msg)
+	}
+	if !strings.Contains(msg, "ATOMOS_PROGRESS 150") {
+		t.Errorf("expected an out-of-range progress line to stay in stderr, got %q", msg)
+	}
+}
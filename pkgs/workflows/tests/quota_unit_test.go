@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestConcurrentRunsQuota(t *testing.T) {
+	t.Parallel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writeEchoWorkflow(t)); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	wm.SetQuota("ids", workflows.Quota{MaxConcurrentRuns: 4})
+
+	// Concurrent runs share the recorded outputs and the telemetry
+	// baselines; run with -race to catch unguarded state.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for range 4 {
+		wg.Go(func() {
+			for range 2 {
+				result, err := wm.RunWorkFlowWithOptions("ids", workflows.RunOptions{})
+				if errors.Is(err, workflows.ErrQuotaExceeded) {
+					continue
+				}
+				if err != nil {
+					errs <- err
+					continue
+				}
+				for _, name := range result.Order {
+					for _, a := range result.Blocks[name].Anomalies {
+						if a.ExecutionID != result.Blocks[name].ExecutionID {
+							t.Errorf("run %s reported anomaly %+v of another execution", result.RunID, a)
+						}
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("run failed: %v", err)
+	}
+
+	replayed, err := wm.ReplayBlock("ids", "second")
+	if err != nil {
+		t.Fatalf("ReplayBlock failed: %v", err)
+	}
+	if replayed["greeting"] != "hello\n" {
+		t.Errorf("expected the last run's input to be replayed, got %q", replayed["greeting"])
+	}
+
+	usage, err := wm.QuotaUsage("ids")
+	if err != nil {
+		t.Fatalf("QuotaUsage failed: %v", err)
+	}
+	if usage.ActiveRuns != 0 {
+		t.Errorf("expected no active runs once every run ended, got %d", usage.ActiveRuns)
+	}
+}
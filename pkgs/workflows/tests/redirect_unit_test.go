@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %s", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+// The test points the package manager at a fake GitHub through the
+// environment, so it can't run in parallel.
+func TestCompileWarnsAboutMovedRepository(t *testing.T) {
+	manifest := fmt.Sprintf("name: tool\nversion: v1.0.0\nbinary:\n  assets:\n    %s-%s: tool\nentries:\n  - name: run\n", runtime.GOOS, runtime.GOARCH)
+	release := packagemanager.GitHubRelease{TagName: "v1.0.0", Assets: []packagemanager.ReleaseAsset{{ID: 1, Name: "tool"}}}
+
+	mux := http.NewServeMux()
+	for _, repo := range []string{"old/name", "new/name"} {
+		mux.HandleFunc("/api/repos/"+repo, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{"full_name": "new/name"})
+		})
+	}
+	mux.HandleFunc("/raw/new/name/HEAD/agentic_support.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, manifest)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+	mux.HandleFunc("/api/repos/new/name/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "#!/bin/sh\ncat\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("ATOMOS_GITHUB_API_URL", server.URL+"/api")
+	t.Setenv("ATOMOS_GITHUB_RAW_URL", server.URL+"/raw/")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+	path := filepath.Join(dir, "moved.yaml")
+	workflow := fmt.Sprintf(`workflow_name: moved
+blocks:
+  - name: tool
+    github: old/name
+  - name: sink
+    github: %q
+connections:
+  - from_block: tool
+    from_entry: run
+    output: greeting
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: greeting
+    output: stored
+`, writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	var compileErr error
+	out := captureStdout(t, func() { compileErr = wm.CompileWorkflow(t.Context(), path) })
+	if compileErr != nil {
+		t.Fatalf("CompileWorkflow failed: %v", compileErr)
+	}
+	if want := "Warning: block 'tool' in workflow 'moved' references old/name, which now lives at new/name"; !strings.Contains(out, want) {
+		t.Errorf("expected %q, got:\n%s", want, out)
+	}
+
+	if _, err := wm.RunWorkFlowWithOptions("moved", workflows.RunOptions{}); err != nil {
+		t.Errorf("expected the workflow to run from the new coordinates, got %v", err)
+	}
+}
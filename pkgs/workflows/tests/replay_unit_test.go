@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestReplayBlockSkipsUpstream(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	executions := filepath.Join(dir, "executions.txt")
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	// Both blocks log each of their executions before echoing their input.
+	script := fmt.Sprintf("#!/bin/sh\necho \"$ATOMOS_BLOCK\" >> %q\ncat\n", executions)
+	upstream := writeScriptBlock(t, "upstream", script, "  - name: run\n")
+	downstream := writeScriptBlock(t, "downstream", script, "  - name: run\n")
+
+	path := filepath.Join(dir, "replay.yaml")
+	workflow := fmt.Sprintf(`workflow_name: replay
+blocks:
+  - name: first
+    github: %q
+  - name: second
+    github: %q
+connections:
+  - from_block: first
+    from_entry: run
+    output: greeting
+    source: %q
+  - from_block: second
+    from_entry: run
+    input: greeting
+    output: echoed
+`, upstream, downstream, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	if _, err := wm.ReplayBlock("replay", "second"); err == nil {
+		t.Error("expected a replay before any run to fail")
+	}
+
+	if _, err := wm.RunWorkFlowWithOptions("replay", workflows.RunOptions{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	readExecutions := func() []string {
+		t.Helper()
+		data, err := os.ReadFile(executions)
+		if err != nil {
+			t.Fatalf("failed to read executions: %v", err)
+		}
+		return strings.Fields(string(data))
+	}
+	ran := len(readExecutions())
+
+	replayed, err := wm.ReplayBlock("replay", "second")
+	if err != nil {
+		t.Fatalf("ReplayBlock failed: %v", err)
+	}
+	if replayed["greeting"] != "hello\n" {
+		t.Errorf("expected the recorded input, got %q", replayed["greeting"])
+	}
+
+	after := readExecutions()
+	if len(after) != ran+1 || after[len(after)-1] != "second" {
+		t.Errorf("expected only the replayed block to run again, got executions %v", after)
+	}
+
+	if _, err := wm.ReplayBlock("replay", "missing"); err == nil {
+		t.Error("expected replaying an unknown block to fail")
+	}
+	if _, err := wm.ReplayBlock("unknown", "second"); err == nil {
+		t.Error("expected replaying an unknown workflow to fail")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeFlakyWorkflow writes a workflow named "flaky" whose first block exits
+// with code for its first fails executions, under the given retry policy,
+// and returns its path.
+func writeFlakyWorkflow(t *testing.T, fails, code int, retry string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "count")
+	binary := fmt.Sprintf("#!/bin/sh\nn=$(cat %[1]q 2>/dev/null || echo 0)\nn=$((n+1))\necho $n > %[1]q\nif [ $n -le %[2]d ]; then exit %[3]d; fi\ncat\n", counter, fails, code)
+	if err := os.WriteFile(filepath.Join(dir, "flaky"), []byte(binary), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: flaky\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: flaky\nentries:\n  - name: run\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "flaky.yaml")
+	workflow := fmt.Sprintf(`workflow_name: flaky
+blocks:
+  - name: flaky
+    github: %q
+    retry: %s
+  - name: sink
+    github: %q
+connections:
+  - from_block: flaky
+    from_entry: run
+    output: greeting
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: greeting
+    output: done
+`, "file://"+filepath.ToSlash(dir), retry, writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test block is a shell script")
+	}
+
+	tests := []struct {
+		name        string
+		fails, code int
+		retry       string
+		wantStatus  workflows.BlockStatus
+		wantRetries int
+	}{
+		{"recovers", 2, 75, "{attempts: 3, backoff: 10ms, retry_on_exit_codes: [75]}", workflows.BlockSucceeded, 2},
+		{"any exit code", 1, 1, "{attempts: 2, backoff: 1ms}", workflows.BlockSucceeded, 1},
+		{"other exit code", 1, 1, "{attempts: 3, retry_on_exit_codes: [75]}", workflows.BlockFailed, 0},
+		{"attempts exhausted", 5, 75, "{attempts: 2, retry_on_exit_codes: [75]}", workflows.BlockFailed, 1},
+		{"no policy", 1, 1, "null", workflows.BlockFailed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wm := workflows.NewWorkflowManager(t.TempDir())
+			if err := wm.CompileWorkflow(t.Context(), writeFlakyWorkflow(t, tt.fails, tt.code, tt.retry)); err != nil {
+				t.Fatalf("CompileWorkflow failed: %v", err)
+			}
+			result, err := wm.RunWorkFlowWithOptions("flaky", workflows.RunOptions{})
+			if (err == nil) != (tt.wantStatus == workflows.BlockSucceeded) {
+				t.Errorf("unexpected run error: %v", err)
+			}
+
+			br := result.Blocks["flaky"]
+			if br == nil {
+				t.Fatal("expected a result for the flaky block")
+			}
+			if br.Status != tt.wantStatus || br.Retries != tt.wantRetries {
+				t.Errorf("expected %s after %d retries, got %s after %d", tt.wantStatus, tt.wantRetries, br.Status, br.Retries)
+			}
+		})
+	}
+}
+
+func TestLintInvalidRetry(t *testing.T) {
+	t.Parallel()
+
+	for _, retry := range []string{"{attempts: -1}", "{attempts: 21}", "{backoff: -1s}", "{attempts: 3, retry_on_exit_codes: [0]}"} {
+		wm := workflows.NewWorkflowManager(t.TempDir())
+		diags, err := wm.LintWorkflow(writeFlakyWorkflow(t, 0, 0, retry))
+		if err != nil {
+			t.Fatalf("LintWorkflow failed: %v", err)
+		}
+
+		found := false
+		for _, d := range diags {
+			if d.Code == workflows.CodeInvalidRetry && d.Severity == workflows.SeverityError {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an %s diagnostic for %s, got %v", workflows.CodeInvalidRetry, retry, diags)
+		}
+	}
+}
+
+func TestRetryBackoffCancelled(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test block is a shell script")
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writeFlakyWorkflow(t, 5, 75, "{attempts: 20, backoff: 1h}")); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := wm.RunWorkFlowWithOptions("flaky", workflows.RunOptions{Context: ctx})
+	if err == nil {
+		t.Fatal("expected the cancelled run to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the backoff to stop with the context, took %s", elapsed)
+	}
+	if br := result.Blocks["flaky"]; br == nil || br.Status != workflows.BlockFailed || br.Retries != 1 {
+		t.Errorf("expected flaky to fail after one retry, got %+v", br)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeSandboxedWorkflow writes a workflow named "sandboxed" whose first
+// block prints its environment under the given sandbox profile.
+func writeSandboxedWorkflow(t *testing.T, sandbox string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "printer"), []byte("#!/bin/sh\ncat >/dev/null\nenv\n"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: printer\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: printer\nentries:\n  - name: run\nsandbox:\n%s",
+		runtime.GOOS, runtime.GOARCH, sandbox)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("go\n"), 0644); err != nil {
+		t.Fatalf("Failed to write source: %s", err)
+	}
+
+	path := filepath.Join(dir, "sandboxed.yaml")
+	workflow := fmt.Sprintf(`workflow_name: sandboxed
+blocks:
+  - name: printer
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: printer
+    from_entry: run
+    output: env
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: env
+    output: done
+`, "file://"+filepath.ToSlash(dir), "file://"+filepath.ToSlash(dir), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path
+}
+
+func TestSandboxEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test block is a shell script")
+	}
+	t.Setenv("ATOMOS_TEST_DECLARED", "kept")
+	t.Setenv("ATOMOS_TEST_UNDECLARED", "dropped")
+
+	writable := t.TempDir()
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), writeSandboxedWorkflow(t, fmt.Sprintf("  network: [api.example.com]\n  writable: [%q]\n  env: [ATOMOS_TEST_DECLARED]\n", writable))); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("sandboxed", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _, err := wm.LoadArtifact(workflows.ArtifactRef{Workflow: "sandboxed", RunID: result.RunID, Output: "env"})
+	if err != nil {
+		t.Fatalf("LoadArtifact failed: %v", err)
+	}
+	env := map[string]string{}
+	for line := range strings.Lines(string(data)) {
+		if name, value, ok := strings.Cut(strings.TrimSuffix(line, "\n"), "="); ok {
+			env[name] = value
+		}
+	}
+
+	if env["ATOMOS_TEST_DECLARED"] != "kept" {
+		t.Errorf("expected the declared variable to be passed, got %q", env["ATOMOS_TEST_DECLARED"])
+	}
+	if _, ok := env["ATOMOS_TEST_UNDECLARED"]; ok {
+		t.Error("expected the undeclared variable to be withheld")
+	}
+	if env[workflows.EnvWritable] != writable {
+		t.Errorf("expected %s=%s, got %q", workflows.EnvWritable, writable, env[workflows.EnvWritable])
+	}
+	for _, name := range []string{"HOME", "TMPDIR"} {
+		if !strings.HasPrefix(env[name], result.WorkDir) {
+			t.Errorf("expected %s under the run's work directory %s, got %q", name, result.WorkDir, env[name])
+		}
+	}
+	// The network declared by the profile is enforced through the egress
+	// proxy like a workflow allowlist.
+	if env["HTTP_PROXY"] == "" && env["http_proxy"] == "" {
+		t.Error("expected the block to run behind the egress proxy")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeDiamondWorkflow writes a workflow named "diamond" where "merge"
+// consumes the output of "first" directly and through the "left" -> "right"
+// chain, plus an independent "other" root. It returns the workflow path and
+// the file every block invocation appends its block name to.
+func writeDiamondWorkflow(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	binary := fmt.Sprintf("#!/bin/sh\necho \"$ATOMOS_BLOCK\" >> %q\ncat\n", calls)
+	if err := os.WriteFile(filepath.Join(dir, "tracer"), []byte(binary), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %s", err)
+	}
+	manifest := fmt.Sprintf("name: tracer\nversion: v0.1.0\nbinary:\n  assets:\n    %s-%s: tracer\nentries:\n  - name: run\n", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	block := "file://" + filepath.ToSlash(dir)
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	path := filepath.Join(dir, "diamond.yaml")
+	workflow := fmt.Sprintf(`workflow_name: diamond
+blocks:
+  - name: first
+    github: %[1]q
+  - name: left
+    github: %[1]q
+  - name: right
+    github: %[1]q
+  - name: merge
+    github: %[1]q
+  - name: other
+    github: %[1]q
+  - name: sink
+    github: %[1]q
+connections:
+  - from_block: first
+    from_entry: run
+    output: greeting
+    source: %[2]q
+  - from_block: left
+    from_entry: run
+    input: greeting
+    output: once
+  - from_block: right
+    from_entry: run
+    input: once
+    output: twice
+  - from_block: merge
+    from_entry: run
+    input: twice
+    output: merged_chain
+  - from_block: merge
+    from_entry: run
+    input: greeting
+    output: merged_direct
+  - from_block: other
+    from_entry: run
+    output: unrelated
+    source: %[2]q
+  - from_block: sink
+    from_entry: run
+    input: unrelated
+    output: done
+`, block, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	return path, calls
+}
+
+func TestSchedulerWaitsForAllUpstream(t *testing.T) {
+	t.Parallel()
+
+	path, calls := writeDiamondWorkflow(t)
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("diamond", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	wantStages := [][]string{{"first", "other"}, {"left", "sink"}, {"right"}, {"merge"}}
+	if !reflect.DeepEqual(result.Stages, wantStages) {
+		t.Errorf("expected stages %v, got %v", wantStages, result.Stages)
+	}
+	if want := slices.Concat(wantStages...); !slices.Equal(result.Order, want) {
+		t.Errorf("expected blocks to settle in order %v, got %v", want, result.Order)
+	}
+	for name, br := range result.Blocks {
+		if br.Status != workflows.BlockSucceeded {
+			t.Errorf("expected %s to succeed, got %s: %s", name, br.Status, br.Reason)
+		}
+	}
+
+	// merge only ran once the longer branch had produced its output.
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %s", err)
+	}
+	invoked := strings.Fields(string(data))
+	if slices.Index(invoked, "merge") < slices.Index(invoked, "right") {
+		t.Errorf("expected merge to run after right, got invocations %v", invoked)
+	}
+
+	ex, err := wm.Explain("diamond")
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if !slices.Equal(ex.Blocks, result.Order) {
+		t.Errorf("expected Explain to list blocks in run order %v, got %v", result.Order, ex.Blocks)
+	}
+}
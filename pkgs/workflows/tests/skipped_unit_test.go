@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestSkippedPropagation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+	merged := filepath.Join(dir, "merged.txt")
+
+	echo := writeLocalBlock(t, "echo", "  - name: run\n")
+	fail := writeScriptBlock(t, "fail", "#!/bin/sh\necho broken >&2\nexit 1\n", "  - name: run\n")
+	merge := writeScriptBlock(t, "merge", fmt.Sprintf("#!/bin/sh\ncat >> %q\necho >> %q\n", merged, merged), "  - name: run\n")
+
+	// bad fails under continue_on_error: after and last only depend on it and
+	// are skipped, while merge also reads good's output and still runs.
+	path := filepath.Join(dir, "skipped.yaml")
+	workflow := fmt.Sprintf(`workflow_name: skipped
+blocks:
+  - name: bad
+    github: %q
+    continue_on_error: true
+  - name: good
+    github: %q
+  - name: after
+    github: %q
+  - name: last
+    github: %q
+  - name: merge
+    github: %q
+connections:
+  - from_block: bad
+    from_entry: run
+    output: broken
+    source: %q
+  - from_block: good
+    from_entry: run
+    output: greeting
+    source: %q
+  - from_block: after
+    from_entry: run
+    input: broken
+    output: derived
+  - from_block: last
+    from_entry: run
+    input: derived
+    output: final
+  - from_block: merge
+    from_entry: run
+    input: broken
+    output: merged_broken
+  - from_block: merge
+    from_entry: run
+    input: greeting
+    output: merged_greeting
+`, fail, echo, echo, echo, merge, source, source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	result, err := wm.RunWorkFlowWithOptions("skipped", workflows.RunOptions{})
+	if err != nil {
+		t.Fatalf("expected continue_on_error to keep the run going, got %v", err)
+	}
+
+	want := map[string]workflows.BlockStatus{
+		"bad":   workflows.BlockFailed,
+		"good":  workflows.BlockSucceeded,
+		"after": workflows.BlockSkipped,
+		"last":  workflows.BlockSkipped,
+		"merge": workflows.BlockSucceeded,
+	}
+	for block, status := range want {
+		if got, ok := result.Status(block); !ok || got != status {
+			t.Errorf("expected %s to be %s, got %q", block, status, got)
+		}
+	}
+
+	if reason := result.Blocks["after"].Reason; !strings.Contains(reason, "upstream 'bad' failed") {
+		t.Errorf("expected after's reason to name the failed block, got %q", reason)
+	}
+	if reason := result.Blocks["last"].Reason; !strings.Contains(reason, "upstream 'after' skipped") || !strings.Contains(reason, "upstream 'bad' failed") {
+		t.Errorf("expected last's reason to chain the upstream reasons, got %q", reason)
+	}
+	if id := result.Blocks["after"].ExecutionID; id != "" {
+		t.Errorf("expected a skipped block to never start, got execution %s", id)
+	}
+
+	data, err := os.ReadFile(merged)
+	if err != nil {
+		t.Fatalf("merge never ran: %v", err)
+	}
+	if !strings.Contains(string(data), string(workflows.AbsentInput)) || !strings.Contains(string(data), "hello") {
+		t.Errorf("expected merge to receive the absent marker and the greeting, got %q", data)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// writeModalWorkflow writes a workflow named "modal" whose root block behaves
+// according to the mode written to the returned mode file: "normal" echoes
+// its input, "empty" prints nothing, and "fail" exits with 3.
+func writeModalWorkflow(t *testing.T) (path, modeFile string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	modeFile = filepath.Join(dir, "mode")
+	source := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(source, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %s", err)
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+case "$(cat %q)" in
+empty) cat > /dev/null ;;
+fail) exit 3 ;;
+*) cat ;;
+esac
+`, modeFile)
+	tool := writeScriptBlock(t, "tool", script, "  - name: run\n")
+
+	path = filepath.Join(dir, "modal.yaml")
+	workflow := fmt.Sprintf(`workflow_name: modal
+blocks:
+  - name: tool
+    github: %q
+    continue_on_error: true
+  - name: sink
+    github: %q
+connections:
+  - from_block: tool
+    from_entry: run
+    output: report
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: report
+    output: stored
+`, tool, writeLocalBlock(t, "sink", "  - name: run\n"), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	return path, modeFile
+}
+
+func TestTelemetryFlagsAnomalies(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path, modeFile := writeModalWorkflow(t)
+	run := func(wm *workflows.WorkflowManager, mode string) *workflows.RunResult {
+		t.Helper()
+		if err := os.WriteFile(modeFile, []byte(mode), 0644); err != nil {
+			t.Fatalf("Failed to write mode: %s", err)
+		}
+		result, err := wm.RunWorkFlowWithOptions("modal", workflows.RunOptions{})
+		if err != nil {
+			t.Fatalf("%s run failed: %v", mode, err)
+		}
+		return result
+	}
+
+	wm := workflows.NewWorkflowManager(root)
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	// The first executions build the baseline and are never flagged.
+	for range 3 {
+		if anomalies := run(wm, "normal").Anomalies(); len(anomalies) > 0 {
+			t.Fatalf("expected no anomalies while building the baseline, got %+v", anomalies)
+		}
+	}
+
+	// Baselines are persisted, so a new manager flags deviations too.
+	restarted := workflows.NewWorkflowManager(root)
+	if err := restarted.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	tests := []struct {
+		mode string
+		want workflows.AnomalyKind
+	}{
+		{"empty", workflows.AnomalyEmptyOutput},
+		{"fail", workflows.AnomalyExitCode},
+	}
+	for _, tt := range tests {
+		result := run(restarted, tt.mode)
+		kinds := map[workflows.AnomalyKind]bool{}
+		for _, a := range result.Blocks["tool"].Anomalies {
+			if a.Block != "tool" || a.Entry != "run" || a.ExecutionID != result.Blocks["tool"].ExecutionID {
+				t.Errorf("unexpected origin of %+v", a)
+			}
+			kinds[a.Kind] = true
+		}
+		if !kinds[tt.want] {
+			t.Errorf("%s run: expected a %s anomaly, got %+v", tt.mode, tt.want, result.Blocks["tool"].Anomalies)
+		}
+	}
+
+	if anomalies := run(restarted, "normal").Anomalies(); len(anomalies) > 0 {
+		t.Errorf("expected a normal run to raise no anomalies, got %+v", anomalies)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+// fakeWasmRuntime upper-cases its input and records the modules it ran.
+type fakeWasmRuntime struct {
+	mu   sync.Mutex
+	runs []workflows.WasmRun
+}
+
+func (r *fakeWasmRuntime) Run(ctx context.Context, run workflows.WasmRun) (int, error) {
+	r.mu.Lock()
+	r.runs = append(r.runs, run)
+	r.mu.Unlock()
+
+	input, err := io.ReadAll(run.Stdin)
+	if err != nil {
+		return 1, err
+	}
+	_, err = io.WriteString(run.Stdout, strings.ToUpper(string(input)))
+	return 0, err
+}
+
+func TestWasmBlock(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "upper.wasm"), []byte("\x00asm\x01\x00\x00\x00"), 0644); err != nil {
+		t.Fatalf("Failed to write module: %s", err)
+	}
+	manifest := "name: upper\nversion: v0.1.0\nbinary:\n  assets:\n    wasm: upper.wasm\nentries:\n  - name: run\n"
+	if err := os.WriteFile(filepath.Join(dir, "agentic_support.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+	source := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(source, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to write source: %s", err)
+	}
+
+	path := filepath.Join(dir, "wasm.yaml")
+	workflow := fmt.Sprintf(`workflow_name: wasm
+blocks:
+  - name: upper
+    github: %q
+  - name: sink
+    github: %q
+connections:
+  - from_block: upper
+    from_entry: run
+    output: shout
+    source: %q
+  - from_block: sink
+    from_entry: run
+    input: shout
+    output: done
+`, "file://"+filepath.ToSlash(dir), "file://"+filepath.ToSlash(dir), source)
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	runtime := &fakeWasmRuntime{}
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	wm.SetWasmRuntime(runtime)
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	if _, err := wm.RunWorkFlowWithOptions("wasm", workflows.RunOptions{}); err != nil {
+		t.Fatalf("RunWorkFlowWithOptions failed: %v", err)
+	}
+
+	if len(runtime.runs) == 0 {
+		t.Fatal("expected the module to run through the WASM runtime")
+	}
+	run := runtime.runs[0]
+	if filepath.Base(run.Module) != "upper.wasm" || len(run.Args) != 1 || run.Args[0] != "run" {
+		t.Errorf("expected upper.wasm to run entry run, got %s %v", run.Module, run.Args)
+	}
+}
+
+func TestWasmRuntimeMissing(t *testing.T) {
+	t.Setenv(workflows.EnvWasmRuntime, filepath.Join(t.TempDir(), "missing"))
+
+	_, err := workflows.CLIWasmRuntime{}.Run(context.Background(), workflows.WasmRun{Module: "block.wasm"})
+	if err == nil {
+		t.Fatal("expected an error when the WASM runtime does not exist")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/workflows"
+)
+
+func TestWatchWorkflowsReloadsChanges(t *testing.T) {
+	t.Parallel()
+
+	block := writeLocalBlock(t, "echoer", "  - name: run\n")
+	workflow := func(output string) string {
+		return fmt.Sprintf(`workflow_name: watched
+blocks:
+  - name: echoer
+    github: %q
+connections:
+  - from_block: echoer
+    from_entry: run
+    output: %s
+    source: ./input.txt
+`, block, output)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "watched.yaml")
+	if err := os.WriteFile(path, []byte(workflow("first")), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+
+	events := make(chan workflows.ReloadEvent, 16)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	done := make(chan error, 1)
+	go func() {
+		done <- wm.WatchWorkflows(ctx, dir, 10*time.Millisecond, func(e workflows.ReloadEvent) { events <- e })
+	}()
+
+	next := func() workflows.ReloadEvent {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a reload event")
+			return workflows.ReloadEvent{}
+		}
+	}
+
+	if e := next(); e.Status != workflows.ReloadSucceeded || e.Workflow != "watched" {
+		t.Fatalf("expected the initial compile to succeed, got %+v", e)
+	}
+
+	// An invalid edit is rejected and reported with its diagnostics.
+	if err := os.WriteFile(path, []byte(workflow("")), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	e := next()
+	var diags *workflows.DiagnosticsError
+	if e.Status != workflows.ReloadFailed || !errors.As(e.Err, &diags) {
+		t.Fatalf("expected the broken edit to fail with diagnostics, got %+v", e)
+	}
+
+	if err := os.WriteFile(path, []byte(workflow("second")), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %s", err)
+	}
+	if e := next(); e.Status != workflows.ReloadSucceeded {
+		t.Fatalf("expected the fixed edit to succeed, got %+v", e)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove workflow: %s", err)
+	}
+	if e := next(); e.Status != workflows.ReloadRemoved || e.Path != path {
+		t.Fatalf("expected a removal event, got %+v", e)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestReloadDuringReplay(t *testing.T) {
+	t.Parallel()
+
+	path := writeEchoWorkflow(t)
+	wm := workflows.NewWorkflowManager(t.TempDir())
+	if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+	if err := wm.RunWorkFlow("ids"); err != nil {
+		t.Fatalf("RunWorkFlow failed: %v", err)
+	}
+
+	// Reloads swap the compiled workflow while it's being read; run with
+	// -race to catch unguarded reads.
+	done := make(chan error, 1)
+	go func() {
+		for range 5 {
+			if err := wm.CompileWorkflow(t.Context(), path); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for range 5 {
+		if _, err := wm.ReplayBlock("ids", "second"); err != nil {
+			t.Errorf("ReplayBlock failed: %v", err)
+		}
+		if _, err := wm.BlockPlacement("ids", "second"); err != nil {
+			t.Errorf("BlockPlacement failed: %v", err)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+}
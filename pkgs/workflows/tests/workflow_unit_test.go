@@ -44,7 +44,7 @@ func TestWorkflow(t *testing.T) {
 
 	t.Run("compile", func(t *testing.T) {
 		workflowPath := filepath.Join("validcases", "pipeline_workflow_atoms.yaml")
-		err := wm.CompileWorkflow(workflowPath)
+		err := wm.CompileWorkflow(t.Context(), workflowPath)
 		if err != nil {
 			t.Fatalf("CompileWorkflow failed: %v", err)
 		}
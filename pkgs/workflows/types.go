@@ -10,6 +10,11 @@
 package workflows
 
 import (
+	"context"
+	"sync"
+
+	"github.com/AlexsanderHamir/AtomOS/pkgs/compression"
+	"github.com/AlexsanderHamir/AtomOS/pkgs/logship"
 	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
 	"github.com/dominikbraun/graph"
 )
@@ -30,6 +35,18 @@ type Block struct {
 	Version string `yaml:"version"`
 	GitHub  string `yaml:"github"`
 	Force   bool   `yaml:"force"`
+	// Alias installs the block under another name, e.g. to use a fork
+	// next to the block it was forked from.
+	Alias string `yaml:"alias"`
+	// ContinueOnError keeps the run going when this block fails; blocks fed
+	// only by it are then marked skipped instead of failing the workflow.
+	ContinueOnError bool `yaml:"continue_on_error"`
+	// Placement carries scheduling hints for remote executors.
+	Placement *Placement `yaml:"placement"`
+	// Egress restricts the hosts the block may reach; unrestricted when nil.
+	Egress *Egress `yaml:"egress"`
+	// Retry re-executes the block's entries when they fail.
+	Retry *Retry `yaml:"retry"`
 }
 
 // Connection wires outputs from one block entry to inputs of another block entry.
@@ -39,6 +56,9 @@ type Connection struct {
 	Output    string `yaml:"output"`
 	Input     string `yaml:"input"`
 	Source    string `yaml:"source"`
+	// FromEntries chains several entries of the same block, each one reading
+	// the previous one's stdout; it replaces FromEntry when set.
+	FromEntries []string `yaml:"from_entries"`
 }
 
 type Blockname string
@@ -50,7 +70,37 @@ type WorkflowManager struct {
 	pkgmanager *packagemanager.PackageManager
 	metadata   map[Blockname]*packagemanager.BlockMetadata
 	workflows  map[Workflowname]graph.Graph[string, *Block]
-	results    map[Outputkey]Outputres
+	// definitions keeps the parsed file of each compiled workflow.
+	definitions map[Workflowname]*RawWorkflow
+	// compiled keeps the block metadata each workflow was compiled against,
+	// so workflows pinning different versions of a block run side by side.
+	compiled       map[Workflowname]map[Blockname]*packagemanager.BlockMetadata
+	conflictPolicy ConflictPolicy
+	compileMu      sync.RWMutex // Guards metadata and workflows against reloads
+	// recorded keeps the artifacts of the last run of each workflow so a
+	// single block can be replayed without rerunning its upstream blocks.
+	recorded map[Workflowname]map[Outputkey]Outputres
+	stateMu  sync.Mutex // Guards recorded and telemetry across concurrent runs
+
+	progressHandler ProgressHandler
+	explainPolisher ExplainPolisher
+
+	overrideMu         sync.Mutex
+	overridePkgManager *packagemanager.PackageManager // Scratch installs for version overrides
+
+	telemetry map[string]*entryBaseline // Execution baselines keyed by "block/entry"
+
+	compression       map[Store]compression.Codec // Per-store codec overrides
+	artifactRetention int                         // Stored runs kept per workflow
+
+	logSinks logship.Multi // Destinations for block output and run logs
+
+	quotaMu    sync.Mutex
+	quotas     map[Workflowname]Quota   // Limits per workflow, Workspace for all
+	activeRuns map[Workflowname]int     // Runs in flight per workflow
+	cpuUsage   map[Workflowname]float64 // CPU seconds charged per workflow
+
+	wasmRuntime WasmRuntime // Runs WebAssembly blocks, nil for CLIWasmRuntime
 }
 
 type ExecuteArgs struct {
@@ -60,4 +110,58 @@ type ExecuteArgs struct {
 	inblock  []string
 	outcon   []graph.Edge[string]
 	outblock []string
+	results  map[Outputkey]Outputres
+	run      *runEnv
 }
+
+// RunOptions customizes a single workflow run without changing the compiled
+// workflow or the installed blocks.
+type RunOptions struct {
+	// Context cancels the run, including the installs of override
+	// versions. Runs are only bounded by their budget when nil.
+	Context context.Context
+	// Overrides replaces the binary used for the given blocks during this run.
+	Overrides map[Blockname]BlockOverride
+	// Params are exposed to every block as ATOMOS_PARAM_<NAME> variables.
+	Params map[string]string
+	// Determinism enables the deterministic execution mode when set.
+	Determinism *Determinism
+	// Budget stops the run once it uses up its limits.
+	Budget *Budget
+}
+
+// BlockOverride points a block at a different binary for a single run. Set
+// either BinaryPath to use a local build, or Version to run another release of
+// the block; the alternate release is installed in a scratch directory under
+// the install dir so the installed metadata is never touched.
+type BlockOverride struct {
+	BinaryPath string
+	Version    string
+}
+
+// invocation identifies a single execution of a block entry.
+type invocation struct {
+	block  string
+	binary string
+	entry  string
+	env    []string // Workflow environment variables ("KEY=value")
+	dir    string   // Working directory, shared by the entries of a chain
+	run    *runEnv  // Run the invocation belongs to, nil outside of a run
+	execID string   // Execution ID of the block within the run
+	// sandbox is the block's sandbox profile, nil when it runs unconfined.
+	sandbox *packagemanager.Sandbox
+	retry   *Retry // Retry policy of the block, nil to never retry
+}
+
+// BlockProgress is a progress update reported by a running block through
+// the progress protocol (see ProgressLinePrefix).
+type BlockProgress struct {
+	Block       string
+	ExecutionID string
+	Entry       string
+	Percent     int
+	Step        string
+}
+
+// ProgressHandler receives progress updates reported by running blocks.
+type ProgressHandler func(BlockProgress)
@@ -11,51 +11,104 @@ package workflows
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
+
+	packagemanager "github.com/AlexsanderHamir/AtomOS/pkgs/package_manager"
 )
 
-func runBinaryWithPipe(binary, entry, filePath string) (string, error) {
+func runBinaryWithPipe(inv invocation, filePath string, onProgress func(percent int, step string)) (string, execution, error) {
+	var stdin io.Reader
 	file, err := os.Open(filePath)
-
-	cmd := exec.Command(binary, entry)
 	if err == nil {
-		cmd.Stdin = file
+		stdin = file
 	}
 	defer file.Close()
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	return runInvocation(inv, stdin, onProgress)
+}
 
-	// Run the command
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("binary failed: %v, stderr: %s", err, stderr.String())
+// runBinaryWithString pipes the given input string into the binary's stdin
+// and returns the binary's stdout output.
+func runBinaryWithString(inv invocation, input Outputres, onProgress func(percent int, step string)) (string, execution, error) {
+	return runInvocation(inv, strings.NewReader(string(input)), onProgress)
+}
+
+// runInvocation runs the invoked entry with the given stdin, as a native
+// process, in a container for image blocks, or through the run's
+// WasmRuntime for WebAssembly blocks.
+func runInvocation(inv invocation, stdin io.Reader, onProgress func(percent int, step string)) (string, execution, error) {
+	image, isImage, err := packagemanager.ImageRef(inv.binary)
+	if err != nil {
+		return "", execution{}, err
+	}
+	if isImage {
+		return runCaptured(func(stdout, stderr io.Writer) (int, time.Duration, error) {
+			code, err := runContainer(inv, image, stdin, stdout, stderr)
+			return code, 0, err
+		}, onProgress)
+	}
+	if isWasmModule(inv.binary) {
+		return runCaptured(func(stdout, stderr io.Writer) (int, time.Duration, error) {
+			code, err := runWasm(inv, stdin, stdout, stderr)
+			return code, 0, err
+		}, onProgress)
 	}
 
-	return stdout.String(), nil
+	cmd := newBinaryCommand(inv)
+	cmd.Stdin = stdin
+	return runCaptured(func(stdout, stderr io.Writer) (int, time.Duration, error) {
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		err := cmd.Run()
+		if cmd.ProcessState == nil {
+			return 0, 0, err
+		}
+		return cmd.ProcessState.ExitCode(), cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime(), err
+	}, onProgress)
 }
 
-// runBinaryWithString pipes the given input string into the binary's stdin
-// and returns the binary's stdout output.
-func runBinaryWithString(binary, entry string, input Outputres) (string, error) {
-	// Prepare the command
-	cmd := exec.Command(binary, entry)
+// newBinaryCommand prepares the command running the invoked entry, with the
+// workflow environment added on top of the orchestrator's own, or on top of
+// a minimal one for sandboxed blocks.
+func newBinaryCommand(inv invocation) *exec.Cmd {
+	ctx := context.Background()
+	if inv.run != nil {
+		ctx = inv.run.ctx
+	}
 
-	// Pipe string into stdin
-	cmd.Stdin = strings.NewReader(string(input))
+	cmd := exec.CommandContext(ctx, inv.binary, inv.entry)
+	cmd.Dir = inv.dir
+	switch {
+	case inv.sandbox != nil && inv.run != nil:
+		cmd.Env = append(inv.run.sandboxProcessEnv(inv.block, inv.sandbox), inv.env...)
+	case len(inv.env) > 0:
+		cmd.Env = append(os.Environ(), inv.env...)
+	}
+	return cmd
+}
 
-	// Capture stdout and stderr
+// runCaptured calls run with stdout and stderr captured into buffers and
+// collects the execution stats. run returns the exit code and CPU time of
+// the execution. Progress lines written to stderr are left out of the
+// captured stderr and forwarded to onProgress when it is set.
+func runCaptured(run func(stdout, stderr io.Writer) (int, time.Duration, error), onProgress func(percent int, step string)) (string, execution, error) {
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	progress := newProgressWriter(&stderr, onProgress)
 
 	// Run the command
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("binary failed: %v, stderr: %s", err, stderr.String())
+	start := time.Now()
+	exitCode, cpu, err := run(&stdout, progress)
+	stats := execution{duration: time.Since(start), outputSize: stdout.Len(), exitCode: exitCode, cpu: cpu}
+	_ = progress.flush()
+	stats.stdout, stats.stderr = stdout.String(), stderr.String()
+	if err != nil {
+		return "", stats, fmt.Errorf("binary failed: %v, stderr: %s", err, stderr.String())
 	}
 
-	return stdout.String(), nil
+	return stdout.String(), stats, nil
 }
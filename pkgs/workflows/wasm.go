@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// wasmModuleExt marks block binaries that are WebAssembly modules. They are
+// installed from the "wasm" key of binary.assets and run through a
+// WasmRuntime instead of being executed directly.
+const wasmModuleExt = ".wasm"
+
+// EnvWasmRuntime overrides the WASI command-line runtime used by
+// CLIWasmRuntime, e.g. /usr/local/bin/wasmtime.
+const EnvWasmRuntime = "ATOMOS_WASM_RUNTIME"
+
+// WasmRun describes one execution of a WebAssembly block. The module reads
+// its input from Stdin and writes its output to Stdout, exactly like a
+// native block.
+type WasmRun struct {
+	Module string   // Path of the .wasm module
+	Args   []string // Arguments after the program name: the entry
+	Env    []string // Workflow environment ("KEY=value"); the host's isn't inherited
+	Dir    string   // Directory the module may access, "" for none
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// WasmRuntime executes WebAssembly blocks and returns the module's exit
+// code. Runtimes embedded in the process can implement it as well as
+// wrappers around a command-line runtime.
+type WasmRuntime interface {
+	Run(ctx context.Context, run WasmRun) (int, error)
+}
+
+// CLIWasmRuntime runs modules with a WASI command-line runtime: Path, the
+// ATOMOS_WASM_RUNTIME environment variable, or wazero or wasmtime found in
+// PATH, in that order.
+type CLIWasmRuntime struct {
+	Path string
+}
+
+func (r CLIWasmRuntime) Run(ctx context.Context, run WasmRun) (int, error) {
+	path, err := r.runtimePath()
+	if err != nil {
+		return -1, err
+	}
+
+	var args []string
+	if strings.Contains(filepath.Base(path), "wazero") {
+		args = append(args, "run")
+		for _, kv := range run.Env {
+			args = append(args, "-env="+kv)
+		}
+		if run.Dir != "" {
+			args = append(args, "-mount="+run.Dir)
+		}
+	} else {
+		args = append(args, "run")
+		for _, kv := range run.Env {
+			args = append(args, "--env", kv)
+		}
+		if run.Dir != "" {
+			args = append(args, "--dir="+run.Dir)
+		}
+	}
+	args = append(append(args, "--", run.Module), run.Args...)
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = run.Dir
+	cmd.Stdin = run.Stdin
+	cmd.Stdout = run.Stdout
+	cmd.Stderr = run.Stderr
+	err = cmd.Run()
+
+	code := -1
+	if cmd.ProcessState != nil {
+		code = cmd.ProcessState.ExitCode()
+	}
+	return code, err
+}
+
+func (r CLIWasmRuntime) runtimePath() (string, error) {
+	if r.Path != "" {
+		return r.Path, nil
+	}
+	if path := os.Getenv(EnvWasmRuntime); path != "" {
+		return path, nil
+	}
+	for _, name := range []string{"wazero", "wasmtime"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("no WebAssembly runtime found: install wazero or wasmtime, set " + EnvWasmRuntime + ", or call SetWasmRuntime")
+}
+
+// SetWasmRuntime sets the runtime executing WebAssembly blocks. Passing nil
+// restores CLIWasmRuntime.
+func (wm *WorkflowManager) SetWasmRuntime(runtime WasmRuntime) {
+	wm.wasmRuntime = runtime
+}
+
+// isWasmModule reports whether binary is a WebAssembly module.
+func isWasmModule(binary string) bool {
+	return strings.EqualFold(filepath.Ext(binary), wasmModuleExt)
+}
+
+// runWasm runs the invoked entry of a WebAssembly block with the run's
+// runtime, wiring stdin, stdout, and stderr like a native binary.
+func runWasm(inv invocation, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	runtime := WasmRuntime(CLIWasmRuntime{})
+	ctx := context.Background()
+	if inv.run != nil {
+		ctx = inv.run.ctx
+		if inv.run.wasm != nil {
+			runtime = inv.run.wasm
+		}
+	}
+
+	code, err := runtime.Run(ctx, WasmRun{
+		Module: inv.binary,
+		Args:   []string{inv.entry},
+		Env:    inv.env,
+		Dir:    inv.dir,
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return code, fmt.Errorf("wasm module failed: %w", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Alexsander Hamir Gomes Baptista
+//
+// This file is part of AtomOS and licensed under the Sustainable Use License (SUL).
+// You may use, modify, and redistribute this software for personal or internal business use.
+// Offering it as a commercial hosted service requires a separate license.
+//
+// Full license: see the LICENSE file in the root of this repository
+// or contact alexsanderhamirgomesbaptista@gmail.com.
+
+package workflows
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchWorkflows polls the directory when
+// no interval is given.
+const DefaultWatchInterval = 2 * time.Second
+
+// ReloadStatus is the outcome of reloading a workflow file.
+type ReloadStatus string
+
+const (
+	ReloadSucceeded ReloadStatus = "succeeded" // New version compiled and swapped in
+	ReloadFailed    ReloadStatus = "failed"    // Previous version kept running
+	ReloadRemoved   ReloadStatus = "removed"   // File deleted; compiled version kept
+)
+
+// ReloadEvent reports a workflow file picked up by WatchWorkflows.
+type ReloadEvent struct {
+	Path     string
+	Workflow Workflowname // Empty when the file couldn't be parsed
+	Status   ReloadStatus
+	Err      error // Set when Status is ReloadFailed; a *DiagnosticsError for invalid workflows
+	Time     time.Time
+}
+
+// ReloadHandler receives the events emitted by WatchWorkflows.
+type ReloadHandler func(ReloadEvent)
+
+// WatchWorkflows compiles every workflow file (*.yaml, *.yml) under dir and
+// then polls the directory every interval, recompiling files whose content
+// changed. A new version replaces the running one only once it compiled
+// successfully, so a broken edit leaves the previous version in place. Runs
+// already in flight keep the version they started with. Every compile and
+// removal is reported to handler, which may be nil. WatchWorkflows blocks
+// until ctx is cancelled and returns ctx.Err().
+func (wm *WorkflowManager) WatchWorkflows(ctx context.Context, dir string, interval time.Duration, handler ReloadHandler) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	if handler == nil {
+		handler = func(ReloadEvent) {}
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+
+	seen := map[string][sha256.Size]byte{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		wm.reloadChanged(ctx, dir, seen, handler)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadChanged recompiles the files of dir whose content differs from seen
+// and reports files that disappeared.
+func (wm *WorkflowManager) reloadChanged(ctx context.Context, dir string, seen map[string][sha256.Size]byte, handler ReloadHandler) {
+	current := map[string][sha256.Size]byte{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Files can vanish between the walk and the read.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		current[path] = sha256.Sum256(data)
+		return nil
+	})
+	if err != nil {
+		handler(ReloadEvent{Path: dir, Status: ReloadFailed, Err: err, Time: time.Now()})
+		return
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(current)) {
+		if sum, ok := seen[path]; ok && sum == current[path] {
+			continue
+		}
+		seen[path] = current[path]
+
+		event := ReloadEvent{Path: path, Status: ReloadSucceeded}
+		if rwf, err := parseWorkflow(path); err == nil {
+			event.Workflow = Workflowname(rwf.Name)
+		}
+		if err := wm.CompileWorkflow(ctx, path); err != nil {
+			event.Status, event.Err = ReloadFailed, err
+		}
+		event.Time = time.Now()
+		handler(event)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(seen)) {
+		if _, ok := current[path]; !ok {
+			delete(seen, path)
+			handler(ReloadEvent{Path: path, Status: ReloadRemoved, Time: time.Now()})
+		}
+	}
+}